@@ -0,0 +1,162 @@
+// Package awsinv inventories AWS resources across services and regions, with their
+// estimated costs. It is the library the awsinv command is built on, for tools that
+// want the inventory as Go values rather than a report:
+//
+//	inv, err := awsinv.New(
+//		awsinv.WithProfile("prod"),
+//		awsinv.WithServices("ec2", "rds"),
+//		awsinv.WithRegions("us-east-1", "eu-west-1"),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	collection, err := inv.Collect(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	for _, resource := range collection.Resources {
+//		if estimate := collection.Costs[resource.Key()]; estimate != nil {
+//			fmt.Println(resource.Service, resource.ID, estimate.Amount)
+//		}
+//	}
+package awsinv
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/orchestrator"
+	"github.com/xiaochen/awsinv/pkg/output"
+	"github.com/xiaochen/awsinv/pkg/pricing"
+)
+
+// Resource is a collected AWS resource, normalized across services
+type Resource = models.Resource
+
+// Collection is the result of a collection: the resources, the problems met while
+// collecting them, a summary and the cost estimates of the resources keyed by
+// Resource.Key
+type Collection = models.ResourceCollection
+
+// CostEstimate is the estimated monthly cost of a resource
+type CostEstimate = models.CostEstimate
+
+// Identity is the principal the credentials resolve to
+type Identity = awspkg.Identity
+
+// Collector collects the resources of one service
+type Collector = models.Collector
+
+// CollectorFactory creates the collector of a service for an inventory
+type CollectorFactory = orchestrator.CollectorFactory
+
+// RegisterCollector adds a collector for a service awsinv does not ship, collected
+// like the built-in ones by the inventories created afterwards. It panics if the
+// name is invalid or already taken.
+func RegisterCollector(name string, factory CollectorFactory) {
+	orchestrator.RegisterCollector(name, factory)
+}
+
+// Formatter writes reports of collections
+type Formatter = output.Formatter
+
+// FormatterFactory creates a formatter that writes reports to a writer
+type FormatterFactory = output.FormatterFactory
+
+// RegisterFormatter adds an output format awsinv does not ship, which the CLI then
+// offers with --output. It panics if the name is invalid or already taken.
+func RegisterFormatter(name string, factory FormatterFactory) {
+	output.RegisterFormatter(name, factory)
+}
+
+// Inventory collects the resources of one AWS account
+type Inventory struct {
+	clientManager *awspkg.ClientManager
+	orchestrator  *orchestrator.Orchestrator
+	collect       orchestrator.CollectOptions
+	costEngine    *pricing.Engine
+}
+
+// config is what the options of New set
+type config struct {
+	credentials   awspkg.Config
+	rateLimits    map[string]float64
+	budget        *awspkg.APIBudget
+	clientManager *awspkg.ClientManager
+	settings      orchestrator.Settings
+	collect       orchestrator.CollectOptions
+	costEngine    *pricing.Engine
+	logger        io.Writer
+}
+
+// New creates an inventory of the account the default AWS credentials, or those the
+// options choose, belong to. Without options, every service is collected in every
+// enabled region.
+func New(opts ...Option) (*Inventory, error) {
+	cfg := config{
+		settings: orchestrator.DefaultSettings(),
+		collect:  orchestrator.CollectOptions{Parallel: DefaultParallel},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.collect.Parallel < 1 {
+		return nil, fmt.Errorf("invalid parallelism: %d (expected at least 1)", cfg.collect.Parallel)
+	}
+
+	clientManager := cfg.clientManager
+	if clientManager == nil {
+		credentials := cfg.credentials
+		credentials.RateLimits = cfg.rateLimits
+		credentials.Budget = cfg.budget
+
+		var err error
+		clientManager, err = awspkg.NewClientManager(credentials)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.logger != nil {
+		cfg.settings.Logger = cfg.logger
+		cfg.collect.Verbose = true
+	}
+
+	costEngine := cfg.costEngine
+	if costEngine == nil {
+		costEngine = pricing.NewEngine(nil)
+	}
+
+	return &Inventory{
+		clientManager: clientManager,
+		orchestrator:  orchestrator.NewOrchestratorWithSettings(clientManager, cfg.settings),
+		collect:       cfg.collect,
+		costEngine:    costEngine,
+	}, nil
+}
+
+// Collect collects the resources and estimates their costs. Services that fail are
+// recorded in the collection's Errors rather than failing the collection, unless
+// WithFailFast is given.
+func (inv *Inventory) Collect(ctx context.Context) (*Collection, error) {
+	collection, err := inv.orchestrator.Collect(ctx, inv.collect)
+	if err != nil {
+		return nil, err
+	}
+
+	inv.costEngine.Annotate(ctx, collection)
+	return collection, nil
+}
+
+// Identity returns the account and principal the credentials belong to
+func (inv *Inventory) Identity(ctx context.Context) (*Identity, error) {
+	return inv.clientManager.CallerIdentity(ctx)
+}
+
+// Services returns the names of the services that can be collected
+func Services() []string {
+	return orchestrator.NewOrchestrator(nil).GetAvailableServices()
+}
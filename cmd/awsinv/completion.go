@@ -0,0 +1,119 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/xiaochen/awsinv/pkg/config"
+	"github.com/xiaochen/awsinv/pkg/orchestrator"
+)
+
+// knownRegions are completed for --regions. Completion must be fast and work without
+// credentials, so the regions are listed here rather than discovered.
+var knownRegions = []string{
+	"af-south-1",
+	"ap-east-1",
+	"ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-south-1", "ap-south-2",
+	"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ap-southeast-4", "ap-southeast-5",
+	"ca-central-1", "ca-west-1",
+	"eu-central-1", "eu-central-2",
+	"eu-north-1",
+	"eu-south-1", "eu-south-2",
+	"eu-west-1", "eu-west-2", "eu-west-3",
+	"il-central-1",
+	"me-central-1", "me-south-1",
+	"sa-east-1",
+	"us-east-1", "us-east-2",
+	"us-west-1", "us-west-2",
+}
+
+// sortFields are the fields completed for --sort
+var sortFields = []string{"service", "region", "id", "name", "type", "state", "class", "created", "age", "cost", "size"}
+
+// flagChoicesPattern finds the choices listed in a flag's usage, e.g. "(table|json)"
+var flagChoicesPattern = regexp.MustCompile(`\(([a-z0-9-]+(?:\|[a-z0-9-]+)+)\)`)
+
+// registerCompletions adds value completion to the flags and arguments of cmd and
+// its subcommands. Cobra provides the completion command itself.
+func registerCompletions(cmd *cobra.Command) {
+	services := orchestrator.NewOrchestrator(nil).GetAvailableServices()
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		var complete func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+		switch flag.Name {
+		case "services":
+			complete = completeList(services)
+		case "regions", "exclude-regions":
+			complete = completeList(knownRegions)
+		case "region", "probe-region":
+			complete = cobra.FixedCompletions(knownRegions, cobra.ShellCompDirectiveNoFileComp)
+		case "sort":
+			complete = completeSort
+		case "preset":
+			complete = completePreset
+		case "config", "out", "html-logo", "input":
+			return
+		default:
+			// Flags that list their choices in the usage, such as --output (table|json|...)
+			match := flagChoicesPattern.FindStringSubmatch(flag.Usage)
+			if match == nil {
+				return
+			}
+			complete = cobra.FixedCompletions(strings.Split(match[1], "|"), cobra.ShellCompDirectiveNoFileComp)
+		}
+
+		// The flag exists and is visited once, so registering cannot fail
+		_ = cmd.RegisterFlagCompletionFunc(flag.Name, complete)
+	})
+
+	for _, sub := range cmd.Commands() {
+		registerCompletions(sub)
+	}
+}
+
+// completeList completes the last entry of a comma-separated list
+func completeList(choices []string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		prefix := ""
+		if i := strings.LastIndex(toComplete, ","); i >= 0 {
+			prefix = toComplete[:i+1]
+		}
+
+		var completions []string
+		for _, choice := range choices {
+			completions = append(completions, prefix+choice)
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+	}
+}
+
+// completeSort completes the fields of a sort specification, ascending or descending
+func completeSort(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+	if strings.HasPrefix(toComplete[len(prefix):], "-") {
+		prefix += "-"
+	}
+
+	var completions []string
+	for _, field := range sortFields {
+		completions = append(completions, prefix+field)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completePreset completes the presets of the config file
+func completePreset(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	path, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return cfg.PresetNames(), cobra.ShellCompDirectiveNoFileComp
+}
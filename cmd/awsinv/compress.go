@@ -0,0 +1,52 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressionsByExtension maps compressed file extensions to their algorithm
+var compressionsByExtension = map[string]string{
+	".gz":  "gzip",
+	".zst": "zstd",
+}
+
+// compressionFromExtension returns the compression implied by a file name, if any,
+// and the name without the compression extension
+func compressionFromExtension(path string) (string, string) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if compression, exists := compressionsByExtension[ext]; exists {
+		return compression, strings.TrimSuffix(path, filepath.Ext(path))
+	}
+	return "", path
+}
+
+// nopWriteCloser lets uncompressed output share the compressed code path
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close does nothing
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// newCompressWriter wraps writer with the requested compression. Close must be
+// called to flush the compressed stream; it does not close writer.
+func newCompressWriter(writer io.Writer, compression string) (io.WriteCloser, error) {
+	switch compression {
+	case "", "none":
+		return nopWriteCloser{writer}, nil
+	case "gzip":
+		return gzip.NewWriter(writer), nil
+	case "zstd":
+		return zstd.NewWriter(writer)
+	default:
+		return nil, fmt.Errorf("invalid compression: %s (expected gzip, zstd or none)", compression)
+	}
+}
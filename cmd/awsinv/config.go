@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv/pkg/config"
+	"github.com/xiaochen/awsinv/pkg/findings"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/output"
+)
+
+// applyConfig applies the configuration file's defaults, then the selected preset.
+// Flags given on the command line always win, except --filter and --exclude, whose
+// expressions are added to the configured ones.
+func applyConfig(cmd *cobra.Command, opts *options) error {
+	cfg, err := config.Load(opts.configPath)
+	if err != nil {
+		return err
+	}
+
+	applyPreset(cmd, opts, cfg.Defaults)
+	rules := cfg.Defaults.Rules
+	tagMappings := make(map[string][]string)
+	for key, aliases := range cfg.Defaults.TagMappings {
+		tagMappings[key] = aliases
+	}
+
+	if opts.preset != "" {
+		preset, err := cfg.Preset(opts.preset)
+		if err != nil {
+			return err
+		}
+		applyPreset(cmd, opts, preset)
+		rules = append(append([]config.Rule(nil), rules...), preset.Rules...)
+		for key, aliases := range preset.TagMappings {
+			tagMappings[key] = aliases
+		}
+	}
+
+	if err := setTagMappings(tagMappings); err != nil {
+		return err
+	}
+	return registerRules(rules)
+}
+
+// setTagMappings adds the config file's tag mappings to the built-in ones
+func setTagMappings(tagMappings map[string][]string) error {
+	var mappings []models.TagMapping
+	for _, key := range sortedMapKeys(tagMappings) {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid tag mapping: missing key")
+		}
+		mappings = append(mappings, models.TagMapping{Key: key, Aliases: tagMappings[key]})
+	}
+	models.SetTagMappings(mappings)
+	return nil
+}
+
+// registerRules adds the config file's rules to the findings engine. A rule with the
+// ID of a built-in or earlier rule replaces it.
+func registerRules(rules []config.Rule) error {
+	for _, rule := range rules {
+		if rule.ID == "" {
+			return fmt.Errorf("invalid rule %q: missing id", rule.Match)
+		}
+		severity, err := findings.ParseSeverity(rule.Severity)
+		if err != nil {
+			return fmt.Errorf("invalid rule %s: %w", rule.ID, err)
+		}
+		if rule.Match == "" {
+			return fmt.Errorf("invalid rule %s: missing match", rule.ID)
+		}
+		filter, err := output.ParseFilter(rule.Match)
+		if err != nil {
+			return fmt.Errorf("invalid rule %s: %w", rule.ID, err)
+		}
+
+		message := rule.Message
+		if message == "" {
+			message = "Matches " + rule.Match
+		}
+		findings.RegisterRule(findings.Rule{
+			ID:       rule.ID,
+			Severity: severity,
+			Title:    message,
+			Check: func(resource models.Resource) []string {
+				if filter.Matches(resource) {
+					return []string{message}
+				}
+				return nil
+			},
+		})
+	}
+	return nil
+}
+
+// applyPreset sets the options a preset configures, skipping flags the command does
+// not have or that were given on the command line
+func applyPreset(cmd *cobra.Command, opts *options, preset config.Preset) {
+	flags := cmd.Flags()
+	set := func(name string, configured bool, apply func()) {
+		if configured && flags.Lookup(name) != nil && !flags.Changed(name) {
+			apply()
+		}
+	}
+
+	set("services", len(preset.Services) > 0, func() { opts.services = preset.Services })
+	set("regions", len(preset.Regions) > 0, func() { opts.regions = preset.Regions })
+	set("exclude-regions", len(preset.ExcludeRegions) > 0, func() { opts.excludeRegions = preset.ExcludeRegions })
+	set("include-opt-in-regions", preset.IncludeOptInRegions, func() { opts.includeOptIn = true })
+	set("parallel", preset.Parallel > 0, func() { opts.parallel = preset.Parallel })
+	set("timeout", preset.Timeout > 0, func() { opts.timeout = preset.Timeout })
+	set("service-timeout", preset.ServiceTimeout > 0, func() { opts.serviceTimeout = preset.ServiceTimeout })
+	set("api-budget", preset.APIBudget > 0, func() { opts.apiBudget = preset.APIBudget })
+	set("rate-limit", len(preset.RateLimits) > 0, func() {
+		opts.rateLimits = nil
+		for _, api := range sortedMapKeys(preset.RateLimits) {
+			opts.rateLimits = append(opts.rateLimits, fmt.Sprintf("%s=%g", api, preset.RateLimits[api]))
+		}
+	})
+	set("quota-threshold", preset.QuotaThreshold > 0, func() { opts.quotaThreshold = preset.QuotaThreshold })
+	set("cloudcontrol-types", len(preset.CloudControlTypes) > 0, func() { opts.cloudControl = preset.CloudControlTypes })
+	set("usage-metrics", preset.UsageMetrics, func() { opts.usageMetrics = true })
+	set("rightsizing", preset.Rightsizing, func() { opts.rightsizing = true })
+	set("skip-tags", preset.SkipTags, func() { opts.skipTags = true })
+	set("skip-bucket-details", preset.SkipBucketDetails, func() { opts.skipBucketInfo = true })
+
+	set("profile", preset.Profile != "", func() { opts.profile = preset.Profile })
+	set("profiles", len(preset.Profiles) > 0, func() { opts.profiles = preset.Profiles })
+	set("expect-account", len(preset.ExpectAccounts) > 0, func() { opts.expectAccounts = preset.ExpectAccounts })
+	set("role-arn", preset.RoleARN != "", func() { opts.roleARN = preset.RoleARN })
+	set("external-id", preset.ExternalID != "", func() { opts.externalID = preset.ExternalID })
+	set("role-session-name", preset.RoleSessionName != "", func() { opts.sessionName = preset.RoleSessionName })
+	set("role-duration", preset.RoleDuration > 0, func() { opts.roleDuration = preset.RoleDuration })
+	set("mfa-serial", preset.MFASerial != "", func() { opts.mfaSerial = preset.MFASerial })
+	set("mfa-token-command", preset.MFATokenCommand != "", func() { opts.mfaCommand = preset.MFATokenCommand })
+	set("web-identity-token-file", preset.WebIdentityTokenFile != "", func() { opts.tokenFile = preset.WebIdentityTokenFile })
+
+	// Filters narrow each other, so configured and command line expressions combine
+	if flags.Lookup("filter") != nil {
+		opts.filters = append(append([]string(nil), preset.Filters...), opts.filters...)
+	}
+	if flags.Lookup("exclude") != nil {
+		opts.excludes = append(append([]string(nil), preset.Excludes...), opts.excludes...)
+	}
+	set("sort", preset.Sort != "", func() { opts.sortField = preset.Sort })
+
+	// Report options only apply to commands that write reports; watch has its own
+	// --output formats
+	if flags.Lookup("out") != nil {
+		set("output", preset.Output != "", func() { opts.output = preset.Output })
+		set("out", preset.Out != "", func() { opts.out = preset.Out })
+		set("compress", preset.Compress != "", func() { opts.compress = preset.Compress })
+		set("query", preset.Query != "", func() { opts.query = preset.Query })
+		set("cost-period", preset.CostPeriod != "", func() { opts.costPeriod = preset.CostPeriod })
+		set("required-tags", len(preset.RequiredTags) > 0, func() { opts.requiredTags = preset.RequiredTags })
+	}
+	set("html-theme", preset.HTMLTheme != "", func() { opts.htmlTheme = preset.HTMLTheme })
+	set("html-title", preset.HTMLTitle != "", func() { opts.htmlTitle = preset.HTMLTitle })
+	set("html-logo", preset.HTMLLogo != "", func() { opts.htmlLogo = preset.HTMLLogo })
+
+	set("snapshot-store", preset.SnapshotStore != "", func() { opts.snapshotStore = preset.SnapshotStore })
+	set("cache-ttl", preset.CacheTTL > 0, func() { opts.cacheTTL = preset.CacheTTL })
+	set("compare-to", preset.CompareTo != "", func() { opts.compareTo = preset.CompareTo })
+
+	set("fail-on-errors", preset.FailOnErrors, func() { opts.failOnErrors = true })
+	set("fail-if-cost-over", preset.FailIfCostOver > 0, func() { opts.failIfCostOver = preset.FailIfCostOver })
+	if flags.Lookup("fail-if-count") != nil {
+		opts.failIfCount = append(append([]string(nil), preset.FailIfCount...), opts.failIfCount...)
+	}
+
+	// Budgets have no flag; they apply wherever --fail-on-budget can check them
+	if len(preset.Budgets) > 0 && flags.Lookup("fail-on-budget") != nil {
+		opts.budgets = nil
+		for _, budget := range preset.Budgets {
+			opts.budgets = append(opts.budgets, models.Budget(budget))
+		}
+	}
+	set("fail-on-budget", preset.FailOnBudget, func() { opts.failOnBudget = true })
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv/pkg/collectors"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/orchestrator"
+)
+
+// description is the describe command's JSON output
+type description struct {
+	Resource     models.Resource      `json:"resource"`
+	CostEstimate *models.CostEstimate `json:"costEstimate,omitempty"`
+	Raw          interface{}          `json:"raw,omitempty"`
+}
+
+// newDescribeCommand creates the describe command, which shows everything known about
+// one resource
+func newDescribeCommand() *cobra.Command {
+	// A resource is looked up by one collector, so there is no --parallel
+	opts := &options{parallel: 1}
+	var region string
+
+	cmd := &cobra.Command{
+		Use:   "describe SERVICE ID",
+		Short: "Show the full detail of one resource: normalized view, tags, cost explanation and raw API response",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDescribe(cmd.Context(), opts, region, args[0], args[1])
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return orchestrator.NewOrchestrator(nil).GetAvailableServices(), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	addCredentialFlags(cmd, opts)
+	addPricingFlags(cmd, opts)
+
+	flags := cmd.Flags()
+	flags.StringVar(&region, "region", "", "Region of the resource (default the profile's region)")
+	flags.StringVar(&opts.output, "output", "text", "Output format (text|json)")
+	flags.DurationVar(&opts.timeout, "timeout", time.Minute, "Overall context timeout")
+	flags.Float64Var(&opts.quotaThreshold, "quota-threshold", collectors.DefaultQuotaThreshold, "Utilization percentage at which service quotas are flagged")
+	flags.StringSliceVar(&opts.cloudControl, "cloudcontrol-types", nil, "Cloud Control resource types to search, for cloudcontrol resources")
+
+	return cmd
+}
+
+// runDescribe fetches one resource and prints it
+func runDescribe(ctx context.Context, opts *options, region, service, id string) error {
+	if opts.output != "text" && opts.output != "json" {
+		return fmt.Errorf("invalid output format: %s (expected text or json)", opts.output)
+	}
+
+	if err := validateCollectionOptions(opts); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+	defer cancel()
+
+	clientManager, err := newClientManager(opts)
+	if err != nil {
+		return err
+	}
+
+	if region == "" {
+		region = clientManager.DefaultRegion()
+		if region == "" {
+			return fmt.Errorf("no region configured; use --region")
+		}
+	}
+
+	// Cost estimates fall back to built-in prices when the pricing API is unavailable
+	if err := usePrices(ctx, opts); err != nil {
+		return err
+	}
+
+	orch := orchestrator.NewOrchestratorWithSettings(clientManager, orchestrator.Settings{
+		QuotaThreshold:    opts.quotaThreshold,
+		CloudControlTypes: opts.cloudControl,
+		UsageMetrics:      opts.usageMetrics,
+		Rightsizing:       opts.rightsizing,
+	})
+
+	resource, raw, err := orch.Describe(ctx, service, region, id)
+	if err != nil {
+		return err
+	}
+
+	result := description{
+		Resource:     *resource,
+		CostEstimate: opts.costEngine.Estimate(ctx, *resource),
+		Raw:          raw,
+	}
+
+	if opts.output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+	return writeDescription(os.Stdout, result)
+}
+
+// writeDescription prints a description as readable sections
+func writeDescription(w io.Writer, d description) error {
+	resource := d.Resource
+
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%-12s %s\n", name+":", value)
+		}
+	}
+
+	fmt.Fprintln(w, "=== Resource ===")
+	field("Service", resource.Service)
+	field("Region", resource.Region)
+	field("ID", resource.ID)
+	field("Name", resource.Name)
+	field("Type", resource.Type)
+	field("State", resource.State)
+	field("Class", resource.Class)
+	if resource.CreatedAt != nil {
+		field("Created", resource.CreatedAt.Format(time.RFC3339))
+	}
+
+	if len(resource.Tags) > 0 {
+		fmt.Fprintln(w, "\n=== Tags ===")
+		for _, key := range sortedMapKeys(resource.Tags) {
+			fmt.Fprintf(w, "%s = %s\n", key, resource.Tags[key])
+		}
+	}
+
+	if len(resource.Extra) > 0 {
+		fmt.Fprintln(w, "\n=== Extra ===")
+		for _, key := range sortedMapKeys(resource.Extra) {
+			fmt.Fprintf(w, "%s: %v\n", key, resource.Extra[key])
+		}
+	}
+
+	if estimate := d.CostEstimate; estimate != nil {
+		fmt.Fprintln(w, "\n=== Cost Estimate ===")
+		field("Monthly", fmt.Sprintf("$%.2f", estimate.Amount))
+		field("Explanation", estimate.Explanation)
+		field("Formula", estimate.Formula)
+		field("Details", estimate.FormulaExplanation)
+		field("Accuracy", estimate.Accuracy)
+		field("Source", estimate.Source)
+		for _, key := range sortedMapKeys(estimate.Breakdown) {
+			fmt.Fprintf(w, "  %s: $%.2f\n", key, estimate.Breakdown[key])
+		}
+		if len(estimate.Assumptions) > 0 {
+			fmt.Fprintf(w, "Assumptions:\n  - %s\n", strings.Join(estimate.Assumptions, "\n  - "))
+		}
+	}
+
+	fmt.Fprintln(w, "\n=== Raw API Response ===")
+	if d.Raw == nil {
+		fmt.Fprintln(w, "Not available for this resource; showing the collected view only")
+		return nil
+	}
+	raw, err := json.MarshalIndent(d.Raw, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode API response: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(raw))
+	return err
+}
+
+func sortedMapKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv/pkg/diff"
+)
+
+// newDiffCommand creates the diff command, which compares two JSON snapshots
+func newDiffCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "diff OLD.json NEW.json",
+		Short: "Report added, removed and changed resources between two JSON inventories",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "json" {
+				return fmt.Errorf("invalid output format: %s (expected table or json)", format)
+			}
+
+			oldSnapshot, err := diff.LoadSnapshot(args[0])
+			if err != nil {
+				return err
+			}
+			newSnapshot, err := diff.LoadSnapshot(args[1])
+			if err != nil {
+				return err
+			}
+
+			result := diff.Compare(oldSnapshot, newSnapshot)
+			if format == "json" {
+				return diff.WriteJSON(os.Stdout, result)
+			}
+			return diff.WriteTable(os.Stdout, result)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "output", "table", "Output format (table|json)")
+
+	return cmd
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv/pkg/orchestrator"
+)
+
+// newDoctorCommand creates the doctor command, a preflight check of credentials and
+// permissions that also estimates the size of a full scan
+func newDoctorCommand() *cobra.Command {
+	opts := &options{}
+	var probeRegion string
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check credentials and permissions, and estimate the size of a scan",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runDoctor(cmd.Context(), opts, probeRegion)
+		},
+	}
+
+	addCollectionFlags(cmd, opts)
+	cmd.Flags().StringVar(&probeRegion, "probe-region", "", "Region in which to probe regional services (default the first --regions entry, the profile's region or us-east-1)")
+
+	return cmd
+}
+
+// runDoctor runs the preflight checks, printing each result, and fails if any check failed
+func runDoctor(ctx context.Context, opts *options, probeRegion string) error {
+	if err := validateCollectionOptions(opts); err != nil {
+		return err
+	}
+
+	w := os.Stdout
+	problems := 0
+
+	fmt.Fprintln(w, "Credentials")
+	clientManager, err := newClientManager(opts)
+	if err != nil {
+		fmt.Fprintf(w, "  ✗ %v\n", err)
+		return fmt.Errorf("credentials could not be loaded")
+	}
+
+	identity, err := clientManager.CallerIdentity(ctx)
+	if err != nil {
+		fmt.Fprintf(w, "  ✗ %v\n", err)
+		return fmt.Errorf("credentials are not valid")
+	}
+	if len(opts.expectAccounts) > 0 && !slices.Contains(opts.expectAccounts, identity.Account) {
+		fmt.Fprintf(w, "  ✗ Account %s, expected %s\n", identity.Account, strings.Join(opts.expectAccounts, " or "))
+		fmt.Fprintf(w, "  ✓ Identity %s\n", identity.ARN)
+		return fmt.Errorf("the credentials belong to an unexpected account")
+	}
+	fmt.Fprintf(w, "  ✓ Account %s\n", identity.Account)
+	fmt.Fprintf(w, "  ✓ Identity %s\n", identity.ARN)
+
+	if probeRegion == "" {
+		switch {
+		case len(opts.regions) > 0:
+			probeRegion = opts.regions[0]
+		case clientManager.DefaultRegion() != "":
+			probeRegion = clientManager.DefaultRegion()
+		default:
+			probeRegion = "us-east-1"
+		}
+	}
+
+	orch := orchestrator.NewOrchestratorWithSettings(clientManager, orchestrator.Settings{
+		QuotaThreshold:    opts.quotaThreshold,
+		CloudControlTypes: opts.cloudControl,
+		UsageMetrics:      opts.usageMetrics,
+		Rightsizing:       opts.rightsizing,
+		SkipTags:          opts.skipTags,
+		SkipBucketDetails: opts.skipBucketInfo,
+	})
+
+	results, regions, err := orch.Probe(ctx, orchestrator.CollectOptions{
+		Services: opts.services,
+		Regions:  opts.regions,
+		Parallel: opts.parallel,
+		Timeout:  opts.timeout,
+
+		IncludeOptInRegions: opts.includeOptIn,
+		ExcludeRegions:      opts.excludeRegions,
+	}, probeRegion)
+	if err != nil {
+		fmt.Fprintf(w, "\nRegions\n  ✗ %v\n", err)
+		return fmt.Errorf("regions could not be listed")
+	}
+
+	fmt.Fprintln(w, "\nRegions")
+	fmt.Fprintf(w, "  ✓ %d regions to scan; probing regional services in %s\n", len(regions), probeRegion)
+
+	fmt.Fprintln(w, "\nCollectors")
+	fmt.Fprintf(w, "  %-14s %-8s %10s %8s  %s\n", "SERVICE", "STATUS", "RESOURCES", "TIME", "DETAIL")
+	for _, result := range results {
+		status, detail := "ok", ""
+		switch {
+		case result.AccessDenied:
+			status = "denied"
+			detail = "missing " + strings.Join(result.MissingActions, ", ")
+			problems++
+		case result.Error != nil:
+			status = "error"
+			detail = firstLine(result.Error.Error())
+			problems++
+		case len(result.Warnings) > 0:
+			detail = fmt.Sprintf("%d warnings, e.g. %s", len(result.Warnings), firstLine(result.Warnings[0]))
+		}
+		fmt.Fprintf(w, "  %-14s %-8s %10d %8s  %s\n",
+			result.Service, status, result.Resources, result.Duration.Round(10*time.Millisecond), detail)
+	}
+
+	writeEstimate(w, results, opts.parallel)
+
+	if problems > 0 {
+		return fmt.Errorf("%d of %d collectors failed their probe", problems, len(results))
+	}
+	fmt.Fprintln(w, "\nAll checks passed.")
+	return nil
+}
+
+// writeEstimate extrapolates the probe to all regions: resources, collector calls and
+// a rough duration at the configured parallelism
+func writeEstimate(w io.Writer, results []orchestrator.ProbeResult, parallel int) {
+	resources, calls := 0, 0
+	var work time.Duration
+	for _, result := range results {
+		calls += result.Regions
+		if result.Error != nil {
+			continue
+		}
+		resources += result.Resources * result.Regions
+		work += result.Duration * time.Duration(result.Regions)
+	}
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	fmt.Fprintln(w, "\nEstimate (extrapolated from the probe region)")
+	fmt.Fprintf(w, "  ~%d resources from %d service/region collections\n", resources, calls)
+	fmt.Fprintf(w, "  ~%s at --parallel %d\n", (work / time.Duration(parallel)).Round(time.Second), parallel)
+}
+
+// firstLine returns the first line of a message
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(message, "\n")
+	return line
+}
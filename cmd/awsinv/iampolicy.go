@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv/pkg/orchestrator"
+)
+
+// iamPolicy is an IAM policy document
+type iamPolicy struct {
+	Version   string               `json:"Version"`
+	Statement []iamPolicyStatement `json:"Statement"`
+}
+
+// iamPolicyStatement is one statement of an IAM policy document
+type iamPolicyStatement struct {
+	Sid      string      `json:"Sid"`
+	Effect   string      `json:"Effect"`
+	Action   []string    `json:"Action"`
+	Resource interface{} `json:"Resource"`
+}
+
+// newIAMPolicyCommand creates the iam-policy command, which prints the read-only IAM
+// policy the selected collectors need
+func newIAMPolicyCommand() *cobra.Command {
+	var services []string
+	var snapshotStore string
+	var usageMetrics, rightsizing, skipTags, skipBucketDetails bool
+
+	cmd := &cobra.Command{
+		Use:   "iam-policy",
+		Short: "Print the least-privilege IAM policy for the selected services",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			orch := orchestrator.NewOrchestratorWithSettings(nil, orchestrator.Settings{
+				UsageMetrics:      usageMetrics,
+				Rightsizing:       rightsizing,
+				SkipTags:          skipTags,
+				SkipBucketDetails: skipBucketDetails,
+			})
+			actions, err := orch.RequiredPermissions(services)
+			if err != nil {
+				return err
+			}
+
+			policy := iamPolicy{
+				Version: "2012-10-17",
+				Statement: []iamPolicyStatement{{
+					Sid:      "AwsinvReadOnly",
+					Effect:   "Allow",
+					Action:   actions,
+					Resource: "*",
+				}},
+			}
+
+			if snapshotStore != "" {
+				statements, err := snapshotStoreStatements(snapshotStore)
+				if err != nil {
+					return err
+				}
+				policy.Statement = append(policy.Statement, statements...)
+			}
+
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(policy)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&services, "services", nil, "Comma-separated list of services (default all)")
+	flags.StringVar(&snapshotStore, "snapshot-store", "", "Also allow storing snapshots under s3://bucket/prefix")
+	flags.BoolVar(&usageMetrics, "usage-metrics", false, "Also allow reading the CloudWatch metrics --usage-metrics uses")
+	flags.BoolVar(&rightsizing, "rightsizing", false, "Also allow reading the CloudWatch metrics --rightsizing uses")
+	flags.BoolVar(&skipTags, "skip-tags", false, "Leave out the permissions to look up tags, for scans with --skip-tags")
+	flags.BoolVar(&skipBucketDetails, "skip-bucket-details", false, "Leave out the permissions to look up S3 bucket settings, for scans with --skip-bucket-details")
+
+	return cmd
+}
+
+// snapshotStoreStatements returns the statements an S3 snapshot store needs
+func snapshotStoreStatements(location string) ([]iamPolicyStatement, error) {
+	rest, ok := strings.CutPrefix(location, "s3://")
+	if !ok {
+		// A local directory needs no permissions
+		return nil, nil
+	}
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("invalid snapshot store %q (expected s3://bucket/prefix)", location)
+	}
+	prefix = strings.Trim(prefix, "/")
+
+	objects := "arn:aws:s3:::" + path.Join(bucket, prefix, "*")
+	return []iamPolicyStatement{
+		{
+			Sid:      "AwsinvSnapshotBucket",
+			Effect:   "Allow",
+			Action:   []string{"s3:GetBucketLocation", "s3:ListBucket"},
+			Resource: "arn:aws:s3:::" + bucket,
+		},
+		{
+			Sid:      "AwsinvSnapshotObjects",
+			Effect:   "Allow",
+			Action:   []string{"s3:GetObject", "s3:PutObject"},
+			Resource: objects,
+		},
+	}, nil
+}
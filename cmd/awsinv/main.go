@@ -0,0 +1,763 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/cache"
+	"github.com/xiaochen/awsinv/pkg/collectors"
+	"github.com/xiaochen/awsinv/pkg/diff"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/orchestrator"
+	"github.com/xiaochen/awsinv/pkg/output"
+	"github.com/xiaochen/awsinv/pkg/pricing"
+	"github.com/xiaochen/awsinv/pkg/snapshot"
+)
+
+// Build information, set via -ldflags at build time
+var (
+	Version   = "dev"
+	CommitSHA = "unknown"
+	BuildDate = "unknown"
+)
+
+// cloudControlTypePattern matches CloudFormation resource type names such as AWS::SQS::Queue
+var cloudControlTypePattern = regexp.MustCompile(`^[A-Za-z0-9]+::[A-Za-z0-9]+::[A-Za-z0-9]+$`)
+
+// options holds the command line options for an inventory run
+type options struct {
+	services       []string
+	regions        []string
+	excludeRegions []string
+	includeOptIn   bool
+	output         string
+	out            string
+	compress       string
+	parallel       int
+	timeout        time.Duration
+	serviceTimeout time.Duration
+	rateLimits     []string
+	apiBudget      float64
+	cacheTTL       time.Duration
+	noCache        bool
+	resume         bool
+	refreshPricing bool
+	pricingBundle  string
+	usageMetrics   bool
+	rightsizing    bool
+	skipTags       bool
+	skipBucketInfo bool
+	trackProgress  bool
+	failFast       bool
+	verbose        bool
+	noColor        bool
+	profile        string
+	profiles       []string
+	expectAccounts []string
+	roleARN        string
+	externalID     string
+	sessionName    string
+	roleDuration   time.Duration
+	mfaSerial      string
+	mfaToken       string
+	mfaCommand     string
+	tokenFile      string
+	sortField      string
+	filters        []string
+	query          string
+	excludes       []string
+	quotaThreshold float64
+	cloudControl   []string
+	htmlTheme      string
+	htmlTitle      string
+	htmlLogo       string
+	costPeriod     string
+	requiredTags   []string
+	snapshotStore  string
+	compareTo      string
+	interval       time.Duration
+	webhook        string
+	configPath     string
+	preset         string
+	failOnErrors   bool
+	failIfCostOver float64
+	failIfCount    []string
+	failOnBudget   bool
+	budgets        []models.Budget
+
+	// costEngine estimates costs, once usePrices has chosen where prices come from
+	costEngine *pricing.Engine
+}
+
+func main() {
+	if err := newRootCommand().ExecuteContext(context.Background()); err != nil {
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
+		os.Exit(1)
+	}
+}
+
+// newRootCommand creates the awsinv command. Without a subcommand it runs a scan.
+func newRootCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "awsinv",
+		Short:        "Inventory AWS resources across services and regions",
+		Version:      fmt.Sprintf("%s (commit %s, built %s)", Version, CommitSHA, BuildDate),
+		SilenceUsage: true,
+	}
+	configureScanCommand(cmd)
+
+	cmd.AddCommand(newScanCommand())
+	cmd.AddCommand(newDiffCommand())
+	cmd.AddCommand(newWatchCommand())
+	cmd.AddCommand(newServeCommand())
+	cmd.AddCommand(newTUICommand())
+	cmd.AddCommand(newQueryCommand())
+	cmd.AddCommand(newDescribeCommand())
+	cmd.AddCommand(newIAMPolicyCommand())
+	cmd.AddCommand(newDoctorCommand())
+	cmd.AddCommand(newPricingCommand())
+
+	registerCompletions(cmd)
+
+	return cmd
+}
+
+// newScanCommand creates the scan command, the same as running awsinv without a subcommand
+func newScanCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Collect the inventory and write a report",
+		Args:  cobra.NoArgs,
+	}
+	configureScanCommand(cmd)
+	return cmd
+}
+
+// configureScanCommand adds the flags and action of an inventory run to cmd
+func configureScanCommand(cmd *cobra.Command) {
+	// Scans cache their progress so an interrupted scan can be resumed
+	opts := &options{trackProgress: true}
+
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := inferOutputOptions(cmd, opts); err != nil {
+			return err
+		}
+		return runInventory(cmd.Context(), opts)
+	}
+
+	addCollectionFlags(cmd, opts)
+	addOutputFlags(cmd, opts)
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.profiles, "profiles", nil, "Comma-separated profiles to scan one after another, merging the results labeled by account (overrides --profile)")
+	flags.StringVar(&opts.snapshotStore, "snapshot-store", "", "Store each run's inventory in a directory or s3://bucket/prefix")
+	flags.DurationVar(&opts.cacheTTL, "cache-ttl", 0, "Reuse resources collected within this long, e.g. 1h, from the cache in ~/.cache/awsinv (default no cache)")
+	flags.BoolVar(&opts.noCache, "no-cache", false, "Collect everything again, ignoring a configured --cache-ttl")
+	flags.BoolVar(&opts.resume, "resume", false, "Continue the last scan if it did not finish, collecting only what it did not")
+	flags.StringVar(&opts.compareTo, "compare-to", "", "Report resources new or disappeared since a stored snapshot (latest|YYYY-MM-DD)")
+	flags.BoolVar(&opts.failOnErrors, "fail-on-errors", false, "Exit with code 2 if any collector failed")
+	flags.Float64Var(&opts.failIfCostOver, "fail-if-cost-over", 0, "Exit with code 2 if the estimated monthly cost of the reported resources exceeds this amount")
+	flags.StringArrayVar(&opts.failIfCount, "fail-if-count", nil, "Exit with code 2 if the count of matching resources breaches a limit, e.g. service=ec2,state=running,gt=100 (repeatable)")
+	flags.BoolVar(&opts.failOnBudget, "fail-on-budget", false, "Exit with code 2 if the reported resources of a budget in the config file cost more than its limit")
+}
+
+// addCollectionFlags adds the flags that control what is collected and how, shared by
+// the commands that scan the account
+func addCollectionFlags(cmd *cobra.Command, opts *options) {
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.services, "services", nil, "Comma-separated list of services (default all)")
+	flags.StringSliceVar(&opts.regions, "regions", nil, "Comma-separated list of regions (default all enabled)")
+	flags.StringSliceVar(&opts.excludeRegions, "exclude-regions", nil, "Comma-separated regions to leave out, by name or pattern such as ap-*")
+	flags.BoolVar(&opts.includeOptIn, "include-opt-in-regions", false, "Also scan regions the account has not opted in to; unavailable services are recorded as skipped")
+	flags.IntVar(&opts.parallel, "parallel", 12, "Number of parallel collectors")
+	flags.DurationVar(&opts.timeout, "timeout", 5*time.Minute, "Overall deadline; services not collected by then are reported as timed out")
+	flags.DurationVar(&opts.serviceTimeout, "service-timeout", 0, "Timeout for collecting one service in one region (default none)")
+	flags.StringSliceVar(&opts.rateLimits, "rate-limit", nil, "API requests per second and region, e.g. ec2=50,default=20 (0 for no limit)")
+	flags.Float64Var(&opts.apiBudget, "api-budget", 0, "Total API requests per second across all APIs and regions (default no limit)")
+	flags.BoolVar(&opts.failFast, "fail-fast", false, "Abort on first collector error")
+	flags.BoolVar(&opts.verbose, "verbose", false, "Log progress to stderr")
+	flags.Float64Var(&opts.quotaThreshold, "quota-threshold", collectors.DefaultQuotaThreshold, "Utilization percentage at which service quotas are flagged")
+	flags.BoolVar(&opts.skipTags, "skip-tags", false, "Skip the API calls that look up tags, for faster scans; resources whose APIs return their tags keep them")
+	flags.BoolVar(&opts.skipBucketInfo, "skip-bucket-details", false, "Skip looking up the versioning, encryption, public access block, lifecycle and logging settings of S3 buckets")
+	addPricingFlags(cmd, opts)
+	flags.StringSliceVar(&opts.cloudControl, "cloudcontrol-types", nil, "Comma-separated resource type names to inventory via the Cloud Control API (e.g. AWS::SQS::Queue)")
+	flags.StringVar(&opts.configPath, "config", "", "Config file (default $AWSINV_CONFIG, ./.awsinv.yaml or ~/.config/awsinv/config.yaml)")
+	flags.StringVar(&opts.preset, "preset", "", "Apply a named preset from the config file")
+
+	addCredentialFlags(cmd, opts)
+	flags.StringSliceVar(&opts.expectAccounts, "expect-account", nil, "Comma-separated account IDs the credentials must belong to; other accounts are not scanned")
+	addFilterFlags(cmd, opts)
+
+	// The config file fills in every option not given on the command line
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return applyConfig(cmd, opts)
+	}
+}
+
+// addCredentialFlags adds the flags that choose the AWS credentials
+func addCredentialFlags(cmd *cobra.Command, opts *options) {
+	flags := cmd.Flags()
+	flags.StringVar(&opts.profile, "profile", "", "AWS shared credentials profile")
+	flags.StringVar(&opts.roleARN, "role-arn", "", "ARN of role to assume")
+	flags.StringVar(&opts.externalID, "external-id", "", "External ID for role assumption")
+	flags.StringVar(&opts.sessionName, "role-session-name", "awsinv", "Session name for role assumption, as shown in CloudTrail")
+	flags.DurationVar(&opts.roleDuration, "role-duration", time.Hour, "How long assumed role credentials last, from 15m up to the role's maximum")
+	flags.StringVar(&opts.mfaSerial, "mfa-serial", "", "ARN of the MFA device role assumption requires")
+	flags.StringVar(&opts.mfaToken, "mfa-token", "", "MFA code for --mfa-serial or the profile's mfa_serial (default prompt at the terminal)")
+	flags.StringVar(&opts.mfaCommand, "mfa-token-command", "", "Shell command printing the MFA code, e.g. from a password manager")
+	flags.StringVar(&opts.tokenFile, "web-identity-token-file", "", "OIDC token file to assume --role-arn with, such as the service account token of a Kubernetes pod")
+}
+
+// addPricingFlags adds the flags that control how costs are estimated
+func addPricingFlags(cmd *cobra.Command, opts *options) {
+	flags := cmd.Flags()
+	flags.BoolVar(&opts.refreshPricing, "refresh-pricing", false, "Fetch prices from the Pricing API again instead of using the prices cached in the last 24 hours")
+	flags.StringVar(&opts.pricingBundle, "pricing-bundle", "", "Estimate costs offline from a bundle written by 'awsinv pricing download'")
+	flags.BoolVar(&opts.usageMetrics, "usage-metrics", false, "Estimate Lambda, DynamoDB and NAT gateway costs from the last 30 days of CloudWatch metrics")
+	flags.BoolVar(&opts.rightsizing, "rightsizing", false, "Recommend smaller EC2 and RDS instance sizes from the last 30 days of CloudWatch CPU and memory utilization")
+}
+
+// addFilterFlags adds the --filter and --exclude flags
+func addFilterFlags(cmd *cobra.Command, opts *options) {
+	flags := cmd.Flags()
+	flags.StringArrayVar(&opts.filters, "filter", nil, "Filter expression, e.g. 'service=ec2 AND (cost>100 OR NOT has(tag:Owner))' (repeatable)")
+	flags.StringArrayVar(&opts.excludes, "exclude", nil, "Exclude resources matching a filter expression, e.g. state=terminated (repeatable)")
+}
+
+// addOutputFlags adds the flags that choose the report format and where it is written
+func addOutputFlags(cmd *cobra.Command, opts *options) {
+	flags := cmd.Flags()
+	// Formats registered by tools built on awsinv are listed too
+	flags.StringVar(&opts.output, "output", "table", "Output format ("+strings.Join(output.Formats(), "|")+")")
+	flags.StringVarP(&opts.out, "out", "o", "", "Write output to FILE, replacing it atomically (format inferred from the extension)")
+	flags.StringVar(&opts.compress, "compress", "", "Compress the output (gzip|zstd); inferred from a .gz or .zst --out file")
+	flags.BoolVar(&opts.noColor, "no-color", false, "Disable ANSI color in table")
+	flags.StringVar(&opts.sortField, "sort", "service", "Comma-separated sort fields, - for descending (e.g. region,-cost,name)")
+	flags.StringVar(&opts.query, "query", "", "JMESPath query applied to the JSON output, e.g. 'resources[].id'")
+	flags.StringVar(&opts.costPeriod, "cost-period", string(output.CostPeriodMonthly), "Period costs are reported for (hourly|daily|monthly|annual)")
+	flags.StringSliceVar(&opts.requiredTags, "required-tags", nil, "Tag keys every resource should have; adds a tag compliance report (e.g. Owner,Environment)")
+	flags.StringVar(&opts.htmlTheme, "html-theme", output.HTMLThemeLight, "Initial HTML report theme (light|dark|auto)")
+	flags.StringVar(&opts.htmlTitle, "html-title", "", "HTML report title")
+	flags.StringVar(&opts.htmlLogo, "html-logo", "", "HTML report logo (image URL or local file to embed)")
+}
+
+// inferOutputOptions fills in the format and compression from the --out file name
+// unless they were set explicitly
+func inferOutputOptions(cmd *cobra.Command, opts *options) error {
+	if opts.out == "" {
+		return nil
+	}
+
+	compression, name := compressionFromExtension(opts.out)
+	if !cmd.Flags().Changed("compress") {
+		opts.compress = compression
+	}
+	if !cmd.Flags().Changed("output") {
+		format, err := formatFromExtension(name)
+		if err != nil {
+			return err
+		}
+		opts.output = format
+	}
+	return nil
+}
+
+// parseFilterOptions parses the --filter and --exclude expressions
+func parseFilterOptions(opts *options) ([]output.Filter, error) {
+	filters, err := output.ParseFilters(opts.filters)
+	if err != nil {
+		return nil, err
+	}
+
+	excludes, err := output.ParseExcludes(opts.excludes)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(filters, excludes...), nil
+}
+
+// validateCollectionOptions checks the collection flags before any AWS call is made
+func validateCollectionOptions(opts *options) error {
+	if opts.parallel < 1 {
+		return fmt.Errorf("invalid parallelism: %d (expected at least 1)", opts.parallel)
+	}
+
+	if opts.quotaThreshold <= 0 || opts.quotaThreshold > 100 {
+		return fmt.Errorf("invalid quota threshold: %g (expected 0-100)", opts.quotaThreshold)
+	}
+
+	for _, pattern := range opts.excludeRegions {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid region pattern: %s", pattern)
+		}
+	}
+
+	for _, typeName := range opts.cloudControl {
+		if !cloudControlTypePattern.MatchString(typeName) {
+			return fmt.Errorf("invalid Cloud Control resource type: %s (expected Provider::Service::Resource)", typeName)
+		}
+	}
+
+	return nil
+}
+
+// parseRateLimits parses --rate-limit entries such as ec2=50
+func parseRateLimits(specs []string) (map[string]float64, error) {
+	limits := make(map[string]float64, len(specs))
+	for _, spec := range specs {
+		api, value, ok := strings.Cut(spec, "=")
+		rate, err := strconv.ParseFloat(value, 64)
+		if !ok || api == "" || err != nil || rate < 0 {
+			return nil, fmt.Errorf("invalid rate limit: %s (expected api=requests-per-second)", spec)
+		}
+		limits[api] = rate
+	}
+	return limits, nil
+}
+
+// newClientManager creates the AWS client manager for the credential flags
+func newClientManager(opts *options) (*awspkg.ClientManager, error) {
+	rateLimits, err := parseRateLimits(opts.rateLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	var budget *awspkg.APIBudget
+	if opts.apiBudget > 0 {
+		budget = awspkg.NewAPIBudget(opts.apiBudget)
+	}
+
+	clientManager, err := awspkg.NewClientManager(awspkg.Config{
+		Profile:              opts.profile,
+		RoleARN:              opts.roleARN,
+		ExternalID:           opts.externalID,
+		RoleSessionName:      opts.sessionName,
+		RoleDuration:         opts.roleDuration,
+		MFASerial:            opts.mfaSerial,
+		MFAToken:             opts.mfaToken,
+		MFATokenCommand:      opts.mfaCommand,
+		WebIdentityTokenFile: opts.tokenFile,
+		RateLimits:           rateLimits,
+		Budget:               budget,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.verbose {
+		orchestrator.SetStderr(os.Stderr)
+		output.SetStderr(os.Stderr)
+	}
+
+	return clientManager, nil
+}
+
+// collect runs the collectors selected by the options
+func collect(ctx context.Context, clientManager *awspkg.ClientManager, opts *options) (*models.ResourceCollection, error) {
+	inventoryOpts := []awsinv.Option{
+		awsinv.WithClientManager(clientManager),
+		awsinv.WithServices(opts.services...),
+		awsinv.WithRegions(opts.regions...),
+		awsinv.WithExcludeRegions(opts.excludeRegions...),
+		awsinv.WithParallel(opts.parallel),
+		awsinv.WithTimeout(opts.timeout),
+		awsinv.WithServiceTimeout(opts.serviceTimeout),
+		awsinv.WithQuotaThreshold(opts.quotaThreshold),
+		awsinv.WithCloudControlTypes(opts.cloudControl...),
+	}
+
+	if opts.trackProgress || opts.cacheTTL > 0 {
+		dir, err := cache.DefaultDir()
+		switch {
+		case err == nil:
+			cacheTTL := opts.cacheTTL
+			if opts.noCache {
+				cacheTTL = 0
+			}
+			inventoryOpts = append(inventoryOpts, awsinv.WithCache(cache.New(dir), cacheTTL))
+		case opts.cacheTTL > 0 || opts.resume:
+			return nil, err
+		default:
+			// Progress is only recorded to resume the scan, which is no reason to fail it
+			fmt.Fprintf(os.Stderr, "Warning: scan progress not recorded: %v\n", err)
+		}
+	}
+
+	for _, flag := range []struct {
+		set    bool
+		option awsinv.Option
+	}{
+		{opts.includeOptIn, awsinv.WithOptInRegions()},
+		{opts.failFast, awsinv.WithFailFast()},
+		{opts.resume, awsinv.WithResume()},
+		{opts.usageMetrics, awsinv.WithUsageMetrics()},
+		{opts.rightsizing, awsinv.WithRightsizing()},
+		{opts.skipTags, awsinv.WithoutTags()},
+		{opts.skipBucketInfo, awsinv.WithoutBucketDetails()},
+		{opts.verbose, awsinv.WithLogger(os.Stderr)},
+		{opts.costEngine != nil, awsinv.WithCostEngine(opts.costEngine)},
+	} {
+		if flag.set {
+			inventoryOpts = append(inventoryOpts, flag.option)
+		}
+	}
+
+	inventory, err := awsinv.New(inventoryOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return inventory.Collect(ctx)
+}
+
+// errUnexpectedAccount is returned when the credentials belong to an account that
+// --expect-account does not list
+var errUnexpectedAccount = errors.New("unexpected account")
+
+// verifyAccount shows the account and identity the credentials belong to, and fails
+// if --expect-account is given and does not list the account
+func verifyAccount(ctx context.Context, clientManager *awspkg.ClientManager, opts *options) (*awspkg.Identity, error) {
+	identity, err := clientManager.CallerIdentity(ctx)
+	if err != nil {
+		if len(opts.expectAccounts) > 0 {
+			return nil, fmt.Errorf("cannot verify the account: %w", err)
+		}
+		return nil, err
+	}
+
+	fmt.Fprintf(os.Stderr, "Account %s as %s\n", identity.Account, identity.ARN)
+	if len(opts.expectAccounts) > 0 && !slices.Contains(opts.expectAccounts, identity.Account) {
+		return nil, fmt.Errorf("%w: the credentials belong to account %s, not %s", errUnexpectedAccount, identity.Account, strings.Join(opts.expectAccounts, " or "))
+	}
+	return identity, nil
+}
+
+// showAccount runs verifyAccount for the commands scanning one account. Failing to
+// look the identity up only stops the scan when the account is to be verified.
+func showAccount(ctx context.Context, clientManager *awspkg.ClientManager, opts *options) error {
+	_, err := verifyAccount(ctx, clientManager, opts)
+	if err != nil && len(opts.expectAccounts) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return nil
+	}
+	return err
+}
+
+// collectProfiles collects the inventory of each account of --profiles and merges
+// them, or with clientManager alone without. Profiles of an account already scanned
+// are skipped, and one whose credentials fail is reported as an error of the scan.
+func collectProfiles(ctx context.Context, clientManager *awspkg.ClientManager, opts *options) (*models.ResourceCollection, error) {
+	if len(opts.profiles) == 0 {
+		return collect(ctx, clientManager, opts)
+	}
+
+	merged := &models.ResourceCollection{}
+	scanned := make(map[string]string, len(opts.profiles))
+	for i, profile := range opts.profiles {
+		if ctx.Err() != nil {
+			break
+		}
+
+		profileOpts := *opts
+		profileOpts.profile = profile
+		err := func() error {
+			manager := clientManager
+			if i > 0 {
+				var err error
+				if manager, err = newClientManager(&profileOpts); err != nil {
+					return err
+				}
+			}
+
+			identity, err := verifyAccount(ctx, manager, &profileOpts)
+			if err != nil {
+				return err
+			}
+			if first, exists := scanned[identity.Account]; exists {
+				fmt.Fprintf(os.Stderr, "Warning: profile %s is account %s, already scanned with profile %s; skipping it\n", profile, identity.Account, first)
+				return nil
+			}
+			scanned[identity.Account] = profile
+
+			if opts.verbose {
+				fmt.Fprintf(os.Stderr, "Scanning profile %s (account %s)\n", profile, identity.Account)
+			}
+			collection, err := collect(ctx, manager, &profileOpts)
+			if err != nil {
+				return err
+			}
+			merged.Merge(collection)
+			return nil
+		}()
+		if err != nil {
+			if opts.failFast || errors.Is(err, errUnexpectedAccount) {
+				return nil, fmt.Errorf("profile %s: %w", profile, err)
+			}
+			merged.Merge(&models.ResourceCollection{
+				Errors: []string{fmt.Sprintf("profile %s: %v", profile, err)},
+				Summary: models.Summary{
+					Errors:        1,
+					ErrorsByClass: map[string]int{models.ErrorClass(awspkg.ClassifyError(err)): 1},
+				},
+			})
+		}
+	}
+
+	if len(scanned) == 0 {
+		return nil, fmt.Errorf("no profile could be scanned: %s", strings.Join(merged.Errors, "; "))
+	}
+	return merged, nil
+}
+
+// runInventory collects the inventory and writes it in the requested format
+func runInventory(ctx context.Context, opts *options) error {
+	target, err := openOutput(opts)
+	if err != nil {
+		return err
+	}
+	defer target.Abort()
+
+	filters, err := parseFilterOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	counts, err := parseThresholdOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := validateCollectionOptions(opts); err != nil {
+		return err
+	}
+
+	if opts.compareTo != "" && opts.snapshotStore == "" {
+		return fmt.Errorf("--compare-to needs --snapshot-store")
+	}
+	if opts.resume && opts.noCache {
+		return fmt.Errorf("--resume reuses cached resources, so it cannot be combined with --no-cache")
+	}
+
+	// The first of several profiles also stores the snapshots
+	if len(opts.profiles) > 0 {
+		opts.profile = opts.profiles[0]
+	}
+	clientManager, err := newClientManager(opts)
+	if err != nil {
+		return err
+	}
+
+	// Each of several profiles is verified as it is scanned
+	if len(opts.profiles) == 0 {
+		if err := showAccount(ctx, clientManager, opts); err != nil {
+			return err
+		}
+	}
+
+	var store snapshot.Store
+	var previous *diff.Snapshot
+	var previousEntry snapshot.Entry
+	if opts.snapshotStore != "" {
+		store, err = snapshot.NewStore(ctx, clientManager, opts.snapshotStore)
+		if err != nil {
+			return err
+		}
+
+		// Load the snapshot to compare with before this run stores its own
+		if opts.compareTo != "" {
+			previousEntry, err = snapshot.Find(ctx, store, opts.compareTo)
+			switch {
+			case errors.Is(err, snapshot.ErrNotFound) && opts.compareTo == "latest":
+				// The first run has nothing to compare with
+				fmt.Fprintf(os.Stderr, "Warning: %v; skipping comparison\n", err)
+			case err != nil:
+				return err
+			default:
+				previous, err = snapshot.Load(ctx, store, previousEntry)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Cost estimates fall back to built-in prices when the pricing API is unavailable
+	if err := usePrices(ctx, opts); err != nil {
+		return err
+	}
+
+	// Ctrl+C stops the collection early and the report is written from what was
+	// collected. Once collection ends, a second Ctrl+C exits immediately.
+	collectCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	collection, err := collectProfiles(collectCtx, clientManager, opts)
+	interrupted := collectCtx.Err() != nil
+	stop()
+	if err != nil {
+		return err
+	}
+	if interrupted {
+		fmt.Fprintln(os.Stderr, "Interrupted; writing a partial inventory")
+	}
+	switch {
+	case collection.Summary.Cached > 0 && opts.resume:
+		fmt.Fprintf(os.Stderr, "Resumed: reused the resources of %d service/region pairs collected earlier\n", collection.Summary.Cached)
+	case collection.Summary.Cached > 0:
+		fmt.Fprintf(os.Stderr, "Using cached resources for %d service/region pairs (--no-cache to collect them again)\n", collection.Summary.Cached)
+	}
+	if failed := collection.Summary.ErrorsByClass[models.ErrorClass(models.ErrCredentials)]; failed > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d service/region pairs failed as the credentials were rejected, or expired and could not be renewed; "+
+			"for SSO profiles, run \"aws sso login\" and rerun with --resume\n", failed)
+	}
+
+	collection.Budgets = opts.budgets
+
+	if store != nil {
+		if previous != nil {
+			collection.Comparison = snapshot.Compare(collection, previous, previousEntry)
+		}
+
+		// A partial snapshot would show the resources never reached as disappeared
+		// in the next comparison
+		if interrupted {
+			fmt.Fprintln(os.Stderr, "Warning: not storing the snapshot of an interrupted run")
+		} else if _, err := snapshot.Save(ctx, store, collection, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if err := target.Write(collection, filters, opts); err != nil {
+		return err
+	}
+
+	if interrupted {
+		return &exitError{code: exitInterrupted, message: "interrupted; the inventory is partial"}
+	}
+	return checkThresholds(collection, filters, opts, counts)
+}
+
+// outputTarget writes a report to stdout or, with --out, atomically to a file
+type outputTarget struct {
+	compressor io.WriteCloser
+	file       *atomicFile
+}
+
+// openOutput checks the format options and creates the destination of the output,
+// so invalid options fail before any collection work
+func openOutput(opts *options) (*outputTarget, error) {
+	target := &outputTarget{}
+
+	var writer io.Writer = os.Stdout
+	if opts.out != "" {
+		file, err := createAtomicFile(opts.out)
+		if err != nil {
+			return nil, err
+		}
+		writer, target.file = file, file
+	}
+
+	compressor, err := newCompressWriter(writer, opts.compress)
+	if err != nil {
+		target.Abort()
+		return nil, err
+	}
+	target.compressor = compressor
+
+	if _, err := newFormatter(opts, io.Discard); err != nil {
+		target.Abort()
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// Write formats the collection and, for a file, replaces it with the result
+func (t *outputTarget) Write(collection *models.ResourceCollection, filters []output.Filter, opts *options) error {
+	// The formatter is created now, with the cost engine usePrices chose after the
+	// options were checked
+	formatter, err := newFormatter(opts, t.compressor)
+	if err != nil {
+		return err
+	}
+	if err := formatter.Format(collection, filters, opts.sortField, opts.noColor); err != nil {
+		return err
+	}
+
+	if err := t.compressor.Close(); err != nil {
+		return fmt.Errorf("failed to compress output: %w", err)
+	}
+
+	if t.file != nil {
+		return t.file.Commit()
+	}
+	return nil
+}
+
+// Abort removes the temporary output file unless it was committed
+func (t *outputTarget) Abort() {
+	if t.file != nil {
+		t.file.Abort()
+	}
+}
+
+// newFormatter returns the formatter for the requested output format, writing to writer
+func newFormatter(opts *options, writer io.Writer) (output.Formatter, error) {
+	if opts.query != "" && opts.output != "json" {
+		return nil, fmt.Errorf("--query only applies to json output")
+	}
+
+	// Filters and thresholds stay monthly; only the report is rescaled
+	period, err := output.ParseCostPeriod(opts.costPeriod)
+	if err != nil {
+		return nil, err
+	}
+	report := output.Options{CostPeriod: period, RequiredTags: opts.requiredTags, CostEngine: opts.costEngine}
+
+	switch opts.output {
+	case "table":
+		return output.NewTableFormatterWithOptions(writer, report), nil
+	case "json":
+		return output.NewJSONFormatterWithOptions(writer, output.JSONOptions{Options: report, Query: opts.query})
+	case "csv":
+		return output.NewCSVFormatterWithOptions(writer, report), nil
+	case "html":
+		return output.NewHTMLFormatterWithOptions(writer, output.HTMLOptions{
+			Options: report,
+			Theme:   opts.htmlTheme,
+			Title:   opts.htmlTitle,
+			Logo:    opts.htmlLogo,
+		})
+	case "markdown":
+		return output.NewMarkdownFormatterWithOptions(writer, report), nil
+	case "xlsx":
+		return output.NewXLSXFormatterWithOptions(writer, report), nil
+	case "sqlite":
+		return output.NewSQLiteFormatterWithOptions(writer, report), nil
+	case "dot":
+		return output.NewDOTFormatterWithOptions(writer, report), nil
+	case "mermaid":
+		return output.NewMermaidFormatterWithOptions(writer, report), nil
+	default:
+		// Formats added with output.RegisterFormatter by tools built on awsinv
+		if factory, exists := output.LookupFormatter(opts.output); exists {
+			return factory(writer)
+		}
+		return nil, fmt.Errorf("invalid output format: %s (expected %s)", opts.output, strings.Join(output.Formats(), ", "))
+	}
+}
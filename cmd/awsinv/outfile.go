@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// outputFormatsByExtension maps output file extensions to the format written
+var outputFormatsByExtension = map[string]string{
+	".json":    "json",
+	".csv":     "csv",
+	".html":    "html",
+	".htm":     "html",
+	".md":      "markdown",
+	".xlsx":    "xlsx",
+	".db":      "sqlite",
+	".sqlite":  "sqlite",
+	".sqlite3": "sqlite",
+	".dot":     "dot",
+	".gv":      "dot",
+	".mmd":     "mermaid",
+	".txt":     "table",
+}
+
+// formatFromExtension infers the output format from a file name
+func formatFromExtension(path string) (string, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	format, exists := outputFormatsByExtension[ext]
+	if !exists {
+		return "", fmt.Errorf("cannot infer output format from %q; use --output to set it", path)
+	}
+	return format, nil
+}
+
+// atomicFile is written in a temporary file next to its destination and renamed into
+// place on Commit, so readers never see a half-written report
+type atomicFile struct {
+	*os.File
+	path      string
+	committed bool
+}
+
+// createAtomicFile creates the temporary file for path in the same directory,
+// so the final rename stays on one filesystem
+func createAtomicFile(path string) (*atomicFile, error) {
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+base+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	return &atomicFile{File: tmp, path: path}, nil
+}
+
+// Commit flushes the temporary file to disk and renames it over the destination
+func (f *atomicFile) Commit() error {
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.path, err)
+	}
+	// CreateTemp uses 0600; reports get the usual permissions of a new file
+	if err := os.Chmod(f.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.path, err)
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", f.path, err)
+	}
+	f.committed = true
+	return nil
+}
+
+// Abort removes the temporary file unless it was committed
+func (f *atomicFile) Abort() {
+	if f.committed {
+		return
+	}
+	f.Close()
+	os.Remove(f.Name())
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv/pkg/cache"
+	"github.com/xiaochen/awsinv/pkg/pricing"
+)
+
+// newPricingCommand creates the pricing command, which manages the price data cost
+// estimates use
+func newPricingCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pricing",
+		Short: "Manage the price data used for cost estimates",
+	}
+
+	cmd.AddCommand(newPricingDownloadCommand())
+
+	return cmd
+}
+
+// newPricingDownloadCommand creates the pricing download command, which snapshots
+// prices into a bundle for estimating costs offline
+func newPricingDownloadCommand() *cobra.Command {
+	opts := &options{}
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "download",
+		Short: "Download prices into a bundle for estimating costs offline with --pricing-bundle",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runPricingDownload(cmd.Context(), opts, out)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.services, "services", pricing.BundleServices, "Comma-separated list of services to download prices for")
+	flags.StringSliceVar(&opts.regions, "regions", nil, "Comma-separated list of regions (default all)")
+	flags.StringVarP(&out, "out", "o", "pricing-bundle.json", "File to write the bundle to")
+	addCredentialFlags(cmd, opts)
+
+	return cmd
+}
+
+// runPricingDownload downloads the prices of the selected services and regions
+func runPricingDownload(ctx context.Context, opts *options, out string) error {
+	clientManager, err := newClientManager(opts)
+	if err != nil {
+		return err
+	}
+
+	cfg := clientManager.GetConfig("us-east-1")
+	service, err := pricing.NewPricingService(ctx, pricing.ServiceOptions{Config: &cfg})
+	if err != nil {
+		return err
+	}
+
+	regions := opts.regions
+	if len(regions) == 0 {
+		regions = pricing.Regions()
+	}
+
+	bundle, err := service.DownloadBundle(ctx, opts.services, regions, func(service, region string, prices int) {
+		fmt.Fprintf(os.Stderr, "%s %s: %d prices\n", service, region, prices)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := bundle.Save(out); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %s\n", out)
+	return nil
+}
+
+// usePrices sets where cost estimates take prices from: the bundle given with
+// --pricing-bundle, or else live prices from the Pricing API, cached on disk for a day
+func usePrices(ctx context.Context, opts *options) error {
+	if opts.pricingBundle != "" {
+		return useOfflinePrices(opts)
+	}
+	useLivePrices(ctx, opts)
+	return nil
+}
+
+// useLivePrices makes cost estimates prefer live prices from the Pricing API, cached
+// on disk for a day
+func useLivePrices(ctx context.Context, opts *options) {
+	serviceOpts := pricing.ServiceOptions{Refresh: opts.refreshPricing}
+	if dir, err := cache.DefaultDir(); err == nil {
+		serviceOpts.CachePath = filepath.Join(dir, "pricing.json")
+	}
+
+	service, err := pricing.NewPricingService(ctx, serviceOpts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: using built-in prices: %v\n", err)
+	}
+	opts.costEngine = pricing.NewEngine(service)
+}
+
+// useOfflinePrices makes cost estimates use the prices of the --pricing-bundle
+// bundle, without calling AWS
+func useOfflinePrices(opts *options) error {
+	bundle, err := pricing.LoadBundle(opts.pricingBundle)
+	if err != nil {
+		return err
+	}
+	opts.costEngine = pricing.NewOfflineEngine(bundle)
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv/pkg/diff"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// newQueryCommand creates the query command, which runs the filter, sort and output
+// pipeline over a saved JSON inventory instead of scanning
+func newQueryCommand() *cobra.Command {
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:   "query FILE",
+		Short: "Filter, sort and format a saved JSON inventory without calling AWS",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := inferOutputOptions(cmd, opts); err != nil {
+				return err
+			}
+			return runQuery(args[0], opts)
+		},
+	}
+
+	addFilterFlags(cmd, opts)
+	addOutputFlags(cmd, opts)
+	cmd.Flags().StringVar(&opts.pricingBundle, "pricing-bundle", "", "Estimate costs from a bundle written by 'awsinv pricing download'")
+
+	return cmd
+}
+
+// runQuery formats a saved inventory. Cost estimates are recomputed with the
+// built-in prices or a pricing bundle, since the pricing API is an AWS call.
+func runQuery(path string, opts *options) error {
+	target, err := openOutput(opts)
+	if err != nil {
+		return err
+	}
+	defer target.Abort()
+
+	filters, err := parseFilterOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.pricingBundle != "" {
+		if err := useOfflinePrices(opts); err != nil {
+			return err
+		}
+	}
+
+	collection, err := loadInventory(path)
+	if err != nil {
+		return err
+	}
+
+	return target.Write(collection, filters, opts)
+}
+
+// loadInventory reads a JSON inventory or snapshot, optionally gzip or zstd compressed
+func loadInventory(path string) (*models.ResourceCollection, error) {
+	snapshot, err := diff.LoadSnapshot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := &models.ResourceCollection{
+		Resources: make([]models.Resource, 0, len(snapshot.Resources)),
+		Errors:    snapshot.Errors,
+		Warnings:  snapshot.Warnings,
+		Skipped:   snapshot.Skipped,
+		Summary:   snapshot.Summary,
+	}
+	for _, resource := range snapshot.Resources {
+		collection.Resources = append(collection.Resources, resource.Resource)
+	}
+	return collection, nil
+}
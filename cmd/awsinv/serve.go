@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/output"
+	"github.com/xiaochen/awsinv/pkg/server"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take once the server stops
+const shutdownTimeout = 10 * time.Second
+
+// newServeCommand creates the serve command, which hosts the HTML report, a JSON API
+// and Prometheus metrics from one process
+func newServeCommand() *cobra.Command {
+	opts := &options{}
+	var listen string
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the inventory as an HTML report, JSON API and Prometheus metrics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runServe(cmd.Context(), opts, listen)
+		},
+	}
+
+	addCollectionFlags(cmd, opts)
+
+	flags := cmd.Flags()
+	flags.StringVar(&listen, "listen", ":8080", "Address to listen on")
+	flags.DurationVar(&opts.interval, "interval", 0, "Time between scans (default 0, scan only on startup and POST /api/scan)")
+	flags.StringVar(&opts.htmlTheme, "html-theme", output.HTMLThemeLight, "Initial HTML report theme (light|dark|auto)")
+	flags.StringVar(&opts.htmlTitle, "html-title", "", "HTML report title")
+	flags.StringVar(&opts.htmlLogo, "html-logo", "", "HTML report logo (image URL or local file to embed)")
+
+	return cmd
+}
+
+// runServe scans in the background and serves the latest inventory until interrupted
+func runServe(ctx context.Context, opts *options, listen string) error {
+	if opts.interval < 0 {
+		return fmt.Errorf("invalid interval: %s", opts.interval)
+	}
+
+	filters, err := parseFilterOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := validateCollectionOptions(opts); err != nil {
+		return err
+	}
+
+	htmlOptions := output.HTMLOptions{
+		Theme: opts.htmlTheme,
+		Title: opts.htmlTitle,
+		Logo:  opts.htmlLogo,
+	}
+	// Check the theme and logo now rather than on the first request
+	if _, err := output.NewHTMLFormatterWithOptions(io.Discard, htmlOptions); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	clientManager, err := newClientManager(opts)
+	if err != nil {
+		return err
+	}
+	if err := showAccount(ctx, clientManager, opts); err != nil {
+		return err
+	}
+
+	// Cost estimates fall back to built-in prices when the pricing API is unavailable
+	if err := usePrices(ctx, opts); err != nil {
+		return err
+	}
+	htmlOptions.CostEngine = opts.costEngine
+
+	srv := server.New(func(ctx context.Context) (*models.ResourceCollection, error) {
+		fmt.Fprintf(os.Stderr, "%s: scanning\n", time.Now().Format(time.RFC3339))
+		collection, err := collect(ctx, clientManager, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: scan failed: %v\n", err)
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "%s: found %d resources (%d errors)\n",
+			time.Now().Format(time.RFC3339), len(collection.Resources), len(collection.Errors))
+		return collection, nil
+	}, server.Options{
+		Interval: opts.interval,
+		Filters:  filters,
+		HTML:     htmlOptions,
+	})
+
+	httpServer := &http.Server{
+		Addr:              listen,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go srv.Run(ctx)
+
+	errCh := make(chan error, 1)
+	go func() {
+		fmt.Fprintf(os.Stderr, "Serving on %s\n", listen)
+		errCh <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/output"
+)
+
+// exitThresholdBreached is the exit code when a --fail-* threshold is breached, so CI
+// jobs can tell a failed gate from a failed run (exit code 1)
+const exitThresholdBreached = 2
+
+// exitInterrupted is the exit code of a run stopped by Ctrl+C, following the shell
+// convention of 128 plus the signal number
+const exitInterrupted = 130
+
+// exitError is an error that ends the program with a specific exit code
+type exitError struct {
+	code    int
+	message string
+}
+
+func (e *exitError) Error() string {
+	return e.message
+}
+
+// countThreshold is a parsed --fail-if-count specification such as
+// service=ec2,state=running,gt=100
+type countThreshold struct {
+	spec     string
+	filters  []output.Filter
+	operator string
+	limit    int
+}
+
+// countOperators are the comparisons a count threshold fails on
+var countOperators = map[string]func(count, limit int) bool{
+	"gt": func(count, limit int) bool { return count > limit },
+	"ge": func(count, limit int) bool { return count >= limit },
+	"lt": func(count, limit int) bool { return count < limit },
+	"le": func(count, limit int) bool { return count <= limit },
+	"eq": func(count, limit int) bool { return count == limit },
+	"ne": func(count, limit int) bool { return count != limit },
+}
+
+// parseCountThreshold parses comma-separated field=value conditions plus exactly one
+// comparison (gt, ge, lt, le, eq or ne) with the count that fails the run
+func parseCountThreshold(spec string) (countThreshold, error) {
+	threshold := countThreshold{spec: spec}
+
+	for _, part := range strings.Split(spec, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return countThreshold{}, fmt.Errorf("invalid count threshold %q: %q is not key=value", spec, part)
+		}
+
+		if _, isOperator := countOperators[key]; isOperator {
+			if threshold.operator != "" {
+				return countThreshold{}, fmt.Errorf("invalid count threshold %q: more than one comparison", spec)
+			}
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				return countThreshold{}, fmt.Errorf("invalid count threshold %q: %s needs a non-negative count", spec, key)
+			}
+			threshold.operator, threshold.limit = key, limit
+			continue
+		}
+
+		filter, err := output.ParseFilter(part)
+		if err != nil {
+			return countThreshold{}, fmt.Errorf("invalid count threshold %q: %w", spec, err)
+		}
+		threshold.filters = append(threshold.filters, filter)
+	}
+
+	if threshold.operator == "" {
+		return countThreshold{}, fmt.Errorf("invalid count threshold %q: missing comparison (gt, ge, lt, le, eq or ne)", spec)
+	}
+	return threshold, nil
+}
+
+// parseThresholdOptions checks the --fail-* flags before any AWS call is made
+func parseThresholdOptions(opts *options) ([]countThreshold, error) {
+	if opts.failIfCostOver < 0 {
+		return nil, fmt.Errorf("invalid cost threshold: %g", opts.failIfCostOver)
+	}
+	for _, budget := range opts.budgets {
+		if err := budget.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.failOnBudget && len(opts.budgets) == 0 {
+		return nil, fmt.Errorf("--fail-on-budget needs budgets in the config file")
+	}
+
+	var thresholds []countThreshold
+	for _, spec := range opts.failIfCount {
+		threshold, err := parseCountThreshold(spec)
+		if err != nil {
+			return nil, err
+		}
+		thresholds = append(thresholds, threshold)
+	}
+	return thresholds, nil
+}
+
+// checkThresholds returns an exitError listing every breached threshold. Cost and
+// counts cover the resources in the report, after --filter and --exclude.
+func checkThresholds(collection *models.ResourceCollection, filters []output.Filter, opts *options, counts []countThreshold) error {
+	var breaches []string
+	report := output.Options{CostEngine: opts.costEngine}
+
+	if opts.failOnErrors && len(collection.Errors) > 0 {
+		breaches = append(breaches, fmt.Sprintf("%d collector errors (--fail-on-errors)", len(collection.Errors)))
+	}
+
+	if opts.failIfCostOver > 0 {
+		_, costEstimates := output.PrepareResources(collection, filters, "service", report)
+		total := 0.0
+		for _, estimate := range costEstimates {
+			if estimate != nil {
+				total += estimate.Amount
+			}
+		}
+		if total > opts.failIfCostOver {
+			breaches = append(breaches, fmt.Sprintf("estimated monthly cost $%.2f is over $%.2f (--fail-if-cost-over)", total, opts.failIfCostOver))
+		}
+	}
+
+	if opts.failOnBudget {
+		resources, costEstimates := output.PrepareResources(collection, filters, "service", report)
+		for _, budget := range models.CheckBudgets(opts.budgets, resources, costEstimates) {
+			if budget.Over {
+				breaches = append(breaches, fmt.Sprintf("budget %s: estimated monthly cost $%.2f is over $%.2f (--fail-on-budget)", budget.Label(), budget.Spend, budget.Limit))
+			}
+		}
+	}
+
+	for _, threshold := range counts {
+		matched, _ := output.PrepareResources(collection, append(append([]output.Filter(nil), filters...), threshold.filters...), "service", report)
+		if countOperators[threshold.operator](len(matched), threshold.limit) {
+			breaches = append(breaches, fmt.Sprintf("%d resources match %s (--fail-if-count)", len(matched), threshold.spec))
+		}
+	}
+
+	if len(breaches) == 0 {
+		return nil
+	}
+	return &exitError{
+		code:    exitThresholdBreached,
+		message: "thresholds breached:\n  " + strings.Join(breaches, "\n  "),
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/tui"
+)
+
+// newTUICommand creates the tui command, an interactive browser for the inventory
+func newTUICommand() *cobra.Command {
+	opts := &options{}
+	var input string
+
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Browse the inventory interactively in the terminal",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTUI(cmd.Context(), opts, input)
+		},
+	}
+
+	addCollectionFlags(cmd, opts)
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.sortField, "sort", "service", "Initial sort, e.g. region,-cost")
+	flags.StringVar(&input, "input", "", "Browse a saved JSON inventory or snapshot instead of scanning")
+
+	return cmd
+}
+
+// runTUI collects the inventory, or reads a saved one, and opens the browser
+func runTUI(ctx context.Context, opts *options, input string) error {
+	filters, err := parseFilterOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	var collection *models.ResourceCollection
+	if input != "" {
+		collection, err = loadInventory(input)
+		if err != nil {
+			return err
+		}
+	} else {
+		if err := validateCollectionOptions(opts); err != nil {
+			return err
+		}
+
+		clientManager, err := newClientManager(opts)
+		if err != nil {
+			return err
+		}
+		if err := showAccount(ctx, clientManager, opts); err != nil {
+			return err
+		}
+
+		// Cost estimates fall back to built-in prices when the pricing API is unavailable
+		if err := usePrices(ctx, opts); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(os.Stderr, "Scanning...")
+		collection, err = collect(ctx, clientManager, opts)
+		if err != nil {
+			return err
+		}
+		for _, collectionErr := range collection.Errors {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", collectionErr)
+		}
+		for _, warning := range collection.Warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+	}
+
+	return tui.Run(collection, tui.Options{
+		Filters:    filters,
+		Sort:       opts.sortField,
+		CostEngine: opts.costEngine,
+	})
+}
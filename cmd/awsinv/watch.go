@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/diff"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/output"
+)
+
+// webhookTimeout bounds each change notification
+const webhookTimeout = 10 * time.Second
+
+// changeReport is a set of changes found by one scan, as written in JSON and posted
+// to the webhook
+type changeReport struct {
+	DetectedAt time.Time `json:"detectedAt"`
+	*diff.Result
+}
+
+// newWatchCommand creates the watch command, which re-scans on a schedule and reports
+// only what changed since the previous scan
+func newWatchCommand() *cobra.Command {
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Re-scan on a schedule and report only the resources that changed",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWatch(cmd.Context(), opts)
+		},
+	}
+
+	addCollectionFlags(cmd, opts)
+
+	flags := cmd.Flags()
+	flags.DurationVar(&opts.interval, "interval", 15*time.Minute, "Time between scans")
+	flags.StringVar(&opts.output, "output", "table", "Change report format (table|json)")
+	flags.StringVar(&opts.webhook, "webhook", "", "POST each change report as JSON to this URL")
+
+	return cmd
+}
+
+// runWatch scans until interrupted, printing and pushing the changes between scans.
+// Progress goes to stderr so stdout only carries change reports.
+func runWatch(ctx context.Context, opts *options) error {
+	if opts.output != "table" && opts.output != "json" {
+		return fmt.Errorf("invalid output format: %s (expected table or json)", opts.output)
+	}
+
+	if opts.interval <= 0 {
+		return fmt.Errorf("invalid interval: %s", opts.interval)
+	}
+
+	if opts.webhook != "" {
+		webhookURL, err := url.Parse(opts.webhook)
+		if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") {
+			return fmt.Errorf("invalid webhook URL: %s", opts.webhook)
+		}
+	}
+
+	filters, err := parseFilterOptions(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := validateCollectionOptions(opts); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	clientManager, err := newClientManager(opts)
+	if err != nil {
+		return err
+	}
+	if err := showAccount(ctx, clientManager, opts); err != nil {
+		return err
+	}
+
+	// Cost estimates fall back to built-in prices when the pricing API is unavailable
+	if err := usePrices(ctx, opts); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	var previous *diff.Snapshot
+	var previousFailed scanFailures
+	for {
+		current, failed, err := scanSnapshot(ctx, clientManager, opts, filters)
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case err != nil:
+			// Keep the previous scan, so a failed scan is not reported as everything disappearing
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		case previous == nil:
+			fmt.Fprintf(os.Stderr, "%s: baseline of %d resources, scanning every %s\n",
+				time.Now().Format(time.RFC3339), len(current.Resources), opts.interval)
+			previous, previousFailed = current, failed
+		default:
+			// The resources of the pairs that failed are carried forward, so they are
+			// neither reported as removed now nor as added once the pair succeeds again
+			current.Resources = append(current.Resources, failed.resources(previous)...)
+			result := diff.Compare(collectedIn(previous, previousFailed, failed), collectedIn(current, previousFailed, failed))
+			previous, previousFailed = current, failed
+			if result.HasChanges() {
+				if err := reportChanges(ctx, opts, changeReport{DetectedAt: time.Now(), Result: result}); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// scanSnapshot collects the inventory and turns it into a snapshot, applying the
+// filters. The service/region pairs that could not be collected are returned and
+// listed as warnings.
+func scanSnapshot(ctx context.Context, clientManager *awspkg.ClientManager, opts *options, filters []output.Filter) (*diff.Snapshot, scanFailures, error) {
+	collection, err := collect(ctx, clientManager, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan failed: %w", err)
+	}
+
+	failed := make(scanFailures)
+	for _, message := range collection.Errors {
+		fmt.Fprintf(os.Stderr, "Warning: not compared: %s\n", message)
+		if pair, _, found := strings.Cut(message, ": "); found {
+			failed[pair] = true
+		}
+	}
+
+	// The JSON output carries the cost estimates the diff compares
+	var buf bytes.Buffer
+	if err := output.NewJSONFormatterWithWriter(&buf).Format(collection, filters, "service", true); err != nil {
+		return nil, nil, err
+	}
+	snapshot, err := diff.ReadSnapshot(&buf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return snapshot, failed, nil
+}
+
+// scanFailures holds the service/region pairs a scan could not collect, as
+// "service/region". The pair of a global collector covers its resources in every
+// region, as S3 buckets are listed once for the account.
+type scanFailures map[string]bool
+
+// covers reports whether a resource belongs to a pair that failed
+func (f scanFailures) covers(resource models.Resource) bool {
+	return f[resource.Service+"/"+resource.Region] || f[resource.Service+"/"+models.GlobalRegion]
+}
+
+// resources returns the resources of a snapshot that belong to the pairs that failed
+func (f scanFailures) resources(snapshot *diff.Snapshot) []diff.SnapshotResource {
+	var resources []diff.SnapshotResource
+	for _, resource := range snapshot.Resources {
+		if f.covers(resource.Resource) {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
+// collectedIn returns a copy of a snapshot leaving out the resources of the pairs
+// that failed in any of the scans, so only the pairs all of them collected are compared
+func collectedIn(snapshot *diff.Snapshot, failures ...scanFailures) *diff.Snapshot {
+	filtered := *snapshot
+	filtered.Resources = nil
+	for _, resource := range snapshot.Resources {
+		failed := false
+		for _, f := range failures {
+			failed = failed || f.covers(resource.Resource)
+		}
+		if !failed {
+			filtered.Resources = append(filtered.Resources, resource)
+		}
+	}
+	return &filtered
+}
+
+// reportChanges prints a change report and posts it to the webhook
+func reportChanges(ctx context.Context, opts *options, report changeReport) error {
+	if opts.output == "json" {
+		if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(os.Stdout, "\n=== Changes detected at %s ===\n", report.DetectedAt.Format(time.RFC3339))
+		if err := diff.WriteTable(os.Stdout, report.Result); err != nil {
+			return err
+		}
+	}
+
+	if opts.webhook == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post changes to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
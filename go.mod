@@ -8,20 +8,50 @@ require (
 	github.com/aws/aws-sdk-go-v2 v1.36.6
 	github.com/aws/aws-sdk-go-v2/config v1.25.5
 	github.com/aws/aws-sdk-go-v2/credentials v1.16.4
+	github.com/aws/aws-sdk-go-v2/service/amplify v1.32.1
+	github.com/aws/aws-sdk-go-v2/service/apprunner v1.34.0
+	github.com/aws/aws-sdk-go-v2/service/backup v1.41.2
+	github.com/aws/aws-sdk-go-v2/service/batch v1.52.3
+	github.com/aws/aws-sdk-go-v2/service/cloudcontrol v1.24.3
+	github.com/aws/aws-sdk-go-v2/service/cloudtrail v1.48.4
 	github.com/aws/aws-sdk-go-v2/service/cloudwatch v1.32.2
+	github.com/aws/aws-sdk-go-v2/service/codebuild v1.61.0
+	github.com/aws/aws-sdk-go-v2/service/codepipeline v1.42.0
+	github.com/aws/aws-sdk-go-v2/service/configservice v1.52.3
+	github.com/aws/aws-sdk-go-v2/service/dax v1.24.2
+	github.com/aws/aws-sdk-go-v2/service/directconnect v1.32.2
 	github.com/aws/aws-sdk-go-v2/service/dynamodb v1.26.8
 	github.com/aws/aws-sdk-go-v2/service/ec2 v1.141.0
 	github.com/aws/aws-sdk-go-v2/service/ecs v1.40.1
+	github.com/aws/aws-sdk-go-v2/service/efs v1.36.3
 	github.com/aws/aws-sdk-go-v2/service/elasticache v1.46.4
+	github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2 v1.45.4
+	github.com/aws/aws-sdk-go-v2/service/eventbridge v1.39.3
+	github.com/aws/aws-sdk-go-v2/service/freetier v1.9.5
+	github.com/aws/aws-sdk-go-v2/service/fsx v1.53.4
+	github.com/aws/aws-sdk-go-v2/service/guardduty v1.54.5
+	github.com/aws/aws-sdk-go-v2/service/iam v1.42.1
 	github.com/aws/aws-sdk-go-v2/service/lambda v1.48.0
 	github.com/aws/aws-sdk-go-v2/service/pricing v1.35.1
 	github.com/aws/aws-sdk-go-v2/service/rds v1.68.0
 	github.com/aws/aws-sdk-go-v2/service/s3 v1.47.0
+	github.com/aws/aws-sdk-go-v2/service/savingsplans v1.23.3
+	github.com/aws/aws-sdk-go-v2/service/scheduler v1.13.4
+	github.com/aws/aws-sdk-go-v2/service/servicequotas v1.28.0
 	github.com/aws/aws-sdk-go-v2/service/sfn v1.24.6
+	github.com/aws/aws-sdk-go-v2/service/shield v1.30.2
 	github.com/aws/aws-sdk-go-v2/service/sts v1.25.4
-	github.com/aws/aws-sdk-go-v2/service/support v1.27.5
+	github.com/aws/aws-sdk-go-v2/service/wafv2 v1.60.1
+	github.com/aws/smithy-go v1.22.4
+	github.com/gdamore/tcell/v2 v2.7.4
 	github.com/google/go-cmp v0.6.0
+	github.com/jmespath/go-jmespath v0.4.0
+	github.com/klauspost/compress v1.17.11
+	github.com/rivo/tview v0.0.0-20240728114935-65571ae51e71
 	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5
+	gopkg.in/yaml.v3 v3.0.1
+	modernc.org/sqlite v1.34.5
 )
 
 require (
@@ -30,8 +60,7 @@ require (
 	github.com/aws/aws-sdk-go-v2/internal/configsources v1.3.37 // indirect
 	github.com/aws/aws-sdk-go-v2/internal/endpoints/v2 v2.6.37 // indirect
 	github.com/aws/aws-sdk-go-v2/internal/ini v1.7.1 // indirect
-	github.com/aws/aws-sdk-go-v2/internal/v4a v1.2.6 // indirect
-	github.com/aws/aws-sdk-go-v2/service/efs v1.36.3 // indirect
+	github.com/aws/aws-sdk-go-v2/internal/v4a v1.3.36 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/accept-encoding v1.10.4 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/checksum v1.2.6 // indirect
 	github.com/aws/aws-sdk-go-v2/service/internal/endpoint-discovery v1.8.11 // indirect
@@ -39,8 +68,20 @@ require (
 	github.com/aws/aws-sdk-go-v2/service/internal/s3shared v1.16.6 // indirect
 	github.com/aws/aws-sdk-go-v2/service/sso v1.17.3 // indirect
 	github.com/aws/aws-sdk-go-v2/service/ssooidc v1.20.1 // indirect
-	github.com/aws/smithy-go v1.22.4 // indirect
+	github.com/dustin/go-humanize v1.0.1 // indirect
+	github.com/gdamore/encoding v1.0.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/jmespath/go-jmespath v0.4.0 // indirect
-	github.com/spf13/pflag v1.0.5 // indirect
+	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/mattn/go-runewidth v0.0.15 // indirect
+	github.com/ncruces/go-strftime v0.1.9 // indirect
+	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
+	github.com/rivo/uniseg v0.4.7 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	golang.org/x/term v0.17.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	modernc.org/libc v1.55.3 // indirect
+	modernc.org/mathutil v1.6.0 // indirect
+	modernc.org/memory v1.8.0 // indirect
 )
@@ -0,0 +1,206 @@
+package awsinv
+
+import (
+	"io"
+	"time"
+
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/cache"
+	"github.com/xiaochen/awsinv/pkg/pricing"
+)
+
+// DefaultParallel is how many services and regions are collected at once by default
+const DefaultParallel = 12
+
+// Option configures an Inventory
+type Option func(*config)
+
+// WithServices collects only the named services, such as "ec2" or "rds", instead of
+// all of them. See Services for the names.
+func WithServices(services ...string) Option {
+	return func(c *config) {
+		c.collect.Services = services
+	}
+}
+
+// WithRegions collects only in the given regions instead of every enabled region
+func WithRegions(regions ...string) Option {
+	return func(c *config) {
+		c.collect.Regions = regions
+	}
+}
+
+// WithExcludeRegions leaves out the regions matching any of the names or shell
+// patterns, such as "ap-*"
+func WithExcludeRegions(patterns ...string) Option {
+	return func(c *config) {
+		c.collect.ExcludeRegions = patterns
+	}
+}
+
+// WithOptInRegions also collects in the regions the account has not opted in to
+func WithOptInRegions() Option {
+	return func(c *config) {
+		c.collect.IncludeOptInRegions = true
+	}
+}
+
+// WithParallel sets how many services and regions are collected at once. New fails
+// if it is below 1.
+func WithParallel(parallel int) Option {
+	return func(c *config) {
+		c.collect.Parallel = parallel
+	}
+}
+
+// WithTimeout sets the overall deadline of a collection. Services not collected by
+// then are recorded as timed out, and the collection returns what it has.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.collect.Timeout = timeout
+	}
+}
+
+// WithServiceTimeout bounds the collection of one service in one region
+func WithServiceTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.collect.ItemTimeout = timeout
+	}
+}
+
+// WithFailFast makes a collection fail on the first service that fails, cancelling
+// the collection of the others
+func WithFailFast() Option {
+	return func(c *config) {
+		c.collect.FailFast = true
+	}
+}
+
+// WithCache stores the resources of each service and region in c, and reuses those
+// stored less than ttl ago instead of collecting them again
+func WithCache(c *cache.Cache, ttl time.Duration) Option {
+	return func(cfg *config) {
+		cfg.collect.Cache = c
+		cfg.collect.CacheTTL = ttl
+	}
+}
+
+// WithResume reuses the resources the last collection cached, if it did not finish,
+// so only what it did not reach is collected. It needs WithCache.
+func WithResume() Option {
+	return func(c *config) {
+		c.collect.Resume = true
+	}
+}
+
+// WithLogger logs the progress of the inventory's collections to w
+func WithLogger(w io.Writer) Option {
+	return func(c *config) {
+		c.logger = w
+	}
+}
+
+// WithProfile uses the credentials of a profile of the shared AWS config files
+func WithProfile(profile string) Option {
+	return func(c *config) {
+		c.credentials.Profile = profile
+	}
+}
+
+// WithRole assumes a role, with an external ID if the role's trust policy requires
+// one, and collects with its credentials
+func WithRole(roleARN, externalID string) Option {
+	return func(c *config) {
+		c.credentials.RoleARN = roleARN
+		c.credentials.ExternalID = externalID
+	}
+}
+
+// WithCredentials sets every credential option at once, including those of MFA and
+// web identity. Its rate limits are replaced by those of WithRateLimits and
+// WithAPIBudget.
+func WithCredentials(credentials awspkg.Config) Option {
+	return func(c *config) {
+		c.credentials = credentials
+	}
+}
+
+// WithClientManager collects with an existing client manager, sharing its
+// credentials and rate limits, instead of creating one from the credential and rate
+// limit options
+func WithClientManager(clientManager *awspkg.ClientManager) Option {
+	return func(c *config) {
+		c.clientManager = clientManager
+	}
+}
+
+// WithRateLimits overrides the requests per second and region of APIs, such as
+// {"ec2": 50}. See awspkg.DefaultRateLimits.
+func WithRateLimits(limits map[string]float64) Option {
+	return func(c *config) {
+		c.rateLimits = limits
+	}
+}
+
+// WithAPIBudget caps the total request rate across all APIs and regions. A budget
+// can be shared by several inventories.
+func WithAPIBudget(budget *awspkg.APIBudget) Option {
+	return func(c *config) {
+		c.budget = budget
+	}
+}
+
+// WithoutTags skips the API calls that look up tags, for faster collections.
+// Resources whose APIs return their tags keep them.
+func WithoutTags() Option {
+	return func(c *config) {
+		c.settings.SkipTags = true
+	}
+}
+
+// WithoutBucketDetails skips looking up the security settings of S3 buckets
+func WithoutBucketDetails() Option {
+	return func(c *config) {
+		c.settings.SkipBucketDetails = true
+	}
+}
+
+// WithUsageMetrics measures usage from CloudWatch metrics for cost estimates based
+// on usage
+func WithUsageMetrics() Option {
+	return func(c *config) {
+		c.settings.UsageMetrics = true
+	}
+}
+
+// WithRightsizing measures the utilization of EC2 and RDS instances for rightsizing
+// recommendations
+func WithRightsizing() Option {
+	return func(c *config) {
+		c.settings.Rightsizing = true
+	}
+}
+
+// WithQuotaThreshold sets the utilization percentage at which service quotas are
+// flagged
+func WithQuotaThreshold(percent float64) Option {
+	return func(c *config) {
+		c.settings.QuotaThreshold = percent
+	}
+}
+
+// WithCloudControlTypes also lists the resources of the given CloudFormation types,
+// such as "AWS::SQS::Queue", through the Cloud Control API
+func WithCloudControlTypes(typeNames ...string) Option {
+	return func(c *config) {
+		c.settings.CloudControlTypes = typeNames
+	}
+}
+
+// WithCostEngine estimates costs with engine instead of the built-in prices, such as
+// one with live prices from pricing.NewPricingService
+func WithCostEngine(engine *pricing.Engine) Option {
+	return func(c *config) {
+		c.costEngine = engine
+	}
+}
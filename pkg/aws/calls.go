@@ -0,0 +1,22 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// addCallRecording times every API call, retries and rate limit waits included, and
+// counts it with the models.CallRecorder of its context
+func addCallRecording(stack *middleware.Stack) error {
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("AwsinvCallRecording",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			start := time.Now()
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			models.RecordCall(ctx, RateKey(awsmiddleware.GetServiceID(ctx)), awsmiddleware.GetRegion(ctx), time.Since(start), err)
+			return out, metadata, err
+		}), middleware.After)
+}
@@ -3,12 +3,16 @@ package aws
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/efs"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
@@ -19,12 +23,49 @@ type Config struct {
 	RoleARN    string
 	ExternalID string
 	Region     string
+
+	// RoleSessionName names the assumed role session, as shown in CloudTrail
+	RoleSessionName string
+
+	// RoleDuration is how long the assumed role credentials last, the SDK default
+	// when zero
+	RoleDuration time.Duration
+
+	// WebIdentityTokenFile is a file holding an OIDC token, such as the service
+	// account token of a Kubernetes pod (IRSA), to assume RoleARN with instead of
+	// the loaded credentials
+	WebIdentityTokenFile string
+
+	// MFASerial is the ARN or serial number of the MFA device the role requires
+	MFASerial string
+
+	// MFAToken is the code of the MFA device, used once. Without it, the output of
+	// MFATokenCommand is used, or the user is prompted at the terminal. This also
+	// applies to profiles that set mfa_serial.
+	MFAToken        string
+	MFATokenCommand string
+
+	// RateLimits overrides DefaultRateLimits: requests per second and region keyed by
+	// API, such as "ec2" or "servicequotas", or DefaultRateKey for the others
+	RateLimits map[string]float64
+
+	// Budget, when set, caps the total request rate on top of the per-API limits
+	Budget *APIBudget
 }
 
 // ClientManager manages AWS clients across regions
 type ClientManager struct {
 	config     Config
 	baseConfig aws.Config
+
+	// optInStatus is the opt-in status of each region, looked up once to classify
+	// errors by ClassifyRegionError
+	optInOnce   sync.Once
+	optInStatus map[string]string
+
+	// resolves records whether the EC2 endpoint of each region resolves
+	resolvesMu sync.Mutex
+	resolves   map[string]bool
 }
 
 // NewClientManager creates a new AWS client manager
@@ -33,25 +74,60 @@ func NewClientManager(cfg Config) (*ClientManager, error) {
 	var awsConfig aws.Config
 	var err error
 
+	// Profiles that assume a role with mfa_serial need a way to get the code
+	loadOptions := []func(*config.LoadOptions) error{
+		config.WithCredentialsCacheOptions(renewEarly),
+		config.WithAssumeRoleCredentialOptions(func(options *stscreds.AssumeRoleOptions) {
+			if options.SerialNumber != nil {
+				options.TokenProvider = cfg.mfaTokenProvider(aws.ToString(options.SerialNumber))
+			}
+		}),
+	}
 	if cfg.Profile != "" {
-		awsConfig, err = config.LoadDefaultConfig(context.Background(),
-			config.WithSharedConfigProfile(cfg.Profile))
-	} else {
-		awsConfig, err = config.LoadDefaultConfig(context.Background())
+		loadOptions = append(loadOptions, config.WithSharedConfigProfile(cfg.Profile))
 	}
+
+	awsConfig, err = config.LoadDefaultConfig(context.Background(), loadOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
 	// Handle role assumption if specified
-	if cfg.RoleARN != "" {
+	switch {
+	case cfg.WebIdentityTokenFile != "":
+		if cfg.RoleARN == "" {
+			return nil, fmt.Errorf("a web identity token file needs the ARN of the role to assume")
+		}
 		stsClient := sts.NewFromConfig(awsConfig)
-		provider := stscreds.NewAssumeRoleProvider(stsClient, cfg.RoleARN)
-		
-		// Note: ExternalID is not available in this version of the SDK
-		// The role assumption will work without it for most use cases
+		provider := stscreds.NewWebIdentityRoleProvider(stsClient, cfg.RoleARN, stscreds.IdentityTokenFile(cfg.WebIdentityTokenFile), func(options *stscreds.WebIdentityRoleOptions) {
+			options.RoleSessionName = cfg.RoleSessionName
+			options.Duration = cfg.RoleDuration
+		})
+
+		// The token file is read again on each renewal, as Kubernetes rotates it
+		awsConfig.Credentials = aws.NewCredentialsCache(provider, renewEarly)
 
-		awsConfig.Credentials = provider
+	case cfg.RoleARN != "":
+		stsClient := sts.NewFromConfig(awsConfig)
+		provider := stscreds.NewAssumeRoleProvider(stsClient, cfg.RoleARN, func(options *stscreds.AssumeRoleOptions) {
+			if cfg.ExternalID != "" {
+				options.ExternalID = aws.String(cfg.ExternalID)
+			}
+			if cfg.RoleSessionName != "" {
+				options.RoleSessionName = cfg.RoleSessionName
+			}
+			if cfg.RoleDuration > 0 {
+				options.Duration = cfg.RoleDuration
+			}
+			if cfg.MFASerial != "" {
+				options.SerialNumber = aws.String(cfg.MFASerial)
+				options.TokenProvider = cfg.mfaTokenProvider(cfg.MFASerial)
+			}
+		})
+
+		// Cached, so that the MFA code is asked for once rather than on every request,
+		// and renewed before they expire, so long scans outlast the role duration
+		awsConfig.Credentials = aws.NewCredentialsCache(provider, renewEarly)
 	}
 
 	// Set default region if specified
@@ -59,12 +135,30 @@ func NewClientManager(cfg Config) (*ClientManager, error) {
 		awsConfig.Region = cfg.Region
 	}
 
+	// Clients of the same API and region share a rate limit. Calls are counted for
+	// the collection summary.
+	limiters := newRateLimiters(cfg.RateLimits, cfg.Budget)
+	awsConfig.APIOptions = append(awsConfig.APIOptions, limiters.addMiddleware, addCallRecording)
+
 	return &ClientManager{
 		config:     cfg,
 		baseConfig: awsConfig,
 	}, nil
 }
 
+// credentialsExpiryWindow is how long before they expire credentials are renewed, so
+// a request is not signed with credentials that expire while it is retried
+const credentialsExpiryWindow = 5 * time.Minute
+
+// renewEarly makes a credentials cache renew credentials ahead of their expiry. The
+// credentials of profiles, assumed roles and SSO sessions configured with sso_session
+// are renewed this way; legacy SSO profiles need a new "aws sso login" once their
+// token expires.
+func renewEarly(options *aws.CredentialsCacheOptions) {
+	options.ExpiryWindow = credentialsExpiryWindow
+	options.ExpiryWindowJitterFrac = 0.5
+}
+
 // GetConfig returns the AWS config for a specific region
 func (cm *ClientManager) GetConfig(region string) aws.Config {
 	cfg := cm.baseConfig
@@ -72,14 +166,65 @@ func (cm *ClientManager) GetConfig(region string) aws.Config {
 	return cfg
 }
 
-// DiscoverRegions discovers all available regions using EC2 DescribeRegions
-func (cm *ClientManager) DiscoverRegions(ctx context.Context) ([]string, error) {
+// DefaultRegion returns the region of the loaded profile or environment, if any
+func (cm *ClientManager) DefaultRegion() string {
+	return cm.baseConfig.Region
+}
+
+// Identity is the principal the credentials resolve to
+type Identity struct {
+	Account string
+	ARN     string
+	UserID  string
+}
+
+// CallerIdentity asks STS who the credentials belong to, which also checks that
+// they are valid
+func (cm *ClientManager) CallerIdentity(ctx context.Context) (*Identity, error) {
+	region := cm.baseConfig.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	client := sts.NewFromConfig(cm.GetConfig(region))
+	result, err := client.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get caller identity: %w", err)
+	}
+
+	return &Identity{
+		Account: aws.ToString(result.Account),
+		ARN:     aws.ToString(result.Arn),
+		UserID:  aws.ToString(result.UserId),
+	}, nil
+}
+
+// DiscoverRegions discovers the regions enabled for the account using EC2
+// DescribeRegions, or all regions, including those not opted in to, with includeOptIn
+func (cm *ClientManager) DiscoverRegions(ctx context.Context, includeOptIn bool) ([]string, error) {
+	regions, err := cm.describeRegions(ctx, includeOptIn)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, region := range regions {
+		if region.RegionName != nil {
+			names = append(names, *region.RegionName)
+		}
+	}
+
+	return names, nil
+}
+
+// describeRegions lists the enabled regions, or all regions with all
+func (cm *ClientManager) describeRegions(ctx context.Context, all bool) ([]ec2types.Region, error) {
 	// Use us-east-1 as the default region for region discovery
 	cfg := cm.GetConfig("us-east-1")
 	client := ec2.NewFromConfig(cfg)
 
 	input := &ec2.DescribeRegionsInput{
-		AllRegions: aws.Bool(false), // Only return enabled regions
+		AllRegions: aws.Bool(all),
 	}
 
 	result, err := client.DescribeRegions(ctx, input)
@@ -87,42 +232,85 @@ func (cm *ClientManager) DiscoverRegions(ctx context.Context) ([]string, error)
 		return nil, fmt.Errorf("failed to describe regions: %w", err)
 	}
 
-	var regions []string
-	for _, region := range result.Regions {
-		if region.RegionName != nil {
-			regions = append(regions, *region.RegionName)
+	return result.Regions, nil
+}
+
+// regionOptedOut reports whether region is an opt-in region the account has not
+// enabled. It is false when the regions cannot be described, such as when the
+// credentials are rejected.
+func (cm *ClientManager) regionOptedOut(ctx context.Context, region string) bool {
+	cm.optInOnce.Do(func() {
+		regions, err := cm.describeRegions(ctx, true)
+		if err != nil {
+			return
 		}
+		cm.optInStatus = make(map[string]string, len(regions))
+		for _, r := range regions {
+			cm.optInStatus[aws.ToString(r.RegionName)] = aws.ToString(r.OptInStatus)
+		}
+	})
+	return cm.optInStatus[region] == "not-opted-in"
+}
+
+// regionResolves reports whether the EC2 endpoint of region resolves, telling a
+// service without an endpoint in the region apart from a network without DNS
+func (cm *ClientManager) regionResolves(ctx context.Context, region string) bool {
+	cm.resolvesMu.Lock()
+	defer cm.resolvesMu.Unlock()
+	if resolves, ok := cm.resolves[region]; ok {
+		return resolves
 	}
 
-	return regions, nil
+	host := "ec2." + region + ".amazonaws.com"
+	if strings.HasPrefix(region, "cn-") {
+		host += ".cn"
+	}
+	_, err := net.DefaultResolver.LookupHost(ctx, host)
+	if ctx.Err() != nil {
+		return false
+	}
+	if cm.resolves == nil {
+		cm.resolves = make(map[string]bool)
+	}
+	cm.resolves[region] = err == nil
+	return err == nil
 }
 
-// ValidateRegions validates that the provided regions exist
-func (cm *ClientManager) ValidateRegions(ctx context.Context, regions []string) ([]string, error) {
-	availableRegions, err := cm.DiscoverRegions(ctx)
+// ValidateRegions validates that the provided regions exist and, unless includeOptIn
+// is set, that they are enabled for the account
+func (cm *ClientManager) ValidateRegions(ctx context.Context, regions []string, includeOptIn bool) ([]string, error) {
+	availableRegions, err := cm.describeRegions(ctx, true)
 	if err != nil {
 		return nil, err
 	}
 
-	regionMap := make(map[string]bool)
+	optInStatus := make(map[string]string)
 	for _, region := range availableRegions {
-		regionMap[region] = true
+		optInStatus[aws.ToString(region.RegionName)] = aws.ToString(region.OptInStatus)
 	}
 
 	var validRegions []string
 	var invalidRegions []string
+	var disabledRegions []string
 
 	for _, region := range regions {
-		if regionMap[region] {
-			validRegions = append(validRegions, region)
-		} else {
+		status, exists := optInStatus[region]
+		switch {
+		case !exists:
 			invalidRegions = append(invalidRegions, region)
+		case status == "not-opted-in" && !includeOptIn:
+			disabledRegions = append(disabledRegions, region)
+		default:
+			validRegions = append(validRegions, region)
 		}
 	}
 
 	if len(invalidRegions) > 0 {
 		return validRegions, fmt.Errorf("invalid regions: %s", strings.Join(invalidRegions, ", "))
 	}
+	if len(disabledRegions) > 0 {
+		return validRegions, fmt.Errorf("regions not enabled for the account: %s", strings.Join(disabledRegions, ", "))
+	}
 
 	return validRegions, nil
 }
@@ -0,0 +1,124 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/smithy-go"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// errorCodeClasses maps AWS API error codes to error classes
+var errorCodeClasses = map[string]error{
+	"AccessDenied":                models.ErrAccessDenied,
+	"AccessDeniedException":       models.ErrAccessDenied,
+	"UnauthorizedOperation":       models.ErrAccessDenied,
+	"UnauthorizedAccess":          models.ErrAccessDenied,
+	"AuthorizationError":          models.ErrAccessDenied,
+	"AuthorizationErrorException": models.ErrAccessDenied,
+
+	"Throttling":                             models.ErrThrottled,
+	"ThrottlingException":                    models.ErrThrottled,
+	"ThrottledException":                     models.ErrThrottled,
+	"RequestThrottled":                       models.ErrThrottled,
+	"RequestThrottledException":              models.ErrThrottled,
+	"RequestLimitExceeded":                   models.ErrThrottled,
+	"TooManyRequestsException":               models.ErrThrottled,
+	"ProvisionedThroughputExceededException": models.ErrThrottled,
+	"SlowDown":                               models.ErrThrottled,
+
+	"OptInRequired": models.ErrRegionDisabled,
+
+	// Requests to a region the account has not opted in to also fail authentication,
+	// but so do wrong or revoked keys: ClientManager.ClassifyRegionError tells them
+	// apart from the region's opt-in status
+	"AuthFailure":                 models.ErrCredentials,
+	"InvalidClientTokenId":        models.ErrCredentials,
+	"UnrecognizedClientException": models.ErrCredentials,
+
+	"RequestTimeout":          models.ErrTimeout,
+	"RequestTimeoutException": models.ErrTimeout,
+
+	"ExpiredToken":          models.ErrCredentials,
+	"ExpiredTokenException": models.ErrCredentials,
+}
+
+// classifiedError adds an error class to an error without changing its message
+type classifiedError struct {
+	class error
+	err   error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() []error {
+	return []error{e.class, e.err}
+}
+
+// ClassifyError wraps err with its error class, such as models.ErrAccessDenied, when
+// the class can be told from the API error code or the cause. Other errors are
+// returned unchanged.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var class error
+	var signErr *v4.SigningError
+	var apiErr smithy.APIError
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	switch {
+	case errors.As(err, &signErr) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled):
+		// Requests cannot be signed when the credentials cannot be retrieved, such as
+		// when an assumed role cannot be renewed or the SSO session expired
+		class = models.ErrCredentials
+	case errors.As(err, &apiErr) && errorCodeClasses[apiErr.ErrorCode()] != nil:
+		class = errorCodeClasses[apiErr.ErrorCode()]
+	case errors.Is(err, context.DeadlineExceeded):
+		class = models.ErrTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		class = models.ErrTimeout
+	case errors.As(err, &dnsErr) || errors.As(err, &opErr):
+		// Services not offered in a region have no endpoint there either, which
+		// ClientManager.ClassifyRegionError checks for
+		class = models.ErrNetwork
+	default:
+		return err
+	}
+
+	if errors.Is(err, class) {
+		return err
+	}
+	return &classifiedError{class: class, err: err}
+}
+
+// ClassifyRegionError classifies an error of a collection in region like
+// ClassifyError, then tells whether the region was the cause: failures in a region
+// the account has not opted in to, and a missing endpoint of a service not offered in
+// the region, are classed as models.ErrRegionDisabled
+func (cm *ClientManager) ClassifyRegionError(ctx context.Context, region string, err error) error {
+	classified := ClassifyError(err)
+	if classified == nil || region == models.GlobalRegion || errors.Is(classified, models.ErrRegionDisabled) {
+		return classified
+	}
+
+	if errors.Is(classified, models.ErrAccessDenied) || errors.Is(classified, models.ErrCredentials) || errors.Is(classified, models.ErrNetwork) {
+		if cm.regionOptedOut(ctx, region) {
+			return &classifiedError{class: models.ErrRegionDisabled, err: err}
+		}
+	}
+
+	// A service not offered in the region has no endpoint there, while the region's
+	// EC2 endpoint, which every region has, resolves
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound && cm.regionResolves(ctx, region) {
+		return &classifiedError{class: models.ErrRegionDisabled, err: err}
+	}
+	return classified
+}
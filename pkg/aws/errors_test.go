@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+func TestClassifyError(t *testing.T) {
+	apiError := func(code string) error {
+		return fmt.Errorf("operation error: %w", &smithy.GenericAPIError{Code: code, Message: "failed"})
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"access denied", apiError("AccessDeniedException"), "access-denied"},
+		{"throttled", apiError("ThrottlingException"), "throttled"},
+		{"opt-in required", apiError("OptInRequired"), "region-disabled"},
+		{"auth failure", apiError("AuthFailure"), "credentials"},
+		{"invalid key", apiError("InvalidClientTokenId"), "credentials"},
+		{"unrecognized client", apiError("UnrecognizedClientException"), "credentials"},
+		{"expired token", apiError("ExpiredToken"), "credentials"},
+		{"endpoint not found", &net.DNSError{Err: "no such host", Name: "apprunner.eu-north-1.amazonaws.com", IsNotFound: true}, "network"},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, "network"},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", IsTimeout: true}, "timeout"},
+		{"deadline", fmt.Errorf("list: %w", context.DeadlineExceeded), "timeout"},
+		{"unknown code", apiError("ValidationException"), "other"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyError(tt.err)
+			if class := models.ErrorClass(got); class != tt.want {
+				t.Errorf("ClassifyError(%v) class = %q, want %q", tt.err, class, tt.want)
+			}
+			if got.Error() != tt.err.Error() {
+				t.Errorf("ClassifyError(%v) message = %q, want it unchanged", tt.err, got.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// mfaTokenProvider returns the function assume-role calls for the code of the MFA
+// device serial. The code is the one configured, which can only be used once, else
+// the output of the configured command, else what the user types at the terminal.
+func (cfg Config) mfaTokenProvider(serial string) func() (string, error) {
+	if cfg.MFAToken != "" {
+		var mu sync.Mutex
+		used := false
+		return func() (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if used {
+				return "", fmt.Errorf("MFA code for %s already used; STS accepts each code once, so renewing the credentials needs a token command", serial)
+			}
+			used = true
+			return cfg.MFAToken, nil
+		}
+	}
+
+	if cfg.MFATokenCommand != "" {
+		return func() (string, error) {
+			return runTokenCommand(cfg.MFATokenCommand, serial)
+		}
+	}
+
+	return func() (string, error) {
+		return promptToken(serial)
+	}
+}
+
+// runTokenCommand runs a command with the shell and returns the MFA code it prints
+func runTokenCommand(command, serial string) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("MFA token command for %s failed: %w", serial, err)
+	}
+
+	token := strings.TrimSpace(string(out))
+	if token == "" {
+		return "", fmt.Errorf("MFA token command for %s printed no code", serial)
+	}
+	return token, nil
+}
+
+// promptToken asks for the MFA code on stderr, so it does not end up in the report,
+// and reads it from stdin, which has to be a terminal
+func promptToken(serial string) (string, error) {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return "", fmt.Errorf("an MFA code is required for %s, but stdin is not a terminal to prompt for it; provide the code or a command that prints it", serial)
+	}
+
+	fmt.Fprintf(os.Stderr, "MFA code for %s: ", serial)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	token := strings.TrimSpace(line)
+	if token == "" {
+		if err == nil {
+			err = errors.New("no code entered")
+		}
+		return "", fmt.Errorf("failed to read the MFA code for %s: %w", serial, err)
+	}
+	return token, nil
+}
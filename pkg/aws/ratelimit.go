@@ -0,0 +1,199 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// DefaultRateKey is the RateLimits entry that applies to APIs without their own entry
+const DefaultRateKey = "default"
+
+// DefaultRateLimits are the request rates, per second and region, used for APIs the
+// configuration does not mention. They stay below the documented throttling limits
+// of each API, so a scan does not starve other tools using the same account.
+var DefaultRateLimits = map[string]float64{
+	DefaultRateKey:  10,
+	"ec2":           20,
+	"dynamodb":      20,
+	"lambda":        10,
+	"cloudcontrol":  5,
+	"pricing":       5,
+	"servicequotas": 5,
+	"shield":        2,
+}
+
+// minimumRate is the lowest rate that throttling backs off to
+const minimumRate = 0.5
+
+// RateKey returns the RateLimits key of an AWS service ID, e.g. "servicequotas" for
+// "Service Quotas"
+func RateKey(serviceID string) string {
+	return strings.ToLower(strings.ReplaceAll(serviceID, " ", ""))
+}
+
+// rateLimiter is a token bucket whose rate adapts to throttling: it halves when a
+// request is throttled and recovers gradually, up to the configured rate, as
+// requests succeed
+type rateLimiter struct {
+	mu      sync.Mutex
+	maxRate float64
+	rate    float64
+	tokens  float64
+	last    time.Time
+}
+
+// newRateLimiter creates a limiter for the given requests per second
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{
+		maxRate: rate,
+		rate:    rate,
+		tokens:  burst(rate),
+		last:    time.Now(),
+	}
+}
+
+// burst is the bucket size for a rate: one second of requests, and at least one
+func burst(rate float64) float64 {
+	return math.Max(1, rate)
+}
+
+// Wait blocks until a request may be sent or the context ends
+func (l *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens = math.Min(burst(l.rate), l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Throttled halves the rate after the API rejected a request for exceeding its limit
+func (l *rateLimiter) Throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = math.Max(minimumRate, l.rate/2)
+	l.tokens = math.Min(l.tokens, burst(l.rate))
+}
+
+// Succeeded raises the rate by a twentieth of the configured rate, so it takes
+// about twenty requests to recover from one halving
+func (l *rateLimiter) Succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = math.Min(l.maxRate, l.rate+l.maxRate/20)
+}
+
+// APIBudget caps the total request rate across all APIs and regions. One budget can
+// be shared by several client managers, to bound scans of many accounts at once.
+type APIBudget struct {
+	limiter *rateLimiter
+}
+
+// NewAPIBudget creates a budget of rate requests per second
+func NewAPIBudget(rate float64) *APIBudget {
+	return &APIBudget{limiter: newRateLimiter(rate)}
+}
+
+// rateLimiters holds one limiter per AWS API and region, as AWS throttles each region
+// separately. Limiters are created on first use.
+type rateLimiters struct {
+	mu       sync.Mutex
+	limits   map[string]float64
+	limiters map[string]*rateLimiter
+	budget   *APIBudget
+}
+
+// newRateLimiters creates the limiters for the configured rates, which override the
+// defaults, within an optional overall budget. A rate of zero or less leaves an API
+// unlimited.
+func newRateLimiters(configured map[string]float64, budget *APIBudget) *rateLimiters {
+	limits := make(map[string]float64, len(DefaultRateLimits)+len(configured))
+	for key, rate := range DefaultRateLimits {
+		limits[key] = rate
+	}
+	for key, rate := range configured {
+		limits[RateKey(key)] = rate
+	}
+
+	return &rateLimiters{
+		limits:   limits,
+		limiters: make(map[string]*rateLimiter),
+		budget:   budget,
+	}
+}
+
+// get returns the limiter of an API in a region, or nil if the API is unlimited
+func (r *rateLimiters) get(key, region string) *rateLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limiter, ok := r.limiters[key+"/"+region]; ok {
+		return limiter
+	}
+
+	rate, ok := r.limits[key]
+	if !ok {
+		rate = r.limits[DefaultRateKey]
+	}
+
+	var limiter *rateLimiter
+	if rate > 0 {
+		limiter = newRateLimiter(rate)
+	}
+	r.limiters[key+"/"+region] = limiter
+	return limiter
+}
+
+// addMiddleware limits every request attempt, retries included, to the rate of its
+// API, and adapts the rate to throttling responses
+func (r *rateLimiters) addMiddleware(stack *middleware.Stack) error {
+	return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("AwsinvRateLimit",
+		func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			if r.budget != nil {
+				if err := r.budget.limiter.Wait(ctx); err != nil {
+					return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+				}
+			}
+
+			limiter := r.get(RateKey(awsmiddleware.GetServiceID(ctx)), awsmiddleware.GetRegion(ctx))
+			if limiter == nil {
+				return next.HandleFinalize(ctx, in)
+			}
+
+			if err := limiter.Wait(ctx); err != nil {
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+			}
+
+			out, metadata, err := next.HandleFinalize(ctx, in)
+			switch {
+			case err == nil:
+				limiter.Succeeded()
+			case errors.Is(ClassifyError(err), models.ErrThrottled):
+				limiter.Throttled()
+			}
+			return out, metadata, err
+		}), middleware.After)
+}
@@ -0,0 +1,151 @@
+// Package cache keeps collected resources on disk for a while, so repeated runs
+// reuse them instead of calling AWS again
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// Cache stores the resources of each account, service and region as a JSON file,
+// and the state of each account's last scan
+type Cache struct {
+	dir string
+}
+
+// entry is the content of a cache file
+type entry struct {
+	StoredAt  time.Time         `json:"storedAt"`
+	Resources []models.Resource `json:"resources"`
+	Warnings  []string          `json:"warnings,omitempty"`
+}
+
+// DefaultDir returns the cache directory, awsinv in the user cache directory (e.g.
+// ~/.cache/awsinv)
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the cache directory: %w", err)
+	}
+	return filepath.Join(dir, "awsinv"), nil
+}
+
+// New creates a cache in dir
+func New(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// path returns the file of an account, service and region. The variant tells apart
+// collections whose result depends on settings, such as the Cloud Control types.
+func (c *Cache) path(account, variant, service, region string) string {
+	name := service + "-" + region + ".json"
+	if variant != "" {
+		name = service + "-" + variant + "-" + region + ".json"
+	}
+	return filepath.Join(c.dir, account, name)
+}
+
+// Get returns the cached resources and warnings of a collection, and when they were
+// stored, if an entry stored at or after since exists
+func (c *Cache) Get(account, variant, service, region string, since time.Time) ([]models.Resource, []string, time.Time, bool) {
+	data, err := os.ReadFile(c.path(account, variant, service, region))
+	if err != nil {
+		return nil, nil, time.Time{}, false
+	}
+
+	var cached entry
+	if err := json.Unmarshal(data, &cached); err != nil {
+		// A damaged entry is collected again and overwritten
+		return nil, nil, time.Time{}, false
+	}
+	if cached.StoredAt.Before(since) {
+		return nil, nil, time.Time{}, false
+	}
+
+	return cached.Resources, cached.Warnings, cached.StoredAt, true
+}
+
+// Put stores the resources and warnings of a collection
+func (c *Cache) Put(account, variant, service, region string, resources []models.Resource, warnings []string) error {
+	data, err := json.Marshal(entry{
+		StoredAt:  time.Now().UTC(),
+		Resources: resources,
+		Warnings:  warnings,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.write(c.path(account, variant, service, region), data)
+}
+
+// write stores a file in the cache, renaming it into place once complete
+func (c *Cache) write(path string, data []byte) error {
+	// Inventories can be sensitive, so only the user may read the cache
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Run is the state of a scan, kept from its start until it finishes so an
+// interrupted scan can be resumed
+type Run struct {
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// runPath returns the run state file of an account
+func (c *Cache) runPath(account string) string {
+	return filepath.Join(c.dir, account, "run.json")
+}
+
+// StartRun records that a scan of the account started
+func (c *Cache) StartRun(account string, run Run) error {
+	data, err := json.Marshal(run)
+	if err != nil {
+		return err
+	}
+	return c.write(c.runPath(account), data)
+}
+
+// UnfinishedRun returns the last scan of the account if it did not finish, or nil
+func (c *Cache) UnfinishedRun(account string) *Run {
+	data, err := os.ReadFile(c.runPath(account))
+	if err != nil {
+		return nil
+	}
+
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil
+	}
+	return &run
+}
+
+// FinishRun records that the scan of the account finished
+func (c *Cache) FinishRun(account string) error {
+	if err := os.Remove(c.runPath(account)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// AMICollector collects account-owned AMIs
+type AMICollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewAMICollector creates a new AMI collector
+func NewAMICollector(clientManager *awspkg.ClientManager) *AMICollector {
+	return &AMICollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *AMICollector) Name() string {
+	return "ami"
+}
+
+// Regions returns the regions this collector supports
+func (c *AMICollector) Regions() []string {
+	// AMIs are regional and available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *AMICollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves account-owned AMIs for the given region
+func (c *AMICollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := ec2.NewFromConfig(cfg)
+
+	// Find which images are referenced by running instances
+	imageUsage, err := c.getRunningImageUsage(ctx, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe instances in %s: %w", region, err)
+	}
+
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ec2.DescribeImagesInput{
+			Owners:    []string{"self"},
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeImages(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe images in %s: %w", region, err)
+		}
+
+		for _, image := range result.Images {
+			resource := c.convertImage(image, imageUsage[aws.ToString(image.ImageId)], region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getRunningImageUsage counts running instances per image ID
+func (c *AMICollector) getRunningImageUsage(ctx context.Context, client *ec2.Client) (map[string]int, error) {
+	usage := make(map[string]int)
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ec2.DescribeInstancesInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("instance-state-name"),
+					Values: []string{"running"},
+				},
+			},
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeInstances(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reservation := range result.Reservations {
+			for _, instance := range reservation.Instances {
+				if instance.ImageId != nil {
+					usage[aws.ToString(instance.ImageId)]++
+				}
+			}
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return usage, nil
+}
+
+// convertImage converts an AMI to a Resource
+func (c *AMICollector) convertImage(image types.Image, runningInstances int, region string) models.Resource {
+	usage := "unused"
+	if runningInstances > 0 {
+		usage = "in-use"
+	}
+
+	resource := models.Resource{
+		Service: "ami",
+		Region:  region,
+		ID:      aws.ToString(image.ImageId),
+		Name:    aws.ToString(image.Name),
+		Type:    "image",
+		State:   string(image.State),
+		Class:   usage,
+	}
+
+	// Extract tags
+	if image.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range image.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Set creation time (returned as an ISO 8601 string)
+	if image.CreationDate != nil {
+		if createdAt, err := time.Parse(time.RFC3339, aws.ToString(image.CreationDate)); err == nil {
+			resource.CreatedAt = &createdAt
+		}
+	}
+
+	// Sum the sizes of the EBS snapshots backing the image
+	var snapshotIDs []string
+	var snapshotSizeGB int32
+	for _, mapping := range image.BlockDeviceMappings {
+		if mapping.Ebs == nil {
+			continue
+		}
+		if mapping.Ebs.SnapshotId != nil {
+			snapshotIDs = append(snapshotIDs, aws.ToString(mapping.Ebs.SnapshotId))
+		}
+		snapshotSizeGB += aws.ToInt32(mapping.Ebs.VolumeSize)
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["runningInstances"] = runningInstances
+	extra["inUse"] = runningInstances > 0
+	extra["snapshotSizeGB"] = snapshotSizeGB
+	if len(snapshotIDs) > 0 {
+		extra["snapshotIds"] = snapshotIDs
+	}
+	if image.Architecture != "" {
+		extra["architecture"] = string(image.Architecture)
+	}
+	if image.PlatformDetails != nil {
+		extra["platformDetails"] = aws.ToString(image.PlatformDetails)
+	}
+	if image.RootDeviceType != "" {
+		extra["rootDeviceType"] = string(image.RootDeviceType)
+	}
+	if image.Public != nil {
+		extra["public"] = aws.ToBool(image.Public)
+	}
+	if image.DeprecationTime != nil {
+		extra["deprecationTime"] = aws.ToString(image.DeprecationTime)
+	}
+	if image.Description != nil {
+		extra["description"] = aws.ToString(image.Description)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
@@ -0,0 +1,195 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/amplify"
+	"github.com/aws/aws-sdk-go-v2/service/amplify/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// AmplifyCollector collects Amplify apps and their branches
+type AmplifyCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewAmplifyCollector creates a new Amplify collector
+func NewAmplifyCollector(clientManager *awspkg.ClientManager) *AmplifyCollector {
+	return &AmplifyCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *AmplifyCollector) Name() string {
+	return "amplify"
+}
+
+// Regions returns the regions this collector supports
+func (c *AmplifyCollector) Regions() []string {
+	// Amplify is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *AmplifyCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves Amplify apps and branches for the given region
+func (c *AmplifyCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := amplify.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &amplify.ListAppsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListApps(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Amplify apps in %s: %w", region, err)
+		}
+
+		for _, app := range result.Apps {
+			resource := c.convertApp(app, region)
+			resources = append(resources, resource)
+
+			// Also collect branches of this app
+			branches, err := c.getAppBranches(ctx, client, app, region)
+			if err != nil {
+				models.Warnf(ctx, "failed to get branches for Amplify app %s: %v", aws.ToString(app.Name), err)
+				continue
+			}
+			resources = append(resources, branches...)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getAppBranches retrieves the branches of an Amplify app
+func (c *AmplifyCollector) getAppBranches(ctx context.Context, client *amplify.Client, app types.App, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &amplify.ListBranchesInput{
+			AppId:     app.AppId,
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListBranches(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, branch := range result.Branches {
+			resource := c.convertBranch(app, branch, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertApp converts an Amplify app to a Resource
+func (c *AmplifyCollector) convertApp(app types.App, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "amplify",
+		Region:    region,
+		ID:        aws.ToString(app.AppId),
+		Name:      aws.ToString(app.Name),
+		Type:      "app",
+		State:     "active",
+		Class:     strings.ToLower(string(app.Platform)),
+		CreatedAt: app.CreateTime,
+		Tags:      app.Tags,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if app.AppArn != nil {
+		extra["appArn"] = aws.ToString(app.AppArn)
+	}
+	if app.DefaultDomain != nil {
+		extra["defaultDomain"] = aws.ToString(app.DefaultDomain)
+	}
+	if app.Repository != nil {
+		extra["repository"] = aws.ToString(app.Repository)
+	}
+	if app.ProductionBranch != nil {
+		extra["productionBranch"] = aws.ToString(app.ProductionBranch.BranchName)
+		if app.ProductionBranch.LastDeployTime != nil {
+			extra["lastDeployTime"] = aws.ToTime(app.ProductionBranch.LastDeployTime)
+		}
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertBranch converts an Amplify branch to a Resource
+func (c *AmplifyCollector) convertBranch(app types.App, branch types.Branch, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "amplify",
+		Region:    region,
+		ID:        fmt.Sprintf("%s/%s", aws.ToString(app.AppId), aws.ToString(branch.BranchName)),
+		Name:      aws.ToString(branch.BranchName),
+		Type:      "branch",
+		State:     "active",
+		Class:     strings.ToLower(string(branch.Stage)),
+		CreatedAt: branch.CreateTime,
+		Tags:      branch.Tags,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["appId"] = aws.ToString(app.AppId)
+	extra["appName"] = aws.ToString(app.Name)
+	if branch.BranchArn != nil {
+		extra["branchArn"] = aws.ToString(branch.BranchArn)
+	}
+	if branch.Framework != nil && aws.ToString(branch.Framework) != "" {
+		extra["framework"] = aws.ToString(branch.Framework)
+	}
+	if branch.EnableAutoBuild != nil {
+		extra["autoBuild"] = aws.ToBool(branch.EnableAutoBuild)
+	}
+	if branch.ActiveJobId != nil {
+		extra["activeJobId"] = aws.ToString(branch.ActiveJobId)
+	}
+	if branch.UpdateTime != nil {
+		extra["updateTime"] = aws.ToTime(branch.UpdateTime)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
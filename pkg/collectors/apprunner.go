@@ -0,0 +1,193 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apprunner"
+	"github.com/aws/aws-sdk-go-v2/service/apprunner/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// AppRunnerCollector collects App Runner services
+type AppRunnerCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewAppRunnerCollector creates a new App Runner collector
+func NewAppRunnerCollector(clientManager *awspkg.ClientManager) *AppRunnerCollector {
+	return &AppRunnerCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *AppRunnerCollector) Name() string {
+	return "apprunner"
+}
+
+// Regions returns the regions this collector supports
+func (c *AppRunnerCollector) Regions() []string {
+	// App Runner is only available in some regions, unsupported ones are skipped in Collect
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *AppRunnerCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves App Runner services for the given region. In regions without App
+// Runner, the endpoint does not resolve and the region is recorded as skipped.
+func (c *AppRunnerCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := apprunner.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	var nextToken *string
+
+	// Auto scaling configurations are shared between services
+	autoScaling := make(map[string]*types.AutoScalingConfiguration)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &apprunner.ListServicesInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListServices(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list App Runner services in %s: %w", region, err)
+		}
+
+		for _, summary := range result.ServiceSummaryList {
+			service, err := c.getServiceInfo(ctx, client, aws.ToString(summary.ServiceArn))
+			if err != nil {
+				// Log error but continue with other services
+				models.Warnf(ctx, "failed to get info for App Runner service %s: %v", aws.ToString(summary.ServiceName), err)
+				continue
+			}
+
+			var scaling *types.AutoScalingConfiguration
+			if service.AutoScalingConfigurationSummary != nil {
+				scaling, err = c.getAutoScalingConfiguration(ctx, client, aws.ToString(service.AutoScalingConfigurationSummary.AutoScalingConfigurationArn), autoScaling)
+				if err != nil {
+					models.Warnf(ctx, "failed to get auto scaling configuration for App Runner service %s: %v", aws.ToString(summary.ServiceName), err)
+				}
+			}
+
+			resource := c.convertService(service, scaling, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getServiceInfo retrieves detailed information about an App Runner service
+func (c *AppRunnerCollector) getServiceInfo(ctx context.Context, client *apprunner.Client, serviceArn string) (*types.Service, error) {
+	result, err := client.DescribeService(ctx, &apprunner.DescribeServiceInput{
+		ServiceArn: aws.String(serviceArn),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Service == nil {
+		return nil, fmt.Errorf("service not found: %s", serviceArn)
+	}
+
+	return result.Service, nil
+}
+
+// getAutoScalingConfiguration retrieves an auto scaling configuration, using the cache when it was already described
+func (c *AppRunnerCollector) getAutoScalingConfiguration(ctx context.Context, client *apprunner.Client, arn string, cache map[string]*types.AutoScalingConfiguration) (*types.AutoScalingConfiguration, error) {
+	if scaling, ok := cache[arn]; ok {
+		return scaling, nil
+	}
+
+	result, err := client.DescribeAutoScalingConfiguration(ctx, &apprunner.DescribeAutoScalingConfigurationInput{
+		AutoScalingConfigurationArn: aws.String(arn),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cache[arn] = result.AutoScalingConfiguration
+	return result.AutoScalingConfiguration, nil
+}
+
+// convertService converts an App Runner service to a Resource
+func (c *AppRunnerCollector) convertService(service *types.Service, scaling *types.AutoScalingConfiguration, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "apprunner",
+		Region:    region,
+		ID:        aws.ToString(service.ServiceId),
+		Name:      aws.ToString(service.ServiceName),
+		Type:      "service",
+		State:     strings.ToLower(string(service.Status)),
+		CreatedAt: service.CreatedAt,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if service.ServiceArn != nil {
+		extra["serviceArn"] = aws.ToString(service.ServiceArn)
+	}
+	if service.ServiceUrl != nil {
+		extra["serviceUrl"] = aws.ToString(service.ServiceUrl)
+	}
+	if instance := service.InstanceConfiguration; instance != nil {
+		vcpu := parseTaskSize(aws.ToString(instance.Cpu)) / 1024
+		memoryGB := parseTaskSize(aws.ToString(instance.Memory)) / 1024
+		if vcpu > 0 {
+			extra["vcpu"] = vcpu
+		}
+		if memoryGB > 0 {
+			extra["memoryGB"] = memoryGB
+		}
+		if vcpu > 0 && memoryGB > 0 {
+			resource.Class = fmt.Sprintf("%gvCPU/%gGB", vcpu, memoryGB)
+		}
+	}
+	if summary := service.AutoScalingConfigurationSummary; summary != nil {
+		extra["autoScalingConfiguration"] = aws.ToString(summary.AutoScalingConfigurationName)
+	}
+	if scaling != nil {
+		if scaling.MinSize != nil {
+			extra["minSize"] = aws.ToInt32(scaling.MinSize)
+		}
+		if scaling.MaxSize != nil {
+			extra["maxSize"] = aws.ToInt32(scaling.MaxSize)
+		}
+		if scaling.MaxConcurrency != nil {
+			extra["maxConcurrency"] = aws.ToInt32(scaling.MaxConcurrency)
+		}
+	}
+	if source := service.SourceConfiguration; source != nil {
+		switch {
+		case source.ImageRepository != nil:
+			extra["sourceType"] = "image"
+		case source.CodeRepository != nil:
+			extra["sourceType"] = "code"
+		}
+		if source.AutoDeploymentsEnabled != nil {
+			extra["autoDeployments"] = aws.ToBool(source.AutoDeploymentsEnabled)
+		}
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
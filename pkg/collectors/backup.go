@@ -0,0 +1,389 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/backup"
+	"github.com/aws/aws-sdk-go-v2/service/backup/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// BackupCollector collects AWS Backup vaults, plans and protected resources
+type BackupCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewBackupCollector creates a new Backup collector
+func NewBackupCollector(clientManager *awspkg.ClientManager) *BackupCollector {
+	return &BackupCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *BackupCollector) Name() string {
+	return "backup"
+}
+
+// Regions returns the regions this collector supports
+func (c *BackupCollector) Regions() []string {
+	// AWS Backup is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *BackupCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// recoveryPointStats summarizes the recovery points stored in a vault
+type recoveryPointStats struct {
+	count          int
+	sizeBytes      int64
+	byResourceType map[string]int
+}
+
+// Collect retrieves backup vaults, plans and protected resources for the given region
+func (c *BackupCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := backup.NewFromConfig(cfg)
+
+	vaults, err := c.collectVaults(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := vaults
+
+	plans, err := c.collectPlans(ctx, client, region)
+	if err != nil {
+		models.Warnf(ctx, "failed to list backup plans in %s: %v", region, err)
+	} else {
+		resources = append(resources, plans...)
+	}
+
+	protected, err := c.collectProtectedResources(ctx, client, region)
+	if err != nil {
+		models.Warnf(ctx, "failed to list protected resources in %s: %v", region, err)
+	} else {
+		resources = append(resources, protected...)
+	}
+
+	return resources, nil
+}
+
+// collectVaults lists backup vaults with their recovery point totals
+func (c *BackupCollector) collectVaults(ctx context.Context, client *backup.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &backup.ListBackupVaultsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListBackupVaults(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list backup vaults in %s: %w", region, err)
+		}
+
+		for _, vault := range result.BackupVaultList {
+			stats, err := c.getRecoveryPointStats(ctx, client, aws.ToString(vault.BackupVaultName))
+			if err != nil {
+				// Log error but still report the vault
+				models.Warnf(ctx, "failed to list recovery points for vault %s: %v", aws.ToString(vault.BackupVaultName), err)
+			}
+			resource := c.convertVault(vault, stats, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getRecoveryPointStats counts and sizes the recovery points in a vault
+func (c *BackupCollector) getRecoveryPointStats(ctx context.Context, client *backup.Client, vaultName string) (*recoveryPointStats, error) {
+	stats := &recoveryPointStats{
+		byResourceType: make(map[string]int),
+	}
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &backup.ListRecoveryPointsByBackupVaultInput{
+			BackupVaultName: aws.String(vaultName),
+			NextToken:       nextToken,
+			MaxResults:      aws.Int32(1000),
+		}
+
+		result, err := client.ListRecoveryPointsByBackupVault(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, point := range result.RecoveryPoints {
+			stats.count++
+			stats.sizeBytes += aws.ToInt64(point.BackupSizeInBytes)
+			if point.ResourceType != nil {
+				stats.byResourceType[aws.ToString(point.ResourceType)]++
+			}
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return stats, nil
+}
+
+// collectPlans lists backup plans with their rules and selections
+func (c *BackupCollector) collectPlans(ctx context.Context, client *backup.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &backup.ListBackupPlansInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListBackupPlans(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, plan := range result.BackupPlansList {
+			details, err := client.GetBackupPlan(ctx, &backup.GetBackupPlanInput{
+				BackupPlanId: plan.BackupPlanId,
+			})
+			if err != nil {
+				models.Warnf(ctx, "failed to get info for backup plan %s: %v", aws.ToString(plan.BackupPlanName), err)
+				continue
+			}
+
+			selections, err := c.countSelections(ctx, client, aws.ToString(plan.BackupPlanId))
+			if err != nil {
+				models.Warnf(ctx, "failed to list selections for backup plan %s: %v", aws.ToString(plan.BackupPlanName), err)
+			}
+
+			resource := c.convertPlan(plan, details.BackupPlan, selections, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// countSelections counts the resource selections assigned to a backup plan
+func (c *BackupCollector) countSelections(ctx context.Context, client *backup.Client, planID string) (int, error) {
+	count := 0
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		result, err := client.ListBackupSelections(ctx, &backup.ListBackupSelectionsInput{
+			BackupPlanId: aws.String(planID),
+			NextToken:    nextToken,
+		})
+		if err != nil {
+			return count, err
+		}
+
+		count += len(result.BackupSelectionsList)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return count, nil
+}
+
+// collectProtectedResources lists the resources that have at least one recovery point
+func (c *BackupCollector) collectProtectedResources(ctx context.Context, client *backup.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &backup.ListProtectedResourcesInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListProtectedResources(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, protected := range result.Results {
+			resource := c.convertProtectedResource(protected, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertVault converts a backup vault to a Resource
+func (c *BackupCollector) convertVault(vault types.BackupVaultListMember, stats *recoveryPointStats, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "backup",
+		Region:    region,
+		ID:        aws.ToString(vault.BackupVaultName),
+		Name:      aws.ToString(vault.BackupVaultName),
+		Type:      "vault",
+		State:     "active",
+		Class:     string(vault.VaultType),
+		CreatedAt: vault.CreationDate,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["recoveryPoints"] = vault.NumberOfRecoveryPoints
+	if vault.BackupVaultArn != nil {
+		extra["backupVaultArn"] = aws.ToString(vault.BackupVaultArn)
+	}
+	if vault.EncryptionKeyArn != nil {
+		extra["encryptionKeyArn"] = aws.ToString(vault.EncryptionKeyArn)
+	}
+	if vault.Locked != nil {
+		extra["locked"] = aws.ToBool(vault.Locked)
+	}
+	if stats != nil {
+		extra["recoveryPoints"] = stats.count
+		extra["storageSizeGB"] = float64(stats.sizeBytes) / (1024 * 1024 * 1024)
+		if len(stats.byResourceType) > 0 {
+			extra["recoveryPointsByType"] = stats.byResourceType
+		}
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertPlan converts a backup plan to a Resource
+func (c *BackupCollector) convertPlan(plan types.BackupPlansListMember, details *types.BackupPlan, selections int, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "backup",
+		Region:    region,
+		ID:        aws.ToString(plan.BackupPlanId),
+		Name:      aws.ToString(plan.BackupPlanName),
+		Type:      "plan",
+		State:     "active",
+		CreatedAt: plan.CreationDate,
+	}
+
+	// A plan without selections backs up nothing
+	if selections == 0 {
+		resource.State = "unassigned"
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["selections"] = selections
+	if plan.BackupPlanArn != nil {
+		extra["backupPlanArn"] = aws.ToString(plan.BackupPlanArn)
+	}
+	if plan.LastExecutionDate != nil {
+		extra["lastExecutionDate"] = aws.ToTime(plan.LastExecutionDate)
+	}
+	if details != nil {
+		var rules []map[string]interface{}
+		for _, rule := range details.Rules {
+			ruleInfo := map[string]interface{}{
+				"name":        aws.ToString(rule.RuleName),
+				"targetVault": aws.ToString(rule.TargetBackupVaultName),
+			}
+			if rule.ScheduleExpression != nil {
+				ruleInfo["schedule"] = aws.ToString(rule.ScheduleExpression)
+			}
+			if rule.Lifecycle != nil {
+				if rule.Lifecycle.DeleteAfterDays != nil {
+					ruleInfo["deleteAfterDays"] = aws.ToInt64(rule.Lifecycle.DeleteAfterDays)
+				}
+				if rule.Lifecycle.MoveToColdStorageAfterDays != nil {
+					ruleInfo["coldStorageAfterDays"] = aws.ToInt64(rule.Lifecycle.MoveToColdStorageAfterDays)
+				}
+			}
+			rules = append(rules, ruleInfo)
+		}
+		extra["ruleCount"] = len(details.Rules)
+		if len(rules) > 0 {
+			extra["rules"] = rules
+		}
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertProtectedResource converts a protected resource to a Resource
+func (c *BackupCollector) convertProtectedResource(protected types.ProtectedResource, region string) models.Resource {
+	arn := aws.ToString(protected.ResourceArn)
+
+	resource := models.Resource{
+		Service: "backup",
+		Region:  region,
+		ID:      arn,
+		Name:    aws.ToString(protected.ResourceName),
+		Type:    "protected-resource",
+		State:   "protected",
+		Class:   aws.ToString(protected.ResourceType),
+	}
+	if resource.Name == "" {
+		resource.Name = arn
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["resourceArn"] = arn
+	if protected.LastBackupTime != nil {
+		extra["lastBackupTime"] = aws.ToTime(protected.LastBackupTime)
+	}
+	if protected.LastBackupVaultArn != nil {
+		extra["lastBackupVaultArn"] = aws.ToString(protected.LastBackupVaultArn)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
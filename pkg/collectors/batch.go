@@ -0,0 +1,183 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/batch"
+	"github.com/aws/aws-sdk-go-v2/service/batch/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// BatchCollector collects AWS Batch compute environments and job queues
+type BatchCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewBatchCollector creates a new Batch collector
+func NewBatchCollector(clientManager *awspkg.ClientManager) *BatchCollector {
+	return &BatchCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *BatchCollector) Name() string {
+	return "batch"
+}
+
+// Regions returns the regions this collector supports
+func (c *BatchCollector) Regions() []string {
+	// AWS Batch is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *BatchCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves compute environments and job queues for the given region
+func (c *BatchCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := batch.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &batch.DescribeComputeEnvironmentsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeComputeEnvironments(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe compute environments in %s: %w", region, err)
+		}
+
+		for _, environment := range result.ComputeEnvironments {
+			resource := c.convertComputeEnvironment(environment, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	nextToken = nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &batch.DescribeJobQueuesInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeJobQueues(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe job queues in %s: %w", region, err)
+		}
+
+		for _, queue := range result.JobQueues {
+			resource := c.convertJobQueue(queue, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertComputeEnvironment converts a Batch compute environment to a Resource
+func (c *BatchCollector) convertComputeEnvironment(environment types.ComputeEnvironmentDetail, region string) models.Resource {
+	resource := models.Resource{
+		Service: "batch",
+		Region:  region,
+		ID:      aws.ToString(environment.ComputeEnvironmentName),
+		Name:    aws.ToString(environment.ComputeEnvironmentName),
+		Type:    "compute-environment",
+		State:   strings.ToLower(string(environment.State)),
+		Class:   string(environment.Type),
+		Tags:    environment.Tags,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if environment.ComputeEnvironmentArn != nil {
+		extra["computeEnvironmentArn"] = aws.ToString(environment.ComputeEnvironmentArn)
+	}
+	if environment.Status != "" {
+		extra["status"] = string(environment.Status)
+	}
+	if compute := environment.ComputeResources; compute != nil {
+		// Managed environments report the capacity type, e.g. EC2, SPOT or FARGATE
+		resource.Class = string(compute.Type)
+		extra["computeType"] = string(compute.Type)
+		if compute.MinvCpus != nil {
+			extra["minVcpus"] = aws.ToInt32(compute.MinvCpus)
+		}
+		if compute.MaxvCpus != nil {
+			extra["maxVcpus"] = aws.ToInt32(compute.MaxvCpus)
+		}
+		if compute.DesiredvCpus != nil {
+			extra["desiredVcpus"] = aws.ToInt32(compute.DesiredvCpus)
+		}
+		if len(compute.InstanceTypes) > 0 {
+			extra["instanceTypes"] = compute.InstanceTypes
+		}
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertJobQueue converts a Batch job queue to a Resource
+func (c *BatchCollector) convertJobQueue(queue types.JobQueueDetail, region string) models.Resource {
+	resource := models.Resource{
+		Service: "batch",
+		Region:  region,
+		ID:      aws.ToString(queue.JobQueueName),
+		Name:    aws.ToString(queue.JobQueueName),
+		Type:    "job-queue",
+		State:   strings.ToLower(string(queue.State)),
+		Tags:    queue.Tags,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if queue.JobQueueArn != nil {
+		extra["jobQueueArn"] = aws.ToString(queue.JobQueueArn)
+	}
+	if queue.Status != "" {
+		extra["status"] = string(queue.Status)
+	}
+	if queue.Priority != nil {
+		extra["priority"] = aws.ToInt32(queue.Priority)
+	}
+	if len(queue.ComputeEnvironmentOrder) > 0 {
+		environments := make([]string, len(queue.ComputeEnvironmentOrder))
+		for i, order := range queue.ComputeEnvironmentOrder {
+			environments[i] = aws.ToString(order.ComputeEnvironment)
+		}
+		extra["computeEnvironments"] = environments
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
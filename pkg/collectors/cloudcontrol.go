@@ -0,0 +1,193 @@
+package collectors
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol"
+	"github.com/aws/aws-sdk-go-v2/service/cloudcontrol/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// cloudControlNameProperties lists the properties checked, in order, for a resource name
+var cloudControlNameProperties = []string{"Name", "DisplayName", "Alias"}
+
+// CloudControlCollector collects arbitrary resource types through the Cloud Control API.
+// It is a fallback for resource types that have no dedicated collector.
+type CloudControlCollector struct {
+	clientManager *awspkg.ClientManager
+	typeNames     []string
+}
+
+// NewCloudControlCollector creates a new Cloud Control collector for the given
+// CloudFormation resource type names (e.g. AWS::SQS::Queue)
+func NewCloudControlCollector(clientManager *awspkg.ClientManager, typeNames []string) *CloudControlCollector {
+	return &CloudControlCollector{
+		clientManager: clientManager,
+		typeNames:     typeNames,
+	}
+}
+
+// Name returns the service name
+func (c *CloudControlCollector) Name() string {
+	return "cloudcontrol"
+}
+
+// Regions returns the regions this collector supports
+func (c *CloudControlCollector) Regions() []string {
+	// Cloud Control is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *CloudControlCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves resources of the configured types for the given region
+func (c *CloudControlCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	if len(c.typeNames) == 0 {
+		return nil, nil
+	}
+
+	cfg := c.clientManager.GetConfig(region)
+	client := cloudcontrol.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	for _, typeName := range c.typeNames {
+		typeResources, err := c.listResources(ctx, client, typeName, region)
+		if err != nil {
+			// Log error but continue with other resource types
+			models.Warnf(ctx, "failed to list %s resources in %s: %v", typeName, region, err)
+			continue
+		}
+		resources = append(resources, typeResources...)
+	}
+
+	return resources, nil
+}
+
+// listResources retrieves all resources of a single type
+func (c *CloudControlCollector) listResources(ctx context.Context, client *cloudcontrol.Client, typeName, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &cloudcontrol.ListResourcesInput{
+			TypeName:  aws.String(typeName),
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListResources(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, description := range result.ResourceDescriptions {
+			resource := c.convertResource(ctx, description, typeName, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertResource converts a Cloud Control resource description to a Resource
+func (c *CloudControlCollector) convertResource(ctx context.Context, description types.ResourceDescription, typeName, region string) models.Resource {
+	identifier := aws.ToString(description.Identifier)
+
+	resource := models.Resource{
+		Service: "cloudcontrol",
+		Region:  region,
+		ID:      identifier,
+		Name:    identifier,
+		Type:    typeName,
+		State:   "active",
+		Class:   cloudControlServiceName(typeName),
+	}
+
+	// Properties are returned as a JSON document whose shape depends on the type
+	var properties map[string]interface{}
+	if description.Properties != nil {
+		if err := json.Unmarshal([]byte(aws.ToString(description.Properties)), &properties); err != nil {
+			models.Warnf(ctx, "failed to parse properties of %s %s: %v", typeName, identifier, err)
+		}
+	}
+
+	for _, key := range cloudControlNameProperties {
+		if name, ok := properties[key].(string); ok && name != "" {
+			resource.Name = name
+			break
+		}
+	}
+
+	// Extract tags
+	if tags := cloudControlTags(properties["Tags"]); len(tags) > 0 {
+		resource.Tags = tags
+		if name, exists := tags["Name"]; exists {
+			resource.Name = name
+		}
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if properties != nil {
+		delete(properties, "Tags")
+		extra["properties"] = properties
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// cloudControlServiceName returns the lowercase service part of a resource type name,
+// e.g. "sqs" for AWS::SQS::Queue
+func cloudControlServiceName(typeName string) string {
+	parts := strings.Split(typeName, "::")
+	if len(parts) < 2 {
+		return strings.ToLower(typeName)
+	}
+	return strings.ToLower(parts[1])
+}
+
+// cloudControlTags reads resource tags, which are either a list of Key/Value objects
+// or a plain map depending on the resource type
+func cloudControlTags(value interface{}) map[string]string {
+	tags := make(map[string]string)
+
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			tag, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			key, keyOk := tag["Key"].(string)
+			tagValue, valueOk := tag["Value"].(string)
+			if keyOk && valueOk {
+				tags[key] = tagValue
+			}
+		}
+	case map[string]interface{}:
+		for key, item := range v {
+			if tagValue, ok := item.(string); ok {
+				tags[key] = tagValue
+			}
+		}
+	}
+
+	return tags
+}
@@ -3,6 +3,8 @@ package collectors
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
@@ -11,7 +13,10 @@ import (
 	"github.com/xiaochen/awsinv/pkg/models"
 )
 
-// CloudWatchCollector collects CloudWatch alarms
+// freeDashboards is the number of dashboards per account covered by the free tier
+const freeDashboards = 3
+
+// CloudWatchCollector collects CloudWatch alarms, dashboards, metric streams and custom metrics
 type CloudWatchCollector struct {
 	clientManager *awspkg.ClientManager
 }
@@ -34,7 +39,14 @@ func (c *CloudWatchCollector) Regions() []string {
 	return nil // Will be populated by the orchestrator
 }
 
-// Collect retrieves CloudWatch alarms for the given region
+// Scope returns the scope of the collector's resources
+func (c *CloudWatchCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves CloudWatch alarms, metric streams and custom metrics for the given
+// region. Dashboards are account-wide, so they are only listed from us-east-1 and are
+// reported as global.
 func (c *CloudWatchCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
 	cfg := c.clientManager.GetConfig(region)
 	client := cloudwatch.NewFromConfig(cfg)
@@ -43,8 +55,15 @@ func (c *CloudWatchCollector) Collect(ctx context.Context, region string) ([]mod
 	var nextToken *string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Only metric alarms are returned unless composite alarms are asked for too
 		input := &cloudwatch.DescribeAlarmsInput{
-			NextToken: nextToken,
+			AlarmTypes: []types.AlarmType{types.AlarmTypeMetricAlarm, types.AlarmTypeCompositeAlarm},
+			MaxRecords: aws.Int32(100),
+			NextToken:  nextToken,
 		}
 
 		result, err := client.DescribeAlarms(ctx, input)
@@ -69,6 +88,197 @@ func (c *CloudWatchCollector) Collect(ctx context.Context, region string) ([]mod
 		}
 	}
 
+	if region == "us-east-1" {
+		dashboards, err := c.getDashboards(ctx, client)
+		if err != nil {
+			// Log error but continue with other resource types
+			models.Warnf(ctx, "failed to list dashboards: %v", err)
+		} else {
+			resources = append(resources, dashboards...)
+		}
+	}
+
+	streams, err := c.getMetricStreams(ctx, client, region)
+	if err != nil {
+		// Log error but continue with other resource types
+		models.Warnf(ctx, "failed to list metric streams in %s: %v", region, err)
+	} else {
+		resources = append(resources, streams...)
+	}
+
+	customMetrics, err := c.getCustomMetrics(ctx, client, region)
+	if err != nil {
+		// Log error but continue with other resource types
+		models.Warnf(ctx, "failed to list custom metrics in %s: %v", region, err)
+	} else {
+		resources = append(resources, customMetrics...)
+	}
+
+	return resources, nil
+}
+
+// cloudWatchTagArns are the extra fields holding the ARNs of the taggable resources
+var cloudWatchTagArns = map[string]string{
+	"metric-alarm":    "alarmArn",
+	"composite-alarm": "alarmArn",
+	"metric-stream":   "streamArn",
+}
+
+// AddTags looks up the tags of the alarms and metric streams
+func (c *CloudWatchCollector) AddTags(ctx context.Context, region string, resources []models.Resource) {
+	client := cloudwatch.NewFromConfig(c.clientManager.GetConfig(region))
+
+	taggable := func(resource models.Resource) bool {
+		_, ok := resource.Extra[cloudWatchTagArns[resource.Type]].(string)
+		return ok
+	}
+	fetchTags(ctx, resources, taggable, func(ctx context.Context, resource models.Resource) (map[string]string, error) {
+		arn, _ := resource.Extra[cloudWatchTagArns[resource.Type]].(string)
+		result, err := client.ListTagsForResource(ctx, &cloudwatch.ListTagsForResourceInput{
+			ResourceARN: aws.String(arn),
+		})
+		if err != nil {
+			return nil, err
+		}
+		tags := make(map[string]string, len(result.Tags))
+		for _, tag := range result.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		return tags, nil
+	})
+}
+
+// getDashboards retrieves the account's dashboards. The first dashboards by name are
+// marked as covered by the free tier, since only dashboards beyond that are billed.
+func (c *CloudWatchCollector) getDashboards(ctx context.Context, client *cloudwatch.Client) ([]models.Resource, error) {
+	var dashboards []types.DashboardEntry
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &cloudwatch.ListDashboardsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListDashboards(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		dashboards = append(dashboards, result.DashboardEntries...)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	sort.Slice(dashboards, func(i, j int) bool {
+		return aws.ToString(dashboards[i].DashboardName) < aws.ToString(dashboards[j].DashboardName)
+	})
+
+	var resources []models.Resource
+	for i, dashboard := range dashboards {
+		resource := c.convertDashboard(dashboard, i < freeDashboards, len(dashboards))
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// getMetricStreams retrieves the metric streams in a region
+func (c *CloudWatchCollector) getMetricStreams(ctx context.Context, client *cloudwatch.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &cloudwatch.ListMetricStreamsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListMetricStreams(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, stream := range result.Entries {
+			resource := c.convertMetricStream(stream, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getCustomMetrics counts custom metrics per namespace. Each metric (a unique
+// combination of name and dimensions) is billed, so one resource is reported per
+// namespace with its metric count. Namespaces starting with "AWS/" are free.
+func (c *CloudWatchCollector) getCustomMetrics(ctx context.Context, client *cloudwatch.Client, region string) ([]models.Resource, error) {
+	counts := make(map[string]int)
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &cloudwatch.ListMetricsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListMetrics(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, metric := range result.Metrics {
+			namespace := aws.ToString(metric.Namespace)
+			if namespace == "" || strings.HasPrefix(namespace, "AWS/") {
+				continue
+			}
+			counts[namespace]++
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	namespaces := make([]string, 0, len(counts))
+	for namespace := range counts {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+
+	var resources []models.Resource
+	for _, namespace := range namespaces {
+		resources = append(resources, models.Resource{
+			Service: "cloudwatch",
+			Region:  region,
+			ID:      namespace,
+			Name:    namespace,
+			Type:    "custom-metrics",
+			State:   "active",
+			Class:   "custom",
+			Extra: map[string]interface{}{
+				"namespace":   namespace,
+				"metricCount": counts[namespace],
+			},
+		})
+	}
+
 	return resources, nil
 }
 
@@ -82,10 +292,11 @@ func (c *CloudWatchCollector) convertAlarm(alarm types.MetricAlarm, region strin
 		Type:    "metric-alarm",
 		State:   string(alarm.StateValue),
 		Class:   "metric",
-	}
 
-	// Set creation time (CloudWatch doesn't provide creation time, so we'll use empty)
-	// resource.CreatedAt = nil
+		// CloudWatch doesn't report when alarms were created; the last configuration
+		// change is the closest, and the creation time of alarms never changed since
+		CreatedAt: alarm.AlarmConfigurationUpdatedTimestamp,
+	}
 
 	// Add extra information
 	extra := make(map[string]interface{})
@@ -147,6 +358,9 @@ func (c *CloudWatchCollector) convertCompositeAlarm(alarm types.CompositeAlarm,
 		Type:    "composite-alarm",
 		State:   string(alarm.StateValue),
 		Class:   "composite",
+
+		// Like metric alarms, composite alarms only report their last configuration change
+		CreatedAt: alarm.AlarmConfigurationUpdatedTimestamp,
 	}
 
 	// Add extra information
@@ -176,4 +390,65 @@ func (c *CloudWatchCollector) convertCompositeAlarm(alarm types.CompositeAlarm,
 	resource.Extra = extra
 
 	return resource
-} 
\ No newline at end of file
+}
+
+// convertDashboard converts a CloudWatch dashboard to a Resource
+func (c *CloudWatchCollector) convertDashboard(dashboard types.DashboardEntry, freeTier bool, dashboardCount int) models.Resource {
+	resource := models.Resource{
+		Service: "cloudwatch",
+		Region:  "global",
+		ID:      aws.ToString(dashboard.DashboardName),
+		Name:    aws.ToString(dashboard.DashboardName),
+		Type:    "dashboard",
+		State:   "active",
+		Class:   "dashboard",
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if dashboard.DashboardArn != nil {
+		extra["dashboardArn"] = aws.ToString(dashboard.DashboardArn)
+	}
+	if dashboard.LastModified != nil {
+		extra["lastModified"] = aws.ToTime(dashboard.LastModified)
+	}
+	if dashboard.Size != nil {
+		extra["sizeBytes"] = aws.ToInt64(dashboard.Size)
+	}
+	extra["freeTier"] = freeTier
+	extra["dashboardCount"] = dashboardCount
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertMetricStream converts a CloudWatch metric stream to a Resource
+func (c *CloudWatchCollector) convertMetricStream(stream types.MetricStreamEntry, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "cloudwatch",
+		Region:    region,
+		ID:        aws.ToString(stream.Name),
+		Name:      aws.ToString(stream.Name),
+		Type:      "metric-stream",
+		State:     aws.ToString(stream.State),
+		Class:     string(stream.OutputFormat),
+		CreatedAt: stream.CreationDate,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if stream.Arn != nil {
+		extra["streamArn"] = aws.ToString(stream.Arn)
+	}
+	if stream.FirehoseArn != nil {
+		extra["firehoseArn"] = aws.ToString(stream.FirehoseArn)
+	}
+	if stream.LastUpdateDate != nil {
+		extra["lastUpdated"] = aws.ToTime(stream.LastUpdateDate)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
@@ -0,0 +1,197 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codebuild"
+	"github.com/aws/aws-sdk-go-v2/service/codebuild/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// CodeBuildCollector collects CodeBuild projects
+type CodeBuildCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewCodeBuildCollector creates a new CodeBuild collector
+func NewCodeBuildCollector(clientManager *awspkg.ClientManager) *CodeBuildCollector {
+	return &CodeBuildCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *CodeBuildCollector) Name() string {
+	return "codebuild"
+}
+
+// Regions returns the regions this collector supports
+func (c *CodeBuildCollector) Regions() []string {
+	// CodeBuild is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *CodeBuildCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves CodeBuild projects for the given region
+func (c *CodeBuildCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := codebuild.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &codebuild.ListProjectsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListProjects(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list CodeBuild projects in %s: %w", region, err)
+		}
+
+		// ListProjects returns at most 100 names, which matches the BatchGetProjects limit
+		if len(result.Projects) > 0 {
+			projects, err := client.BatchGetProjects(ctx, &codebuild.BatchGetProjectsInput{
+				Names: result.Projects,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get CodeBuild projects in %s: %w", region, err)
+			}
+
+			lastBuilds := c.getLastBuilds(ctx, client, result.Projects)
+
+			for _, project := range projects.Projects {
+				lastBuild, known := lastBuilds[aws.ToString(project.Name)]
+				resource := c.convertProject(project, lastBuild, known, region)
+				resources = append(resources, resource)
+			}
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getLastBuilds retrieves the most recent build of each project, keyed by project name.
+// Projects that were never built map to nil; projects whose builds could not be
+// listed are left out.
+func (c *CodeBuildCollector) getLastBuilds(ctx context.Context, client *codebuild.Client, projectNames []string) map[string]*types.Build {
+	lastBuilds := make(map[string]*types.Build)
+	buildProjects := make(map[string]string)
+
+	var buildIDs []string
+	for _, name := range projectNames {
+		result, err := client.ListBuildsForProject(ctx, &codebuild.ListBuildsForProjectInput{
+			ProjectName: aws.String(name),
+			SortOrder:   types.SortOrderTypeDescending,
+		})
+		if err != nil {
+			models.Warnf(ctx, "failed to list builds for project %s: %v", name, err)
+			continue
+		}
+		if len(result.Ids) == 0 {
+			lastBuilds[name] = nil
+			continue
+		}
+		buildIDs = append(buildIDs, result.Ids[0])
+		buildProjects[result.Ids[0]] = name
+	}
+
+	if len(buildIDs) == 0 {
+		return lastBuilds
+	}
+
+	// One build per project keeps this within the 100 ID limit of BatchGetBuilds
+	result, err := client.BatchGetBuilds(ctx, &codebuild.BatchGetBuildsInput{
+		Ids: buildIDs,
+	})
+	if err != nil {
+		models.Warnf(ctx, "failed to get last builds: %v", err)
+		return lastBuilds
+	}
+
+	for i := range result.Builds {
+		build := result.Builds[i]
+		lastBuilds[buildProjects[aws.ToString(build.Id)]] = &build
+	}
+
+	return lastBuilds
+}
+
+// convertProject converts a CodeBuild project to a Resource
+func (c *CodeBuildCollector) convertProject(project types.Project, lastBuild *types.Build, known bool, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "codebuild",
+		Region:    region,
+		ID:        aws.ToString(project.Name),
+		Name:      aws.ToString(project.Name),
+		Type:      "project",
+		State:     "unknown",
+		CreatedAt: project.Created,
+	}
+
+	// Extract tags
+	if project.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range project.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if project.Arn != nil {
+		extra["projectArn"] = aws.ToString(project.Arn)
+	}
+	if project.Description != nil {
+		extra["description"] = aws.ToString(project.Description)
+	}
+	if env := project.Environment; env != nil {
+		resource.Class = string(env.ComputeType)
+		extra["computeType"] = string(env.ComputeType)
+		extra["environmentType"] = string(env.Type)
+		if env.Image != nil {
+			extra["image"] = aws.ToString(env.Image)
+		}
+	}
+	if project.Source != nil {
+		extra["sourceType"] = string(project.Source.Type)
+	}
+	if project.TimeoutInMinutes != nil {
+		extra["timeoutMinutes"] = aws.ToInt32(project.TimeoutInMinutes)
+	}
+	switch {
+	case lastBuild != nil:
+		resource.State = "active"
+		if lastBuild.StartTime != nil {
+			extra["lastBuildTime"] = aws.ToTime(lastBuild.StartTime)
+		}
+		extra["lastBuildStatus"] = strings.ToLower(string(lastBuild.BuildStatus))
+	case known:
+		resource.State = "never-built"
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
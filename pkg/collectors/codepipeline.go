@@ -0,0 +1,141 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline"
+	"github.com/aws/aws-sdk-go-v2/service/codepipeline/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// CodePipelineCollector collects CodePipeline pipelines
+type CodePipelineCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewCodePipelineCollector creates a new CodePipeline collector
+func NewCodePipelineCollector(clientManager *awspkg.ClientManager) *CodePipelineCollector {
+	return &CodePipelineCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *CodePipelineCollector) Name() string {
+	return "codepipeline"
+}
+
+// Regions returns the regions this collector supports
+func (c *CodePipelineCollector) Regions() []string {
+	// CodePipeline is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *CodePipelineCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves CodePipeline pipelines for the given region
+func (c *CodePipelineCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := codepipeline.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &codepipeline.ListPipelinesInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListPipelines(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pipelines in %s: %w", region, err)
+		}
+
+		for _, pipeline := range result.Pipelines {
+			lastExecution, err := c.getLastExecution(ctx, client, aws.ToString(pipeline.Name))
+			if err != nil {
+				// Log error but still report the pipeline
+				models.Warnf(ctx, "failed to get executions for pipeline %s: %v", aws.ToString(pipeline.Name), err)
+			}
+			resource := c.convertPipeline(pipeline, lastExecution, region)
+			if err != nil {
+				resource.State = "unknown"
+			}
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getLastExecution retrieves the most recent execution of a pipeline
+func (c *CodePipelineCollector) getLastExecution(ctx context.Context, client *codepipeline.Client, pipelineName string) (*types.PipelineExecutionSummary, error) {
+	result, err := client.ListPipelineExecutions(ctx, &codepipeline.ListPipelineExecutionsInput{
+		PipelineName: aws.String(pipelineName),
+		MaxResults:   aws.Int32(1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.PipelineExecutionSummaries) == 0 {
+		return nil, nil
+	}
+
+	return &result.PipelineExecutionSummaries[0], nil
+}
+
+// convertPipeline converts a CodePipeline pipeline to a Resource
+func (c *CodePipelineCollector) convertPipeline(pipeline types.PipelineSummary, lastExecution *types.PipelineExecutionSummary, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "codepipeline",
+		Region:    region,
+		ID:        aws.ToString(pipeline.Name),
+		Name:      aws.ToString(pipeline.Name),
+		Type:      "pipeline",
+		State:     "never-run",
+		Class:     string(pipeline.PipelineType),
+		CreatedAt: pipeline.Created,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if pipeline.Version != nil {
+		extra["version"] = aws.ToInt32(pipeline.Version)
+	}
+	if pipeline.ExecutionMode != "" {
+		extra["executionMode"] = string(pipeline.ExecutionMode)
+	}
+	if pipeline.Updated != nil {
+		extra["updated"] = aws.ToTime(pipeline.Updated)
+	}
+	if lastExecution != nil {
+		resource.State = strings.ToLower(string(lastExecution.Status))
+		if lastExecution.StartTime != nil {
+			extra["lastExecutionTime"] = aws.ToTime(lastExecution.StartTime)
+		}
+		if lastExecution.PipelineExecutionId != nil {
+			extra["lastExecutionId"] = aws.ToString(lastExecution.PipelineExecutionId)
+		}
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
@@ -0,0 +1,164 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/directconnect"
+	"github.com/aws/aws-sdk-go-v2/service/directconnect/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// DirectConnectCollector collects Direct Connect connections and virtual interfaces
+type DirectConnectCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewDirectConnectCollector creates a new Direct Connect collector
+func NewDirectConnectCollector(clientManager *awspkg.ClientManager) *DirectConnectCollector {
+	return &DirectConnectCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *DirectConnectCollector) Name() string {
+	return "directconnect"
+}
+
+// Regions returns the regions this collector supports
+func (c *DirectConnectCollector) Regions() []string {
+	// Direct Connect is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *DirectConnectCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves Direct Connect connections and virtual interfaces for the given region
+func (c *DirectConnectCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := directconnect.NewFromConfig(cfg)
+
+	var resources []models.Resource
+
+	// DescribeConnections is not paginated
+	connections, err := client.DescribeConnections(ctx, &directconnect.DescribeConnectionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Direct Connect connections in %s: %w", region, err)
+	}
+
+	for _, connection := range connections.Connections {
+		resource := c.convertConnection(connection, region)
+		resources = append(resources, resource)
+	}
+
+	// DescribeVirtualInterfaces is not paginated
+	interfaces, err := client.DescribeVirtualInterfaces(ctx, &directconnect.DescribeVirtualInterfacesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Direct Connect virtual interfaces in %s: %w", region, err)
+	}
+
+	for _, vif := range interfaces.VirtualInterfaces {
+		resource := c.convertVirtualInterface(vif, region)
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// convertConnection converts a Direct Connect connection to a Resource
+func (c *DirectConnectCollector) convertConnection(connection types.Connection, region string) models.Resource {
+	// Hosted connections are provisioned through a partner
+	connectionType := "dedicated"
+	if connection.PartnerName != nil {
+		connectionType = "hosted"
+	}
+
+	resource := models.Resource{
+		Service: "directconnect",
+		Region:  region,
+		ID:      aws.ToString(connection.ConnectionId),
+		Name:    aws.ToString(connection.ConnectionName),
+		Type:    "connection",
+		State:   string(connection.ConnectionState),
+		Class:   aws.ToString(connection.Bandwidth),
+		Tags:    convertDirectConnectTags(connection.Tags),
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["connectionType"] = connectionType
+	if connection.Bandwidth != nil {
+		extra["bandwidth"] = aws.ToString(connection.Bandwidth)
+	}
+	if connection.Location != nil {
+		extra["location"] = aws.ToString(connection.Location)
+	}
+	if connection.PartnerName != nil {
+		extra["partnerName"] = aws.ToString(connection.PartnerName)
+	}
+	if connection.ProviderName != nil {
+		extra["providerName"] = aws.ToString(connection.ProviderName)
+	}
+	if connection.LagId != nil {
+		extra["lagId"] = aws.ToString(connection.LagId)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertVirtualInterface converts a Direct Connect virtual interface to a Resource
+func (c *DirectConnectCollector) convertVirtualInterface(vif types.VirtualInterface, region string) models.Resource {
+	resource := models.Resource{
+		Service: "directconnect",
+		Region:  region,
+		ID:      aws.ToString(vif.VirtualInterfaceId),
+		Name:    aws.ToString(vif.VirtualInterfaceName),
+		Type:    "virtual-interface",
+		State:   string(vif.VirtualInterfaceState),
+		Class:   aws.ToString(vif.VirtualInterfaceType),
+		Tags:    convertDirectConnectTags(vif.Tags),
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["vlan"] = vif.Vlan
+	if vif.ConnectionId != nil {
+		extra["connectionId"] = aws.ToString(vif.ConnectionId)
+	}
+	if vif.DirectConnectGatewayId != nil {
+		extra["directConnectGatewayId"] = aws.ToString(vif.DirectConnectGatewayId)
+	}
+	if vif.VirtualGatewayId != nil {
+		extra["virtualGatewayId"] = aws.ToString(vif.VirtualGatewayId)
+	}
+	if vif.Mtu != nil {
+		extra["mtu"] = aws.ToInt32(vif.Mtu)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertDirectConnectTags converts Direct Connect tags to the standard format
+func convertDirectConnectTags(tags []types.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	result := make(map[string]string)
+	for _, tag := range tags {
+		if tag.Key != nil {
+			result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	return result
+}
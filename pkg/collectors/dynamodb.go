@@ -2,16 +2,19 @@ package collectors
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dax"
+	daxtypes "github.com/aws/aws-sdk-go-v2/service/dax/types"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	awspkg "github.com/xiaochen/awsinv/pkg/aws"
 	"github.com/xiaochen/awsinv/pkg/models"
 )
 
-// DynamoDBCollector collects DynamoDB tables
+// DynamoDBCollector collects DynamoDB tables and DAX clusters
 type DynamoDBCollector struct {
 	clientManager *awspkg.ClientManager
 }
@@ -34,7 +37,12 @@ func (c *DynamoDBCollector) Regions() []string {
 	return nil // Will be populated by the orchestrator
 }
 
-// Collect retrieves DynamoDB tables for the given region
+// Scope returns the scope of the collector's resources
+func (c *DynamoDBCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves DynamoDB tables and DAX clusters for the given region
 func (c *DynamoDBCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
 	cfg := c.clientManager.GetConfig(region)
 	client := dynamodb.NewFromConfig(cfg)
@@ -43,6 +51,10 @@ func (c *DynamoDBCollector) Collect(ctx context.Context, region string) ([]model
 	var lastEvaluatedTableName *string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		input := &dynamodb.ListTablesInput{
 			ExclusiveStartTableName: lastEvaluatedTableName,
 		}
@@ -57,7 +69,7 @@ func (c *DynamoDBCollector) Collect(ctx context.Context, region string) ([]model
 			tableInfo, err := c.getTableInfo(ctx, client, tableName)
 			if err != nil {
 				// Log error but continue with other tables
-				fmt.Printf("Warning: failed to get info for table %s: %v\n", tableName, err)
+				models.Warnf(ctx, "failed to get info for table %s: %v", tableName, err)
 				continue
 			}
 			resource := c.convertTable(tableInfo, region)
@@ -70,6 +82,124 @@ func (c *DynamoDBCollector) Collect(ctx context.Context, region string) ([]model
 		}
 	}
 
+	clusters, err := c.getDAXClusters(ctx, dax.NewFromConfig(cfg), region)
+	if err != nil {
+		// DAX is not offered in every region that has DynamoDB, and has no endpoint
+		// there. Other errors are logged, still reporting the tables.
+		if !errors.Is(c.clientManager.ClassifyRegionError(ctx, region, err), models.ErrRegionDisabled) {
+			models.Warnf(ctx, "failed to describe DAX clusters in %s: %v", region, err)
+		}
+	} else {
+		resources = append(resources, clusters...)
+	}
+
+	return resources, nil
+}
+
+// Describe retrieves a single DynamoDB table by name. DAX clusters are left to the caller.
+func (c *DynamoDBCollector) Describe(ctx context.Context, region, id string) (*models.Resource, interface{}, error) {
+	client := dynamodb.NewFromConfig(c.clientManager.GetConfig(region))
+
+	table, err := c.getTableInfo(ctx, client, id)
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to describe table %s in %s: %w", id, region, err)
+	}
+
+	resource := c.convertTable(table, region)
+	return &resource, table, nil
+}
+
+// AddTags looks up the tags of the tables and DAX clusters
+func (c *DynamoDBCollector) AddTags(ctx context.Context, region string, resources []models.Resource) {
+	cfg := c.clientManager.GetConfig(region)
+	client := dynamodb.NewFromConfig(cfg)
+	daxClient := dax.NewFromConfig(cfg)
+
+	isTable := func(resource models.Resource) bool {
+		return resource.Type == "table"
+	}
+	fetchTags(ctx, resources, isTable, func(ctx context.Context, resource models.Resource) (map[string]string, error) {
+		tableArn, _ := resource.Extra["tableArn"].(string)
+		tags := make(map[string]string)
+		var nextToken *string
+		for {
+			result, err := client.ListTagsOfResource(ctx, &dynamodb.ListTagsOfResourceInput{
+				ResourceArn: aws.String(tableArn),
+				NextToken:   nextToken,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, tag := range result.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			nextToken = result.NextToken
+			if nextToken == nil {
+				return tags, nil
+			}
+		}
+	})
+
+	isDAXCluster := func(resource models.Resource) bool {
+		return resource.Type == "dax-cluster"
+	}
+	fetchTags(ctx, resources, isDAXCluster, func(ctx context.Context, resource models.Resource) (map[string]string, error) {
+		clusterArn, _ := resource.Extra["clusterArn"].(string)
+		tags := make(map[string]string)
+		var nextToken *string
+		for {
+			result, err := daxClient.ListTags(ctx, &dax.ListTagsInput{
+				ResourceName: aws.String(clusterArn),
+				NextToken:    nextToken,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, tag := range result.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			nextToken = result.NextToken
+			if nextToken == nil {
+				return tags, nil
+			}
+		}
+	})
+}
+
+// getDAXClusters retrieves the DAX clusters in a region
+func (c *DynamoDBCollector) getDAXClusters(ctx context.Context, client *dax.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &dax.DescribeClustersInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeClusters(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range result.Clusters {
+			resource := c.convertDAXCluster(cluster, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
 	return resources, nil
 }
 
@@ -99,6 +229,17 @@ func (c *DynamoDBCollector) convertTable(table *types.TableDescription, region s
 		Class:   "table",
 	}
 
+	// Global tables list every replica, including the one in this region
+	var replicaRegions []string
+	for _, replica := range table.Replicas {
+		if replicaRegion := aws.ToString(replica.RegionName); replicaRegion != "" && replicaRegion != region {
+			replicaRegions = append(replicaRegions, replicaRegion)
+		}
+	}
+	if len(replicaRegions) > 0 {
+		resource.Class = "global-table"
+	}
+
 	// Set creation time
 	if table.CreationDateTime != nil {
 		createdAt := aws.ToTime(table.CreationDateTime)
@@ -119,8 +260,16 @@ func (c *DynamoDBCollector) convertTable(table *types.TableDescription, region s
 	if table.TableSizeBytes != nil {
 		extra["tableSizeBytes"] = aws.ToInt64(table.TableSizeBytes)
 	}
-	// Note: BillingMode is not available in this version of the SDK
-	// extra["billingMode"] = "unknown"
+	// Tables created before on-demand billing existed have no billing mode summary and
+	// are provisioned; tables without a table class are Standard
+	extra["billingMode"] = string(types.BillingModeProvisioned)
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode != "" {
+		extra["billingMode"] = string(table.BillingModeSummary.BillingMode)
+	}
+	extra["tableClass"] = string(types.TableClassStandard)
+	if table.TableClassSummary != nil && table.TableClassSummary.TableClass != "" {
+		extra["tableClass"] = string(table.TableClassSummary.TableClass)
+	}
 	if table.ProvisionedThroughput != nil {
 		extra["readCapacityUnits"] = aws.ToInt64(table.ProvisionedThroughput.ReadCapacityUnits)
 		extra["writeCapacityUnits"] = aws.ToInt64(table.ProvisionedThroughput.WriteCapacityUnits)
@@ -128,6 +277,18 @@ func (c *DynamoDBCollector) convertTable(table *types.TableDescription, region s
 	if table.GlobalSecondaryIndexes != nil {
 		extra["globalSecondaryIndexes"] = len(table.GlobalSecondaryIndexes)
 	}
+	// Global secondary indexes of provisioned tables have capacity of their own
+	var indexReadUnits, indexWriteUnits int64
+	for _, index := range table.GlobalSecondaryIndexes {
+		if index.ProvisionedThroughput != nil {
+			indexReadUnits += aws.ToInt64(index.ProvisionedThroughput.ReadCapacityUnits)
+			indexWriteUnits += aws.ToInt64(index.ProvisionedThroughput.WriteCapacityUnits)
+		}
+	}
+	if indexReadUnits > 0 || indexWriteUnits > 0 {
+		extra["indexReadCapacityUnits"] = indexReadUnits
+		extra["indexWriteCapacityUnits"] = indexWriteUnits
+	}
 	if table.LocalSecondaryIndexes != nil {
 		extra["localSecondaryIndexes"] = len(table.LocalSecondaryIndexes)
 	}
@@ -137,8 +298,57 @@ func (c *DynamoDBCollector) convertTable(table *types.TableDescription, region s
 	if table.SSEDescription != nil {
 		extra["encryptionType"] = string(table.SSEDescription.SSEType)
 	}
+	if len(replicaRegions) > 0 {
+		extra["replicaRegions"] = replicaRegions
+		extra["replicaCount"] = len(replicaRegions)
+		if table.GlobalTableVersion != nil {
+			extra["globalTableVersion"] = aws.ToString(table.GlobalTableVersion)
+		}
+	}
 
 	resource.Extra = extra
 
 	return resource
-} 
\ No newline at end of file
+}
+
+// convertDAXCluster converts a DAX cluster to a Resource
+func (c *DynamoDBCollector) convertDAXCluster(cluster daxtypes.Cluster, region string) models.Resource {
+	resource := models.Resource{
+		Service: "dynamodb",
+		Region:  region,
+		ID:      aws.ToString(cluster.ClusterName),
+		Name:    aws.ToString(cluster.ClusterName),
+		Type:    "dax-cluster",
+		State:   aws.ToString(cluster.Status),
+		Class:   aws.ToString(cluster.NodeType),
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if cluster.ClusterArn != nil {
+		extra["clusterArn"] = aws.ToString(cluster.ClusterArn)
+	}
+	if cluster.TotalNodes != nil {
+		extra["totalNodes"] = aws.ToInt32(cluster.TotalNodes)
+	}
+	if cluster.ActiveNodes != nil {
+		extra["activeNodes"] = aws.ToInt32(cluster.ActiveNodes)
+	}
+	if cluster.ClusterDiscoveryEndpoint != nil {
+		extra["endpoint"] = aws.ToString(cluster.ClusterDiscoveryEndpoint.Address)
+		extra["port"] = cluster.ClusterDiscoveryEndpoint.Port
+	}
+	if cluster.SubnetGroup != nil {
+		extra["subnetGroup"] = aws.ToString(cluster.SubnetGroup)
+	}
+	if cluster.SSEDescription != nil {
+		extra["encryptionStatus"] = string(cluster.SSEDescription.Status)
+	}
+	if cluster.ClusterEndpointEncryptionType != "" {
+		extra["endpointEncryption"] = string(cluster.ClusterEndpointEncryptionType)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
@@ -0,0 +1,232 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// EBSCollector collects EBS volumes and the snapshots the account owns
+type EBSCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewEBSCollector creates a new EBS collector
+func NewEBSCollector(clientManager *awspkg.ClientManager) *EBSCollector {
+	return &EBSCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *EBSCollector) Name() string {
+	return "ebs"
+}
+
+// Regions returns the regions this collector supports
+func (c *EBSCollector) Regions() []string {
+	// EBS is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *EBSCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves EBS volumes and snapshots for the given region
+func (c *EBSCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := ec2.NewFromConfig(cfg)
+
+	volumes, err := c.collectVolumes(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := c.collectSnapshots(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(volumes, snapshots...), nil
+}
+
+// collectVolumes lists the EBS volumes in a region
+func (c *EBSCollector) collectVolumes(ctx context.Context, client *ec2.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ec2.DescribeVolumesInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeVolumes(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe volumes in %s: %w", region, err)
+		}
+
+		for _, volume := range result.Volumes {
+			resource := c.convertVolume(volume, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// collectSnapshots lists the EBS snapshots the account owns in a region
+func (c *EBSCollector) collectSnapshots(ctx context.Context, client *ec2.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ec2.DescribeSnapshotsInput{
+			OwnerIds:  []string{"self"},
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeSnapshots(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe snapshots in %s: %w", region, err)
+		}
+
+		for _, snapshot := range result.Snapshots {
+			resource := c.convertSnapshot(snapshot, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertVolume converts an EBS volume to a Resource
+func (c *EBSCollector) convertVolume(volume types.Volume, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "ebs",
+		Region:    region,
+		ID:        aws.ToString(volume.VolumeId),
+		Name:      aws.ToString(volume.VolumeId),
+		Type:      "volume",
+		State:     string(volume.State),
+		Class:     string(volume.VolumeType),
+		CreatedAt: volume.CreateTime,
+	}
+
+	// Extract name from tags
+	if volume.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range volume.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["sizeGB"] = aws.ToInt32(volume.Size)
+	extra["volumeType"] = string(volume.VolumeType)
+	extra["encrypted"] = aws.ToBool(volume.Encrypted)
+	if volume.Iops != nil {
+		extra["iops"] = aws.ToInt32(volume.Iops)
+	}
+	if volume.Throughput != nil {
+		extra["throughput"] = aws.ToInt32(volume.Throughput)
+	}
+	if volume.AvailabilityZone != nil {
+		extra["availabilityZone"] = aws.ToString(volume.AvailabilityZone)
+	}
+	if volume.SnapshotId != nil && aws.ToString(volume.SnapshotId) != "" {
+		extra["snapshotId"] = aws.ToString(volume.SnapshotId)
+	}
+	var instanceIDs []string
+	for _, attachment := range volume.Attachments {
+		if attachment.InstanceId != nil {
+			instanceIDs = append(instanceIDs, aws.ToString(attachment.InstanceId))
+		}
+	}
+	if len(instanceIDs) > 0 {
+		extra["instanceIds"] = instanceIDs
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertSnapshot converts an EBS snapshot to a Resource
+func (c *EBSCollector) convertSnapshot(snapshot types.Snapshot, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "ebs",
+		Region:    region,
+		ID:        aws.ToString(snapshot.SnapshotId),
+		Name:      aws.ToString(snapshot.SnapshotId),
+		Type:      "snapshot",
+		State:     string(snapshot.State),
+		Class:     string(snapshot.StorageTier),
+		CreatedAt: snapshot.StartTime,
+	}
+
+	// Extract name from tags
+	if snapshot.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range snapshot.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["volumeSizeGB"] = aws.ToInt32(snapshot.VolumeSize)
+	extra["encrypted"] = aws.ToBool(snapshot.Encrypted)
+	if snapshot.VolumeId != nil {
+		extra["volumeId"] = aws.ToString(snapshot.VolumeId)
+	}
+	if snapshot.Description != nil {
+		extra["description"] = aws.ToString(snapshot.Description)
+		// Snapshots taken for an AMI are billed, and reported, with the image
+		if strings.HasPrefix(aws.ToString(snapshot.Description), "Created by CreateImage(") {
+			extra["imageSnapshot"] = true
+		}
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
@@ -3,6 +3,8 @@ package collectors
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
@@ -34,6 +36,11 @@ func (c *EC2Collector) Regions() []string {
 	return nil // Will be populated by the orchestrator
 }
 
+// Scope returns the scope of the collector's resources
+func (c *EC2Collector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
 // Collect retrieves EC2 instances for the given region
 func (c *EC2Collector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
 	cfg := c.clientManager.GetConfig(region)
@@ -43,6 +50,10 @@ func (c *EC2Collector) Collect(ctx context.Context, region string) ([]models.Res
 	var nextToken *string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		input := &ec2.DescribeInstancesInput{
 			NextToken: nextToken,
 		}
@@ -65,9 +76,195 @@ func (c *EC2Collector) Collect(ctx context.Context, region string) ([]models.Res
 		}
 	}
 
+	// Without the volumes only storage costs are missing, so a failure is a warning
+	if len(resources) > 0 {
+		volumes, err := c.listAttachedVolumes(ctx, client)
+		if err != nil {
+			models.Warnf(ctx, "failed to describe volumes in %s: %v", region, err)
+		} else {
+			for i := range resources {
+				addVolumes(&resources[i], volumes[resources[i].ID])
+			}
+		}
+	}
+
+	// Spot instances without a current price are estimated with a discount instead
+	if err := c.addSpotPrices(ctx, client, resources); err != nil {
+		models.Warnf(ctx, "failed to describe spot prices in %s: %v", region, err)
+	}
+
 	return resources, nil
 }
 
+// addSpotPrices records the current spot price of each running spot instance, for
+// its type, zone and platform
+func (c *EC2Collector) addSpotPrices(ctx context.Context, client *ec2.Client, resources []models.Resource) error {
+	typeSet := make(map[string]bool)
+	for _, resource := range resources {
+		if resource.Extra["lifecycle"] == "spot" && resource.State == "running" {
+			typeSet[resource.Type] = true
+		}
+	}
+	if len(typeSet) == 0 {
+		return nil
+	}
+
+	instanceTypes := make([]types.InstanceType, 0, len(typeSet))
+	for instanceType := range typeSet {
+		instanceTypes = append(instanceTypes, types.InstanceType(instanceType))
+	}
+
+	// Starting the history now returns the current price of each type, zone and product
+	prices := make(map[string]float64)
+	latest := make(map[string]time.Time)
+	now := time.Now()
+	var nextToken *string
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result, err := client.DescribeSpotPriceHistory(ctx, &ec2.DescribeSpotPriceHistoryInput{
+			InstanceTypes:       instanceTypes,
+			ProductDescriptions: []string{"Linux/UNIX", "Windows"},
+			StartTime:           aws.Time(now),
+			NextToken:           nextToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, price := range result.SpotPriceHistory {
+			value, err := strconv.ParseFloat(aws.ToString(price.SpotPrice), 64)
+			if err != nil {
+				continue
+			}
+			key := spotPriceKey(string(price.InstanceType), aws.ToString(price.AvailabilityZone), string(price.ProductDescription))
+			if timestamp := aws.ToTime(price.Timestamp); timestamp.After(latest[key]) {
+				latest[key] = timestamp
+				prices[key] = value
+			}
+		}
+
+		nextToken = result.NextToken
+		if aws.ToString(nextToken) == "" {
+			break
+		}
+	}
+
+	for i := range resources {
+		resource := &resources[i]
+		if resource.Extra["lifecycle"] != "spot" || resource.State != "running" {
+			continue
+		}
+		product := "Linux/UNIX"
+		if resource.Extra["platform"] == "windows" {
+			product = "Windows"
+		}
+		zone, _ := resource.Extra["availabilityZone"].(string)
+		if price, ok := prices[spotPriceKey(resource.Type, zone, product)]; ok {
+			resource.Extra["spotPrice"] = price
+		}
+	}
+
+	return nil
+}
+
+// spotPriceKey identifies a spot price by instance type, availability zone and product
+func spotPriceKey(instanceType, zone, product string) string {
+	return instanceType + "|" + zone + "|" + product
+}
+
+// listAttachedVolumes returns the EBS volumes of the region by the ID of the instance
+// they are attached to
+func (c *EC2Collector) listAttachedVolumes(ctx context.Context, client *ec2.Client) (map[string][]types.Volume, error) {
+	volumes := make(map[string][]types.Volume)
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+			Filters: []types.Filter{
+				{Name: aws.String("attachment.status"), Values: []string{"attached"}},
+			},
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, volume := range result.Volumes {
+			for _, attachment := range volume.Attachments {
+				instanceID := aws.ToString(attachment.InstanceId)
+				volumes[instanceID] = append(volumes[instanceID], volume)
+			}
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return volumes, nil
+}
+
+// addVolumes records an instance's EBS volumes and their total size
+func addVolumes(resource *models.Resource, volumes []types.Volume) {
+	if len(volumes) == 0 {
+		return
+	}
+
+	var totalGB int32
+	attached := make([]map[string]interface{}, 0, len(volumes))
+	for _, volume := range volumes {
+		entry := map[string]interface{}{
+			"id":         aws.ToString(volume.VolumeId),
+			"volumeType": string(volume.VolumeType),
+			"sizeGB":     aws.ToInt32(volume.Size),
+			"encrypted":  aws.ToBool(volume.Encrypted),
+		}
+		if volume.Iops != nil {
+			entry["iops"] = aws.ToInt32(volume.Iops)
+		}
+		if volume.Throughput != nil {
+			entry["throughput"] = aws.ToInt32(volume.Throughput)
+		}
+		attached = append(attached, entry)
+		totalGB += aws.ToInt32(volume.Size)
+	}
+
+	resource.Extra["volumes"] = attached
+	resource.Extra["storageSizeGB"] = totalGB
+}
+
+// Describe retrieves a single EC2 instance by ID
+func (c *EC2Collector) Describe(ctx context.Context, region, id string) (*models.Resource, interface{}, error) {
+	client := ec2.NewFromConfig(c.clientManager.GetConfig(region))
+
+	// Filtering by ID returns no reservations for an unknown instance, rather than an error
+	result, err := client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("instance-id"), Values: []string{id}},
+		},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to describe instance %s in %s: %w", id, region, err)
+	}
+
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			resource := c.convertInstance(instance, region)
+			return &resource, instance, nil
+		}
+	}
+
+	return nil, nil, nil
+}
+
 // convertInstance converts an EC2 instance to a Resource
 func (c *EC2Collector) convertInstance(instance types.Instance, region string) models.Resource {
 	resource := models.Resource{
@@ -103,6 +300,13 @@ func (c *EC2Collector) convertInstance(instance types.Instance, region string) m
 	if instance.Platform != "" {
 		extra["platform"] = string(instance.Platform)
 	}
+	if instance.Placement != nil && instance.Placement.AvailabilityZone != nil {
+		extra["availabilityZone"] = aws.ToString(instance.Placement.AvailabilityZone)
+	}
+	// On-demand instances have no lifecycle
+	if instance.InstanceLifecycle != "" {
+		extra["lifecycle"] = string(instance.InstanceLifecycle)
+	}
 	if instance.Architecture != "" {
 		extra["architecture"] = string(instance.Architecture)
 	}
@@ -124,6 +328,9 @@ func (c *EC2Collector) convertInstance(instance types.Instance, region string) m
 	if instance.KeyName != nil {
 		extra["keyName"] = aws.ToString(instance.KeyName)
 	}
+	if instance.VpcId != nil {
+		extra["vpcId"] = aws.ToString(instance.VpcId)
+	}
 
 	resource.Extra = extra
 
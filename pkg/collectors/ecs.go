@@ -3,6 +3,8 @@ package collectors
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ecs"
@@ -11,7 +13,13 @@ import (
 	"github.com/xiaochen/awsinv/pkg/models"
 )
 
-// ECSCollector collects ECS clusters and services
+// ecsClusterBatchSize is the most clusters DescribeClusters accepts per call
+const ecsClusterBatchSize = 100
+
+// ecsServiceBatchSize is the most services DescribeServices accepts per call
+const ecsServiceBatchSize = 10
+
+// ECSCollector collects ECS clusters, services, standalone tasks and task definitions
 type ECSCollector struct {
 	clientManager *awspkg.ClientManager
 }
@@ -34,7 +42,12 @@ func (c *ECSCollector) Regions() []string {
 	return nil // Will be populated by the orchestrator
 }
 
-// Collect retrieves ECS clusters and services for the given region
+// Scope returns the scope of the collector's resources
+func (c *ECSCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves ECS clusters, services, tasks and task definitions for the given region
 func (c *ECSCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
 	cfg := c.clientManager.GetConfig(region)
 	client := ecs.NewFromConfig(cfg)
@@ -42,8 +55,15 @@ func (c *ECSCollector) Collect(ctx context.Context, region string) ([]models.Res
 	var resources []models.Resource
 	var nextToken *string
 
+	// Task definitions are shared between services, so describe each one only once
+	taskDefinitions := make(map[string]*types.TaskDefinition)
+
 	// List clusters
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		input := &ecs.ListClustersInput{
 			NextToken: nextToken,
 		}
@@ -53,25 +73,33 @@ func (c *ECSCollector) Collect(ctx context.Context, region string) ([]models.Res
 			return nil, fmt.Errorf("failed to list clusters in %s: %w", region, err)
 		}
 
-		// Get detailed information for each cluster
-		for _, clusterArn := range result.ClusterArns {
-			clusterArnStr := aws.ToString(&clusterArn)
-			clusterInfo, err := c.getClusterInfo(ctx, client, clusterArnStr)
-			if err != nil {
-				// Log error but continue with other clusters
-				fmt.Printf("Warning: failed to get info for cluster %s: %v\n", clusterArnStr, err)
-				continue
-			}
-			resource := c.convertCluster(clusterInfo, region)
+		// Get detailed information for the clusters of the page
+		clusters, err := c.describeClusters(ctx, client, result.ClusterArns)
+		if err != nil {
+			// Log error but continue with the next page
+			models.Warnf(ctx, "failed to describe clusters in %s: %v", region, err)
+		}
+
+		for i := range clusters {
+			clusterArnStr := aws.ToString(clusters[i].ClusterArn)
+			resource := c.convertCluster(&clusters[i], region)
 			resources = append(resources, resource)
 
 			// Also collect services in this cluster
-			services, err := c.getClusterServices(ctx, client, clusterArnStr, region)
+			services, err := c.getClusterServices(ctx, client, clusterArnStr, taskDefinitions, region)
 			if err != nil {
-				fmt.Printf("Warning: failed to get services for cluster %s: %v\n", clusterArnStr, err)
+				models.Warnf(ctx, "failed to get services for cluster %s: %v", clusterArnStr, err)
 				continue
 			}
 			resources = append(resources, services...)
+
+			// Standalone tasks are not covered by any service
+			tasks, err := c.getStandaloneTasks(ctx, client, clusterArnStr, region)
+			if err != nil {
+				models.Warnf(ctx, "failed to get tasks for cluster %s: %v", clusterArnStr, err)
+				continue
+			}
+			resources = append(resources, tasks...)
 		}
 
 		nextToken = result.NextToken
@@ -80,36 +108,56 @@ func (c *ECSCollector) Collect(ctx context.Context, region string) ([]models.Res
 		}
 	}
 
+	definitions, err := c.getTaskDefinitions(ctx, client, taskDefinitions, region)
+	if err != nil {
+		models.Warnf(ctx, "failed to list task definitions in %s: %v", region, err)
+	} else {
+		resources = append(resources, definitions...)
+	}
+
 	return resources, nil
 }
 
-// getClusterInfo retrieves detailed information about an ECS cluster
-func (c *ECSCollector) getClusterInfo(ctx context.Context, client *ecs.Client, clusterArn string) (*types.Cluster, error) {
-	input := &ecs.DescribeClustersInput{
-		Clusters: []string{clusterArn},
-	}
+// describeClusters retrieves detailed information about ECS clusters, with their tags,
+// in batches. Clusters that cannot be described are left out with a warning.
+func (c *ECSCollector) describeClusters(ctx context.Context, client *ecs.Client, clusterArns []string) ([]types.Cluster, error) {
+	var clusters []types.Cluster
 
-	result, err := client.DescribeClusters(ctx, input)
-	if err != nil {
-		return nil, err
-	}
+	for start := 0; start < len(clusterArns); start += ecsClusterBatchSize {
+		if err := ctx.Err(); err != nil {
+			return clusters, err
+		}
+
+		end := min(start+ecsClusterBatchSize, len(clusterArns))
+		result, err := client.DescribeClusters(ctx, &ecs.DescribeClustersInput{
+			Clusters: clusterArns[start:end],
+			Include:  []types.ClusterField{types.ClusterFieldTags},
+		})
+		if err != nil {
+			return clusters, err
+		}
 
-	if len(result.Clusters) == 0 {
-		return nil, fmt.Errorf("cluster not found: %s", clusterArn)
+		warnECSFailures(ctx, "cluster", result.Failures)
+		clusters = append(clusters, result.Clusters...)
 	}
 
-	return &result.Clusters[0], nil
+	return clusters, nil
 }
 
 // getClusterServices retrieves services for a cluster
-func (c *ECSCollector) getClusterServices(ctx context.Context, client *ecs.Client, clusterArn string, region string) ([]models.Resource, error) {
+func (c *ECSCollector) getClusterServices(ctx context.Context, client *ecs.Client, clusterArn string, taskDefinitions map[string]*types.TaskDefinition, region string) ([]models.Resource, error) {
 	var resources []models.Resource
 	var nextToken *string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		input := &ecs.ListServicesInput{
-			Cluster:   aws.String(clusterArn),
-			NextToken: nextToken,
+			Cluster:    aws.String(clusterArn),
+			MaxResults: aws.Int32(100),
+			NextToken:  nextToken,
 		}
 
 		result, err := client.ListServices(ctx, input)
@@ -117,15 +165,23 @@ func (c *ECSCollector) getClusterServices(ctx context.Context, client *ecs.Clien
 			return nil, err
 		}
 
-		// Get detailed information for each service
-		for _, serviceArn := range result.ServiceArns {
-			serviceArnStr := aws.ToString(&serviceArn)
-			serviceInfo, err := c.getServiceInfo(ctx, client, serviceArnStr, clusterArn)
-			if err != nil {
-				fmt.Printf("Warning: failed to get info for service %s: %v\n", serviceArnStr, err)
-				continue
+		// Get detailed information for the services of the page
+		services, err := c.describeServices(ctx, client, clusterArn, result.ServiceArns)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range services {
+			serviceInfo := &services[i]
+			serviceArnStr := aws.ToString(serviceInfo.ServiceArn)
+			var taskDefinition *types.TaskDefinition
+			if serviceInfo.TaskDefinition != nil {
+				taskDefinition, err = c.getTaskDefinition(ctx, client, aws.ToString(serviceInfo.TaskDefinition), taskDefinitions)
+				if err != nil {
+					models.Warnf(ctx, "failed to get task definition for service %s: %v", serviceArnStr, err)
+				}
 			}
-			resource := c.convertService(serviceInfo, region)
+			resource := c.convertService(serviceInfo, taskDefinition, region)
 			resources = append(resources, resource)
 		}
 
@@ -138,23 +194,156 @@ func (c *ECSCollector) getClusterServices(ctx context.Context, client *ecs.Clien
 	return resources, nil
 }
 
-// getServiceInfo retrieves detailed information about an ECS service
-func (c *ECSCollector) getServiceInfo(ctx context.Context, client *ecs.Client, serviceArn string, clusterArn string) (*types.Service, error) {
-	input := &ecs.DescribeServicesInput{
-		Cluster:  aws.String(clusterArn),
-		Services: []string{serviceArn},
+// describeServices retrieves detailed information about the services of an ECS
+// cluster, with their tags, in batches. Services that cannot be described are left
+// out with a warning.
+func (c *ECSCollector) describeServices(ctx context.Context, client *ecs.Client, clusterArn string, serviceArns []string) ([]types.Service, error) {
+	var services []types.Service
+
+	for start := 0; start < len(serviceArns); start += ecsServiceBatchSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		end := min(start+ecsServiceBatchSize, len(serviceArns))
+		result, err := client.DescribeServices(ctx, &ecs.DescribeServicesInput{
+			Cluster:  aws.String(clusterArn),
+			Services: serviceArns[start:end],
+			Include:  []types.ServiceField{types.ServiceFieldTags},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		warnECSFailures(ctx, "service", result.Failures)
+		services = append(services, result.Services...)
+	}
+
+	return services, nil
+}
+
+// warnECSFailures reports the resources a describe call could not describe
+func warnECSFailures(ctx context.Context, kind string, failures []types.Failure) {
+	for _, failure := range failures {
+		models.Warnf(ctx, "failed to get info for %s %s: %s", kind, aws.ToString(failure.Arn), aws.ToString(failure.Reason))
+	}
+}
+
+// getTaskDefinition retrieves a task definition, using the cache when it was already described
+func (c *ECSCollector) getTaskDefinition(ctx context.Context, client *ecs.Client, taskDefinitionArn string, cache map[string]*types.TaskDefinition) (*types.TaskDefinition, error) {
+	if taskDefinition, ok := cache[taskDefinitionArn]; ok {
+		return taskDefinition, nil
 	}
 
-	result, err := client.DescribeServices(ctx, input)
+	result, err := client.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: aws.String(taskDefinitionArn),
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if len(result.Services) == 0 {
-		return nil, fmt.Errorf("service not found: %s", serviceArn)
+	if result.TaskDefinition == nil {
+		return nil, fmt.Errorf("task definition not found: %s", taskDefinitionArn)
+	}
+
+	cache[taskDefinitionArn] = result.TaskDefinition
+	if result.TaskDefinition.TaskDefinitionArn != nil {
+		cache[aws.ToString(result.TaskDefinition.TaskDefinitionArn)] = result.TaskDefinition
 	}
 
-	return &result.Services[0], nil
+	return result.TaskDefinition, nil
+}
+
+// getTaskDefinitions retrieves the latest active revision of every task definition family
+func (c *ECSCollector) getTaskDefinitions(ctx context.Context, client *ecs.Client, cache map[string]*types.TaskDefinition, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ecs.ListTaskDefinitionFamiliesInput{
+			Status:    types.TaskDefinitionFamilyStatusActive,
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListTaskDefinitionFamilies(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, family := range result.Families {
+			// Describing a family returns its latest active revision
+			taskDefinition, err := c.getTaskDefinition(ctx, client, family, cache)
+			if err != nil {
+				models.Warnf(ctx, "failed to get info for task definition %s: %v", family, err)
+				continue
+			}
+			resource := c.convertTaskDefinition(taskDefinition, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getStandaloneTasks retrieves running tasks in a cluster that were not started by a service
+func (c *ECSCollector) getStandaloneTasks(ctx context.Context, client *ecs.Client, clusterArn string, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ecs.ListTasksInput{
+			Cluster:       aws.String(clusterArn),
+			DesiredStatus: types.DesiredStatusRunning,
+			NextToken:     nextToken,
+		}
+
+		result, err := client.ListTasks(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		// ListTasks returns at most 100 ARNs, which matches the DescribeTasks limit
+		if len(result.TaskArns) > 0 {
+			tasks, err := client.DescribeTasks(ctx, &ecs.DescribeTasksInput{
+				Cluster: aws.String(clusterArn),
+				Tasks:   result.TaskArns,
+				Include: []types.TaskField{types.TaskFieldTags},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			warnECSFailures(ctx, "task", tasks.Failures)
+			for _, task := range tasks.Tasks {
+				// Service tasks are accounted for on the service itself
+				if strings.HasPrefix(aws.ToString(task.Group), "service:") {
+					continue
+				}
+				resource := c.convertTask(task, region)
+				resources = append(resources, resource)
+			}
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
 }
 
 // convertCluster converts an ECS cluster to a Resource
@@ -167,10 +356,10 @@ func (c *ECSCollector) convertCluster(cluster *types.Cluster, region string) mod
 		Type:    "cluster",
 		State:   aws.ToString(cluster.Status),
 		Class:   "cluster",
+		Tags:    ecsTags(cluster.Tags),
 	}
 
-	// Note: ECS clusters don't have a CreatedAt field in this version
-	// resource.CreatedAt = nil
+	// The ECS API does not report when clusters were created, so their age is unknown
 
 	// Add extra information
 	extra := make(map[string]interface{})
@@ -202,20 +391,19 @@ func (c *ECSCollector) convertCluster(cluster *types.Cluster, region string) mod
 }
 
 // convertService converts an ECS service to a Resource
-func (c *ECSCollector) convertService(service *types.Service, region string) models.Resource {
+func (c *ECSCollector) convertService(service *types.Service, taskDefinition *types.TaskDefinition, region string) models.Resource {
 	resource := models.Resource{
-		Service: "ecs",
-		Region:  region,
-		ID:      aws.ToString(service.ServiceName),
-		Name:    aws.ToString(service.ServiceName),
-		Type:    "service",
-		State:   aws.ToString(service.Status),
-		Class:   string(service.LaunchType),
+		Service:   "ecs",
+		Region:    region,
+		ID:        aws.ToString(service.ServiceName),
+		Name:      aws.ToString(service.ServiceName),
+		Type:      "service",
+		State:     aws.ToString(service.Status),
+		Class:     string(service.LaunchType),
+		CreatedAt: service.CreatedAt,
+		Tags:      ecsTags(service.Tags),
 	}
 
-	// Note: ECS services don't have a CreatedAt field in this version
-	// resource.CreatedAt = nil
-
 	// Add extra information
 	extra := make(map[string]interface{})
 	if service.ServiceArn != nil {
@@ -224,6 +412,9 @@ func (c *ECSCollector) convertService(service *types.Service, region string) mod
 	if service.ClusterArn != nil {
 		extra["clusterArn"] = aws.ToString(service.ClusterArn)
 	}
+	if service.CreatedBy != nil {
+		extra["createdBy"] = aws.ToString(service.CreatedBy)
+	}
 	if service.DesiredCount > 0 {
 		extra["desiredCount"] = service.DesiredCount
 	}
@@ -249,7 +440,204 @@ func (c *ECSCollector) convertService(service *types.Service, region string) mod
 		extra["serviceRegistries"] = len(service.ServiceRegistries)
 	}
 
+	// Record the Fargate footprint so costs can be computed from task size
+	fargate := isFargateService(service)
+	extra["fargate"] = fargate
+	if len(service.CapacityProviderStrategy) == 1 {
+		extra["capacityProvider"] = aws.ToString(service.CapacityProviderStrategy[0].CapacityProvider)
+	}
+	if taskDefinition != nil {
+		if taskDefinition.RuntimePlatform != nil && taskDefinition.RuntimePlatform.CpuArchitecture != "" {
+			extra["cpuArchitecture"] = string(taskDefinition.RuntimePlatform.CpuArchitecture)
+		}
+		vcpu, memoryGB := taskDefinitionSize(taskDefinition)
+		if vcpu > 0 {
+			extra["taskVcpu"] = vcpu
+		}
+		if memoryGB > 0 {
+			extra["taskMemoryGB"] = memoryGB
+		}
+		if fargate {
+			extra["fargateVcpu"] = vcpu * float64(service.DesiredCount)
+			extra["fargateMemoryGB"] = memoryGB * float64(service.DesiredCount)
+		}
+	}
+
 	resource.Extra = extra
 
 	return resource
-} 
\ No newline at end of file
+}
+
+// convertTaskDefinition converts an ECS task definition to a Resource
+func (c *ECSCollector) convertTaskDefinition(taskDefinition *types.TaskDefinition, region string) models.Resource {
+	family := aws.ToString(taskDefinition.Family)
+	id := fmt.Sprintf("%s:%d", family, taskDefinition.Revision)
+
+	resource := models.Resource{
+		Service:   "ecs",
+		Region:    region,
+		ID:        id,
+		Name:      family,
+		Type:      "task-definition",
+		State:     strings.ToLower(string(taskDefinition.Status)),
+		CreatedAt: taskDefinition.RegisteredAt,
+	}
+
+	// Class is the launch type the definition is compatible with
+	var compatibilities []string
+	for _, compatibility := range taskDefinition.RequiresCompatibilities {
+		compatibilities = append(compatibilities, string(compatibility))
+	}
+	resource.Class = strings.Join(compatibilities, ",")
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["family"] = family
+	extra["revision"] = taskDefinition.Revision
+	vcpu, memoryGB := taskDefinitionSize(taskDefinition)
+	if vcpu > 0 {
+		extra["vcpu"] = vcpu
+	}
+	if memoryGB > 0 {
+		extra["memoryGB"] = memoryGB
+	}
+	if taskDefinition.TaskDefinitionArn != nil {
+		extra["taskDefinitionArn"] = aws.ToString(taskDefinition.TaskDefinitionArn)
+	}
+	if taskDefinition.NetworkMode != "" {
+		extra["networkMode"] = string(taskDefinition.NetworkMode)
+	}
+	if taskDefinition.RuntimePlatform != nil && taskDefinition.RuntimePlatform.CpuArchitecture != "" {
+		extra["cpuArchitecture"] = string(taskDefinition.RuntimePlatform.CpuArchitecture)
+	}
+	extra["containers"] = len(taskDefinition.ContainerDefinitions)
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertTask converts a standalone ECS task to a Resource
+func (c *ECSCollector) convertTask(task types.Task, region string) models.Resource {
+	// Task IDs are the last segment of the task ARN
+	taskArn := aws.ToString(task.TaskArn)
+	id := taskArn[strings.LastIndex(taskArn, "/")+1:]
+
+	resource := models.Resource{
+		Service:   "ecs",
+		Region:    region,
+		ID:        id,
+		Name:      id,
+		Type:      "task",
+		State:     strings.ToLower(aws.ToString(task.LastStatus)),
+		Class:     string(task.LaunchType),
+		CreatedAt: task.CreatedAt,
+		Tags:      ecsTags(task.Tags),
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["taskArn"] = taskArn
+	if task.ClusterArn != nil {
+		extra["clusterArn"] = aws.ToString(task.ClusterArn)
+	}
+	if task.TaskDefinitionArn != nil {
+		extra["taskDefinition"] = aws.ToString(task.TaskDefinitionArn)
+	}
+	if task.Group != nil {
+		extra["group"] = aws.ToString(task.Group)
+	}
+	if task.StartedBy != nil {
+		extra["startedBy"] = aws.ToString(task.StartedBy)
+	}
+	if task.StartedAt != nil {
+		extra["startedAt"] = aws.ToTime(task.StartedAt)
+	}
+
+	// Tasks started with a capacity provider strategy have no launch type
+	provider := aws.ToString(task.CapacityProviderName)
+	fargate := task.LaunchType == types.LaunchTypeFargate || provider == "FARGATE" || provider == "FARGATE_SPOT"
+	extra["fargate"] = fargate
+	if provider != "" {
+		extra["capacityProvider"] = provider
+	}
+	for _, attribute := range task.Attributes {
+		if aws.ToString(attribute.Name) == "ecs.cpu-architecture" {
+			extra["cpuArchitecture"] = strings.ToUpper(aws.ToString(attribute.Value))
+		}
+	}
+	vcpu := parseTaskSize(aws.ToString(task.Cpu)) / 1024
+	memoryGB := parseTaskSize(aws.ToString(task.Memory)) / 1024
+	if vcpu > 0 {
+		extra["taskVcpu"] = vcpu
+	}
+	if memoryGB > 0 {
+		extra["taskMemoryGB"] = memoryGB
+	}
+	if fargate {
+		extra["fargateVcpu"] = vcpu
+		extra["fargateMemoryGB"] = memoryGB
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// ecsTags converts ECS tags, which the describe calls include on request, to a map
+func ecsTags(tags []types.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	tagMap := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		tagMap[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tagMap
+}
+
+// isFargateService reports whether a service runs its tasks on Fargate
+func isFargateService(service *types.Service) bool {
+	if service.LaunchType == types.LaunchTypeFargate {
+		return true
+	}
+	for _, strategy := range service.CapacityProviderStrategy {
+		provider := aws.ToString(strategy.CapacityProvider)
+		if provider == "FARGATE" || provider == "FARGATE_SPOT" {
+			return true
+		}
+	}
+	return false
+}
+
+// taskDefinitionSize returns the task-level vCPU and memory (GB) of a task definition
+func taskDefinitionSize(taskDefinition *types.TaskDefinition) (float64, float64) {
+	cpuUnits := parseTaskSize(aws.ToString(taskDefinition.Cpu))
+	memoryMiB := parseTaskSize(aws.ToString(taskDefinition.Memory))
+	return cpuUnits / 1024, memoryMiB / 1024
+}
+
+// parseTaskSize parses an ECS CPU or memory value. Values are usually plain
+// units ("256", "512") but can also be given as "0.25 vCPU" or "1 GB".
+func parseTaskSize(value string) float64 {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == "" {
+		return 0
+	}
+
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(value, "vcpu"):
+		value = strings.TrimSpace(strings.TrimSuffix(value, "vcpu"))
+		multiplier = 1024
+	case strings.HasSuffix(value, "gb"):
+		value = strings.TrimSpace(strings.TrimSuffix(value, "gb"))
+		multiplier = 1024
+	}
+
+	size, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	return size * multiplier
+}
@@ -32,6 +32,11 @@ func (c *EFSCollector) Regions() []string {
 	return nil // EFS is available in all regions
 }
 
+// Scope returns the scope of the collector's resources
+func (c *EFSCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
 // Collect discovers EFS file systems in the specified region
 func (c *EFSCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
 	client, err := c.clientManager.GetEFSClient(ctx, region)
@@ -44,6 +49,10 @@ func (c *EFSCollector) Collect(ctx context.Context, region string) ([]models.Res
 	// List file systems
 	paginator := efs.NewDescribeFileSystemsPaginator(client, &efs.DescribeFileSystemsInput{})
 	for paginator.HasMorePages() {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		page, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to describe file systems: %w", err)
@@ -62,7 +71,7 @@ func (c *EFSCollector) Collect(ctx context.Context, region string) ([]models.Res
 				Tags:      convertEFSTags(fs.Tags),
 				Extra: map[string]interface{}{
 					"sizeBytes":        fs.SizeInBytes,
-					"encrypted":        fs.Encrypted,
+					"encrypted":        fs.Encrypted != nil && *fs.Encrypted,
 					"kmsKeyId":         fs.KmsKeyId,
 					"availabilityZone": fs.AvailabilityZoneId,
 				},
@@ -0,0 +1,127 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// EIPCollector collects Elastic IP addresses
+type EIPCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewEIPCollector creates a new Elastic IP collector
+func NewEIPCollector(clientManager *awspkg.ClientManager) *EIPCollector {
+	return &EIPCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *EIPCollector) Name() string {
+	return "eip"
+}
+
+// Regions returns the regions this collector supports
+func (c *EIPCollector) Regions() []string {
+	// Elastic IPs are available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *EIPCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves Elastic IP addresses for the given region
+func (c *EIPCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := ec2.NewFromConfig(cfg)
+
+	var resources []models.Resource
+
+	// DescribeAddresses is not paginated
+	result, err := client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe addresses in %s: %w", region, err)
+	}
+
+	for _, address := range result.Addresses {
+		resource := c.convertAddress(address, region)
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// convertAddress converts an Elastic IP address to a Resource
+func (c *EIPCollector) convertAddress(address types.Address, region string) models.Resource {
+	id := aws.ToString(address.AllocationId)
+	if id == "" {
+		id = aws.ToString(address.PublicIp)
+	}
+
+	// An address without an association is billed while doing nothing
+	state := "associated"
+	if address.AssociationId == nil && address.InstanceId == nil && address.NetworkInterfaceId == nil {
+		state = "unassociated"
+	}
+
+	resource := models.Resource{
+		Service: "eip",
+		Region:  region,
+		ID:      id,
+		Name:    aws.ToString(address.PublicIp),
+		Type:    "elastic-ip",
+		State:   state,
+		Class:   string(address.Domain),
+	}
+
+	// Extract name from tags
+	if address.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range address.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if address.PublicIp != nil {
+		extra["publicIp"] = aws.ToString(address.PublicIp)
+	}
+	if address.PrivateIpAddress != nil {
+		extra["privateIp"] = aws.ToString(address.PrivateIpAddress)
+	}
+	if address.AssociationId != nil {
+		extra["associationId"] = aws.ToString(address.AssociationId)
+	}
+	if address.InstanceId != nil {
+		extra["instanceId"] = aws.ToString(address.InstanceId)
+	}
+	if address.NetworkInterfaceId != nil {
+		extra["networkInterfaceId"] = aws.ToString(address.NetworkInterfaceId)
+	}
+	if address.NetworkBorderGroup != nil {
+		extra["networkBorderGroup"] = aws.ToString(address.NetworkBorderGroup)
+	}
+	if address.PublicIpv4Pool != nil {
+		extra["publicIpv4Pool"] = aws.ToString(address.PublicIpv4Pool)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
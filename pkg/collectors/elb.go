@@ -0,0 +1,241 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	elb "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// ELBCollector collects Application, Network and Gateway Load Balancers and their
+// target groups
+type ELBCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewELBCollector creates a new load balancer collector
+func NewELBCollector(clientManager *awspkg.ClientManager) *ELBCollector {
+	return &ELBCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *ELBCollector) Name() string {
+	return "elb"
+}
+
+// Regions returns the regions this collector supports
+func (c *ELBCollector) Regions() []string {
+	// Elastic Load Balancing is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *ELBCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves load balancers and target groups for the given region
+func (c *ELBCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := elb.NewFromConfig(cfg)
+
+	loadBalancers, err := c.collectLoadBalancers(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	targetGroups, err := c.collectTargetGroups(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(loadBalancers, targetGroups...), nil
+}
+
+// elbTagBatchSize is the most resources DescribeTags accepts per call
+const elbTagBatchSize = 20
+
+// AddTags looks up the tags of the load balancers and target groups, in batches
+func (c *ELBCollector) AddTags(ctx context.Context, region string, resources []models.Resource) {
+	client := elb.NewFromConfig(c.clientManager.GetConfig(region))
+
+	// Both are identified by ARN
+	byArn := make(map[string]int, len(resources))
+	var arns []string
+	for i, resource := range resources {
+		if resource.Tags == nil {
+			byArn[resource.ID] = i
+			arns = append(arns, resource.ID)
+		}
+	}
+
+	for start := 0; start < len(arns); start += elbTagBatchSize {
+		if err := ctx.Err(); err != nil {
+			return
+		}
+
+		end := min(start+elbTagBatchSize, len(arns))
+		result, err := client.DescribeTags(ctx, &elb.DescribeTagsInput{
+			ResourceArns: arns[start:end],
+		})
+		if err != nil {
+			models.Warnf(ctx, "failed to get the tags of load balancers in %s: %v", region, err)
+			return
+		}
+
+		for _, description := range result.TagDescriptions {
+			i, exists := byArn[aws.ToString(description.ResourceArn)]
+			if !exists || len(description.Tags) == 0 {
+				continue
+			}
+			tags := make(map[string]string, len(description.Tags))
+			for _, tag := range description.Tags {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+			}
+			resources[i].Tags = tags
+		}
+	}
+}
+
+// collectLoadBalancers lists the load balancers in a region
+func (c *ELBCollector) collectLoadBalancers(ctx context.Context, client *elb.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var marker *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &elb.DescribeLoadBalancersInput{
+			Marker: marker,
+		}
+
+		result, err := client.DescribeLoadBalancers(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe load balancers in %s: %w", region, err)
+		}
+
+		for _, loadBalancer := range result.LoadBalancers {
+			resource := c.convertLoadBalancer(loadBalancer, region)
+			resources = append(resources, resource)
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// collectTargetGroups lists the target groups in a region
+func (c *ELBCollector) collectTargetGroups(ctx context.Context, client *elb.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var marker *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &elb.DescribeTargetGroupsInput{
+			Marker: marker,
+		}
+
+		result, err := client.DescribeTargetGroups(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe target groups in %s: %w", region, err)
+		}
+
+		for _, targetGroup := range result.TargetGroups {
+			resource := c.convertTargetGroup(targetGroup, region)
+			resources = append(resources, resource)
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertLoadBalancer converts a load balancer to a Resource. Load balancers are
+// identified by ARN, which target groups refer to them by.
+func (c *ELBCollector) convertLoadBalancer(loadBalancer types.LoadBalancer, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "elb",
+		Region:    region,
+		ID:        aws.ToString(loadBalancer.LoadBalancerArn),
+		Name:      aws.ToString(loadBalancer.LoadBalancerName),
+		Type:      string(loadBalancer.Type),
+		Class:     string(loadBalancer.Scheme),
+		CreatedAt: loadBalancer.CreatedTime,
+	}
+	if loadBalancer.State != nil {
+		resource.State = string(loadBalancer.State.Code)
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if loadBalancer.VpcId != nil {
+		extra["vpcId"] = aws.ToString(loadBalancer.VpcId)
+	}
+	if loadBalancer.DNSName != nil {
+		extra["dnsName"] = aws.ToString(loadBalancer.DNSName)
+	}
+	var zones []string
+	for _, zone := range loadBalancer.AvailabilityZones {
+		if zone.ZoneName != nil {
+			zones = append(zones, aws.ToString(zone.ZoneName))
+		}
+	}
+	if len(zones) > 0 {
+		extra["availabilityZones"] = zones
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertTargetGroup converts a target group to a Resource
+func (c *ELBCollector) convertTargetGroup(targetGroup types.TargetGroup, region string) models.Resource {
+	resource := models.Resource{
+		Service: "elb",
+		Region:  region,
+		ID:      aws.ToString(targetGroup.TargetGroupArn),
+		Name:    aws.ToString(targetGroup.TargetGroupName),
+		Type:    "target-group",
+		State:   "available", // Target groups have no state
+		Class:   string(targetGroup.TargetType),
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if targetGroup.VpcId != nil {
+		extra["vpcId"] = aws.ToString(targetGroup.VpcId)
+	}
+	if targetGroup.Protocol != "" {
+		extra["protocol"] = string(targetGroup.Protocol)
+	}
+	if targetGroup.Port != nil {
+		extra["port"] = aws.ToInt32(targetGroup.Port)
+	}
+	if len(targetGroup.LoadBalancerArns) > 0 {
+		extra["loadBalancerArns"] = targetGroup.LoadBalancerArns
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
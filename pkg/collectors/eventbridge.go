@@ -0,0 +1,317 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	ebtypes "github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"github.com/aws/aws-sdk-go-v2/service/scheduler"
+	schedtypes "github.com/aws/aws-sdk-go-v2/service/scheduler/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// EventBridgeCollector collects EventBridge event buses, rules and Scheduler schedules
+type EventBridgeCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewEventBridgeCollector creates a new EventBridge collector
+func NewEventBridgeCollector(clientManager *awspkg.ClientManager) *EventBridgeCollector {
+	return &EventBridgeCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *EventBridgeCollector) Name() string {
+	return "eventbridge"
+}
+
+// Regions returns the regions this collector supports
+func (c *EventBridgeCollector) Regions() []string {
+	// EventBridge is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *EventBridgeCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves event buses, rules and schedules for the given region
+func (c *EventBridgeCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := eventbridge.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &eventbridge.ListEventBusesInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListEventBuses(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list event buses in %s: %w", region, err)
+		}
+
+		for _, bus := range result.EventBuses {
+			resource := c.convertEventBus(bus, region)
+			resources = append(resources, resource)
+
+			// Also collect rules on this bus
+			rules, err := c.getBusRules(ctx, client, aws.ToString(bus.Name), region)
+			if err != nil {
+				models.Warnf(ctx, "failed to get rules for event bus %s: %v", aws.ToString(bus.Name), err)
+				continue
+			}
+			resources = append(resources, rules...)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	schedules, err := c.getSchedules(ctx, scheduler.NewFromConfig(cfg), region)
+	if err != nil {
+		models.Warnf(ctx, "failed to list schedules in %s: %v", region, err)
+	} else {
+		resources = append(resources, schedules...)
+	}
+
+	return resources, nil
+}
+
+// getBusRules retrieves the rules on an event bus with their targets
+func (c *EventBridgeCollector) getBusRules(ctx context.Context, client *eventbridge.Client, busName string, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &eventbridge.ListRulesInput{
+			EventBusName: aws.String(busName),
+			NextToken:    nextToken,
+		}
+
+		result, err := client.ListRules(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rule := range result.Rules {
+			targets, err := c.getRuleTargets(ctx, client, aws.ToString(rule.Name), busName)
+			if err != nil {
+				models.Warnf(ctx, "failed to get targets for rule %s: %v", aws.ToString(rule.Name), err)
+			}
+			resource := c.convertRule(rule, targets, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getRuleTargets retrieves the target ARNs of a rule
+func (c *EventBridgeCollector) getRuleTargets(ctx context.Context, client *eventbridge.Client, ruleName string, busName string) ([]string, error) {
+	var targets []string
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &eventbridge.ListTargetsByRuleInput{
+			Rule:         aws.String(ruleName),
+			EventBusName: aws.String(busName),
+			NextToken:    nextToken,
+		}
+
+		result, err := client.ListTargetsByRule(ctx, input)
+		if err != nil {
+			return targets, err
+		}
+
+		for _, target := range result.Targets {
+			targets = append(targets, aws.ToString(target.Arn))
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return targets, nil
+}
+
+// getSchedules retrieves EventBridge Scheduler schedules
+func (c *EventBridgeCollector) getSchedules(ctx context.Context, client *scheduler.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &scheduler.ListSchedulesInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListSchedules(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, schedule := range result.Schedules {
+			resource := c.convertSchedule(schedule, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertEventBus converts an event bus to a Resource
+func (c *EventBridgeCollector) convertEventBus(bus ebtypes.EventBus, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "eventbridge",
+		Region:    region,
+		ID:        aws.ToString(bus.Name),
+		Name:      aws.ToString(bus.Name),
+		Type:      "event-bus",
+		State:     "active",
+		Class:     "custom",
+		CreatedAt: bus.CreationTime,
+	}
+
+	if aws.ToString(bus.Name) == "default" {
+		resource.Class = "default"
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if bus.Arn != nil {
+		extra["eventBusArn"] = aws.ToString(bus.Arn)
+	}
+	if bus.Description != nil {
+		extra["description"] = aws.ToString(bus.Description)
+	}
+	extra["hasPolicy"] = bus.Policy != nil
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertRule converts an EventBridge rule to a Resource
+func (c *EventBridgeCollector) convertRule(rule ebtypes.Rule, targets []string, region string) models.Resource {
+	// Rules are only unique per bus
+	busName := aws.ToString(rule.EventBusName)
+	id := aws.ToString(rule.Name)
+	if busName != "" && busName != "default" {
+		id = busName + "/" + id
+	}
+
+	state := "enabled"
+	if rule.State == ebtypes.RuleStateDisabled {
+		state = "disabled"
+	}
+
+	resource := models.Resource{
+		Service: "eventbridge",
+		Region:  region,
+		ID:      id,
+		Name:    aws.ToString(rule.Name),
+		Type:    "rule",
+		State:   state,
+		Class:   "event-pattern",
+	}
+
+	if rule.ScheduleExpression != nil {
+		resource.Class = "schedule"
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["eventBusName"] = busName
+	extra["targetCount"] = len(targets)
+	if len(targets) > 0 {
+		extra["targetArns"] = targets
+	}
+	if rule.Arn != nil {
+		extra["ruleArn"] = aws.ToString(rule.Arn)
+	}
+	if rule.ScheduleExpression != nil {
+		extra["scheduleExpression"] = aws.ToString(rule.ScheduleExpression)
+	}
+	if rule.ManagedBy != nil {
+		extra["managedBy"] = aws.ToString(rule.ManagedBy)
+	}
+	if rule.Description != nil {
+		extra["description"] = aws.ToString(rule.Description)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertSchedule converts an EventBridge Scheduler schedule to a Resource
+func (c *EventBridgeCollector) convertSchedule(schedule schedtypes.ScheduleSummary, region string) models.Resource {
+	groupName := aws.ToString(schedule.GroupName)
+
+	resource := models.Resource{
+		Service:   "eventbridge",
+		Region:    region,
+		ID:        groupName + "/" + aws.ToString(schedule.Name),
+		Name:      aws.ToString(schedule.Name),
+		Type:      "schedule",
+		State:     strings.ToLower(string(schedule.State)),
+		Class:     "scheduler",
+		CreatedAt: schedule.CreationDate,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["groupName"] = groupName
+	if schedule.Arn != nil {
+		extra["scheduleArn"] = aws.ToString(schedule.Arn)
+	}
+	if schedule.Target != nil && schedule.Target.Arn != nil {
+		extra["targetArn"] = aws.ToString(schedule.Target.Arn)
+	}
+	if schedule.LastModificationDate != nil {
+		extra["lastModified"] = aws.ToTime(schedule.LastModificationDate)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
@@ -0,0 +1,151 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/fsx"
+	"github.com/aws/aws-sdk-go-v2/service/fsx/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// FSxCollector collects FSx file systems
+type FSxCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewFSxCollector creates a new FSx collector
+func NewFSxCollector(clientManager *awspkg.ClientManager) *FSxCollector {
+	return &FSxCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *FSxCollector) Name() string {
+	return "fsx"
+}
+
+// Regions returns the regions this collector supports
+func (c *FSxCollector) Regions() []string {
+	// FSx is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *FSxCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves FSx file systems for the given region
+func (c *FSxCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := fsx.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &fsx.DescribeFileSystemsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeFileSystems(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe FSx file systems in %s: %w", region, err)
+		}
+
+		for _, fs := range result.FileSystems {
+			resource := c.convertFileSystem(fs, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertFileSystem converts an FSx file system to a Resource
+func (c *FSxCollector) convertFileSystem(fs types.FileSystem, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "fsx",
+		Region:    region,
+		ID:        aws.ToString(fs.FileSystemId),
+		Name:      aws.ToString(fs.FileSystemId),
+		Type:      strings.ToLower(string(fs.FileSystemType)),
+		State:     strings.ToLower(string(fs.Lifecycle)),
+		CreatedAt: fs.CreationTime,
+	}
+
+	// Extract name from tags
+	if fs.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range fs.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if fs.StorageCapacity != nil {
+		extra["storageCapacityGB"] = aws.ToInt32(fs.StorageCapacity)
+	}
+	if fs.StorageType != "" {
+		extra["storageType"] = string(fs.StorageType)
+	}
+	if fs.ResourceARN != nil {
+		extra["resourceArn"] = aws.ToString(fs.ResourceARN)
+	}
+	if fs.VpcId != nil {
+		extra["vpcId"] = aws.ToString(fs.VpcId)
+	}
+
+	// Deployment type and throughput live in the type-specific configuration
+	var deploymentType string
+	var throughput *int32
+	switch {
+	case fs.WindowsConfiguration != nil:
+		deploymentType = string(fs.WindowsConfiguration.DeploymentType)
+		throughput = fs.WindowsConfiguration.ThroughputCapacity
+	case fs.LustreConfiguration != nil:
+		deploymentType = string(fs.LustreConfiguration.DeploymentType)
+		if fs.LustreConfiguration.PerUnitStorageThroughput != nil {
+			extra["perUnitStorageThroughput"] = aws.ToInt32(fs.LustreConfiguration.PerUnitStorageThroughput)
+		}
+	case fs.OntapConfiguration != nil:
+		deploymentType = string(fs.OntapConfiguration.DeploymentType)
+		throughput = fs.OntapConfiguration.ThroughputCapacity
+	case fs.OpenZFSConfiguration != nil:
+		deploymentType = string(fs.OpenZFSConfiguration.DeploymentType)
+		throughput = fs.OpenZFSConfiguration.ThroughputCapacity
+	}
+
+	resource.Class = deploymentType
+	if deploymentType != "" {
+		extra["deploymentType"] = deploymentType
+	}
+	if throughput != nil {
+		extra["throughputCapacity"] = aws.ToInt32(throughput)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
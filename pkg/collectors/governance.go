@@ -0,0 +1,362 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	cttypes "github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/aws/aws-sdk-go-v2/service/guardduty"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// GovernanceCollector collects CloudTrail trails, AWS Config recorders and
+// GuardDuty detectors, plus a per-region audit coverage summary
+type GovernanceCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewGovernanceCollector creates a new governance collector
+func NewGovernanceCollector(clientManager *awspkg.ClientManager) *GovernanceCollector {
+	return &GovernanceCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *GovernanceCollector) Name() string {
+	return "governance"
+}
+
+// Regions returns the regions this collector supports
+func (c *GovernanceCollector) Regions() []string {
+	// Audit services are regional, so coverage is checked in every region
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *GovernanceCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// auditCoverage tracks which audit controls are active in a region.
+// A nil value means the state could not be determined.
+type auditCoverage struct {
+	cloudTrail *bool
+	config     *bool
+	guardDuty  *bool
+}
+
+// Collect retrieves audit resources and the coverage summary for the given region
+func (c *GovernanceCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+
+	var resources []models.Resource
+	var coverage auditCoverage
+	var errs []error
+
+	trails, covered, err := c.collectTrails(ctx, cloudtrail.NewFromConfig(cfg), region)
+	if err != nil {
+		models.Warnf(ctx, "failed to collect CloudTrail trails in %s: %v", region, err)
+		errs = append(errs, fmt.Errorf("CloudTrail: %w", err))
+	} else {
+		resources = append(resources, trails...)
+		coverage.cloudTrail = aws.Bool(covered)
+	}
+
+	recorders, covered, err := c.collectConfigRecorders(ctx, configservice.NewFromConfig(cfg), region)
+	if err != nil {
+		models.Warnf(ctx, "failed to collect AWS Config recorders in %s: %v", region, err)
+		errs = append(errs, fmt.Errorf("AWS Config: %w", err))
+	} else {
+		resources = append(resources, recorders...)
+		coverage.config = aws.Bool(covered)
+	}
+
+	detectors, covered, err := c.collectDetectors(ctx, guardduty.NewFromConfig(cfg), region)
+	if err != nil {
+		models.Warnf(ctx, "failed to collect GuardDuty detectors in %s: %v", region, err)
+		errs = append(errs, fmt.Errorf("GuardDuty: %w", err))
+	} else {
+		resources = append(resources, detectors...)
+		coverage.guardDuty = aws.Bool(covered)
+	}
+
+	// Only a region where every audit service failed fails, with all three causes
+	if len(errs) == 3 {
+		return nil, fmt.Errorf("failed to collect governance data in %s: %w", region, errors.Join(errs...))
+	}
+
+	resources = append(resources, c.convertCoverage(coverage, region))
+
+	return resources, nil
+}
+
+// collectTrails lists the trails that apply to a region. Trails are only reported
+// as resources in their home region, but multi-region trails from other home
+// regions still count towards this region's coverage.
+func (c *GovernanceCollector) collectTrails(ctx context.Context, client *cloudtrail.Client, region string) ([]models.Resource, bool, error) {
+	input := &cloudtrail.DescribeTrailsInput{
+		IncludeShadowTrails: aws.Bool(true),
+	}
+
+	result, err := client.DescribeTrails(ctx, input)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var resources []models.Resource
+	covered := false
+
+	for _, trail := range result.TrailList {
+		status, err := client.GetTrailStatus(ctx, &cloudtrail.GetTrailStatusInput{
+			Name: trail.TrailARN,
+		})
+		if err != nil {
+			models.Warnf(ctx, "failed to get status for trail %s: %v", aws.ToString(trail.Name), err)
+		}
+
+		logging := status != nil && aws.ToBool(status.IsLogging)
+		if logging && (aws.ToString(trail.HomeRegion) == region || aws.ToBool(trail.IsMultiRegionTrail)) {
+			covered = true
+		}
+
+		if aws.ToString(trail.HomeRegion) == region {
+			resources = append(resources, c.convertTrail(trail, status, region))
+		}
+	}
+
+	return resources, covered, nil
+}
+
+// collectConfigRecorders lists AWS Config recorders and their recording status
+func (c *GovernanceCollector) collectConfigRecorders(ctx context.Context, client *configservice.Client, region string) ([]models.Resource, bool, error) {
+	result, err := client.DescribeConfigurationRecorderStatus(ctx, &configservice.DescribeConfigurationRecorderStatusInput{})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var resources []models.Resource
+	covered := false
+
+	for _, status := range result.ConfigurationRecordersStatus {
+		state := "stopped"
+		if status.Recording {
+			state = "recording"
+			covered = true
+		}
+
+		resource := models.Resource{
+			Service: "governance",
+			Region:  region,
+			ID:      aws.ToString(status.Name),
+			Name:    aws.ToString(status.Name),
+			Type:    "config-recorder",
+			State:   state,
+			Class:   "config",
+		}
+
+		extra := make(map[string]interface{})
+		if status.LastStatus != "" {
+			extra["lastStatus"] = string(status.LastStatus)
+		}
+		if status.LastStartTime != nil {
+			extra["lastStartTime"] = aws.ToTime(status.LastStartTime)
+		}
+		if status.LastErrorCode != nil {
+			extra["lastErrorCode"] = aws.ToString(status.LastErrorCode)
+		}
+		resource.Extra = extra
+
+		resources = append(resources, resource)
+	}
+
+	return resources, covered, nil
+}
+
+// collectDetectors lists GuardDuty detectors and their status
+func (c *GovernanceCollector) collectDetectors(ctx context.Context, client *guardduty.Client, region string) ([]models.Resource, bool, error) {
+	var resources []models.Resource
+	var nextToken *string
+	covered := false
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+
+		result, err := client.ListDetectors(ctx, &guardduty.ListDetectorsInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, detectorID := range result.DetectorIds {
+			detector, err := client.GetDetector(ctx, &guardduty.GetDetectorInput{
+				DetectorId: aws.String(detectorID),
+			})
+			if err != nil {
+				models.Warnf(ctx, "failed to get info for detector %s: %v", detectorID, err)
+				continue
+			}
+
+			resource := c.convertDetector(detectorID, detector, region)
+			if resource.State == "enabled" {
+				covered = true
+			}
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, covered, nil
+}
+
+// convertTrail converts a CloudTrail trail to a Resource
+func (c *GovernanceCollector) convertTrail(trail cttypes.Trail, status *cloudtrail.GetTrailStatusOutput, region string) models.Resource {
+	resource := models.Resource{
+		Service: "governance",
+		Region:  region,
+		ID:      aws.ToString(trail.Name),
+		Name:    aws.ToString(trail.Name),
+		Type:    "cloudtrail-trail",
+		State:   "unknown",
+		Class:   "cloudtrail",
+	}
+
+	if status != nil {
+		if aws.ToBool(status.IsLogging) {
+			resource.State = "logging"
+		} else {
+			resource.State = "stopped"
+		}
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if trail.TrailARN != nil {
+		extra["trailArn"] = aws.ToString(trail.TrailARN)
+	}
+	if trail.S3BucketName != nil {
+		extra["s3BucketName"] = aws.ToString(trail.S3BucketName)
+	}
+	if trail.IsMultiRegionTrail != nil {
+		extra["multiRegion"] = aws.ToBool(trail.IsMultiRegionTrail)
+	}
+	if trail.IsOrganizationTrail != nil {
+		extra["organizationTrail"] = aws.ToBool(trail.IsOrganizationTrail)
+	}
+	if trail.LogFileValidationEnabled != nil {
+		extra["logFileValidation"] = aws.ToBool(trail.LogFileValidationEnabled)
+	}
+	if trail.KmsKeyId != nil {
+		extra["kmsKeyId"] = aws.ToString(trail.KmsKeyId)
+	}
+	if trail.CloudWatchLogsLogGroupArn != nil {
+		extra["cloudWatchLogsLogGroupArn"] = aws.ToString(trail.CloudWatchLogsLogGroupArn)
+	}
+	if status != nil && status.LatestDeliveryTime != nil {
+		extra["latestDeliveryTime"] = aws.ToTime(status.LatestDeliveryTime)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertDetector converts a GuardDuty detector to a Resource
+func (c *GovernanceCollector) convertDetector(detectorID string, detector *guardduty.GetDetectorOutput, region string) models.Resource {
+	resource := models.Resource{
+		Service: "governance",
+		Region:  region,
+		ID:      detectorID,
+		Name:    detectorID,
+		Type:    "guardduty-detector",
+		State:   strings.ToLower(string(detector.Status)),
+		Class:   "guardduty",
+		Tags:    detector.Tags,
+	}
+
+	// GuardDuty returns timestamps as strings
+	if detector.CreatedAt != nil {
+		if createdAt, err := time.Parse(time.RFC3339, aws.ToString(detector.CreatedAt)); err == nil {
+			resource.CreatedAt = &createdAt
+		}
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if detector.FindingPublishingFrequency != "" {
+		extra["findingPublishingFrequency"] = string(detector.FindingPublishingFrequency)
+	}
+	if detector.ServiceRole != nil {
+		extra["serviceRole"] = aws.ToString(detector.ServiceRole)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertCoverage builds the per-region audit coverage summary resource
+func (c *GovernanceCollector) convertCoverage(coverage auditCoverage, region string) models.Resource {
+	controls := []struct {
+		name   string
+		active *bool
+	}{
+		{"cloudtrail", coverage.cloudTrail},
+		{"config", coverage.config},
+		{"guardduty", coverage.guardDuty},
+	}
+
+	extra := make(map[string]interface{})
+	var missing, unknown []string
+	for _, control := range controls {
+		switch {
+		case control.active == nil:
+			extra[control.name] = "unknown"
+			unknown = append(unknown, control.name)
+		case *control.active:
+			extra[control.name] = true
+		default:
+			extra[control.name] = false
+			missing = append(missing, control.name)
+		}
+	}
+
+	state := "covered"
+	switch {
+	case len(missing) == len(controls):
+		state = "uncovered"
+	case len(missing) > 0:
+		state = "partial"
+	case len(unknown) > 0:
+		state = "unknown"
+	}
+
+	if len(missing) > 0 {
+		extra["missing"] = missing
+	}
+
+	return models.Resource{
+		Service: "governance",
+		Region:  region,
+		ID:      "audit-coverage-" + region,
+		Name:    "audit-coverage",
+		Type:    "coverage",
+		State:   state,
+		Class:   "coverage",
+		Extra:   extra,
+	}
+}
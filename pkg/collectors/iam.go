@@ -0,0 +1,148 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/iam/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// IAMCollector collects IAM users with their access keys
+type IAMCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewIAMCollector creates a new IAM collector
+func NewIAMCollector(clientManager *awspkg.ClientManager) *IAMCollector {
+	return &IAMCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *IAMCollector) Name() string {
+	return "iam"
+}
+
+// Regions returns the regions this collector supports
+func (c *IAMCollector) Regions() []string {
+	// IAM belongs to the account, see Scope
+	return nil
+}
+
+// Scope returns the scope of the collector's resources
+func (c *IAMCollector) Scope() models.Scope {
+	return models.ScopeGlobal
+}
+
+// Collect retrieves the IAM users and their access keys
+func (c *IAMCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	// IAM is a global service served from us-east-1
+	cfg := c.clientManager.GetConfig("us-east-1")
+	client := iam.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	var marker *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &iam.ListUsersInput{
+			Marker: marker,
+		}
+
+		result, err := client.ListUsers(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list IAM users: %w", err)
+		}
+
+		for _, user := range result.Users {
+			resource := c.convertUser(user)
+
+			keys, err := c.listAccessKeys(ctx, client, aws.ToString(user.UserName))
+			if err != nil {
+				models.Warnf(ctx, "failed to list access keys of IAM user %s: %v", resource.Name, err)
+			} else if len(keys) > 0 {
+				resource.Extra["accessKeys"] = keys
+			}
+
+			resources = append(resources, resource)
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.Marker
+	}
+
+	return resources, nil
+}
+
+// listAccessKeys returns the ID, status and creation time of a user's access keys
+func (c *IAMCollector) listAccessKeys(ctx context.Context, client *iam.Client, userName string) ([]map[string]interface{}, error) {
+	var keys []map[string]interface{}
+	var marker *string
+
+	for {
+		result, err := client.ListAccessKeys(ctx, &iam.ListAccessKeysInput{
+			UserName: aws.String(userName),
+			Marker:   marker,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range result.AccessKeyMetadata {
+			keys = append(keys, map[string]interface{}{
+				"id":        aws.ToString(key.AccessKeyId),
+				"status":    string(key.Status),
+				"createdAt": aws.ToTime(key.CreateDate),
+			})
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.Marker
+	}
+
+	return keys, nil
+}
+
+// convertUser converts an IAM user to a Resource
+func (c *IAMCollector) convertUser(user types.User) models.Resource {
+	resource := models.Resource{
+		Service:   "iam",
+		Region:    models.GlobalRegion, // IAM users belong to the account
+		ID:        aws.ToString(user.UserName),
+		Name:      aws.ToString(user.UserName),
+		Type:      "user",
+		State:     "active",
+		CreatedAt: user.CreateDate,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if user.Arn != nil {
+		extra["arn"] = aws.ToString(user.Arn)
+	}
+	if user.UserId != nil {
+		extra["userId"] = aws.ToString(user.UserId)
+	}
+	if user.Path != nil {
+		extra["path"] = aws.ToString(user.Path)
+	}
+	if user.PasswordLastUsed != nil {
+		extra["passwordLastUsed"] = aws.ToTime(user.PasswordLastUsed)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
@@ -2,7 +2,9 @@ package collectors
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,7 +14,7 @@ import (
 	"github.com/xiaochen/awsinv/pkg/models"
 )
 
-// LambdaCollector collects Lambda functions
+// LambdaCollector collects Lambda functions, event source mappings and layers
 type LambdaCollector struct {
 	clientManager *awspkg.ClientManager
 }
@@ -35,7 +37,12 @@ func (c *LambdaCollector) Regions() []string {
 	return nil // Will be populated by the orchestrator
 }
 
-// Collect retrieves Lambda functions for the given region
+// Scope returns the scope of the collector's resources
+func (c *LambdaCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves Lambda functions, event source mappings and layers for the given region
 func (c *LambdaCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
 	cfg := c.clientManager.GetConfig(region)
 	client := lambda.NewFromConfig(cfg)
@@ -44,6 +51,10 @@ func (c *LambdaCollector) Collect(ctx context.Context, region string) ([]models.
 	var marker *string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		input := &lambda.ListFunctionsInput{
 			Marker: marker,
 		}
@@ -54,7 +65,192 @@ func (c *LambdaCollector) Collect(ctx context.Context, region string) ([]models.
 		}
 
 		for _, function := range result.Functions {
-			resource := c.convertFunction(function, region)
+			concurrency := c.getConcurrency(ctx, client, aws.ToString(function.FunctionName))
+			resource := c.convertFunction(function, concurrency, region)
+			resources = append(resources, resource)
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	mappings, err := c.getEventSourceMappings(ctx, client, region)
+	if err != nil {
+		models.Warnf(ctx, "failed to list event source mappings in %s: %v", region, err)
+	} else {
+		resources = append(resources, mappings...)
+	}
+
+	layers, err := c.getLayers(ctx, client, region)
+	if err != nil {
+		models.Warnf(ctx, "failed to list layers in %s: %v", region, err)
+	} else {
+		resources = append(resources, layers...)
+	}
+
+	return resources, nil
+}
+
+// Describe retrieves a single Lambda function by name. Event source mappings and
+// layers are left to the caller.
+func (c *LambdaCollector) Describe(ctx context.Context, region, id string) (*models.Resource, interface{}, error) {
+	client := lambda.NewFromConfig(c.clientManager.GetConfig(region))
+
+	result, err := client.GetFunction(ctx, &lambda.GetFunctionInput{
+		FunctionName: aws.String(id),
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get function %s in %s: %w", id, region, err)
+	}
+	if result.Configuration == nil {
+		return nil, nil, nil
+	}
+
+	concurrency := c.getConcurrency(ctx, client, aws.ToString(result.Configuration.FunctionName))
+	resource := c.convertFunction(*result.Configuration, concurrency, region)
+	if len(result.Tags) > 0 {
+		resource.Tags = result.Tags
+	}
+
+	raw := map[string]interface{}{
+		"Configuration": result.Configuration,
+		"Code":          result.Code,
+		"Concurrency":   result.Concurrency,
+		"Tags":          result.Tags,
+	}
+	return &resource, raw, nil
+}
+
+// AddTags looks up the tags of the functions, which ListFunctions does not return
+func (c *LambdaCollector) AddTags(ctx context.Context, region string, resources []models.Resource) {
+	client := lambda.NewFromConfig(c.clientManager.GetConfig(region))
+
+	isFunction := func(resource models.Resource) bool {
+		return resource.Type != "event-source-mapping" && resource.Type != "layer"
+	}
+	fetchTags(ctx, resources, isFunction, func(ctx context.Context, resource models.Resource) (map[string]string, error) {
+		functionArn, _ := resource.Extra["functionArn"].(string)
+		result, err := client.ListTags(ctx, &lambda.ListTagsInput{
+			Resource: aws.String(functionArn),
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.Tags, nil
+	})
+}
+
+// functionConcurrency holds the reserved and provisioned concurrency of a function.
+// A nil reserved value means the function uses the unreserved account pool.
+type functionConcurrency struct {
+	reserved             *int32
+	provisionedRequested int32
+	provisionedAllocated int32
+}
+
+// getConcurrency retrieves reserved and provisioned concurrency for a function
+func (c *LambdaCollector) getConcurrency(ctx context.Context, client *lambda.Client, functionName string) functionConcurrency {
+	var concurrency functionConcurrency
+
+	reserved, err := client.GetFunctionConcurrency(ctx, &lambda.GetFunctionConcurrencyInput{
+		FunctionName: aws.String(functionName),
+	})
+	if err != nil {
+		models.Warnf(ctx, "failed to get concurrency for function %s: %v", functionName, err)
+	} else {
+		concurrency.reserved = reserved.ReservedConcurrentExecutions
+	}
+
+	// Provisioned concurrency is configured per version or alias
+	var marker *string
+	for {
+		if ctx.Err() != nil {
+			return concurrency
+		}
+
+		result, err := client.ListProvisionedConcurrencyConfigs(ctx, &lambda.ListProvisionedConcurrencyConfigsInput{
+			FunctionName: aws.String(functionName),
+			Marker:       marker,
+		})
+		if err != nil {
+			models.Warnf(ctx, "failed to list provisioned concurrency for function %s: %v", functionName, err)
+			break
+		}
+
+		for _, config := range result.ProvisionedConcurrencyConfigs {
+			concurrency.provisionedRequested += aws.ToInt32(config.RequestedProvisionedConcurrentExecutions)
+			concurrency.provisionedAllocated += aws.ToInt32(config.AllocatedProvisionedConcurrentExecutions)
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	return concurrency
+}
+
+// getEventSourceMappings retrieves the event source mappings in a region
+func (c *LambdaCollector) getEventSourceMappings(ctx context.Context, client *lambda.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var marker *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &lambda.ListEventSourceMappingsInput{
+			Marker: marker,
+		}
+
+		result, err := client.ListEventSourceMappings(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, mapping := range result.EventSourceMappings {
+			resource := c.convertEventSourceMapping(mapping, region)
+			resources = append(resources, resource)
+		}
+
+		marker = result.NextMarker
+		if marker == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// getLayers retrieves the layers in a region with their latest version
+func (c *LambdaCollector) getLayers(ctx context.Context, client *lambda.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var marker *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &lambda.ListLayersInput{
+			Marker: marker,
+		}
+
+		result, err := client.ListLayers(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, layer := range result.Layers {
+			resource := c.convertLayer(layer, region)
 			resources = append(resources, resource)
 		}
 
@@ -68,7 +264,7 @@ func (c *LambdaCollector) Collect(ctx context.Context, region string) ([]models.
 }
 
 // convertFunction converts a Lambda function to a Resource
-func (c *LambdaCollector) convertFunction(function types.FunctionConfiguration, region string) models.Resource {
+func (c *LambdaCollector) convertFunction(function types.FunctionConfiguration, concurrency functionConcurrency, region string) models.Resource {
 	resource := models.Resource{
 		Service: "lambda",
 		Region:  region,
@@ -113,7 +309,20 @@ func (c *LambdaCollector) convertFunction(function types.FunctionConfiguration,
 	if function.Environment != nil && function.Environment.Variables != nil {
 		extra["environmentVariables"] = len(function.Environment.Variables)
 	}
-	// Note: ReservedConcurrentExecutions is not available in this version
+	if concurrency.reserved != nil {
+		extra["reservedConcurrency"] = aws.ToInt32(concurrency.reserved)
+	}
+	if concurrency.provisionedRequested > 0 {
+		extra["provisionedConcurrency"] = concurrency.provisionedRequested
+		extra["provisionedConcurrencyAllocated"] = concurrency.provisionedAllocated
+	}
+	if len(function.Layers) > 0 {
+		layers := make([]string, len(function.Layers))
+		for i, layer := range function.Layers {
+			layers[i] = aws.ToString(layer.Arn)
+		}
+		extra["layers"] = layers
+	}
 	if function.LastUpdateStatus != "" {
 		extra["lastUpdateStatus"] = string(function.LastUpdateStatus)
 	}
@@ -127,8 +336,105 @@ func (c *LambdaCollector) convertFunction(function types.FunctionConfiguration,
 		}
 		extra["architectures"] = architectures
 	}
+	if function.VpcConfig != nil && aws.ToString(function.VpcConfig.VpcId) != "" {
+		extra["vpcId"] = aws.ToString(function.VpcConfig.VpcId)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertEventSourceMapping converts a Lambda event source mapping to a Resource
+func (c *LambdaCollector) convertEventSourceMapping(mapping types.EventSourceMappingConfiguration, region string) models.Resource {
+	functionArn := aws.ToString(mapping.FunctionArn)
+	eventSourceArn := aws.ToString(mapping.EventSourceArn)
+
+	resource := models.Resource{
+		Service:   "lambda",
+		Region:    region,
+		ID:        aws.ToString(mapping.UUID),
+		Name:      functionArn[strings.LastIndex(functionArn, ":")+1:],
+		Type:      "event-source-mapping",
+		State:     strings.ToLower(aws.ToString(mapping.State)),
+		Class:     eventSourceType(eventSourceArn),
+		CreatedAt: mapping.LastModified,
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["functionArn"] = functionArn
+	if eventSourceArn != "" {
+		extra["eventSourceArn"] = eventSourceArn
+	}
+	if mapping.BatchSize != nil {
+		extra["batchSize"] = aws.ToInt32(mapping.BatchSize)
+	}
+	if mapping.MaximumBatchingWindowInSeconds != nil {
+		extra["maxBatchingWindow"] = aws.ToInt32(mapping.MaximumBatchingWindowInSeconds)
+	}
+	if mapping.StartingPosition != "" {
+		extra["startingPosition"] = string(mapping.StartingPosition)
+	}
+	if mapping.LastProcessingResult != nil {
+		extra["lastProcessingResult"] = aws.ToString(mapping.LastProcessingResult)
+	}
 
 	resource.Extra = extra
 
 	return resource
-} 
\ No newline at end of file
+}
+
+// convertLayer converts a Lambda layer to a Resource
+func (c *LambdaCollector) convertLayer(layer types.LayersListItem, region string) models.Resource {
+	resource := models.Resource{
+		Service: "lambda",
+		Region:  region,
+		ID:      aws.ToString(layer.LayerName),
+		Name:    aws.ToString(layer.LayerName),
+		Type:    "layer",
+		State:   "active",
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if layer.LayerArn != nil {
+		extra["layerArn"] = aws.ToString(layer.LayerArn)
+	}
+	if version := layer.LatestMatchingVersion; version != nil {
+		resource.Class = fmt.Sprintf("v%d", version.Version)
+		extra["latestVersion"] = version.Version
+		if version.LayerVersionArn != nil {
+			extra["layerVersionArn"] = aws.ToString(version.LayerVersionArn)
+		}
+		if version.Description != nil {
+			extra["description"] = aws.ToString(version.Description)
+		}
+		if len(version.CompatibleRuntimes) > 0 {
+			runtimes := make([]string, len(version.CompatibleRuntimes))
+			for i, runtime := range version.CompatibleRuntimes {
+				runtimes[i] = string(runtime)
+			}
+			extra["compatibleRuntimes"] = runtimes
+		}
+		// Layer versions report their creation date as a string
+		if version.CreatedDate != nil {
+			if createdAt, err := time.Parse("2006-01-02T15:04:05.000-0700", aws.ToString(version.CreatedDate)); err == nil {
+				resource.CreatedAt = &createdAt
+			}
+		}
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// eventSourceType returns the AWS service of an event source ARN, e.g. "sqs" or "kinesis"
+func eventSourceType(arn string) string {
+	parts := strings.SplitN(arn, ":", 4)
+	if len(parts) < 3 {
+		return "unknown"
+	}
+	return parts[2]
+}
@@ -0,0 +1,138 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+// maxMetricQueries is the number of queries GetMetricData accepts in one request
+const maxMetricQueries = 500
+
+// metricPeriod is the period of the datapoints fetched. Daily datapoints keep the
+// number of values small and match the daily storage metrics of S3.
+const metricPeriod = 24 * time.Hour
+
+// metricQuery is a CloudWatch metric to fetch
+type metricQuery struct {
+	namespace  string
+	name       string
+	stat       string
+	dimensions map[string]string
+
+	// latest keeps the most recent datapoint instead of the sum over the period
+	latest bool
+
+	// peak keeps the largest datapoint and mean the average of the datapoints,
+	// instead of the sum over the period
+	peak bool
+	mean bool
+}
+
+// getMetricData fetches the metrics between start and end, up to maxMetricQueries in
+// each request, and returns the value of each: the sum of its datapoints or, for
+// latest, peak and mean metrics, the most recent, largest or average one. Metrics
+// without datapoints are 0, except peak and mean metrics, which are NaN since a
+// utilization of 0 is not the same as none measured.
+func getMetricData(ctx context.Context, client *cloudwatch.Client, metrics []metricQuery, start, end time.Time) ([]float64, error) {
+	values := make([]float64, len(metrics))
+	latest := make([]time.Time, len(metrics))
+	counts := make([]int, len(metrics))
+
+	for batchStart := 0; batchStart < len(metrics); batchStart += maxMetricQueries {
+		batchEnd := min(batchStart+maxMetricQueries, len(metrics))
+
+		input := &cloudwatch.GetMetricDataInput{
+			StartTime: aws.Time(start),
+			EndTime:   aws.Time(end),
+		}
+		for q := batchStart; q < batchEnd; q++ {
+			input.MetricDataQueries = append(input.MetricDataQueries, metricDataQuery(fmt.Sprintf("m%d", q), metrics[q]))
+		}
+
+		for {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			result, err := client.GetMetricData(ctx, input)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, data := range result.MetricDataResults {
+				var q int
+				if _, err := fmt.Sscanf(aws.ToString(data.Id), "m%d", &q); err != nil || q < batchStart || q >= batchEnd {
+					continue
+				}
+
+				for k, value := range data.Values {
+					switch {
+					case metrics[q].latest:
+						if k < len(data.Timestamps) && data.Timestamps[k].After(latest[q]) {
+							latest[q] = data.Timestamps[k]
+							values[q] = value
+						}
+					case metrics[q].peak:
+						values[q] = max(values[q], value)
+					default:
+						values[q] += value
+					}
+					counts[q]++
+				}
+			}
+
+			input.NextToken = result.NextToken
+			if input.NextToken == nil {
+				break
+			}
+		}
+	}
+
+	for q, metric := range metrics {
+		switch {
+		case (metric.peak || metric.mean) && counts[q] == 0:
+			values[q] = math.NaN()
+		case metric.mean:
+			values[q] /= float64(counts[q])
+		}
+	}
+
+	return values, nil
+}
+
+// metricDataQuery builds the GetMetricData query of a metric
+func metricDataQuery(id string, metric metricQuery) cwtypes.MetricDataQuery {
+	names := make([]string, 0, len(metric.dimensions))
+	for name := range metric.dimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dimensions := make([]cwtypes.Dimension, 0, len(names))
+	for _, name := range names {
+		dimensions = append(dimensions, cwtypes.Dimension{
+			Name:  aws.String(name),
+			Value: aws.String(metric.dimensions[name]),
+		})
+	}
+
+	return cwtypes.MetricDataQuery{
+		Id: aws.String(id),
+		MetricStat: &cwtypes.MetricStat{
+			Metric: &cwtypes.Metric{
+				Namespace:  aws.String(metric.namespace),
+				MetricName: aws.String(metric.name),
+				Dimensions: dimensions,
+			},
+			Period: aws.Int32(int32(metricPeriod.Seconds())),
+			Stat:   aws.String(metric.stat),
+		},
+	}
+}
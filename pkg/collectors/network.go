@@ -0,0 +1,584 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// NetworkCollector collects transit gateways, their attachments, site-to-site VPN
+// connections, NAT gateways, security groups and network interfaces
+type NetworkCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewNetworkCollector creates a new network collector
+func NewNetworkCollector(clientManager *awspkg.ClientManager) *NetworkCollector {
+	return &NetworkCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *NetworkCollector) Name() string {
+	return "network"
+}
+
+// Regions returns the regions this collector supports
+func (c *NetworkCollector) Regions() []string {
+	// Transit gateways, VPN connections, NAT gateways, security groups and network interfaces are available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *NetworkCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves transit gateways, attachments, VPN connections, NAT gateways,
+// security groups and network interfaces for the given region
+func (c *NetworkCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := ec2.NewFromConfig(cfg)
+
+	var resources []models.Resource
+
+	gateways, err := c.collectTransitGateways(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, gateways...)
+
+	attachments, err := c.collectTransitGatewayAttachments(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, attachments...)
+
+	vpnConnections, err := c.collectVPNConnections(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, vpnConnections...)
+
+	natGateways, err := c.collectNATGateways(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, natGateways...)
+
+	securityGroups, err := c.collectSecurityGroups(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, securityGroups...)
+
+	networkInterfaces, err := c.collectNetworkInterfaces(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, networkInterfaces...)
+
+	return resources, nil
+}
+
+// collectTransitGateways lists the transit gateways in a region
+func (c *NetworkCollector) collectTransitGateways(ctx context.Context, client *ec2.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ec2.DescribeTransitGatewaysInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeTransitGateways(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe transit gateways in %s: %w", region, err)
+		}
+
+		for _, gateway := range result.TransitGateways {
+			resource := c.convertTransitGateway(gateway, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// collectTransitGatewayAttachments lists the transit gateway attachments in a region
+func (c *NetworkCollector) collectTransitGatewayAttachments(ctx context.Context, client *ec2.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ec2.DescribeTransitGatewayAttachmentsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeTransitGatewayAttachments(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe transit gateway attachments in %s: %w", region, err)
+		}
+
+		for _, attachment := range result.TransitGatewayAttachments {
+			resource := c.convertTransitGatewayAttachment(attachment, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// collectVPNConnections lists the site-to-site VPN connections in a region
+func (c *NetworkCollector) collectVPNConnections(ctx context.Context, client *ec2.Client, region string) ([]models.Resource, error) {
+	// DescribeVpnConnections is not paginated
+	result, err := client.DescribeVpnConnections(ctx, &ec2.DescribeVpnConnectionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe VPN connections in %s: %w", region, err)
+	}
+
+	var resources []models.Resource
+	for _, connection := range result.VpnConnections {
+		resource := c.convertVPNConnection(connection, region)
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// collectNATGateways lists the NAT gateways in a region
+func (c *NetworkCollector) collectNATGateways(ctx context.Context, client *ec2.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ec2.DescribeNatGatewaysInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeNatGateways(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe NAT gateways in %s: %w", region, err)
+		}
+
+		for _, gateway := range result.NatGateways {
+			resource := c.convertNATGateway(gateway, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// collectSecurityGroups lists the security groups in a region
+func (c *NetworkCollector) collectSecurityGroups(ctx context.Context, client *ec2.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ec2.DescribeSecurityGroupsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeSecurityGroups(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe security groups in %s: %w", region, err)
+		}
+
+		for _, group := range result.SecurityGroups {
+			resource := c.convertSecurityGroup(group, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// collectNetworkInterfaces lists the elastic network interfaces in a region
+func (c *NetworkCollector) collectNetworkInterfaces(ctx context.Context, client *ec2.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &ec2.DescribeNetworkInterfacesInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeNetworkInterfaces(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe network interfaces in %s: %w", region, err)
+		}
+
+		for _, networkInterface := range result.NetworkInterfaces {
+			resource := c.convertNetworkInterface(networkInterface, region)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertTransitGateway converts a transit gateway to a Resource
+func (c *NetworkCollector) convertTransitGateway(gateway types.TransitGateway, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "network",
+		Region:    region,
+		ID:        aws.ToString(gateway.TransitGatewayId),
+		Name:      aws.ToString(gateway.TransitGatewayId),
+		Type:      "transit-gateway",
+		State:     string(gateway.State),
+		CreatedAt: gateway.CreationTime,
+	}
+
+	// Extract name from tags
+	if gateway.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range gateway.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if gateway.OwnerId != nil {
+		extra["ownerId"] = aws.ToString(gateway.OwnerId)
+	}
+	if gateway.Description != nil {
+		extra["description"] = aws.ToString(gateway.Description)
+	}
+	if gateway.Options != nil && gateway.Options.AmazonSideAsn != nil {
+		extra["amazonSideAsn"] = aws.ToInt64(gateway.Options.AmazonSideAsn)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertTransitGatewayAttachment converts a transit gateway attachment to a Resource
+func (c *NetworkCollector) convertTransitGatewayAttachment(attachment types.TransitGatewayAttachment, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "network",
+		Region:    region,
+		ID:        aws.ToString(attachment.TransitGatewayAttachmentId),
+		Name:      aws.ToString(attachment.TransitGatewayAttachmentId),
+		Type:      "tgw-attachment",
+		State:     string(attachment.State),
+		Class:     string(attachment.ResourceType),
+		CreatedAt: attachment.CreationTime,
+	}
+
+	// Extract name from tags
+	if attachment.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range attachment.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if attachment.TransitGatewayId != nil {
+		extra["transitGatewayId"] = aws.ToString(attachment.TransitGatewayId)
+	}
+	if attachment.ResourceId != nil {
+		extra["resourceId"] = aws.ToString(attachment.ResourceId)
+	}
+	if attachment.ResourceOwnerId != nil {
+		extra["resourceOwnerId"] = aws.ToString(attachment.ResourceOwnerId)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertVPNConnection converts a site-to-site VPN connection to a Resource
+func (c *NetworkCollector) convertVPNConnection(connection types.VpnConnection, region string) models.Resource {
+	resource := models.Resource{
+		Service: "network",
+		Region:  region,
+		ID:      aws.ToString(connection.VpnConnectionId),
+		Name:    aws.ToString(connection.VpnConnectionId),
+		Type:    "vpn-connection",
+		State:   string(connection.State),
+		Class:   string(connection.Type),
+	}
+
+	// Extract name from tags
+	if connection.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range connection.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Count tunnels reporting UP
+	tunnelsUp := 0
+	for _, telemetry := range connection.VgwTelemetry {
+		if telemetry.Status == types.TelemetryStatusUp {
+			tunnelsUp++
+		}
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["tunnels"] = len(connection.VgwTelemetry)
+	extra["tunnelsUp"] = tunnelsUp
+	if connection.CustomerGatewayId != nil {
+		extra["customerGatewayId"] = aws.ToString(connection.CustomerGatewayId)
+	}
+	if connection.VpnGatewayId != nil {
+		extra["vpnGatewayId"] = aws.ToString(connection.VpnGatewayId)
+	}
+	if connection.TransitGatewayId != nil {
+		extra["transitGatewayId"] = aws.ToString(connection.TransitGatewayId)
+	}
+	if connection.Category != nil {
+		extra["category"] = aws.ToString(connection.Category)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertNATGateway converts a NAT gateway to a Resource
+func (c *NetworkCollector) convertNATGateway(gateway types.NatGateway, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "network",
+		Region:    region,
+		ID:        aws.ToString(gateway.NatGatewayId),
+		Name:      aws.ToString(gateway.NatGatewayId),
+		Type:      "nat-gateway",
+		State:     string(gateway.State),
+		Class:     string(gateway.ConnectivityType),
+		CreatedAt: gateway.CreateTime,
+	}
+
+	// Extract name from tags
+	if gateway.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range gateway.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if gateway.VpcId != nil {
+		extra["vpcId"] = aws.ToString(gateway.VpcId)
+	}
+	if gateway.SubnetId != nil {
+		extra["subnetId"] = aws.ToString(gateway.SubnetId)
+	}
+	var publicIPs []string
+	for _, address := range gateway.NatGatewayAddresses {
+		if address.PublicIp != nil {
+			publicIPs = append(publicIPs, aws.ToString(address.PublicIp))
+		}
+	}
+	if len(publicIPs) > 0 {
+		extra["publicIps"] = publicIPs
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertSecurityGroup converts a security group to a Resource
+func (c *NetworkCollector) convertSecurityGroup(group types.SecurityGroup, region string) models.Resource {
+	resource := models.Resource{
+		Service: "network",
+		Region:  region,
+		ID:      aws.ToString(group.GroupId),
+		Name:    aws.ToString(group.GroupName),
+		Type:    "security-group",
+		State:   "available", // Security groups have no state
+	}
+
+	// Extract name from tags
+	if group.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range group.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Record the inbound rules open to address ranges. Rules allowing other security
+	// groups or prefix lists are left out.
+	var ingress []map[string]interface{}
+	for _, permission := range group.IpPermissions {
+		var cidrs []string
+		for _, ipRange := range permission.IpRanges {
+			cidrs = append(cidrs, aws.ToString(ipRange.CidrIp))
+		}
+		for _, ipRange := range permission.Ipv6Ranges {
+			cidrs = append(cidrs, aws.ToString(ipRange.CidrIpv6))
+		}
+		if len(cidrs) == 0 {
+			continue
+		}
+
+		rule := map[string]interface{}{
+			"protocol": aws.ToString(permission.IpProtocol),
+			"cidrs":    cidrs,
+		}
+		// Rules for all protocols have no ports
+		if permission.FromPort != nil {
+			rule["fromPort"] = aws.ToInt32(permission.FromPort)
+		}
+		if permission.ToPort != nil {
+			rule["toPort"] = aws.ToInt32(permission.ToPort)
+		}
+		ingress = append(ingress, rule)
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if group.VpcId != nil {
+		extra["vpcId"] = aws.ToString(group.VpcId)
+	}
+	if group.Description != nil {
+		extra["description"] = aws.ToString(group.Description)
+	}
+	if len(ingress) > 0 {
+		extra["ingress"] = ingress
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertNetworkInterface converts an elastic network interface to a Resource
+func (c *NetworkCollector) convertNetworkInterface(networkInterface types.NetworkInterface, region string) models.Resource {
+	resource := models.Resource{
+		Service: "network",
+		Region:  region,
+		ID:      aws.ToString(networkInterface.NetworkInterfaceId),
+		Name:    aws.ToString(networkInterface.NetworkInterfaceId),
+		Type:    "network-interface",
+		State:   string(networkInterface.Status),
+		Class:   string(networkInterface.InterfaceType),
+	}
+
+	// Extract name from tags
+	if networkInterface.TagSet != nil {
+		tags := make(map[string]string)
+		for _, tag := range networkInterface.TagSet {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if networkInterface.VpcId != nil {
+		extra["vpcId"] = aws.ToString(networkInterface.VpcId)
+	}
+	if networkInterface.SubnetId != nil {
+		extra["subnetId"] = aws.ToString(networkInterface.SubnetId)
+	}
+	if networkInterface.PrivateIpAddress != nil {
+		extra["privateIp"] = aws.ToString(networkInterface.PrivateIpAddress)
+	}
+	if networkInterface.Description != nil {
+		extra["description"] = aws.ToString(networkInterface.Description)
+	}
+	extra["requesterManaged"] = aws.ToBool(networkInterface.RequesterManaged)
+	if attachment := networkInterface.Attachment; attachment != nil && attachment.InstanceId != nil {
+		extra["instanceId"] = aws.ToString(attachment.InstanceId)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
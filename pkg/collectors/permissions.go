@@ -0,0 +1,175 @@
+package collectors
+
+// CommonPermissions are the IAM actions every run needs: region discovery, and the
+// pricing and Free Tier APIs used for cost estimates
+var CommonPermissions = []string{
+	"ec2:DescribeRegions",
+	"freetier:GetFreeTierUsage",
+	"pricing:GetProducts",
+}
+
+// Permissions lists the IAM actions each collector calls, keyed by service name.
+// Keep it in step with the API calls when changing a collector.
+var Permissions = map[string][]string{
+	"ec2": {
+		"ec2:DescribeInstances",
+		"ec2:DescribeVolumes",
+		"ec2:DescribeSpotPriceHistory",
+	},
+	"rds": {
+		"rds:DescribeDBInstances",
+	},
+	"lambda": {
+		"lambda:ListFunctions",
+		"lambda:GetFunction",
+		"lambda:GetFunctionConcurrency",
+		"lambda:ListProvisionedConcurrencyConfigs",
+		"lambda:ListEventSourceMappings",
+		"lambda:ListLayers",
+	},
+	"s3": {
+		"s3:ListAllMyBuckets",
+		"s3:GetBucketLocation",
+		"s3:GetBucketPolicyStatus",
+		"cloudwatch:ListMetrics",
+		"cloudwatch:GetMetricData",
+	},
+	"dynamodb": {
+		"dynamodb:ListTables",
+		"dynamodb:DescribeTable",
+		"dax:DescribeClusters",
+	},
+	"sfn": {
+		"states:ListStateMachines",
+		"states:DescribeStateMachine",
+	},
+	"cloudwatch": {
+		"cloudwatch:DescribeAlarms",
+		"cloudwatch:ListDashboards",
+		"cloudwatch:ListMetricStreams",
+		"cloudwatch:ListMetrics",
+	},
+	"eventbridge": {
+		"events:ListEventBuses",
+		"events:ListRules",
+		"events:ListTargetsByRule",
+		"scheduler:ListSchedules",
+	},
+	"ecs": {
+		"ecs:ListClusters",
+		"ecs:DescribeClusters",
+		"ecs:ListServices",
+		"ecs:DescribeServices",
+		"ecs:ListTasks",
+		"ecs:DescribeTasks",
+		"ecs:ListTaskDefinitionFamilies",
+		"ecs:DescribeTaskDefinition",
+	},
+	"redis": {
+		"elasticache:DescribeCacheClusters",
+	},
+	"efs": {
+		"elasticfilesystem:DescribeFileSystems",
+	},
+	"fsx": {
+		"fsx:DescribeFileSystems",
+	},
+	"governance": {
+		"cloudtrail:DescribeTrails",
+		"cloudtrail:GetTrailStatus",
+		"config:DescribeConfigurationRecorderStatus",
+		"guardduty:ListDetectors",
+		"guardduty:GetDetector",
+	},
+	"waf": {
+		"wafv2:ListWebACLs",
+		"wafv2:GetWebACL",
+		"wafv2:ListResourcesForWebACL",
+	},
+	"shield": {
+		"shield:DescribeSubscription",
+		"shield:ListProtections",
+	},
+	"eip": {
+		"ec2:DescribeAddresses",
+	},
+	"ebs": {
+		"ec2:DescribeVolumes",
+		"ec2:DescribeSnapshots",
+	},
+	"elb": {
+		"elasticloadbalancing:DescribeLoadBalancers",
+		"elasticloadbalancing:DescribeTargetGroups",
+	},
+	"quotas": {
+		"servicequotas:GetServiceQuota",
+		"servicequotas:GetAWSDefaultServiceQuota",
+		"ec2:DescribeInstances",
+		"ec2:DescribeAddresses",
+		"ec2:DescribeVpcs",
+		"ec2:DescribeInternetGateways",
+	},
+	"ami": {
+		"ec2:DescribeImages",
+		"ec2:DescribeInstances",
+	},
+	"network": {
+		"ec2:DescribeTransitGateways",
+		"ec2:DescribeTransitGatewayAttachments",
+		"ec2:DescribeVpnConnections",
+		"ec2:DescribeNatGateways",
+		"ec2:DescribeSecurityGroups",
+		"ec2:DescribeNetworkInterfaces",
+	},
+	"directconnect": {
+		"directconnect:DescribeConnections",
+		"directconnect:DescribeVirtualInterfaces",
+	},
+	"apprunner": {
+		"apprunner:ListServices",
+		"apprunner:DescribeService",
+		"apprunner:DescribeAutoScalingConfiguration",
+	},
+	"amplify": {
+		"amplify:ListApps",
+		"amplify:ListBranches",
+	},
+	"backup": {
+		"backup:ListBackupVaults",
+		"backup:ListRecoveryPointsByBackupVault",
+		"backup:ListBackupPlans",
+		"backup:GetBackupPlan",
+		"backup:ListBackupSelections",
+		"backup:ListProtectedResources",
+	},
+	"batch": {
+		"batch:DescribeComputeEnvironments",
+		"batch:DescribeJobQueues",
+	},
+	"codebuild": {
+		"codebuild:ListProjects",
+		"codebuild:BatchGetProjects",
+		"codebuild:ListBuildsForProject",
+		"codebuild:BatchGetBuilds",
+	},
+	"codepipeline": {
+		"codepipeline:ListPipelines",
+		"codepipeline:ListPipelineExecutions",
+	},
+	"reservations": {
+		"ec2:DescribeReservedInstances",
+		"rds:DescribeReservedDBInstances",
+	},
+	"savingsplans": {
+		"savingsplans:DescribeSavingsPlans",
+	},
+	"iam": {
+		"iam:ListUsers",
+		"iam:ListAccessKeys",
+	},
+	// Cloud Control also calls the list and read actions of each resource type, which
+	// depend on the types configured
+	"cloudcontrol": {
+		"cloudformation:ListResources",
+	},
+}
@@ -0,0 +1,279 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicequotas"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// DefaultQuotaThreshold is the utilization percentage above which a quota is flagged
+const DefaultQuotaThreshold = 80.0
+
+// quotaCheck describes a service quota and how its current usage is counted
+type quotaCheck struct {
+	serviceCode string
+	quotaCode   string
+	name        string
+	usage       func(usage *regionUsage) float64
+}
+
+// trackedQuotas lists the quotas checked in every region
+var trackedQuotas = []quotaCheck{
+	{"vpc", "L-F678F1CE", "VPCs per Region", func(u *regionUsage) float64 { return float64(u.vpcs) }},
+	{"vpc", "L-A4707A72", "Internet gateways per Region", func(u *regionUsage) float64 { return float64(u.internetGateways) }},
+	{"ec2", "L-0263D0A3", "EC2-VPC Elastic IPs", func(u *regionUsage) float64 { return float64(u.elasticIPs) }},
+	{"ec2", "L-1216C47A", "Running On-Demand Standard instances (vCPUs)", func(u *regionUsage) float64 { return float64(u.onDemandStandardVcpus) }},
+	{"ec2", "L-34B43A08", "All Standard Spot Instance Requests (vCPUs)", func(u *regionUsage) float64 { return float64(u.spotStandardVcpus) }},
+}
+
+// regionUsage holds the resource counts that tracked quotas are compared against
+type regionUsage struct {
+	vpcs                  int
+	internetGateways      int
+	elasticIPs            int
+	onDemandStandardVcpus int32
+	spotStandardVcpus     int32
+}
+
+// QuotasCollector collects key Service Quotas with their current usage
+type QuotasCollector struct {
+	clientManager *awspkg.ClientManager
+	threshold     float64
+}
+
+// NewQuotasCollector creates a new quotas collector. Quotas whose utilization is at
+// or above threshold percent are flagged; a threshold of 0 uses DefaultQuotaThreshold.
+func NewQuotasCollector(clientManager *awspkg.ClientManager, threshold float64) *QuotasCollector {
+	if threshold <= 0 {
+		threshold = DefaultQuotaThreshold
+	}
+	return &QuotasCollector{
+		clientManager: clientManager,
+		threshold:     threshold,
+	}
+}
+
+// Name returns the service name
+func (c *QuotasCollector) Name() string {
+	return "quotas"
+}
+
+// Regions returns the regions this collector supports
+func (c *QuotasCollector) Regions() []string {
+	// Quotas are regional and checked in every region
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *QuotasCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves tracked quotas and their utilization for the given region
+func (c *QuotasCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	quotasClient := servicequotas.NewFromConfig(cfg)
+
+	usage, err := c.countUsage(ctx, ec2.NewFromConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count quota usage in %s: %w", region, err)
+	}
+
+	var resources []models.Resource
+	for _, check := range trackedQuotas {
+		limit, adjustable, err := c.getQuotaValue(ctx, quotasClient, check)
+		if err != nil {
+			// Log error but continue with other quotas
+			models.Warnf(ctx, "failed to get quota %s in %s: %v", check.quotaCode, region, err)
+			continue
+		}
+
+		resource := c.convertQuota(check, limit, check.usage(usage), adjustable, region)
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// getQuotaValue returns the applied quota value, falling back to the AWS default
+// when the quota has never been changed for the account
+func (c *QuotasCollector) getQuotaValue(ctx context.Context, client *servicequotas.Client, check quotaCheck) (float64, bool, error) {
+	applied, err := client.GetServiceQuota(ctx, &servicequotas.GetServiceQuotaInput{
+		ServiceCode: aws.String(check.serviceCode),
+		QuotaCode:   aws.String(check.quotaCode),
+	})
+	if err == nil && applied.Quota != nil && applied.Quota.Value != nil {
+		return aws.ToFloat64(applied.Quota.Value), applied.Quota.Adjustable, nil
+	}
+
+	defaults, defaultErr := client.GetAWSDefaultServiceQuota(ctx, &servicequotas.GetAWSDefaultServiceQuotaInput{
+		ServiceCode: aws.String(check.serviceCode),
+		QuotaCode:   aws.String(check.quotaCode),
+	})
+	if defaultErr != nil {
+		if err != nil {
+			return 0, false, err
+		}
+		return 0, false, defaultErr
+	}
+	if defaults.Quota == nil || defaults.Quota.Value == nil {
+		return 0, false, fmt.Errorf("quota has no value")
+	}
+
+	return aws.ToFloat64(defaults.Quota.Value), defaults.Quota.Adjustable, nil
+}
+
+// countUsage counts the resources that tracked quotas apply to
+func (c *QuotasCollector) countUsage(ctx context.Context, client *ec2.Client) (*regionUsage, error) {
+	usage := &regionUsage{}
+
+	var nextToken *string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{NextToken: nextToken})
+		if err != nil {
+			return nil, err
+		}
+		usage.vpcs += len(result.Vpcs)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	nextToken = nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := client.DescribeInternetGateways(ctx, &ec2.DescribeInternetGatewaysInput{NextToken: nextToken})
+		if err != nil {
+			return nil, err
+		}
+		usage.internetGateways += len(result.InternetGateways)
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	// DescribeAddresses is not paginated
+	addresses, err := client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, err
+	}
+	usage.elasticIPs = len(addresses.Addresses)
+
+	// vCPU quotas count running and pending instances
+	nextToken = nil
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+			Filters: []ec2types.Filter{
+				{
+					Name:   aws.String("instance-state-name"),
+					Values: []string{"pending", "running"},
+				},
+			},
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reservation := range result.Reservations {
+			for _, instance := range reservation.Instances {
+				if !isStandardInstanceFamily(string(instance.InstanceType)) {
+					continue
+				}
+				vcpus := instanceVcpus(instance)
+				if instance.InstanceLifecycle == ec2types.InstanceLifecycleTypeSpot {
+					usage.spotStandardVcpus += vcpus
+				} else {
+					usage.onDemandStandardVcpus += vcpus
+				}
+			}
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return usage, nil
+}
+
+// convertQuota converts a quota and its usage to a Resource
+func (c *QuotasCollector) convertQuota(check quotaCheck, limit, used float64, adjustable bool, region string) models.Resource {
+	utilization := 0.0
+	if limit > 0 {
+		utilization = used / limit * 100
+	}
+
+	state := "ok"
+	if utilization >= c.threshold {
+		state = "near-limit"
+	}
+
+	return models.Resource{
+		Service: "quotas",
+		Region:  region,
+		ID:      fmt.Sprintf("%s/%s", check.serviceCode, check.quotaCode),
+		Name:    check.name,
+		Type:    check.serviceCode,
+		State:   state,
+		Class:   fmt.Sprintf("%.0f%%", utilization),
+		Extra: map[string]interface{}{
+			"quotaCode":   check.quotaCode,
+			"limit":       limit,
+			"usage":       used,
+			"utilization": utilization,
+			"threshold":   c.threshold,
+			"adjustable":  adjustable,
+		},
+	}
+}
+
+// isStandardInstanceFamily reports whether an instance type counts towards the
+// Standard (A, C, D, H, I, M, R, T, Z) vCPU quota
+func isStandardInstanceFamily(instanceType string) bool {
+	if instanceType == "" {
+		return false
+	}
+	// These families share a leading letter but have their own quotas
+	for _, prefix := range []string{"inf", "hpc", "mac", "trn", "dl"} {
+		if strings.HasPrefix(instanceType, prefix) {
+			return false
+		}
+	}
+	return strings.ContainsRune("acdhimrtz", rune(instanceType[0]))
+}
+
+// instanceVcpus returns the number of vCPUs of an instance from its CPU options
+func instanceVcpus(instance ec2types.Instance) int32 {
+	if instance.CpuOptions == nil {
+		return 0
+	}
+	threads := aws.ToInt32(instance.CpuOptions.ThreadsPerCore)
+	if threads == 0 {
+		threads = 1
+	}
+	return aws.ToInt32(instance.CpuOptions.CoreCount) * threads
+}
@@ -2,6 +2,7 @@ package collectors
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -34,6 +35,11 @@ func (c *RDSCollector) Regions() []string {
 	return nil // Will be populated by the orchestrator
 }
 
+// Scope returns the scope of the collector's resources
+func (c *RDSCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
 // Collect retrieves RDS database instances for the given region
 func (c *RDSCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
 	cfg := c.clientManager.GetConfig(region)
@@ -43,6 +49,10 @@ func (c *RDSCollector) Collect(ctx context.Context, region string) ([]models.Res
 	var marker *string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		input := &rds.DescribeDBInstancesInput{
 			Marker: marker,
 		}
@@ -66,6 +76,29 @@ func (c *RDSCollector) Collect(ctx context.Context, region string) ([]models.Res
 	return resources, nil
 }
 
+// Describe retrieves a single RDS DB instance by identifier
+func (c *RDSCollector) Describe(ctx context.Context, region, id string) (*models.Resource, interface{}, error) {
+	client := rds.NewFromConfig(c.clientManager.GetConfig(region))
+
+	result, err := client.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(id),
+	})
+	if err != nil {
+		var notFound *types.DBInstanceNotFoundFault
+		if errors.As(err, &notFound) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to describe DB instance %s in %s: %w", id, region, err)
+	}
+
+	for _, instance := range result.DBInstances {
+		resource := c.convertDBInstance(instance, region)
+		return &resource, instance, nil
+	}
+
+	return nil, nil, nil
+}
+
 // convertDBInstance converts an RDS DB instance to a Resource
 func (c *RDSCollector) convertDBInstance(instance types.DBInstance, region string) models.Resource {
 	resource := models.Resource{
@@ -84,6 +117,15 @@ func (c *RDSCollector) convertDBInstance(instance types.DBInstance, region strin
 		resource.CreatedAt = &createdAt
 	}
 
+	// Tags come with the instance
+	if len(instance.TagList) > 0 {
+		tags := make(map[string]string, len(instance.TagList))
+		for _, tag := range instance.TagList {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		resource.Tags = tags
+	}
+
 	// Add extra information
 	extra := make(map[string]interface{})
 	if instance.EngineVersion != nil {
@@ -111,6 +153,12 @@ func (c *RDSCollector) convertDBInstance(instance types.DBInstance, region strin
 	if instance.StorageType != nil {
 		extra["storageType"] = aws.ToString(instance.StorageType)
 	}
+	if instance.Iops != nil {
+		extra["iops"] = aws.ToInt32(instance.Iops)
+	}
+	if instance.StorageThroughput != nil {
+		extra["storageThroughput"] = aws.ToInt32(instance.StorageThroughput)
+	}
 	if instance.LicenseModel != nil {
 		extra["licenseModel"] = aws.ToString(instance.LicenseModel)
 	}
@@ -126,6 +174,9 @@ func (c *RDSCollector) convertDBInstance(instance types.DBInstance, region strin
 	if instance.PreferredMaintenanceWindow != nil {
 		extra["preferredMaintenanceWindow"] = aws.ToString(instance.PreferredMaintenanceWindow)
 	}
+	if instance.DBSubnetGroup != nil && instance.DBSubnetGroup.VpcId != nil {
+		extra["vpcId"] = aws.ToString(instance.DBSubnetGroup.VpcId)
+	}
 
 	resource.Extra = extra
 
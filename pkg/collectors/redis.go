@@ -34,6 +34,11 @@ func (c *RedisCollector) Regions() []string {
 	return nil // Will be populated by the orchestrator
 }
 
+// Scope returns the scope of the collector's resources
+func (c *RedisCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
 // Collect retrieves ElastiCache Redis clusters for the given region
 func (c *RedisCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
 	cfg := c.clientManager.GetConfig(region)
@@ -43,6 +48,10 @@ func (c *RedisCollector) Collect(ctx context.Context, region string) ([]models.R
 	var marker *string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		input := &elasticache.DescribeCacheClustersInput{
 			Marker: marker,
 		}
@@ -69,6 +78,30 @@ func (c *RedisCollector) Collect(ctx context.Context, region string) ([]models.R
 	return resources, nil
 }
 
+// AddTags looks up the tags of the clusters
+func (c *RedisCollector) AddTags(ctx context.Context, region string, resources []models.Resource) {
+	client := elasticache.NewFromConfig(c.clientManager.GetConfig(region))
+
+	hasArn := func(resource models.Resource) bool {
+		_, ok := resource.Extra["arn"].(string)
+		return ok
+	}
+	fetchTags(ctx, resources, hasArn, func(ctx context.Context, resource models.Resource) (map[string]string, error) {
+		arn, _ := resource.Extra["arn"].(string)
+		result, err := client.ListTagsForResource(ctx, &elasticache.ListTagsForResourceInput{
+			ResourceName: aws.String(arn),
+		})
+		if err != nil {
+			return nil, err
+		}
+		tags := make(map[string]string, len(result.TagList))
+		for _, tag := range result.TagList {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		return tags, nil
+	})
+}
+
 // convertCacheCluster converts an ElastiCache cluster to a Resource
 func (c *RedisCollector) convertCacheCluster(cluster types.CacheCluster, region string) models.Resource {
 	resource := models.Resource{
@@ -89,6 +122,9 @@ func (c *RedisCollector) convertCacheCluster(cluster types.CacheCluster, region
 
 	// Add extra information
 	extra := make(map[string]interface{})
+	if cluster.ARN != nil {
+		extra["arn"] = aws.ToString(cluster.ARN)
+	}
 	if cluster.EngineVersion != nil {
 		extra["engineVersion"] = aws.ToString(cluster.EngineVersion)
 	}
@@ -0,0 +1,221 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// ReservationsCollector collects the active EC2 Reserved Instances and RDS reserved
+// DB instances, which cost estimates match against the instances they cover
+type ReservationsCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewReservationsCollector creates a new reservations collector
+func NewReservationsCollector(clientManager *awspkg.ClientManager) *ReservationsCollector {
+	return &ReservationsCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *ReservationsCollector) Name() string {
+	return "reservations"
+}
+
+// Regions returns the regions this collector supports
+func (c *ReservationsCollector) Regions() []string {
+	// Reservations are available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *ReservationsCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves the active reservations for the given region
+func (c *ReservationsCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+
+	var resources []models.Resource
+
+	ec2Reservations, err := c.collectEC2Reservations(ctx, ec2.NewFromConfig(cfg), region)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, ec2Reservations...)
+
+	rdsReservations, err := c.collectRDSReservations(ctx, rds.NewFromConfig(cfg), region)
+	if err != nil {
+		return nil, err
+	}
+	resources = append(resources, rdsReservations...)
+
+	return resources, nil
+}
+
+// collectEC2Reservations lists the active EC2 Reserved Instances in a region
+func (c *ReservationsCollector) collectEC2Reservations(ctx context.Context, client *ec2.Client, region string) ([]models.Resource, error) {
+	// DescribeReservedInstances is not paginated. Retired and expired reservations
+	// cover nothing, so only active ones are listed.
+	result, err := client.DescribeReservedInstances(ctx, &ec2.DescribeReservedInstancesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("state"), Values: []string{"active"}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe reserved instances in %s: %w", region, err)
+	}
+
+	var resources []models.Resource
+	for _, reservation := range result.ReservedInstances {
+		resource := c.convertEC2Reservation(reservation, region)
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
+
+// collectRDSReservations lists the active RDS reserved DB instances in a region
+func (c *ReservationsCollector) collectRDSReservations(ctx context.Context, client *rds.Client, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var marker *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &rds.DescribeReservedDBInstancesInput{
+			Marker: marker,
+		}
+
+		result, err := client.DescribeReservedDBInstances(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe reserved DB instances in %s: %w", region, err)
+		}
+
+		for _, reservation := range result.ReservedDBInstances {
+			if aws.ToString(reservation.State) != "active" {
+				continue
+			}
+			resource := c.convertRDSReservation(reservation, region)
+			resources = append(resources, resource)
+		}
+
+		marker = result.Marker
+		if marker == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertEC2Reservation converts an EC2 Reserved Instance to a Resource
+func (c *ReservationsCollector) convertEC2Reservation(reservation ec2types.ReservedInstances, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "reservations",
+		Region:    region,
+		ID:        aws.ToString(reservation.ReservedInstancesId),
+		Name:      aws.ToString(reservation.ReservedInstancesId),
+		Type:      "ec2-reserved-instance",
+		State:     string(reservation.State),
+		Class:     string(reservation.InstanceType),
+		CreatedAt: reservation.Start,
+	}
+
+	// Extract name from tags
+	if reservation.Tags != nil {
+		tags := make(map[string]string)
+		for _, tag := range reservation.Tags {
+			if tag.Key != nil && tag.Value != nil {
+				tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+				if aws.ToString(tag.Key) == "Name" {
+					resource.Name = aws.ToString(tag.Value)
+				}
+			}
+		}
+		resource.Tags = tags
+	}
+
+	// Hourly recurring charges are billed whether or not the reservation is used
+	recurringHourly := 0.0
+	for _, charge := range reservation.RecurringCharges {
+		if charge.Frequency == ec2types.RecurringChargeFrequencyHourly {
+			recurringHourly += aws.ToFloat64(charge.Amount)
+		}
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["instanceType"] = string(reservation.InstanceType)
+	extra["instanceCount"] = aws.ToInt32(reservation.InstanceCount)
+	extra["scope"] = string(reservation.Scope)
+	if reservation.AvailabilityZone != nil {
+		extra["availabilityZone"] = aws.ToString(reservation.AvailabilityZone)
+	}
+	extra["productDescription"] = string(reservation.ProductDescription)
+	extra["offeringClass"] = string(reservation.OfferingClass)
+	extra["offeringType"] = string(reservation.OfferingType)
+	extra["fixedPrice"] = float64(aws.ToFloat32(reservation.FixedPrice))
+	extra["usagePrice"] = float64(aws.ToFloat32(reservation.UsagePrice))
+	extra["recurringHourly"] = recurringHourly
+	extra["durationSeconds"] = aws.ToInt64(reservation.Duration)
+	if reservation.End != nil {
+		extra["end"] = aws.ToTime(reservation.End)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertRDSReservation converts an RDS reserved DB instance to a Resource
+func (c *ReservationsCollector) convertRDSReservation(reservation rdstypes.ReservedDBInstance, region string) models.Resource {
+	resource := models.Resource{
+		Service:   "reservations",
+		Region:    region,
+		ID:        aws.ToString(reservation.ReservedDBInstanceId),
+		Name:      aws.ToString(reservation.ReservedDBInstanceId),
+		Type:      "rds-reserved-instance",
+		State:     aws.ToString(reservation.State),
+		Class:     aws.ToString(reservation.DBInstanceClass),
+		CreatedAt: reservation.StartTime,
+	}
+
+	recurringHourly := 0.0
+	for _, charge := range reservation.RecurringCharges {
+		if aws.ToString(charge.RecurringChargeFrequency) == "Hourly" {
+			recurringHourly += aws.ToFloat64(charge.RecurringChargeAmount)
+		}
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["instanceType"] = aws.ToString(reservation.DBInstanceClass)
+	extra["instanceCount"] = aws.ToInt32(reservation.DBInstanceCount)
+	extra["multiAZ"] = aws.ToBool(reservation.MultiAZ)
+	extra["productDescription"] = aws.ToString(reservation.ProductDescription)
+	extra["offeringType"] = aws.ToString(reservation.OfferingType)
+	extra["fixedPrice"] = aws.ToFloat64(reservation.FixedPrice)
+	extra["usagePrice"] = aws.ToFloat64(reservation.UsagePrice)
+	extra["recurringHourly"] = recurringHourly
+	extra["durationSeconds"] = int64(aws.ToInt32(reservation.Duration))
+	if reservation.ReservedDBInstanceArn != nil {
+		extra["arn"] = aws.ToString(reservation.ReservedDBInstanceArn)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
@@ -2,24 +2,54 @@ package collectors
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	awspkg "github.com/xiaochen/awsinv/pkg/aws"
 	"github.com/xiaochen/awsinv/pkg/models"
 )
 
-// S3Collector collects S3 buckets
+// s3StorageWindow is how far back the storage metrics of buckets are searched. S3
+// publishes them once a day, sometimes a day or two late.
+const s3StorageWindow = 3 * 24 * time.Hour
+
+// bucketWorkers is how many buckets are looked up at once
+const bucketWorkers = 10
+
+// BucketDetailPermissions are the IAM actions looking up the security settings of
+// buckets needs, on top of those of the S3 collector. They are not needed when the
+// bucket details are skipped.
+var BucketDetailPermissions = []string{
+	"s3:GetBucketVersioning",
+	"s3:GetEncryptionConfiguration",
+	"s3:GetBucketPublicAccessBlock",
+	"s3:GetLifecycleConfiguration",
+	"s3:GetBucketLogging",
+}
+
+// S3Collector collects S3 buckets with their size, object count and security
+// settings. Buckets are listed once for the account and reported in the region they
+// live in.
 type S3Collector struct {
 	clientManager *awspkg.ClientManager
+	skipDetails   bool
 }
 
-// NewS3Collector creates a new S3 collector
-func NewS3Collector(clientManager *awspkg.ClientManager) *S3Collector {
+// NewS3Collector creates a new S3 collector. With skipDetails, the versioning,
+// encryption, public access block, lifecycle and logging settings of the buckets,
+// which take an API call each per bucket, are not looked up.
+func NewS3Collector(clientManager *awspkg.ClientManager, skipDetails bool) *S3Collector {
 	return &S3Collector{
 		clientManager: clientManager,
+		skipDetails:   skipDetails,
 	}
 }
 
@@ -30,13 +60,18 @@ func (c *S3Collector) Name() string {
 
 // Regions returns the regions this collector supports
 func (c *S3Collector) Regions() []string {
-	// S3 buckets are global, but we'll use us-east-1 for the API calls
-	return []string{"us-east-1"}
+	// S3 buckets are listed once for the account, see Scope
+	return nil
+}
+
+// Scope returns the scope of the collector's resources
+func (c *S3Collector) Scope() models.Scope {
+	return models.ScopeGlobal
 }
 
 // Collect retrieves S3 buckets
 func (c *S3Collector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
-	// S3 buckets are global, so we use us-east-1 for the API calls
+	// Buckets of every region are listed from us-east-1
 	cfg := c.clientManager.GetConfig("us-east-1")
 	client := s3.NewFromConfig(cfg)
 
@@ -55,14 +90,428 @@ func (c *S3Collector) Collect(ctx context.Context, region string) ([]models.Reso
 		resources = append(resources, resource)
 	}
 
+	byRegion := c.groupByRegion(ctx, client, resources)
+	c.addStorageMetrics(ctx, resources, byRegion)
+	c.addPolicyStatus(ctx, resources, byRegion)
+	if !c.skipDetails {
+		c.addSecuritySettings(ctx, resources, byRegion)
+	}
+
 	return resources, nil
 }
 
+// forEachBucket calls work with each of the indexes, from bucketWorkers goroutines at
+// once, within the API rate limits. No more work is started once the context is done.
+func forEachBucket(ctx context.Context, indexes []int, work func(i int)) {
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(bucketWorkers, len(indexes)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				work(i)
+			}
+		}()
+	}
+	for _, i := range indexes {
+		if ctx.Err() != nil {
+			break
+		}
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+}
+
+// groupByRegion sets the region of each bucket and returns the indexes of the
+// buckets in each region. The regions are looked up concurrently. Buckets whose
+// region cannot be found stay global and are left out with a warning.
+func (c *S3Collector) groupByRegion(ctx context.Context, client *s3.Client, resources []models.Resource) map[string][]int {
+	regions := make([]string, len(resources))
+	indexes := make([]int, len(resources))
+	for i := range resources {
+		indexes[i] = i
+	}
+	forEachBucket(ctx, indexes, func(i int) {
+		region, err := getBucketRegion(ctx, client, resources[i].ID)
+		if err != nil {
+			models.Warnf(ctx, "failed to find the region of bucket %s: %v", resources[i].ID, err)
+			return
+		}
+		regions[i] = region
+	})
+
+	byRegion := make(map[string][]int)
+	for i, region := range regions {
+		if region == "" {
+			continue
+		}
+		resources[i].Region = region
+		byRegion[region] = append(byRegion[region], i)
+	}
+	return byRegion
+}
+
+// addStorageMetrics records the size of each bucket by storage class and its object
+// count, from the daily S3 storage metrics in CloudWatch. The metrics are published in
+// the region of the bucket.
+func (c *S3Collector) addStorageMetrics(ctx context.Context, resources []models.Resource, byRegion map[string][]int) {
+	regions := make([]string, 0, len(byRegion))
+	for region := range byRegion {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	for _, region := range regions {
+		if ctx.Err() != nil {
+			return
+		}
+		cwClient := cloudwatch.NewFromConfig(c.clientManager.GetConfig(region))
+		if err := c.collectStorageMetrics(ctx, cwClient, resources, byRegion[region]); err != nil {
+			models.Warnf(ctx, "failed to get bucket storage metrics in %s: %v", region, err)
+		}
+	}
+}
+
+// addPolicyStatus records whether each bucket's policy makes it public. The policy
+// status has to be requested from the region of the bucket.
+func (c *S3Collector) addPolicyStatus(ctx context.Context, resources []models.Resource, byRegion map[string][]int) {
+	for region, indexes := range byRegion {
+		client := s3.NewFromConfig(c.clientManager.GetConfig(region))
+		for _, i := range indexes {
+			if err := ctx.Err(); err != nil {
+				return
+			}
+
+			public, err := getBucketPublic(ctx, client, resources[i].ID)
+			if err != nil {
+				models.Warnf(ctx, "failed to get the policy status of bucket %s: %v", resources[i].ID, err)
+				continue
+			}
+			resources[i].Extra["public"] = public
+		}
+	}
+}
+
+// bucketSetting looks up one security setting of a bucket and records it in the
+// bucket's extra information
+type bucketSetting struct {
+	name   string
+	lookup func(ctx context.Context, client *s3.Client, bucket string, extra map[string]interface{}) error
+}
+
+// bucketSettings are the security settings recorded for each bucket
+var bucketSettings = []bucketSetting{
+	{name: "versioning", lookup: addBucketVersioning},
+	{name: "default encryption", lookup: addBucketEncryption},
+	{name: "public access block", lookup: addBucketPublicAccessBlock},
+	{name: "lifecycle rules", lookup: addBucketLifecycle},
+	{name: "logging status", lookup: addBucketLogging},
+}
+
+// addSecuritySettings records the versioning status, default encryption, public
+// access block, lifecycle rule count and access logging status of each bucket. The
+// buckets are looked up concurrently, from their own regions.
+func (c *S3Collector) addSecuritySettings(ctx context.Context, resources []models.Resource, byRegion map[string][]int) {
+	clients := make(map[string]*s3.Client, len(byRegion))
+	var indexes []int
+	for region, regionIndexes := range byRegion {
+		clients[region] = s3.NewFromConfig(c.clientManager.GetConfig(region))
+		indexes = append(indexes, regionIndexes...)
+	}
+	sort.Ints(indexes)
+
+	forEachBucket(ctx, indexes, func(i int) {
+		client := clients[resources[i].Region]
+		for _, setting := range bucketSettings {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := setting.lookup(ctx, client, resources[i].ID, resources[i].Extra); err != nil {
+				models.Warnf(ctx, "failed to get the %s of bucket %s: %v", setting.name, resources[i].ID, err)
+			}
+		}
+	})
+}
+
+// isErrorCode reports whether err is an API error with the given code
+func isErrorCode(err error, code string) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == code
+}
+
+// addBucketVersioning records the versioning status of a bucket: Enabled, Suspended,
+// or Disabled for buckets versioning was never enabled on
+func addBucketVersioning(ctx context.Context, client *s3.Client, bucket string, extra map[string]interface{}) error {
+	result, err := client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return err
+	}
+	status := string(result.Status)
+	if status == "" {
+		status = "Disabled"
+	}
+	extra["versioning"] = status
+	if result.MFADelete == types.MFADeleteStatusEnabled {
+		extra["mfaDelete"] = true
+	}
+	return nil
+}
+
+// addBucketEncryption records the default encryption algorithm of a bucket, such as
+// AES256 or aws:kms, or none for buckets without default encryption
+func addBucketEncryption(ctx context.Context, client *s3.Client, bucket string, extra map[string]interface{}) error {
+	result, err := client.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if isErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError") {
+			extra["encryption"] = "none"
+			return nil
+		}
+		return err
+	}
+
+	extra["encryption"] = "none"
+	if result.ServerSideEncryptionConfiguration == nil {
+		return nil
+	}
+	for _, rule := range result.ServerSideEncryptionConfiguration.Rules {
+		if rule.ApplyServerSideEncryptionByDefault == nil {
+			continue
+		}
+		extra["encryption"] = string(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+		if keyID := aws.ToString(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID); keyID != "" {
+			extra["kmsKeyId"] = keyID
+		}
+		break
+	}
+	return nil
+}
+
+// addBucketPublicAccessBlock records the public access block settings of a bucket.
+// Buckets without a configuration block nothing themselves.
+func addBucketPublicAccessBlock(ctx context.Context, client *s3.Client, bucket string, extra map[string]interface{}) error {
+	result, err := client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil && !isErrorCode(err, "NoSuchPublicAccessBlockConfiguration") {
+		return err
+	}
+
+	var config types.PublicAccessBlockConfiguration
+	if err == nil && result.PublicAccessBlockConfiguration != nil {
+		config = *result.PublicAccessBlockConfiguration
+	}
+	extra["publicAccessBlock"] = map[string]interface{}{
+		"blockPublicAcls":       aws.ToBool(config.BlockPublicAcls),
+		"ignorePublicAcls":      aws.ToBool(config.IgnorePublicAcls),
+		"blockPublicPolicy":     aws.ToBool(config.BlockPublicPolicy),
+		"restrictPublicBuckets": aws.ToBool(config.RestrictPublicBuckets),
+	}
+	return nil
+}
+
+// addBucketLifecycle records how many lifecycle rules a bucket has
+func addBucketLifecycle(ctx context.Context, client *s3.Client, bucket string, extra map[string]interface{}) error {
+	result, err := client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if isErrorCode(err, "NoSuchLifecycleConfiguration") {
+			extra["lifecycleRules"] = 0
+			return nil
+		}
+		return err
+	}
+	extra["lifecycleRules"] = len(result.Rules)
+	return nil
+}
+
+// addBucketLogging records whether a bucket logs server access, and where to
+func addBucketLogging(ctx context.Context, client *s3.Client, bucket string, extra map[string]interface{}) error {
+	result, err := client.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return err
+	}
+	extra["accessLogging"] = result.LoggingEnabled != nil
+	if result.LoggingEnabled != nil {
+		extra["logTargetBucket"] = aws.ToString(result.LoggingEnabled.TargetBucket)
+	}
+	return nil
+}
+
+// AddTags looks up the tags of the buckets, from the region of each bucket
+func (c *S3Collector) AddTags(ctx context.Context, region string, resources []models.Resource) {
+	clients := make(map[string]*s3.Client)
+
+	hasRegion := func(resource models.Resource) bool {
+		return resource.Region != models.GlobalRegion
+	}
+	fetchTags(ctx, resources, hasRegion, func(ctx context.Context, resource models.Resource) (map[string]string, error) {
+		client, exists := clients[resource.Region]
+		if !exists {
+			client = s3.NewFromConfig(c.clientManager.GetConfig(resource.Region))
+			clients[resource.Region] = client
+		}
+		return getBucketTags(ctx, client, resource.ID)
+	})
+}
+
+// getBucketTags returns the tags of a bucket. Buckets without tags report an error
+// rather than an empty tag set.
+func getBucketTags(ctx context.Context, client *s3.Client, bucket string) (map[string]string, error) {
+	result, err := client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if isErrorCode(err, "NoSuchTagSet") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	tags := make(map[string]string, len(result.TagSet))
+	for _, tag := range result.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+// getBucketPublic reports whether a bucket's policy grants public access. Buckets
+// without a policy are not public through one.
+func getBucketPublic(ctx context.Context, client *s3.Client, bucket string) (bool, error) {
+	result, err := client.GetBucketPolicyStatus(ctx, &s3.GetBucketPolicyStatusInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if isErrorCode(err, "NoSuchBucketPolicy") {
+			return false, nil
+		}
+		return false, err
+	}
+	if result.PolicyStatus == nil {
+		return false, nil
+	}
+	return aws.ToBool(result.PolicyStatus.IsPublic), nil
+}
+
+// collectStorageMetrics sets the size and object count of the buckets at the given
+// indexes, all in the region of the client
+func (c *S3Collector) collectStorageMetrics(ctx context.Context, client *cloudwatch.Client, resources []models.Resource, indexes []int) error {
+	buckets := make(map[string]int, len(indexes))
+	for _, i := range indexes {
+		buckets[resources[i].ID] = i
+	}
+
+	// Listing the metrics tells which storage classes each bucket has data in
+	var owners []int
+	var queries []metricQuery
+	var nextToken *string
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		input := &cloudwatch.ListMetricsInput{
+			Namespace: aws.String("AWS/S3"),
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListMetrics(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		for _, metric := range result.Metrics {
+			name := aws.ToString(metric.MetricName)
+			if name != "BucketSizeBytes" && name != "NumberOfObjects" {
+				continue
+			}
+
+			dimensions := make(map[string]string, len(metric.Dimensions))
+			for _, dimension := range metric.Dimensions {
+				dimensions[aws.ToString(dimension.Name)] = aws.ToString(dimension.Value)
+			}
+			i, ok := buckets[dimensions["BucketName"]]
+			if !ok {
+				continue
+			}
+
+			owners = append(owners, i)
+			queries = append(queries, metricQuery{namespace: "AWS/S3", name: name, stat: "Average", dimensions: dimensions, latest: true})
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	end := time.Now().UTC()
+	values, err := getMetricData(ctx, client, queries, end.Add(-s3StorageWindow), end)
+	if err != nil {
+		return err
+	}
+
+	// S3 publishes no storage metrics for empty buckets
+	for _, i := range indexes {
+		resources[i].Extra["sizeBytes"] = int64(0)
+		resources[i].Extra["objectCount"] = int64(0)
+	}
+	for q, query := range queries {
+		extra := resources[owners[q]].Extra
+		value := int64(values[q])
+		if query.name == "NumberOfObjects" {
+			extra["objectCount"] = extra["objectCount"].(int64) + value
+			continue
+		}
+		if value == 0 {
+			continue
+		}
+
+		storageClasses, _ := extra["storageClasses"].(map[string]int64)
+		if storageClasses == nil {
+			storageClasses = make(map[string]int64)
+			extra["storageClasses"] = storageClasses
+		}
+		storageClasses[query.dimensions["StorageType"]] += value
+		extra["sizeBytes"] = extra["sizeBytes"].(int64) + value
+	}
+
+	return nil
+}
+
+// getBucketRegion returns the region of a bucket
+func getBucketRegion(ctx context.Context, client *s3.Client, bucket string) (string, error) {
+	result, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Buckets in us-east-1 have no location constraint, and old buckets in eu-west-1
+	// report EU
+	switch location := string(result.LocationConstraint); location {
+	case "":
+		return "us-east-1", nil
+	case "EU":
+		return "eu-west-1", nil
+	default:
+		return location, nil
+	}
+}
+
 // convertBucket converts an S3 bucket to a Resource
 func (c *S3Collector) convertBucket(bucket types.Bucket) models.Resource {
 	resource := models.Resource{
 		Service: "s3",
-		Region:  "global", // S3 buckets are global
+		Region:  models.GlobalRegion, // Until the bucket's region is looked up
 		ID:      aws.ToString(bucket.Name),
 		Name:    aws.ToString(bucket.Name),
 		Type:    "bucket",
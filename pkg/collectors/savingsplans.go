@@ -0,0 +1,134 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// SavingsPlansCollector collects the active Savings Plans, whose hourly commitment
+// cost estimates apply to the compute they cover
+type SavingsPlansCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewSavingsPlansCollector creates a new Savings Plans collector
+func NewSavingsPlansCollector(clientManager *awspkg.ClientManager) *SavingsPlansCollector {
+	return &SavingsPlansCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *SavingsPlansCollector) Name() string {
+	return "savingsplans"
+}
+
+// Regions returns the regions this collector supports
+func (c *SavingsPlansCollector) Regions() []string {
+	// Savings Plans belong to the account, see Scope
+	return nil
+}
+
+// Scope returns the scope of the collector's resources
+func (c *SavingsPlansCollector) Scope() models.Scope {
+	return models.ScopeGlobal
+}
+
+// Collect retrieves the active Savings Plans
+func (c *SavingsPlansCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	// The Savings Plans API is served from us-east-1
+	cfg := c.clientManager.GetConfig("us-east-1")
+	client := savingsplans.NewFromConfig(cfg)
+
+	var resources []models.Resource
+	var nextToken *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &savingsplans.DescribeSavingsPlansInput{
+			States:    []types.SavingsPlanState{types.SavingsPlanStateActive},
+			NextToken: nextToken,
+		}
+
+		result, err := client.DescribeSavingsPlans(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe savings plans: %w", err)
+		}
+
+		for _, plan := range result.SavingsPlans {
+			resource := c.convertSavingsPlan(plan)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertSavingsPlan converts a Savings Plan to a Resource
+func (c *SavingsPlansCollector) convertSavingsPlan(plan types.SavingsPlan) models.Resource {
+	resource := models.Resource{
+		Service: "savingsplans",
+		Region:  models.GlobalRegion, // Savings Plans apply across regions
+		ID:      aws.ToString(plan.SavingsPlanId),
+		Name:    aws.ToString(plan.SavingsPlanId),
+		Type:    string(plan.SavingsPlanType),
+		State:   string(plan.State),
+		Class:   aws.ToString(plan.Ec2InstanceFamily),
+		Tags:    plan.Tags,
+	}
+	if name, ok := plan.Tags["Name"]; ok {
+		resource.Name = name
+	}
+
+	// The API returns times and amounts as strings
+	if start, err := time.Parse(time.RFC3339, aws.ToString(plan.Start)); err == nil {
+		resource.CreatedAt = &start
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if commitment, err := strconv.ParseFloat(aws.ToString(plan.Commitment), 64); err == nil {
+		extra["commitmentHourly"] = commitment
+	}
+	if upfront, err := strconv.ParseFloat(aws.ToString(plan.UpfrontPaymentAmount), 64); err == nil {
+		extra["upfrontPayment"] = upfront
+	}
+	if recurring, err := strconv.ParseFloat(aws.ToString(plan.RecurringPaymentAmount), 64); err == nil {
+		extra["recurringPayment"] = recurring
+	}
+	// EC2 Instance Savings Plans apply to one instance family in one region
+	if plan.Region != nil {
+		extra["region"] = aws.ToString(plan.Region)
+	}
+	if plan.Ec2InstanceFamily != nil {
+		extra["instanceFamily"] = aws.ToString(plan.Ec2InstanceFamily)
+	}
+	extra["paymentOption"] = string(plan.PaymentOption)
+	extra["durationSeconds"] = plan.TermDurationInSeconds
+	if plan.End != nil {
+		extra["end"] = aws.ToString(plan.End)
+	}
+	if plan.SavingsPlanArn != nil {
+		extra["arn"] = aws.ToString(plan.SavingsPlanArn)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
@@ -34,6 +34,11 @@ func (c *SFNCollector) Regions() []string {
 	return nil // Will be populated by the orchestrator
 }
 
+// Scope returns the scope of the collector's resources
+func (c *SFNCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
 // Collect retrieves Step Functions state machines for the given region
 func (c *SFNCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
 	cfg := c.clientManager.GetConfig(region)
@@ -43,6 +48,10 @@ func (c *SFNCollector) Collect(ctx context.Context, region string) ([]models.Res
 	var nextToken *string
 
 	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		input := &sfn.ListStateMachinesInput{
 			MaxResults: 100,
 			NextToken:  nextToken,
@@ -58,7 +67,7 @@ func (c *SFNCollector) Collect(ctx context.Context, region string) ([]models.Res
 			stateMachineInfo, err := c.getStateMachineInfo(ctx, client, aws.ToString(stateMachine.StateMachineArn))
 			if err != nil {
 				// Log error but continue with other state machines
-				fmt.Printf("Warning: failed to get info for state machine %s: %v\n", aws.ToString(stateMachine.Name), err)
+				models.Warnf(ctx, "failed to get info for state machine %s: %v", aws.ToString(stateMachine.Name), err)
 				continue
 			}
 			resource := c.convertStateMachine(stateMachineInfo, region)
@@ -74,6 +83,29 @@ func (c *SFNCollector) Collect(ctx context.Context, region string) ([]models.Res
 	return resources, nil
 }
 
+// AddTags looks up the tags of the state machines
+func (c *SFNCollector) AddTags(ctx context.Context, region string, resources []models.Resource) {
+	client := sfn.NewFromConfig(c.clientManager.GetConfig(region))
+
+	isStateMachine := func(resource models.Resource) bool {
+		return resource.Type == "state-machine"
+	}
+	fetchTags(ctx, resources, isStateMachine, func(ctx context.Context, resource models.Resource) (map[string]string, error) {
+		stateMachineArn, _ := resource.Extra["stateMachineArn"].(string)
+		result, err := client.ListTagsForResource(ctx, &sfn.ListTagsForResourceInput{
+			ResourceArn: aws.String(stateMachineArn),
+		})
+		if err != nil {
+			return nil, err
+		}
+		tags := make(map[string]string, len(result.Tags))
+		for _, tag := range result.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		return tags, nil
+	})
+}
+
 // getStateMachineInfo retrieves detailed information about a Step Functions state machine
 func (c *SFNCollector) getStateMachineInfo(ctx context.Context, client *sfn.Client, stateMachineArn string) (*sfn.DescribeStateMachineOutput, error) {
 	input := &sfn.DescribeStateMachineInput{
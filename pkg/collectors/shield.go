@@ -0,0 +1,157 @@
+package collectors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/shield"
+	"github.com/aws/aws-sdk-go-v2/service/shield/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// ShieldCollector collects the Shield Advanced subscription and its protections
+type ShieldCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewShieldCollector creates a new Shield collector
+func NewShieldCollector(clientManager *awspkg.ClientManager) *ShieldCollector {
+	return &ShieldCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *ShieldCollector) Name() string {
+	return "shield"
+}
+
+// Regions returns the regions this collector supports
+func (c *ShieldCollector) Regions() []string {
+	// Shield Advanced is global, see Scope
+	return nil
+}
+
+// Scope returns the scope of the collector's resources
+func (c *ShieldCollector) Scope() models.Scope {
+	return models.ScopeGlobal
+}
+
+// Collect retrieves the Shield Advanced subscription and protections
+func (c *ShieldCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	// The Shield Advanced API is served from us-east-1
+	cfg := c.clientManager.GetConfig("us-east-1")
+	client := shield.NewFromConfig(cfg)
+
+	subscription, err := client.DescribeSubscription(ctx, &shield.DescribeSubscriptionInput{})
+	if err != nil {
+		// Accounts without Shield Advanced have nothing to report
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe Shield subscription: %w", err)
+	}
+
+	var resources []models.Resource
+	if subscription.Subscription != nil {
+		resources = append(resources, c.convertSubscription(subscription.Subscription))
+	}
+
+	var nextToken *string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &shield.ListProtectionsInput{
+			NextToken: nextToken,
+		}
+
+		result, err := client.ListProtections(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list Shield protections: %w", err)
+		}
+
+		for _, protection := range result.Protections {
+			resource := c.convertProtection(protection)
+			resources = append(resources, resource)
+		}
+
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+
+	return resources, nil
+}
+
+// convertSubscription converts the Shield Advanced subscription to a Resource
+func (c *ShieldCollector) convertSubscription(subscription *types.Subscription) models.Resource {
+	resource := models.Resource{
+		Service: "shield",
+		Region:  models.GlobalRegion,
+		ID:      "shield-advanced",
+		Name:    "shield-advanced",
+		Type:    "subscription",
+		State:   "active",
+		Class:   "advanced",
+	}
+
+	if subscription.StartTime != nil {
+		startTime := aws.ToTime(subscription.StartTime)
+		resource.CreatedAt = &startTime
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if subscription.SubscriptionArn != nil {
+		extra["subscriptionArn"] = aws.ToString(subscription.SubscriptionArn)
+	}
+	if subscription.EndTime != nil {
+		extra["endTime"] = aws.ToTime(subscription.EndTime)
+	}
+	if subscription.AutoRenew != "" {
+		extra["autoRenew"] = string(subscription.AutoRenew)
+	}
+	if subscription.ProactiveEngagementStatus != "" {
+		extra["proactiveEngagement"] = string(subscription.ProactiveEngagementStatus)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
+
+// convertProtection converts a Shield protection to a Resource
+func (c *ShieldCollector) convertProtection(protection types.Protection) models.Resource {
+	resource := models.Resource{
+		Service: "shield",
+		Region:  models.GlobalRegion,
+		ID:      aws.ToString(protection.Id),
+		Name:    aws.ToString(protection.Name),
+		Type:    "protection",
+		State:   "active",
+		Class:   "advanced",
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	if protection.ResourceArn != nil {
+		extra["resourceArn"] = aws.ToString(protection.ResourceArn)
+	}
+	if protection.ProtectionArn != nil {
+		extra["protectionArn"] = aws.ToString(protection.ProtectionArn)
+	}
+	if len(protection.HealthCheckIds) > 0 {
+		extra["healthChecks"] = len(protection.HealthCheckIds)
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
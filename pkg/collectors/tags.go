@@ -0,0 +1,58 @@
+package collectors
+
+import (
+	"context"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// TagPermissions are the IAM actions looking up the tags of each service's resources
+// needs, on top of those of its collector, keyed by service name. They are not needed
+// when tags are skipped.
+var TagPermissions = map[string][]string{
+	"lambda": {
+		"lambda:ListTags",
+	},
+	"s3": {
+		"s3:GetBucketTagging",
+	},
+	"dynamodb": {
+		"dynamodb:ListTagsOfResource",
+		"dax:ListTags",
+	},
+	"sfn": {
+		"states:ListTagsForResource",
+	},
+	"cloudwatch": {
+		"cloudwatch:ListTagsForResource",
+	},
+	"redis": {
+		"elasticache:ListTagsForResource",
+	},
+	"elb": {
+		"elasticloadbalancing:DescribeTags",
+	},
+}
+
+// fetchTags sets the tags fetch returns on each resource that include accepts and
+// that has no tags yet. The first failure is reported as a warning and ends the
+// lookup, as it is usually a missing permission the other resources would fail on too.
+func fetchTags(ctx context.Context, resources []models.Resource, include func(models.Resource) bool, fetch func(ctx context.Context, resource models.Resource) (map[string]string, error)) {
+	for i := range resources {
+		if resources[i].Tags != nil || !include(resources[i]) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return
+		}
+
+		tags, err := fetch(ctx, resources[i])
+		if err != nil {
+			models.Warnf(ctx, "failed to get the tags of %s %s: %v", resources[i].Service, resources[i].ID, err)
+			return
+		}
+		if len(tags) > 0 {
+			resources[i].Tags = tags
+		}
+	}
+}
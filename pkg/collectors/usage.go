@@ -0,0 +1,130 @@
+package collectors
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// UsageWindow is the period over which usage metrics are measured
+const UsageWindow = 30 * 24 * time.Hour
+
+// UsagePermissions are the IAM actions measuring usage needs, on top of those of the
+// collectors
+var UsagePermissions = []string{
+	"cloudwatch:GetMetricData",
+}
+
+// usageMetric is a CloudWatch metric measured for a resource and the extra field it
+// is recorded in. Metrics with the same key add up.
+type usageMetric struct {
+	key string
+	metricQuery
+}
+
+// usageMetrics returns the metrics measured for a resource, or nil for resources
+// whose cost does not depend on usage
+func usageMetrics(resource models.Resource) []usageMetric {
+	switch {
+	case resource.Service == "lambda" && resource.Type != "event-source-mapping" && resource.Type != "layer":
+		dimensions := map[string]string{"FunctionName": resource.ID}
+		return []usageMetric{
+			{"invocations30d", metricQuery{namespace: "AWS/Lambda", name: "Invocations", stat: "Sum", dimensions: dimensions}},
+			{"durationMs30d", metricQuery{namespace: "AWS/Lambda", name: "Duration", stat: "Sum", dimensions: dimensions}},
+		}
+	case resource.Service == "dynamodb" && resource.Type == "table":
+		dimensions := map[string]string{"TableName": resource.ID}
+		return []usageMetric{
+			{"readUnits30d", metricQuery{namespace: "AWS/DynamoDB", name: "ConsumedReadCapacityUnits", stat: "Sum", dimensions: dimensions}},
+			{"writeUnits30d", metricQuery{namespace: "AWS/DynamoDB", name: "ConsumedWriteCapacityUnits", stat: "Sum", dimensions: dimensions}},
+		}
+	case resource.Service == "network" && resource.Type == "nat-gateway":
+		// NAT gateways charge for the data they process in both directions
+		dimensions := map[string]string{"NatGatewayId": resource.ID}
+		return []usageMetric{
+			{"natBytes30d", metricQuery{namespace: "AWS/NATGateway", name: "BytesInFromSource", stat: "Sum", dimensions: dimensions}},
+			{"natBytes30d", metricQuery{namespace: "AWS/NATGateway", name: "BytesInFromDestination", stat: "Sum", dimensions: dimensions}},
+		}
+	default:
+		return nil
+	}
+}
+
+// AddUsageMetrics measures the usage of the resources over the last UsageWindow from
+// CloudWatch metrics and records it in their extra information, for cost estimates
+// based on measured usage. Failures are reported as warnings and leave the resources
+// without usage.
+func AddUsageMetrics(ctx context.Context, clientManager *awspkg.ClientManager, resources []models.Resource) {
+	addMetrics(ctx, clientManager, resources, usageMetrics, "usage")
+}
+
+// addMetrics measures the metrics metricsOf returns for each resource over the last
+// UsageWindow and records them in the resources' extra information. what names the
+// metrics in warnings.
+func addMetrics(ctx context.Context, clientManager *awspkg.ClientManager, resources []models.Resource, metricsOf func(models.Resource) []usageMetric, what string) {
+	// Metrics are published in the region of the resource
+	byRegion := make(map[string][]int)
+	for i, resource := range resources {
+		if len(metricsOf(resource)) > 0 {
+			byRegion[resource.Region] = append(byRegion[resource.Region], i)
+		}
+	}
+
+	regions := make([]string, 0, len(byRegion))
+	for region := range byRegion {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	for _, region := range regions {
+		if ctx.Err() != nil {
+			return
+		}
+		client := cloudwatch.NewFromConfig(clientManager.GetConfig(region))
+		if err := measureUsage(ctx, client, resources, byRegion[region], metricsOf); err != nil {
+			models.Warnf(ctx, "failed to get %s metrics in %s: %v", what, region, err)
+		}
+	}
+}
+
+// measureUsage queries the metrics of the resources at the given indexes, all in the
+// region of the client, and sets them in the resources' extra information
+func measureUsage(ctx context.Context, client *cloudwatch.Client, resources []models.Resource, indexes []int, metricsOf func(models.Resource) []usageMetric) error {
+	var owners []int
+	var metrics []usageMetric
+	var queries []metricQuery
+	for _, i := range indexes {
+		for _, metric := range metricsOf(resources[i]) {
+			owners = append(owners, i)
+			metrics = append(metrics, metric)
+			queries = append(queries, metric.metricQuery)
+		}
+	}
+
+	end := time.Now().UTC().Truncate(metricPeriod)
+	values, err := getMetricData(ctx, client, queries, end.Add(-UsageWindow), end)
+	if err != nil {
+		return err
+	}
+
+	// A resource without datapoints was not used, so it is recorded with no usage.
+	// Utilization without datapoints was not measured and is not recorded.
+	for q, metric := range metrics {
+		if math.IsNaN(values[q]) {
+			continue
+		}
+		resource := &resources[owners[q]]
+		if resource.Extra == nil {
+			resource.Extra = make(map[string]interface{})
+		}
+		total, _ := resource.Extra[metric.key].(float64)
+		resource.Extra[metric.key] = total + values[q]
+	}
+
+	return nil
+}
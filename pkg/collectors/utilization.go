@@ -0,0 +1,45 @@
+package collectors
+
+import (
+	"context"
+
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// UtilizationPermissions are the IAM actions measuring utilization for rightsizing
+// needs, on top of those of the collectors
+var UtilizationPermissions = []string{
+	"cloudwatch:GetMetricData",
+}
+
+// utilizationMetrics returns the utilization metrics measured for a resource, or nil
+// for resources that are not rightsized. Memory is only published for instances whose
+// CloudWatch agent reports mem_used_percent by InstanceId.
+func utilizationMetrics(resource models.Resource) []usageMetric {
+	switch {
+	case resource.Service == "ec2" && resource.State == "running" && resource.Extra["lifecycle"] == nil:
+		dimensions := map[string]string{"InstanceId": resource.ID}
+		return []usageMetric{
+			{"cpuAverage30d", metricQuery{namespace: "AWS/EC2", name: "CPUUtilization", stat: "Average", dimensions: dimensions, mean: true}},
+			{"cpuPeak30d", metricQuery{namespace: "AWS/EC2", name: "CPUUtilization", stat: "Maximum", dimensions: dimensions, peak: true}},
+			{"memoryPeak30d", metricQuery{namespace: "CWAgent", name: "mem_used_percent", stat: "Maximum", dimensions: dimensions, peak: true}},
+		}
+	case resource.Service == "rds" && resource.State == "available":
+		dimensions := map[string]string{"DBInstanceIdentifier": resource.ID}
+		return []usageMetric{
+			{"cpuAverage30d", metricQuery{namespace: "AWS/RDS", name: "CPUUtilization", stat: "Average", dimensions: dimensions, mean: true}},
+			{"cpuPeak30d", metricQuery{namespace: "AWS/RDS", name: "CPUUtilization", stat: "Maximum", dimensions: dimensions, peak: true}},
+		}
+	default:
+		return nil
+	}
+}
+
+// AddUtilizationMetrics measures the CPU, and where published memory, utilization of
+// running EC2 and RDS instances over the last UsageWindow and records it in their
+// extra information as percentages, for rightsizing recommendations. Failures are
+// reported as warnings and leave the resources without utilization.
+func AddUtilizationMetrics(ctx context.Context, clientManager *awspkg.ClientManager, resources []models.Resource) {
+	addMetrics(ctx, clientManager, resources, utilizationMetrics, "utilization")
+}
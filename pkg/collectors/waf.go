@@ -0,0 +1,210 @@
+package collectors
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/wafv2"
+	"github.com/aws/aws-sdk-go-v2/service/wafv2/types"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// wafRegionalResourceTypes lists the resource types a regional web ACL can be associated with
+var wafRegionalResourceTypes = []types.ResourceType{
+	types.ResourceType("APPLICATION_LOAD_BALANCER"),
+	types.ResourceType("API_GATEWAY"),
+	types.ResourceType("APPSYNC"),
+	types.ResourceType("COGNITO_USER_POOL"),
+	types.ResourceType("APP_RUNNER_SERVICE"),
+	types.ResourceType("VERIFIED_ACCESS_INSTANCE"),
+}
+
+// WAFCollector collects WAFv2 web ACLs
+type WAFCollector struct {
+	clientManager *awspkg.ClientManager
+}
+
+// NewWAFCollector creates a new WAF collector
+func NewWAFCollector(clientManager *awspkg.ClientManager) *WAFCollector {
+	return &WAFCollector{
+		clientManager: clientManager,
+	}
+}
+
+// Name returns the service name
+func (c *WAFCollector) Name() string {
+	return "waf"
+}
+
+// Regions returns the regions this collector supports
+func (c *WAFCollector) Regions() []string {
+	// WAFv2 is available in all regions
+	return nil // Will be populated by the orchestrator
+}
+
+// Scope returns the scope of the collector's resources
+func (c *WAFCollector) Scope() models.Scope {
+	return models.ScopeRegional
+}
+
+// Collect retrieves WAFv2 web ACLs for the given region. CloudFront-scoped
+// web ACLs can only be listed from us-east-1 and are reported as global.
+func (c *WAFCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	cfg := c.clientManager.GetConfig(region)
+	client := wafv2.NewFromConfig(cfg)
+
+	resources, err := c.collectWebACLs(ctx, client, types.ScopeRegional, region)
+	if err != nil {
+		return nil, err
+	}
+
+	if region == "us-east-1" {
+		cloudFrontACLs, err := c.collectWebACLs(ctx, client, types.ScopeCloudfront, "global")
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, cloudFrontACLs...)
+	}
+
+	return resources, nil
+}
+
+// collectWebACLs lists the web ACLs for a scope
+func (c *WAFCollector) collectWebACLs(ctx context.Context, client *wafv2.Client, scope types.Scope, region string) ([]models.Resource, error) {
+	var resources []models.Resource
+	var nextMarker *string
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		input := &wafv2.ListWebACLsInput{
+			Scope:      scope,
+			NextMarker: nextMarker,
+			Limit:      aws.Int32(100),
+		}
+
+		result, err := client.ListWebACLs(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s web ACLs in %s: %w", scope, region, err)
+		}
+
+		for _, summary := range result.WebACLs {
+			webACL, err := c.getWebACL(ctx, client, summary, scope)
+			if err != nil {
+				// Log error but continue with other web ACLs
+				models.Warnf(ctx, "failed to get info for web ACL %s: %v", aws.ToString(summary.Name), err)
+				continue
+			}
+
+			var associated []string
+			if scope == types.ScopeRegional {
+				associated, err = c.getAssociatedResources(ctx, client, aws.ToString(summary.ARN))
+				if err != nil {
+					models.Warnf(ctx, "failed to list resources for web ACL %s: %v", aws.ToString(summary.Name), err)
+				}
+			}
+
+			resource := c.convertWebACL(webACL, associated, scope, region)
+			resources = append(resources, resource)
+		}
+
+		// WAFv2 can return a marker with an empty final page
+		if result.NextMarker == nil || len(result.WebACLs) == 0 {
+			break
+		}
+		nextMarker = result.NextMarker
+	}
+
+	return resources, nil
+}
+
+// getWebACL retrieves the full definition of a web ACL
+func (c *WAFCollector) getWebACL(ctx context.Context, client *wafv2.Client, summary types.WebACLSummary, scope types.Scope) (*types.WebACL, error) {
+	input := &wafv2.GetWebACLInput{
+		Id:    summary.Id,
+		Name:  summary.Name,
+		Scope: scope,
+	}
+
+	result, err := client.GetWebACL(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.WebACL == nil {
+		return nil, fmt.Errorf("web ACL not found: %s", aws.ToString(summary.Name))
+	}
+
+	return result.WebACL, nil
+}
+
+// getAssociatedResources lists the ARNs of resources protected by a regional web ACL
+func (c *WAFCollector) getAssociatedResources(ctx context.Context, client *wafv2.Client, webACLArn string) ([]string, error) {
+	var arns []string
+
+	for _, resourceType := range wafRegionalResourceTypes {
+		result, err := client.ListResourcesForWebACL(ctx, &wafv2.ListResourcesForWebACLInput{
+			WebACLArn:    aws.String(webACLArn),
+			ResourceType: resourceType,
+		})
+		if err != nil {
+			return arns, err
+		}
+		arns = append(arns, result.ResourceArns...)
+	}
+
+	return arns, nil
+}
+
+// convertWebACL converts a WAFv2 web ACL to a Resource
+func (c *WAFCollector) convertWebACL(webACL *types.WebACL, associated []string, scope types.Scope, region string) models.Resource {
+	resource := models.Resource{
+		Service: "waf",
+		Region:  region,
+		ID:      aws.ToString(webACL.Id),
+		Name:    aws.ToString(webACL.Name),
+		Type:    "web-acl",
+		State:   "active",
+		Class:   string(scope),
+	}
+
+	managedRuleGroups := 0
+	for _, rule := range webACL.Rules {
+		if rule.Statement != nil && rule.Statement.ManagedRuleGroupStatement != nil {
+			managedRuleGroups++
+		}
+	}
+
+	// Add extra information
+	extra := make(map[string]interface{})
+	extra["scope"] = string(scope)
+	extra["ruleCount"] = len(webACL.Rules)
+	extra["managedRuleGroups"] = managedRuleGroups
+	if webACL.ARN != nil {
+		extra["webAclArn"] = aws.ToString(webACL.ARN)
+	}
+	if webACL.Description != nil {
+		extra["description"] = aws.ToString(webACL.Description)
+	}
+	if webACL.DefaultAction != nil {
+		if webACL.DefaultAction.Block != nil {
+			extra["defaultAction"] = "block"
+		} else {
+			extra["defaultAction"] = "allow"
+		}
+	}
+	if scope == types.ScopeRegional {
+		extra["associatedResources"] = len(associated)
+		if len(associated) > 0 {
+			extra["associatedResourceArns"] = associated
+		}
+	}
+
+	resource.Extra = extra
+
+	return resource
+}
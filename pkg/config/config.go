@@ -0,0 +1,192 @@
+// Package config loads the awsinv configuration file: defaults for every scan and
+// named presets that bundle the options of a recurring scan
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvVar names an environment variable holding the path of the configuration file
+const EnvVar = "AWSINV_CONFIG"
+
+// Config is the content of a configuration file
+type Config struct {
+	// Defaults apply to every scan
+	Defaults Preset `yaml:"defaults"`
+
+	// Presets are named option sets selected with --preset
+	Presets map[string]Preset `yaml:"presets"`
+
+	// Path is the file the configuration was loaded from, empty when none was found
+	Path string `yaml:"-"`
+}
+
+// Preset is a set of scan options. Unset fields leave the option unchanged.
+type Preset struct {
+	Description string `yaml:"description"`
+
+	Services            []string           `yaml:"services"`
+	Regions             []string           `yaml:"regions"`
+	ExcludeRegions      []string           `yaml:"exclude_regions"`
+	IncludeOptInRegions bool               `yaml:"include_opt_in_regions"`
+	Parallel            int                `yaml:"parallel"`
+	Timeout             time.Duration      `yaml:"timeout"`
+	ServiceTimeout      time.Duration      `yaml:"service_timeout"`
+	RateLimits          map[string]float64 `yaml:"rate_limits"`
+	APIBudget           float64            `yaml:"api_budget"`
+	QuotaThreshold      float64            `yaml:"quota_threshold"`
+	CloudControlTypes   []string           `yaml:"cloudcontrol_types"`
+	UsageMetrics        bool               `yaml:"usage_metrics"`
+	Rightsizing         bool               `yaml:"rightsizing"`
+	SkipTags            bool               `yaml:"skip_tags"`
+	SkipBucketDetails   bool               `yaml:"skip_bucket_details"`
+
+	Profile              string        `yaml:"profile"`
+	Profiles             []string      `yaml:"profiles"`
+	ExpectAccounts       []string      `yaml:"expect_account"`
+	RoleARN              string        `yaml:"role_arn"`
+	ExternalID           string        `yaml:"external_id"`
+	RoleSessionName      string        `yaml:"role_session_name"`
+	RoleDuration         time.Duration `yaml:"role_duration"`
+	MFASerial            string        `yaml:"mfa_serial"`
+	MFATokenCommand      string        `yaml:"mfa_token_command"`
+	WebIdentityTokenFile string        `yaml:"web_identity_token_file"`
+
+	Filters  []string `yaml:"filters"`
+	Excludes []string `yaml:"excludes"`
+	Sort     string   `yaml:"sort"`
+
+	Output     string `yaml:"output"`
+	Out        string `yaml:"out"`
+	Compress   string `yaml:"compress"`
+	Query      string `yaml:"query"`
+	CostPeriod string `yaml:"cost_period"`
+	HTMLTheme  string `yaml:"html_theme"`
+	HTMLTitle  string `yaml:"html_title"`
+	HTMLLogo   string `yaml:"html_logo"`
+
+	RequiredTags []string `yaml:"required_tags"`
+
+	// TagMappings derive normalized tags from the keys holding their values, such as
+	// Owner from team; a preset's mappings are added to the defaults', replacing those
+	// with the same key
+	TagMappings map[string][]string `yaml:"tag_mappings"`
+
+	SnapshotStore string        `yaml:"snapshot_store"`
+	CacheTTL      time.Duration `yaml:"cache_ttl"`
+	CompareTo     string        `yaml:"compare_to"`
+
+	FailOnErrors   bool     `yaml:"fail_on_errors"`
+	FailIfCostOver float64  `yaml:"fail_if_cost_over"`
+	FailIfCount    []string `yaml:"fail_if_count"`
+
+	// Budgets are reported with the costs; a preset's budgets replace the defaults'
+	Budgets      []Budget `yaml:"budgets"`
+	FailOnBudget bool     `yaml:"fail_on_budget"`
+
+	// Rules are checked with the built-in findings rules; a preset's rules are added
+	// to the defaults', replacing those with the same ID
+	Rules []Rule `yaml:"rules"`
+}
+
+// Budget is a monthly spending limit on the resources of a service, with a tag, or both
+type Budget struct {
+	Name    string  `yaml:"name"`
+	Service string  `yaml:"service"`
+	Tag     string  `yaml:"tag"` // key=value, or key for any value
+	Limit   float64 `yaml:"limit"`
+}
+
+// Rule is a findings rule: resources matching a filter expression, such as
+// "service=ec2 AND NOT has(tag:Owner)", are reported with its severity and message
+type Rule struct {
+	ID       string `yaml:"id"`
+	Match    string `yaml:"match"`
+	Severity string `yaml:"severity"`
+	Message  string `yaml:"message"`
+}
+
+// SearchPaths returns the files tried when no path is given, in order:
+// .awsinv.yaml in the working directory, then awsinv/config.yaml in the user
+// configuration directory (e.g. ~/.config/awsinv/config.yaml)
+func SearchPaths() []string {
+	paths := []string{".awsinv.yaml"}
+	if dir, err := os.UserConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "awsinv", "config.yaml"))
+	}
+	return paths
+}
+
+// Load reads the configuration file at path, or from $AWSINV_CONFIG or the search
+// paths when path is empty. A missing file is only an error when it was named
+// explicitly; otherwise an empty configuration is returned.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		path = os.Getenv(EnvVar)
+	}
+	if path != "" {
+		return loadFile(path)
+	}
+
+	for _, candidate := range SearchPaths() {
+		cfg, err := loadFile(candidate)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return cfg, err
+	}
+	return &Config{}, nil
+}
+
+// loadFile reads and validates one configuration file
+func loadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unknown keys are rejected so that a misspelled option is not silently ignored
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	cfg.Path = path
+	return &cfg, nil
+}
+
+// Preset returns the named preset
+func (c *Config) Preset(name string) (Preset, error) {
+	preset, exists := c.Presets[name]
+	if !exists {
+		if c.Path == "" {
+			return Preset{}, fmt.Errorf("unknown preset %q: no config file found (tried %s)", name, strings.Join(SearchPaths(), ", "))
+		}
+		if len(c.Presets) == 0 {
+			return Preset{}, fmt.Errorf("unknown preset %q: %s defines no presets", name, c.Path)
+		}
+		return Preset{}, fmt.Errorf("unknown preset %q in %s (available: %s)", name, c.Path, strings.Join(c.PresetNames(), ", "))
+	}
+	return preset, nil
+}
+
+// PresetNames returns the names of the presets, sorted
+func (c *Config) PresetNames() []string {
+	names := make([]string, 0, len(c.Presets))
+	for name := range c.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
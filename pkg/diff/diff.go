@@ -0,0 +1,274 @@
+// Package diff compares two inventory snapshots written by the JSON output and
+// reports added, removed and changed resources
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// Change statuses
+const (
+	StatusAdded   = "added"
+	StatusRemoved = "removed"
+	StatusChanged = "changed"
+)
+
+// SnapshotResource is a resource as written by the JSON output
+type SnapshotResource struct {
+	models.Resource
+	CostEstimate *struct {
+		Amount float64
+	} `json:"costEstimate,omitempty"`
+}
+
+// Snapshot is an inventory written by the JSON output
+type Snapshot struct {
+	Resources        []SnapshotResource `json:"resources"`
+	Summary          models.Summary     `json:"summary"`
+	TotalMonthlyCost float64            `json:"totalMonthlyCost"`
+	Errors           []string           `json:"errors,omitempty"`
+	Warnings         []string           `json:"warnings,omitempty"`
+	Skipped          []string           `json:"skipped,omitempty"`
+}
+
+// Change is a difference in one field of a resource
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// ResourceDiff describes how a single resource differs between two snapshots
+type ResourceDiff struct {
+	Status    string   `json:"status"`
+	Service   string   `json:"service"`
+	Region    string   `json:"region"`
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Changes   []Change `json:"changes,omitempty"`
+	OldCost   float64  `json:"oldCost"`
+	NewCost   float64  `json:"newCost"`
+	CostDelta float64  `json:"costDelta"`
+}
+
+// Result is the difference between two snapshots
+type Result struct {
+	Added        []ResourceDiff `json:"added"`
+	Removed      []ResourceDiff `json:"removed"`
+	Changed      []ResourceDiff `json:"changed"`
+	Unchanged    int            `json:"unchanged"`
+	OldTotalCost float64        `json:"oldTotalCost"`
+	NewTotalCost float64        `json:"newTotalCost"`
+	CostDelta    float64        `json:"costDelta"`
+}
+
+// HasChanges reports whether the snapshots differ
+func (r *Result) HasChanges() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0 || len(r.Changed) > 0
+}
+
+// LoadSnapshot reads a snapshot file, which may be gzip or zstd compressed
+func LoadSnapshot(path string) (*Snapshot, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	snapshot, err := ReadSnapshot(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", path, err)
+	}
+	return snapshot, nil
+}
+
+// gzipMagic and zstdMagic start gzip and zstd streams
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ReadSnapshot decodes a snapshot, detecting gzip and zstd compression
+func ReadSnapshot(reader io.Reader) (*Snapshot, error) {
+	buffered := bufio.NewReader(reader)
+	header, _ := buffered.Peek(len(zstdMagic))
+
+	var input io.Reader = buffered
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		input = gz
+	case bytes.HasPrefix(header, zstdMagic):
+		zr, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		input = zr
+	}
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(input).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// ResourceKey identifies a resource across snapshots by its account, service, region
+// and ID. The type is left out so that type changes, such as an EC2 instance resize,
+// show up as changes.
+func ResourceKey(resource models.Resource) string {
+	return resource.Key()
+}
+
+// cost returns the estimated monthly cost of a snapshot resource
+func (r SnapshotResource) cost() float64 {
+	if r.CostEstimate == nil {
+		return 0
+	}
+	return r.CostEstimate.Amount
+}
+
+// Compare reports the differences between an old and a new snapshot
+func Compare(oldSnapshot, newSnapshot *Snapshot) *Result {
+	result := &Result{
+		Added:   []ResourceDiff{},
+		Removed: []ResourceDiff{},
+		Changed: []ResourceDiff{},
+	}
+
+	oldResources := make(map[string]SnapshotResource, len(oldSnapshot.Resources))
+	for _, resource := range oldSnapshot.Resources {
+		oldResources[ResourceKey(resource.Resource)] = resource
+		result.OldTotalCost += resource.cost()
+	}
+
+	seen := make(map[string]bool, len(newSnapshot.Resources))
+	for _, resource := range newSnapshot.Resources {
+		key := ResourceKey(resource.Resource)
+		seen[key] = true
+		result.NewTotalCost += resource.cost()
+
+		old, exists := oldResources[key]
+		if !exists {
+			result.Added = append(result.Added, newResourceDiff(StatusAdded, resource.Resource, 0, resource.cost()))
+			continue
+		}
+
+		changes := compareResources(old.Resource, resource.Resource)
+		costDelta := resource.cost() - old.cost()
+		if len(changes) == 0 && !significant(costDelta) {
+			result.Unchanged++
+			continue
+		}
+
+		d := newResourceDiff(StatusChanged, resource.Resource, old.cost(), resource.cost())
+		d.Changes = changes
+		result.Changed = append(result.Changed, d)
+	}
+
+	for _, resource := range oldSnapshot.Resources {
+		if !seen[ResourceKey(resource.Resource)] {
+			result.Removed = append(result.Removed, newResourceDiff(StatusRemoved, resource.Resource, resource.cost(), 0))
+		}
+	}
+
+	result.CostDelta = result.NewTotalCost - result.OldTotalCost
+
+	for _, diffs := range [][]ResourceDiff{result.Added, result.Removed, result.Changed} {
+		sortDiffs(diffs)
+	}
+
+	return result
+}
+
+// newResourceDiff creates a diff entry for a resource
+func newResourceDiff(status string, resource models.Resource, oldCost, newCost float64) ResourceDiff {
+	return ResourceDiff{
+		Status:    status,
+		Service:   resource.Service,
+		Region:    resource.Region,
+		ID:        resource.ID,
+		Name:      resource.Name,
+		Type:      resource.Type,
+		OldCost:   oldCost,
+		NewCost:   newCost,
+		CostDelta: newCost - oldCost,
+	}
+}
+
+// compareResources lists the field and tag changes between two versions of a resource
+func compareResources(before, after models.Resource) []Change {
+	var changes []Change
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"name", before.Name, after.Name},
+		{"state", before.State, after.State},
+		{"type", before.Type, after.Type},
+		{"class", before.Class, after.Class},
+	}
+	for _, field := range fields {
+		if field.old != field.new {
+			changes = append(changes, Change{Field: field.name, Old: field.old, New: field.new})
+		}
+	}
+
+	keys := make(map[string]bool)
+	for key := range before.Tags {
+		keys[key] = true
+	}
+	for key := range after.Tags {
+		keys[key] = true
+	}
+	tagKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		tagKeys = append(tagKeys, key)
+	}
+	sort.Strings(tagKeys)
+
+	for _, key := range tagKeys {
+		oldValue, oldExists := before.Tags[key]
+		newValue, newExists := after.Tags[key]
+		if oldExists != newExists || oldValue != newValue {
+			changes = append(changes, Change{Field: "tag:" + key, Old: oldValue, New: newValue})
+		}
+	}
+
+	return changes
+}
+
+// significant reports whether a cost delta is at least a cent
+func significant(delta float64) bool {
+	return delta >= 0.005 || delta <= -0.005
+}
+
+// sortDiffs orders diffs by service, region and ID
+func sortDiffs(diffs []ResourceDiff) {
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Service != diffs[j].Service {
+			return diffs[i].Service < diffs[j].Service
+		}
+		if diffs[i].Region != diffs[j].Region {
+			return diffs[i].Region < diffs[j].Region
+		}
+		return diffs[i].ID < diffs[j].ID
+	})
+}
@@ -0,0 +1,96 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteJSON writes the result as indented JSON
+func WriteJSON(writer io.Writer, result *Result) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+// WriteTable writes the result as a human-readable report
+func WriteTable(writer io.Writer, result *Result) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "\nAWS Inventory Diff\n")
+	fmt.Fprintf(&b, "==================\n")
+	fmt.Fprintf(&b, "Added: %d  Removed: %d  Changed: %d  Unchanged: %d\n",
+		len(result.Added), len(result.Removed), len(result.Changed), result.Unchanged)
+	fmt.Fprintf(&b, "Estimated Monthly Cost: $%.2f -> $%.2f (%s)\n",
+		result.OldTotalCost, result.NewTotalCost, formatDelta(result.CostDelta))
+
+	sections := []struct {
+		title string
+		diffs []ResourceDiff
+	}{
+		{"Added", result.Added},
+		{"Removed", result.Removed},
+		{"Changed", result.Changed},
+	}
+	for _, section := range sections {
+		if len(section.diffs) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n%s:\n", section.title)
+		fmt.Fprintf(&b, "%-12s %-15s %-20s %-20s %-12s %s\n", "SERVICE", "REGION", "ID", "NAME", "COST DELTA", "CHANGES")
+		fmt.Fprintf(&b, "%-12s %-15s %-20s %-20s %-12s %s\n", "-------", "------", "--", "----", "----------", "-------")
+		for _, d := range section.diffs {
+			fmt.Fprintf(&b, "%-12s %-15s %-20s %-20s %-12s %s\n",
+				truncate(d.Service, 12),
+				truncate(d.Region, 15),
+				truncate(d.ID, 20),
+				truncate(d.Name, 20),
+				formatDelta(d.CostDelta),
+				formatChanges(d.Changes))
+		}
+	}
+
+	if !result.HasChanges() {
+		b.WriteString("\nNo changes.\n")
+	}
+
+	_, err := io.WriteString(writer, b.String())
+	return err
+}
+
+// formatChanges summarizes field changes, e.g. "state: running -> stopped"
+func formatChanges(changes []Change) string {
+	parts := make([]string, len(changes))
+	for i, change := range changes {
+		before, after := change.Old, change.New
+		if before == "" {
+			before = "(none)"
+		}
+		if after == "" {
+			after = "(none)"
+		}
+		parts[i] = fmt.Sprintf("%s: %s -> %s", change.Field, before, after)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDelta formats a cost delta with its sign
+func formatDelta(delta float64) string {
+	if !significant(delta) {
+		return "$0.00"
+	}
+	if delta < 0 {
+		return fmt.Sprintf("-$%.2f", -delta)
+	}
+	return fmt.Sprintf("+$%.2f", delta)
+}
+
+// truncate truncates a string to the specified length
+func truncate(s string, maxLen int) string {
+	if len(s) <= maxLen {
+		return s
+	}
+	return s[:maxLen-3] + "..."
+}
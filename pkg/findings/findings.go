@@ -0,0 +1,147 @@
+// Package findings checks collected resources for security posture problems, such
+// as public buckets, open security groups and unencrypted storage.
+package findings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// Severity is how serious a finding is
+type Severity string
+
+// Severity levels, most serious first
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+)
+
+// Severities are the severity levels, most serious first
+var Severities = []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+
+// ParseSeverity parses a severity level name
+func ParseSeverity(value string) (Severity, error) {
+	for _, severity := range Severities {
+		if string(severity) == strings.ToLower(value) {
+			return severity, nil
+		}
+	}
+	return "", fmt.Errorf("invalid severity: %s (expected critical, high, medium or low)", value)
+}
+
+// rank orders severities, lower is more serious
+func (s Severity) rank() int {
+	for i, severity := range Severities {
+		if severity == s {
+			return i
+		}
+	}
+	return len(Severities)
+}
+
+// Finding is a problem a rule found with a resource
+type Finding struct {
+	RuleID       string   `json:"ruleId"`
+	Severity     Severity `json:"severity"`
+	Account      string   `json:"account,omitempty"`
+	Service      string   `json:"service"`
+	Region       string   `json:"region"`
+	ResourceID   string   `json:"resourceId"`
+	ResourceName string   `json:"resourceName,omitempty"`
+	Message      string   `json:"message"`
+}
+
+// Rule checks resources for one problem
+type Rule struct {
+	// ID identifies the rule in findings, such as s3-public-bucket
+	ID string
+
+	// Severity is the severity of the rule's findings
+	Severity Severity
+
+	// Title describes the problem the rule looks for
+	Title string
+
+	// Check returns a message for each problem found with a resource, or none
+	Check func(resource models.Resource) []string
+}
+
+var (
+	rulesMu sync.RWMutex
+	rules   = defaultRules()
+)
+
+// RegisterRule adds a rule that Evaluate checks every resource with. A rule with the
+// ID of a registered rule replaces it.
+func RegisterRule(rule Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+
+	for i := range rules {
+		if rules[i].ID == rule.ID {
+			rules[i] = rule
+			return
+		}
+	}
+	rules = append(rules, rule)
+}
+
+// Rules returns the registered rules
+func Rules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+
+	return append([]Rule(nil), rules...)
+}
+
+// Evaluate checks resources with the registered rules. The findings are sorted by
+// severity, most serious first, then by service, region and resource ID.
+func Evaluate(resources []models.Resource) []Finding {
+	var found []Finding
+	for _, rule := range Rules() {
+		for _, resource := range resources {
+			for _, message := range rule.Check(resource) {
+				found = append(found, Finding{
+					RuleID:       rule.ID,
+					Severity:     rule.Severity,
+					Account:      resource.Account,
+					Service:      resource.Service,
+					Region:       resource.Region,
+					ResourceID:   resource.ID,
+					ResourceName: resource.Name,
+					Message:      message,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		a, b := found[i], found[j]
+		if a.Severity.rank() != b.Severity.rank() {
+			return a.Severity.rank() < b.Severity.rank()
+		}
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.ResourceID < b.ResourceID
+	})
+	return found
+}
+
+// Count returns the number of findings of each severity
+func Count(found []Finding) map[Severity]int {
+	counts := make(map[Severity]int)
+	for _, finding := range found {
+		counts[finding.Severity]++
+	}
+	return counts
+}
@@ -0,0 +1,308 @@
+package findings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// maxAccessKeyAge is the age after which active IAM access keys should be rotated
+const maxAccessKeyAge = 90 * 24 * time.Hour
+
+// sensitivePorts are the ports of remote administration, databases and other services
+// that should not be reachable from the internet
+var sensitivePorts = map[int]string{
+	22:    "SSH",
+	23:    "Telnet",
+	445:   "SMB",
+	1433:  "SQL Server",
+	1521:  "Oracle",
+	3306:  "MySQL",
+	3389:  "RDP",
+	5432:  "PostgreSQL",
+	5439:  "Redshift",
+	6379:  "Redis",
+	9200:  "Elasticsearch",
+	11211: "Memcached",
+	27017: "MongoDB",
+}
+
+// defaultRules returns the built-in rules
+func defaultRules() []Rule {
+	return []Rule{
+		{
+			ID:       "s3-public-bucket",
+			Severity: SeverityCritical,
+			Title:    "S3 bucket policy allows public access",
+			Check:    checkPublicBucket,
+		},
+		{
+			ID:       "sg-open-all-ports",
+			Severity: SeverityCritical,
+			Title:    "Security group allows all traffic from the internet",
+			Check:    checkOpenAllPorts,
+		},
+		{
+			ID:       "sg-open-sensitive-port",
+			Severity: SeverityHigh,
+			Title:    "Security group allows a sensitive port from the internet",
+			Check:    checkOpenSensitivePorts,
+		},
+		{
+			ID:       "ami-public-image",
+			Severity: SeverityHigh,
+			Title:    "AMI is shared publicly",
+			Check:    checkPublicImage,
+		},
+		{
+			ID:       "rds-unencrypted",
+			Severity: SeverityHigh,
+			Title:    "RDS instance storage is not encrypted",
+			Check:    checkUnencrypted("rds", "storageEncrypted", "RDS instance storage is not encrypted"),
+		},
+		{
+			ID:       "efs-unencrypted",
+			Severity: SeverityMedium,
+			Title:    "EFS file system is not encrypted",
+			Check:    checkUnencrypted("efs", "encrypted", "EFS file system is not encrypted at rest"),
+		},
+		{
+			ID:       "redis-unencrypted",
+			Severity: SeverityMedium,
+			Title:    "ElastiCache cluster is not encrypted at rest",
+			Check:    checkUnencrypted("redis", "atRestEncryption", "ElastiCache cluster is not encrypted at rest"),
+		},
+		{
+			ID:       "ebs-unencrypted",
+			Severity: SeverityMedium,
+			Title:    "EBS volume is not encrypted",
+			Check:    checkUnencryptedVolumes,
+		},
+		{
+			ID:       "s3-public-access-block-off",
+			Severity: SeverityMedium,
+			Title:    "S3 bucket does not block public access",
+			Check:    checkPublicAccessBlock,
+		},
+		{
+			ID:       "s3-no-default-encryption",
+			Severity: SeverityMedium,
+			Title:    "S3 bucket has no default encryption",
+			Check:    checkBucketEncryption,
+		},
+		{
+			ID:       "iam-old-access-key",
+			Severity: SeverityMedium,
+			Title:    "IAM access key is older than 90 days",
+			Check:    checkOldAccessKeys,
+		},
+		{
+			ID:       "s3-versioning-disabled",
+			Severity: SeverityLow,
+			Title:    "S3 bucket versioning is not enabled",
+			Check:    checkBucketVersioning,
+		},
+		{
+			ID:       "s3-access-logging-disabled",
+			Severity: SeverityLow,
+			Title:    "S3 bucket server access logging is off",
+			Check:    checkBucketLogging,
+		},
+	}
+}
+
+// checkPublicBucket finds buckets whose policy makes them public
+func checkPublicBucket(resource models.Resource) []string {
+	if resource.Service != "s3" {
+		return nil
+	}
+	if public, _ := resource.Extra["public"].(bool); public {
+		return []string{"Bucket policy allows public access"}
+	}
+	return nil
+}
+
+// publicAccessBlockSettings are the public access block settings of a bucket, in the
+// order the console lists them
+var publicAccessBlockSettings = []string{"blockPublicAcls", "ignorePublicAcls", "blockPublicPolicy", "restrictPublicBuckets"}
+
+// checkPublicAccessBlock finds buckets with public access block settings turned off.
+// Buckets whose settings were not looked up are not checked.
+func checkPublicAccessBlock(resource models.Resource) []string {
+	if resource.Service != "s3" {
+		return nil
+	}
+	block, ok := resource.Extra["publicAccessBlock"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	var off []string
+	for _, setting := range publicAccessBlockSettings {
+		if on, _ := block[setting].(bool); !on {
+			off = append(off, setting)
+		}
+	}
+	if len(off) == 0 {
+		return nil
+	}
+	return []string{fmt.Sprintf("Public access block settings are off: %s", strings.Join(off, ", "))}
+}
+
+// checkBucketEncryption finds buckets without default encryption
+func checkBucketEncryption(resource models.Resource) []string {
+	if resource.Service != "s3" {
+		return nil
+	}
+	if resource.Extra["encryption"] == "none" {
+		return []string{"Bucket has no default encryption"}
+	}
+	return nil
+}
+
+// checkBucketVersioning finds buckets that never had versioning enabled or had it
+// suspended
+func checkBucketVersioning(resource models.Resource) []string {
+	if resource.Service != "s3" {
+		return nil
+	}
+	if status, ok := resource.Extra["versioning"].(string); ok && status != "Enabled" {
+		return []string{fmt.Sprintf("Bucket versioning is %s", strings.ToLower(status))}
+	}
+	return nil
+}
+
+// checkBucketLogging finds buckets that do not log server access
+func checkBucketLogging(resource models.Resource) []string {
+	if resource.Service != "s3" {
+		return nil
+	}
+	if logging, ok := resource.Extra["accessLogging"].(bool); ok && !logging {
+		return []string{"Server access logging is off"}
+	}
+	return nil
+}
+
+// checkPublicImage finds AMIs shared with everyone
+func checkPublicImage(resource models.Resource) []string {
+	if resource.Service != "ami" {
+		return nil
+	}
+	if public, _ := resource.Extra["public"].(bool); public {
+		return []string{"Image launch permissions are public"}
+	}
+	return nil
+}
+
+// checkUnencrypted returns a check finding resources of a service whose boolean
+// encryption field is false. Resources without the field are not checked.
+func checkUnencrypted(service, key, message string) func(models.Resource) []string {
+	return func(resource models.Resource) []string {
+		if resource.Service != service {
+			return nil
+		}
+		if encrypted, ok := resource.Extra[key].(bool); ok && !encrypted {
+			return []string{message}
+		}
+		return nil
+	}
+}
+
+// checkUnencryptedVolumes finds the unencrypted EBS volumes attached to instances
+func checkUnencryptedVolumes(resource models.Resource) []string {
+	if resource.Service != "ec2" {
+		return nil
+	}
+	var messages []string
+	for _, volume := range models.ExtraList(resource.Extra, "volumes") {
+		if encrypted, ok := volume["encrypted"].(bool); ok && !encrypted {
+			messages = append(messages, fmt.Sprintf("Volume %v is not encrypted", volume["id"]))
+		}
+	}
+	return messages
+}
+
+// checkOldAccessKeys finds active access keys of IAM users created over 90 days ago
+func checkOldAccessKeys(resource models.Resource) []string {
+	if resource.Service != "iam" {
+		return nil
+	}
+	var messages []string
+	for _, key := range models.ExtraList(resource.Extra, "accessKeys") {
+		if key["status"] != "Active" {
+			continue
+		}
+		created, ok := models.ExtraTime(key, "createdAt")
+		if !ok {
+			continue
+		}
+		if age := time.Since(created); age > maxAccessKeyAge {
+			messages = append(messages, fmt.Sprintf("Access key %v is %d days old", key["id"], int(age.Hours()/24)))
+		}
+	}
+	return messages
+}
+
+// openIngress returns the inbound rules of a security group that allow traffic from
+// any IPv4 or IPv6 address
+func openIngress(resource models.Resource) []map[string]interface{} {
+	if resource.Service != "network" || resource.Type != "security-group" {
+		return nil
+	}
+	var open []map[string]interface{}
+	for _, rule := range models.ExtraList(resource.Extra, "ingress") {
+		for _, cidr := range models.ExtraStrings(rule, "cidrs") {
+			if cidr == "0.0.0.0/0" || cidr == "::/0" {
+				open = append(open, rule)
+				break
+			}
+		}
+	}
+	return open
+}
+
+// checkOpenAllPorts finds security groups allowing every protocol from the internet
+func checkOpenAllPorts(resource models.Resource) []string {
+	for _, rule := range openIngress(resource) {
+		if rule["protocol"] == "-1" {
+			return []string{"All traffic is allowed from the internet"}
+		}
+	}
+	return nil
+}
+
+// checkOpenSensitivePorts finds security groups allowing sensitive TCP or UDP ports
+// from the internet
+func checkOpenSensitivePorts(resource models.Resource) []string {
+	exposed := make(map[int]bool)
+	for _, rule := range openIngress(resource) {
+		protocol, _ := rule["protocol"].(string)
+		if protocol != "tcp" && protocol != "udp" && protocol != "6" && protocol != "17" {
+			continue
+		}
+		from, okFrom := models.ExtraNumber(rule, "fromPort")
+		to, okTo := models.ExtraNumber(rule, "toPort")
+		if !okFrom || !okTo {
+			continue
+		}
+		for port := range sensitivePorts {
+			if float64(port) >= from && float64(port) <= to {
+				exposed[port] = true
+			}
+		}
+	}
+
+	ports := make([]int, 0, len(exposed))
+	for port := range exposed {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+
+	messages := make([]string, 0, len(ports))
+	for _, port := range ports {
+		messages = append(messages, fmt.Sprintf("Port %d (%s) is open to the internet", port, sensitivePorts[port]))
+	}
+	return messages
+}
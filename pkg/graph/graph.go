@@ -0,0 +1,256 @@
+// Package graph links collected resources to the resources they depend on, such as
+// volumes to the instances they are attached to, and finds orphans: resources whose
+// parents no longer exist, which keep costing money without doing anything.
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// Ref identifies a resource by service, region and ID
+type Ref struct {
+	Service string
+	Region  string
+	ID      string
+}
+
+// Link is a resource's dependency on a parent resource
+type Link struct {
+	Child    models.Resource
+	Parent   Ref
+	Relation string // such as "attached to"
+}
+
+// Orphan is a resource whose parents no longer exist, or that has none
+type Orphan struct {
+	Account string `json:"account,omitempty"`
+	Service string `json:"service"`
+	Region  string `json:"region"`
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Reason  string `json:"reason"`
+}
+
+// Graph holds the collected resources and the links between them
+type Graph struct {
+	resources map[Ref]models.Resource
+	links     []Link
+}
+
+// imagePattern finds the AMI a snapshot was created for in its description, such as
+// "Created by CreateImage(i-0abc) for ami-0def"
+var imagePattern = regexp.MustCompile(`\bfor (ami-[0-9a-f]+)`)
+
+// copiedVolumeID is the source volume ID of snapshots copied from other snapshots
+const copiedVolumeID = "vol-ffffffff"
+
+// parentRule describes how a kind of resource depends on its parents
+type parentRule struct {
+	// parents returns the resource's parents, or none when it has none
+	parents func(resource models.Resource) []Ref
+
+	// relation labels the links to the parents
+	relation string
+
+	// unparented is the orphan reason for a resource without parents, or empty when
+	// having none is fine
+	unparented func(resource models.Resource) string
+}
+
+// rules returns how a resource depends on its parents, and whether it does at all
+func rules(resource models.Resource) (parentRule, bool) {
+	region := resource.Region
+	switch {
+	case resource.Service == "ebs" && resource.Type == "volume":
+		return parentRule{
+			parents: func(resource models.Resource) []Ref {
+				return refs("ec2", region, models.ExtraStrings(resource.Extra, "instanceIds")...)
+			},
+			relation: "attached to",
+			unparented: func(resource models.Resource) string {
+				if resource.State == "available" {
+					return "Not attached to any instance"
+				}
+				return ""
+			},
+		}, true
+	case resource.Service == "ebs" && resource.Type == "snapshot":
+		return parentRule{
+			parents: func(resource models.Resource) []Ref {
+				// Snapshots of AMIs belong to the image; the volume may be long gone
+				description, _ := resource.Extra["description"].(string)
+				if match := imagePattern.FindStringSubmatch(description); match != nil {
+					return refs("ami", region, match[1])
+				}
+				volumeID, _ := resource.Extra["volumeId"].(string)
+				if volumeID == copiedVolumeID {
+					return nil
+				}
+				return refs("ebs", region, volumeID)
+			},
+			relation: "snapshot of",
+		}, true
+	case resource.Service == "network" && resource.Type == "network-interface":
+		return parentRule{
+			parents: func(resource models.Resource) []Ref {
+				instanceID, _ := resource.Extra["instanceId"].(string)
+				return refs("ec2", region, instanceID)
+			},
+			relation: "attached to",
+			unparented: func(resource models.Resource) string {
+				if resource.State == "available" {
+					return "Not attached to anything"
+				}
+				return ""
+			},
+		}, true
+	case resource.Service == "elb" && resource.Type == "target-group":
+		return parentRule{
+			parents: func(resource models.Resource) []Ref {
+				return refs("elb", region, models.ExtraStrings(resource.Extra, "loadBalancerArns")...)
+			},
+			relation: "target of",
+			unparented: func(resource models.Resource) string {
+				return "Not used by any load balancer"
+			},
+		}, true
+	case resource.Service == "eip":
+		return parentRule{
+			parents: func(resource models.Resource) []Ref {
+				instanceID, _ := resource.Extra["instanceId"].(string)
+				return refs("ec2", region, instanceID)
+			},
+			relation: "associated with",
+			unparented: func(resource models.Resource) string {
+				if resource.State == "unassociated" {
+					return "Not associated with any instance or network interface"
+				}
+				return ""
+			},
+		}, true
+	}
+	return parentRule{}, false
+}
+
+// parentKinds name the parents of each service in orphan reasons
+var parentKinds = map[string]string{
+	"ec2": "instance",
+	"ebs": "volume",
+	"ami": "AMI",
+	"elb": "load balancer",
+}
+
+// refs returns references to the resources of a service with the given IDs, leaving
+// out empty IDs
+func refs(service, region string, ids ...string) []Ref {
+	var list []Ref
+	for _, id := range ids {
+		if id != "" {
+			list = append(list, Ref{Service: service, Region: region, ID: id})
+		}
+	}
+	return list
+}
+
+// Build links each resource to its parents: EBS volumes and network interfaces to the
+// instances they are attached to, snapshots to their source volume or AMI, target
+// groups to their load balancers, and Elastic IPs to their instance
+func Build(resources []models.Resource) *Graph {
+	g := &Graph{resources: make(map[Ref]models.Resource, len(resources))}
+	for _, resource := range resources {
+		g.resources[Ref{Service: resource.Service, Region: resource.Region, ID: resource.ID}] = resource
+	}
+
+	for _, resource := range resources {
+		rule, ok := rules(resource)
+		if !ok {
+			continue
+		}
+		for _, parent := range rule.parents(resource) {
+			g.links = append(g.links, Link{Child: resource, Parent: parent, Relation: rule.relation})
+		}
+	}
+
+	return g
+}
+
+// Links returns the links between resources
+func (g *Graph) Links() []Link {
+	return g.links
+}
+
+// Resource returns the collected resource a reference points to, if it was collected
+func (g *Graph) Resource(ref Ref) (models.Resource, bool) {
+	resource, exists := g.resources[ref]
+	return resource, exists
+}
+
+// Orphans returns the resources that have no parent although they need one, or whose
+// parents all no longer exist. A parent only counts as gone when collected reports
+// that its service was collected in its region; otherwise it may simply not have been
+// looked for. The orphans are sorted by service, region and ID.
+func (g *Graph) Orphans(resources []models.Resource, collected func(service, region string) bool) []Orphan {
+	var orphans []Orphan
+	for _, resource := range resources {
+		rule, ok := rules(resource)
+		if !ok {
+			continue
+		}
+
+		var reason string
+		parents := rule.parents(resource)
+		if len(parents) == 0 {
+			if rule.unparented != nil {
+				reason = rule.unparented(resource)
+			}
+		} else if gone := g.missing(parents, collected); len(gone) == len(parents) {
+			kind := parentKinds[gone[0].Service]
+			reason = fmt.Sprintf("The %s %s no longer exists", kind, gone[0].ID)
+			if len(gone) > 1 {
+				reason = fmt.Sprintf("None of its %d %ss exist any more", len(gone), kind)
+			}
+		}
+		if reason == "" {
+			continue
+		}
+
+		orphans = append(orphans, Orphan{
+			Account: resource.Account,
+			Service: resource.Service,
+			Region:  resource.Region,
+			ID:      resource.ID,
+			Name:    resource.Name,
+			Type:    resource.Type,
+			Reason:  reason,
+		})
+	}
+
+	sort.SliceStable(orphans, func(i, j int) bool {
+		a, b := orphans[i], orphans[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		if a.Region != b.Region {
+			return a.Region < b.Region
+		}
+		return a.ID < b.ID
+	})
+	return orphans
+}
+
+// missing returns the parents known not to exist: their service was collected in
+// their region, yet they were not found
+func (g *Graph) missing(parents []Ref, collected func(service, region string) bool) []Ref {
+	var gone []Ref
+	for _, parent := range parents {
+		if _, exists := g.resources[parent]; !exists && collected(parent.Service, parent.Region) {
+			gone = append(gone, parent)
+		}
+	}
+	return gone
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Age ranges resources are bucketed into by the time since their creation
+const (
+	AgeUnder30Days = "<30d"
+	Age30To90Days  = "30-90d"
+	Age90To365Days = "90-365d"
+	AgeOverYear    = ">1y"
+	AgeUnknown     = "unknown"
+)
+
+// AgeBuckets are the age ranges, youngest first
+var AgeBuckets = []string{AgeUnder30Days, Age30To90Days, Age90To365Days, AgeOverYear, AgeUnknown}
+
+// AgeBucket returns the age range of a resource at now; resources without a creation
+// time are of unknown age
+func AgeBucket(resource Resource, now time.Time) string {
+	if resource.CreatedAt == nil || resource.CreatedAt.IsZero() {
+		return AgeUnknown
+	}
+
+	const day = 24 * time.Hour
+	switch age := now.Sub(*resource.CreatedAt); {
+	case age < 30*day:
+		return AgeUnder30Days
+	case age < 90*day:
+		return Age30To90Days
+	case age < 365*day:
+		return Age90To365Days
+	default:
+		return AgeOverYear
+	}
+}
+
+// AgeByService counts the resources of each service in each age range. Rows that are
+// not AWS resources, such as service quotas, are left out.
+func AgeByService(resources []Resource, now time.Time) map[string]map[string]int {
+	ages := make(map[string]map[string]int)
+	for _, resource := range resources {
+		if !Taggable(resource) {
+			continue
+		}
+		if ages[resource.Service] == nil {
+			ages[resource.Service] = make(map[string]int)
+		}
+		ages[resource.Service][AgeBucket(resource, now)]++
+	}
+	return ages
+}
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// APICalls are the calls made to one AWS API in one region during a collection
+type APICalls struct {
+	API    string `json:"api"` // lower case SDK service ID, as used by rate limits
+	Region string `json:"region"`
+	Calls  int    `json:"calls"`
+	Errors int    `json:"errors,omitempty"`
+
+	// Duration is the total time of the calls, with retries and rate limit waits
+	Duration time.Duration `json:"duration"`
+}
+
+// CallRecorder counts the API calls made with a context and how long they took, so
+// users can see what a scan costs and which limits it runs into. The zero value is
+// ready to use, and it is safe for concurrent use.
+type CallRecorder struct {
+	mu    sync.Mutex
+	calls map[string]*APICalls
+}
+
+// callRecorderKey is the context key of the CallRecorder API calls are counted by
+type callRecorderKey struct{}
+
+// WithCallRecorder returns a context whose API calls are counted by r
+func WithCallRecorder(ctx context.Context, r *CallRecorder) context.Context {
+	return context.WithValue(ctx, callRecorderKey{}, r)
+}
+
+// RecordCall counts a call to an API in a region with the CallRecorder of the
+// context, if it has one
+func RecordCall(ctx context.Context, api, region string, duration time.Duration, err error) {
+	r, ok := ctx.Value(callRecorderKey{}).(*CallRecorder)
+	if !ok {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.calls == nil {
+		r.calls = make(map[string]*APICalls)
+	}
+	key := api + "/" + region
+	calls, exists := r.calls[key]
+	if !exists {
+		calls = &APICalls{API: api, Region: region}
+		r.calls[key] = calls
+	}
+	calls.Calls++
+	calls.Duration += duration
+	if err != nil {
+		calls.Errors++
+	}
+}
+
+// Calls returns the calls counted so far by API and region
+func (r *CallRecorder) Calls() []APICalls {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := make([]APICalls, 0, len(r.calls))
+	for _, c := range r.calls {
+		calls = append(calls, *c)
+	}
+	sortAPICalls(calls)
+	return calls
+}
+
+// sortAPICalls orders calls by API, then region
+func sortAPICalls(calls []APICalls) {
+	sort.Slice(calls, func(i, j int) bool {
+		if calls[i].API != calls[j].API {
+			return calls[i].API < calls[j].API
+		}
+		return calls[i].Region < calls[j].Region
+	})
+}
+
+// addAPICalls adds the counts of more to those of calls with the same API and region
+func addAPICalls(calls, more []APICalls) []APICalls {
+	for _, c := range more {
+		i := slices.IndexFunc(calls, func(existing APICalls) bool {
+			return existing.API == c.API && existing.Region == c.Region
+		})
+		if i < 0 {
+			calls = append(calls, c)
+			continue
+		}
+		calls[i].Calls += c.Calls
+		calls[i].Errors += c.Errors
+		calls[i].Duration += c.Duration
+	}
+	sortAPICalls(calls)
+	return calls
+}
@@ -0,0 +1,142 @@
+package models
+
+import "sort"
+
+// Taggable reports whether a resource is an AWS resource that can carry tags, rather
+// than a report row such as a service quota or a region's audit coverage
+func Taggable(resource Resource) bool {
+	switch {
+	case resource.Service == "quotas":
+		return false
+	case resource.Service == "governance" && resource.Type == "coverage":
+		return false
+	case resource.Service == "cloudwatch" && resource.Type == "custom-metrics":
+		return false
+	}
+	return true
+}
+
+// TagCoverage is how many resources of a group carry every required tag
+type TagCoverage struct {
+	Name      string  `json:"name"`
+	Resources int     `json:"resources"`
+	Compliant int     `json:"compliant"`
+	Coverage  float64 `json:"coverage"` // percentage of compliant resources
+}
+
+// NonCompliantResource is a resource missing required tags
+type NonCompliantResource struct {
+	Service string   `json:"service"`
+	Region  string   `json:"region"`
+	Account string   `json:"account,omitempty"`
+	ID      string   `json:"id"`
+	Name    string   `json:"name,omitempty"`
+	Missing []string `json:"missing"`
+}
+
+// TagCompliance is a tag audit of resources against a list of required tag keys
+type TagCompliance struct {
+	RequiredTags []string               `json:"requiredTags"`
+	Total        TagCoverage            `json:"total"`
+	ByService    []TagCoverage          `json:"byService"`
+	ByRegion     []TagCoverage          `json:"byRegion"`
+	ByAccount    []TagCoverage          `json:"byAccount,omitempty"`
+	NonCompliant []NonCompliantResource `json:"nonCompliant,omitempty"`
+}
+
+// MissingTags returns the required tag keys a resource has no non-empty value for.
+// Normalized keys, such as Owner, are satisfied by any of their aliases.
+func MissingTags(resource Resource, required []string) []string {
+	var missing []string
+	for _, key := range required {
+		if value, _ := resource.TagValue(key); value == "" {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// CheckTagCompliance audits resources for the required tag keys, counting coverage
+// in total and per service, region and account. Rows that are not AWS resources,
+// such as service quotas, are left out.
+func CheckTagCompliance(required []string, resources []Resource) *TagCompliance {
+	compliance := &TagCompliance{
+		RequiredTags: required,
+		Total:        TagCoverage{Name: "total"},
+	}
+
+	byService := make(map[string]*TagCoverage)
+	byRegion := make(map[string]*TagCoverage)
+	byAccount := make(map[string]*TagCoverage)
+	count := func(groups map[string]*TagCoverage, name string, compliant bool) {
+		group, exists := groups[name]
+		if !exists {
+			group = &TagCoverage{Name: name}
+			groups[name] = group
+		}
+		group.Resources++
+		if compliant {
+			group.Compliant++
+		}
+	}
+
+	for _, resource := range resources {
+		if !Taggable(resource) {
+			continue
+		}
+
+		missing := MissingTags(resource, required)
+		compliant := len(missing) == 0
+		compliance.Total.Resources++
+		if compliant {
+			compliance.Total.Compliant++
+		} else {
+			compliance.NonCompliant = append(compliance.NonCompliant, NonCompliantResource{
+				Service: resource.Service,
+				Region:  resource.Region,
+				Account: resource.Account,
+				ID:      resource.ID,
+				Name:    resource.Name,
+				Missing: missing,
+			})
+		}
+
+		count(byService, resource.Service, compliant)
+		count(byRegion, resource.Region, compliant)
+		if resource.Account != "" {
+			count(byAccount, resource.Account, compliant)
+		}
+	}
+
+	compliance.Total.Coverage = coveragePercent(compliance.Total)
+	compliance.ByService = sortedCoverage(byService)
+	compliance.ByRegion = sortedCoverage(byRegion)
+	compliance.ByAccount = sortedCoverage(byAccount)
+	return compliance
+}
+
+// coveragePercent returns the percentage of a group's resources that are compliant;
+// a group without resources is fully covered
+func coveragePercent(group TagCoverage) float64 {
+	if group.Resources == 0 {
+		return 100
+	}
+	return float64(group.Compliant) / float64(group.Resources) * 100
+}
+
+// sortedCoverage returns the groups with their coverage, lowest coverage first, as
+// those need attention
+func sortedCoverage(groups map[string]*TagCoverage) []TagCoverage {
+	list := make([]TagCoverage, 0, len(groups))
+	for _, group := range groups {
+		group.Coverage = coveragePercent(*group)
+		list = append(list, *group)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Coverage != list[j].Coverage {
+			return list[i].Coverage < list[j].Coverage
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
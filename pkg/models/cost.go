@@ -0,0 +1,255 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CostEstimate is the estimated monthly cost of a resource, with how it was worked out
+type CostEstimate struct {
+	Amount             float64
+	Explanation        string
+	Formula            string
+	FormulaExplanation string
+	Breakdown          map[string]float64
+	Assumptions        []string
+	Examples           []string
+	Accuracy           string  // "High", "Medium", "Low" - indicates estimation accuracy
+	FreeTierCovered    bool    // Whether this resource is covered by free tier
+	FreeTierSavings    float64 // Amount saved by free tier
+	Source             string  // "api", "cache", "fallback", "custom", ...
+	Coverage           string  // "reserved" or "savings-plan" when a commitment pays for the resource, empty at on-demand rates
+	OnDemandAmount     float64 // What a covered resource would cost at on-demand rates
+}
+
+// CostSplit divides estimated monthly spend by how it is paid
+type CostSplit struct {
+	// OnDemand is the spend of resources paid at on-demand rates
+	OnDemand float64 `json:"onDemand"`
+
+	// Covered is the spend of resources covered by reservations or Savings Plans: the
+	// reserved rates, and what the commitment does not pay for, such as storage
+	Covered float64 `json:"covered"`
+
+	// Commitments is the spend on Savings Plan commitments and unused reservations
+	Commitments float64 `json:"commitments"`
+}
+
+// Total returns the whole estimated monthly spend
+func (s CostSplit) Total() float64 {
+	return s.OnDemand + s.Covered + s.Commitments
+}
+
+// commitmentServices are the services whose resources are purchased commitments
+var commitmentServices = map[string]bool{"reservations": true, "savingsplans": true}
+
+// SplitCosts divides the estimated monthly cost of resources into on-demand, covered
+// and commitment spend
+func SplitCosts(resources []Resource, costs map[string]*CostEstimate) CostSplit {
+	var split CostSplit
+	for _, resource := range resources {
+		estimate := costs[resource.Key()]
+		if estimate == nil {
+			continue
+		}
+		switch {
+		case commitmentServices[resource.Service]:
+			split.Commitments += estimate.Amount
+		case estimate.Coverage != "":
+			split.Covered += estimate.Amount
+		default:
+			split.OnDemand += estimate.Amount
+		}
+	}
+	return split
+}
+
+// Budget is a monthly spending limit on the resources of a service, with a tag, or both
+type Budget struct {
+	Name    string  `json:"name,omitempty"`
+	Service string  `json:"service,omitempty"`
+	Tag     string  `json:"tag,omitempty"` // key=value, or key for any value
+	Limit   float64 `json:"limit"`
+}
+
+// Label names the budget in reports: its name, or the resources it covers
+func (b Budget) Label() string {
+	if b.Name != "" {
+		return b.Name
+	}
+
+	var parts []string
+	if b.Service != "" {
+		parts = append(parts, b.Service)
+	}
+	if b.Tag != "" {
+		parts = append(parts, "tag "+b.Tag)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Validate checks that the budget covers some resources and has a limit
+func (b Budget) Validate() error {
+	if b.Service == "" && b.Tag == "" {
+		return fmt.Errorf("invalid budget %q: needs a service or a tag", b.Name)
+	}
+	if key, _, _ := strings.Cut(b.Tag, "="); b.Tag != "" && key == "" {
+		return fmt.Errorf("invalid budget %q: tag %q has no key", b.Label(), b.Tag)
+	}
+	if b.Limit <= 0 {
+		return fmt.Errorf("invalid budget %q: limit must be positive", b.Label())
+	}
+	return nil
+}
+
+// Matches reports whether a resource counts against the budget
+func (b Budget) Matches(resource Resource) bool {
+	if b.Service != "" && resource.Service != b.Service {
+		return false
+	}
+	if b.Tag != "" {
+		key, value, hasValue := strings.Cut(b.Tag, "=")
+		tagValue, tagged := resource.TagValue(key)
+		if !tagged || (hasValue && tagValue != value) {
+			return false
+		}
+	}
+	return true
+}
+
+// BudgetStatus is the estimated monthly spend against a budget
+type BudgetStatus struct {
+	Budget
+	Spend     float64 `json:"spend"`
+	Resources int     `json:"resources"`
+	Over      bool    `json:"over"`
+}
+
+// CheckBudgets returns the estimated monthly spend of the resources of each budget
+func CheckBudgets(budgets []Budget, resources []Resource, costs map[string]*CostEstimate) []BudgetStatus {
+	statuses := make([]BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		status := BudgetStatus{Budget: budget}
+		for _, resource := range resources {
+			if !budget.Matches(resource) {
+				continue
+			}
+			status.Resources++
+			if estimate := costs[resource.Key()]; estimate != nil {
+				status.Spend += estimate.Amount
+			}
+		}
+		status.Over = status.Spend > budget.Limit
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Recommendation is a rightsizing recommendation: a smaller size for an instance
+// whose measured utilization leaves room to spare
+type Recommendation struct {
+	Service       string  `json:"service"`
+	Region        string  `json:"region"`
+	ID            string  `json:"id"`
+	Name          string  `json:"name,omitempty"`
+	Current       string  `json:"current"`
+	Recommended   string  `json:"recommended"`
+	Reason        string  `json:"reason"`
+	CurrentCost   float64 `json:"currentCost"`
+	ProjectedCost float64 `json:"projectedCost"`
+	Savings       float64 `json:"savings"`
+}
+
+// ExtraNumber reads a numeric value from a resource's extra fields. Collectors store
+// native integer types, while resources loaded from JSON carry float64.
+func ExtraNumber(extra map[string]interface{}, key string) (float64, bool) {
+	switch v := extra[key].(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// ExtraList reads a list of objects from a resource's extra fields, as stored by a
+// collector or loaded from JSON
+func ExtraList(extra map[string]interface{}, key string) []map[string]interface{} {
+	switch v := extra[key].(type) {
+	case []map[string]interface{}:
+		return v
+	case []interface{}:
+		list := make([]map[string]interface{}, 0, len(v))
+		for _, item := range v {
+			if object, ok := item.(map[string]interface{}); ok {
+				list = append(list, object)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// ExtraNumbers reads a map of numbers from a resource's extra fields, as stored by a
+// collector or loaded from JSON
+func ExtraNumbers(extra map[string]interface{}, key string) map[string]float64 {
+	var numbers map[string]float64
+	switch v := extra[key].(type) {
+	case map[string]int64:
+		numbers = make(map[string]float64, len(v))
+		for name, number := range v {
+			numbers[name] = float64(number)
+		}
+	case map[string]float64:
+		numbers = v
+	case map[string]interface{}:
+		numbers = make(map[string]float64, len(v))
+		for name := range v {
+			if number, ok := ExtraNumber(v, name); ok {
+				numbers[name] = number
+			}
+		}
+	}
+	return numbers
+}
+
+// ExtraStrings reads a list of strings from a resource's extra fields, as stored by a
+// collector or loaded from JSON
+func ExtraStrings(extra map[string]interface{}, key string) []string {
+	switch v := extra[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		list := make([]string, 0, len(v))
+		for _, item := range v {
+			if text, ok := item.(string); ok {
+				list = append(list, text)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// ExtraTime reads a time from a resource's extra fields. Collectors store time.Time,
+// while resources loaded from JSON carry RFC 3339 strings.
+func ExtraTime(extra map[string]interface{}, key string) (time.Time, bool) {
+	switch v := extra[key].(type) {
+	case time.Time:
+		return v, true
+	case *time.Time:
+		if v != nil {
+			return *v, true
+		}
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,55 @@
+package models
+
+import "errors"
+
+// Error classes. Collection errors wrap one of these when their cause is known, so
+// callers can test for them with errors.Is.
+var (
+	// ErrAccessDenied means the credentials lack a permission the collector needs
+	ErrAccessDenied = errors.New("access denied")
+
+	// ErrThrottled means the API kept rejecting requests for exceeding its rate limit
+	ErrThrottled = errors.New("throttled")
+
+	// ErrRegionDisabled means the region is not enabled for the account, or the
+	// service is not offered there
+	ErrRegionDisabled = errors.New("region not enabled")
+
+	// ErrTimeout means the collection ran out of time
+	ErrTimeout = errors.New("timed out")
+
+	// ErrInterrupted means the collection was cancelled, e.g. by Ctrl+C
+	ErrInterrupted = errors.New("interrupted")
+
+	// ErrCredentials means the credentials could not be obtained or renewed, such as
+	// when an SSO session expired during the scan, or were rejected
+	ErrCredentials = errors.New("credentials unavailable")
+
+	// ErrNetwork means the API could not be reached, such as when its endpoint does
+	// not resolve
+	ErrNetwork = errors.New("network error")
+)
+
+// errorClasses names the error classes in the summary breakdown
+var errorClasses = []struct {
+	err  error
+	name string
+}{
+	{ErrAccessDenied, "access-denied"},
+	{ErrThrottled, "throttled"},
+	{ErrRegionDisabled, "region-disabled"},
+	{ErrTimeout, "timeout"},
+	{ErrInterrupted, "interrupted"},
+	{ErrCredentials, "credentials"},
+	{ErrNetwork, "network"},
+}
+
+// ErrorClass returns the class name of err, or "other" if it wraps none of the classes
+func ErrorClass(err error) string {
+	for _, class := range errorClasses {
+		if errors.Is(err, class.err) {
+			return class.name
+		}
+	}
+	return "other"
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"slices"
 	"time"
 )
 
@@ -9,6 +10,7 @@ import (
 type Resource struct {
 	Service      string                 `json:"service"`
 	Region       string                 `json:"region"`
+	Account      string                 `json:"account,omitempty"`
 	ID           string                 `json:"id"`
 	Name         string                 `json:"name,omitempty"`
 	Type         string                 `json:"type,omitempty"`          // instance type, engine, runtime...
@@ -19,11 +21,98 @@ type Resource struct {
 	Extra        map[string]interface{} `json:"extra,omitempty"`
 }
 
+// Key identifies a resource within a collection. IDs such as function and table names
+// are only unique within a service, region and account.
+func (r Resource) Key() string {
+	key := r.Service + "/" + r.Region + "/" + r.ID
+	if r.Account != "" {
+		key = r.Account + "/" + key
+	}
+	return key
+}
+
 // ResourceCollection represents a collection of resources with metadata
 type ResourceCollection struct {
-	Resources []Resource `json:"resources"`
-	Errors    []string   `json:"errors,omitempty"`
-	Summary   Summary    `json:"summary"`
+	Resources  []Resource  `json:"resources"`
+	Errors     []string    `json:"errors,omitempty"`
+	Warnings   []string    `json:"warnings,omitempty"`
+	Skipped    []string    `json:"skipped,omitempty"`     // service/region pairs not collected as the region is not enabled
+	Summary    Summary     `json:"summary"`
+	Comparison *Comparison `json:"comparison,omitempty"`
+
+	// Costs are the monthly cost estimates of the resources keyed by Resource.Key, once
+	// the pricing engine has annotated the collection
+	Costs map[string]*CostEstimate `json:"-"`
+
+	// Budgets are the configured spending limits reports check the resources against
+	Budgets []Budget `json:"-"`
+}
+
+// Merge adds the resources, problems and summary of another collection, such as one
+// of another account, to the collection
+func (c *ResourceCollection) Merge(other *ResourceCollection) {
+	c.Resources = append(c.Resources, other.Resources...)
+	c.Errors = append(c.Errors, other.Errors...)
+	c.Warnings = append(c.Warnings, other.Warnings...)
+	c.Skipped = append(c.Skipped, other.Skipped...)
+
+	if len(other.Costs) > 0 && c.Costs == nil {
+		c.Costs = make(map[string]*CostEstimate, len(other.Costs))
+	}
+	for key, estimate := range other.Costs {
+		c.Costs[key] = estimate
+	}
+
+	summary := &c.Summary
+	summary.TotalResources += other.Summary.TotalResources
+	summary.ByService = addCounts(summary.ByService, other.Summary.ByService)
+	summary.ByRegion = addCounts(summary.ByRegion, other.Summary.ByRegion)
+	summary.ByState = addCounts(summary.ByState, other.Summary.ByState)
+	summary.ErrorsByClass = addCounts(summary.ErrorsByClass, other.Summary.ErrorsByClass)
+	for service, ages := range other.Summary.ByAge {
+		if summary.ByAge == nil {
+			summary.ByAge = make(map[string]map[string]int)
+		}
+		summary.ByAge[service] = addCounts(summary.ByAge[service], ages)
+	}
+	summary.Errors += other.Summary.Errors
+	summary.Warnings += other.Summary.Warnings
+	summary.Skipped += other.Summary.Skipped
+	summary.Cached += other.Summary.Cached
+	summary.Partial = summary.Partial || other.Summary.Partial
+	summary.Duration += other.Summary.Duration
+	summary.Regions = appendMissing(summary.Regions, other.Summary.Regions)
+	summary.Services = appendMissing(summary.Services, other.Summary.Services)
+	summary.APICalls = addAPICalls(summary.APICalls, other.Summary.APICalls)
+}
+
+// addCounts adds the counts of from to those of to, creating to if needed
+func addCounts(to, from map[string]int) map[string]int {
+	if to == nil && len(from) > 0 {
+		to = make(map[string]int, len(from))
+	}
+	for key, count := range from {
+		to[key] += count
+	}
+	return to
+}
+
+// appendMissing appends the values not in values yet
+func appendMissing(values, more []string) []string {
+	for _, value := range more {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// Comparison lists the resources that appeared or disappeared since an earlier snapshot
+type Comparison struct {
+	Snapshot    string     `json:"snapshot"`
+	ComparedTo  time.Time  `json:"comparedTo"`
+	New         []Resource `json:"new"`
+	Disappeared []Resource `json:"disappeared"`
 }
 
 // Summary provides statistics about the inventory
@@ -32,10 +121,17 @@ type Summary struct {
 	ByService      map[string]int         `json:"byService"`
 	ByRegion       map[string]int         `json:"byRegion"`
 	ByState        map[string]int         `json:"byState"`
+	ByAge          map[string]map[string]int `json:"byAge,omitempty"` // resources per service and age range, see AgeBuckets
 	Errors         int                    `json:"errors"`
+	ErrorsByClass  map[string]int         `json:"errorsByClass,omitempty"`
+	Warnings       int                    `json:"warnings"`
+	Skipped        int                    `json:"skipped,omitempty"`
+	Cached         int                    `json:"cached,omitempty"`      // service/region pairs taken from the result cache
+	Partial        bool                   `json:"partial,omitempty"`     // collection stopped before every service and region finished
 	Duration       time.Duration          `json:"duration"`
 	Regions        []string               `json:"regions"`
 	Services       []string               `json:"services"`
+	APICalls       []APICalls             `json:"apiCalls,omitempty"` // calls made per API and region
 }
 
 // Collector defines the interface for AWS service collectors
@@ -48,6 +144,45 @@ type Collector interface {
 	
 	// Regions returns the list of regions this collector supports
 	Regions() []string
+
+	// Scope returns whether the collector runs in each region or once per account
+	Scope() Scope
+}
+
+// Scope is where a collector's resources live
+type Scope string
+
+const (
+	// ScopeRegional collectors run once in each region
+	ScopeRegional Scope = "regional"
+
+	// ScopeGlobal collectors run once per account, with the region GlobalRegion
+	ScopeGlobal Scope = "global"
+)
+
+// GlobalRegion is the region of global collectors' work and resources
+const GlobalRegion = "global"
+
+// Describer is implemented by collectors that can fetch a single resource directly
+type Describer interface {
+	// Describe returns the resource with the given ID and the raw API response it was
+	// built from, or a nil resource if the collector cannot look it up directly
+	Describe(ctx context.Context, region, id string) (*Resource, interface{}, error)
+}
+
+// Tagger is implemented by collectors whose resources' tags take API calls of their
+// own, so collections can leave them out for speed
+type Tagger interface {
+	// AddTags looks up the tags of resources the collector returned for a region and
+	// sets them. Failures are reported as warnings.
+	AddTags(ctx context.Context, region string, resources []Resource)
+}
+
+// PermissionLister is implemented by collectors registered from outside the project,
+// whose IAM actions are not in the built-in permission lists
+type PermissionLister interface {
+	// Permissions returns the IAM actions the collector needs
+	Permissions() []string
 }
 
 // CollectorResult represents the result of a collector operation
@@ -56,4 +191,11 @@ type CollectorResult struct {
 	Region    string
 	Resources []Resource
 	Error     error
+
+	// Warnings are the problems the collector worked around, such as a resource it
+	// could not describe
+	Warnings []string
+
+	// Cached is set when the resources came from the result cache
+	Cached bool
 } 
\ No newline at end of file
@@ -84,4 +84,98 @@ func TestResourceCollection_Summary(t *testing.T) {
 	if collection.Summary.ByRegion["us-east-1"] != 2 {
 		t.Errorf("Expected 2 resources in us-east-1, got %d", collection.Summary.ByRegion["us-east-1"])
 	}
-} 
\ No newline at end of file
+} 
+func TestResource_TagValue(t *testing.T) {
+	resource := Resource{
+		Tags: map[string]string{
+			"team":    "data",
+			"ENV":     "prod",
+			"Project": "web",
+		},
+	}
+
+	tests := []struct {
+		key    string
+		value  string
+		exists bool
+	}{
+		{"Owner", "data", true},
+		{"owner", "data", true},
+		{"Environment", "prod", true},
+		{"Project", "web", true},
+		{"project", "", false},
+		{"CostCenter", "", false},
+	}
+	for _, tt := range tests {
+		value, exists := resource.TagValue(tt.key)
+		if value != tt.value || exists != tt.exists {
+			t.Errorf("TagValue(%q) = %q, %v; want %q, %v", tt.key, value, exists, tt.value, tt.exists)
+		}
+	}
+}
+
+func TestResource_Key(t *testing.T) {
+	east := Resource{Service: "lambda", Region: "us-east-1", ID: "handler"}
+	west := Resource{Service: "lambda", Region: "us-west-2", ID: "handler"}
+	if east.Key() == west.Key() {
+		t.Errorf("functions with the same name in different regions share the key %q", east.Key())
+	}
+
+	table := Resource{Service: "dynamodb", Region: "us-east-1", ID: "handler"}
+	if east.Key() == table.Key() {
+		t.Errorf("resources with the same ID in different services share the key %q", east.Key())
+	}
+
+	other := east
+	other.Account = "123456789012"
+	if want := "123456789012/lambda/us-east-1/handler"; other.Key() != want {
+		t.Errorf("Key() = %q; want %q", other.Key(), want)
+	}
+}
+
+func TestResourceCollection_Merge(t *testing.T) {
+	collection := &ResourceCollection{
+		Resources: []Resource{{Service: "ec2", Region: "us-east-1", Account: "111111111111"}},
+		Summary: Summary{
+			TotalResources: 1,
+			ByService:      map[string]int{"ec2": 1},
+			ByRegion:       map[string]int{"us-east-1": 1},
+			Regions:        []string{"us-east-1"},
+			Services:       []string{"ec2"},
+		},
+	}
+	other := &ResourceCollection{
+		Resources: []Resource{
+			{Service: "ec2", Region: "us-east-1", Account: "222222222222"},
+			{Service: "rds", Region: "us-west-2", Account: "222222222222"},
+		},
+		Errors: []string{"lambda/us-west-2: access denied"},
+		Summary: Summary{
+			TotalResources: 2,
+			ByService:      map[string]int{"ec2": 1, "rds": 1},
+			ByRegion:       map[string]int{"us-east-1": 1, "us-west-2": 1},
+			Errors:         1,
+			ErrorsByClass:  map[string]int{"access-denied": 1},
+			Regions:        []string{"us-east-1", "us-west-2"},
+			Services:       []string{"ec2", "rds"},
+		},
+	}
+
+	collection.Merge(other)
+
+	want := Summary{
+		TotalResources: 3,
+		ByService:      map[string]int{"ec2": 2, "rds": 1},
+		ByRegion:       map[string]int{"us-east-1": 2, "us-west-2": 1},
+		Errors:         1,
+		ErrorsByClass:  map[string]int{"access-denied": 1},
+		Regions:        []string{"us-east-1", "us-west-2"},
+		Services:       []string{"ec2", "rds"},
+	}
+	if diff := cmp.Diff(want, collection.Summary); diff != "" {
+		t.Errorf("Summary mismatch after merge (-want +got):\n%s", diff)
+	}
+	if len(collection.Resources) != 3 || len(collection.Errors) != 1 {
+		t.Errorf("Expected 3 resources and 1 error, got %d and %d", len(collection.Resources), len(collection.Errors))
+	}
+}
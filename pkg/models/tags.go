@@ -0,0 +1,128 @@
+package models
+
+import (
+	"sort"
+	"strings"
+)
+
+// TagMapping derives a normalized tag from the keys accounts use for the same thing,
+// such as Owner from owner, Owner or team
+type TagMapping struct {
+	// Key is the normalized tag key
+	Key string `json:"key"`
+
+	// Aliases are the tag keys that hold the value, in order of preference, matched
+	// in any case. The normalized key itself is always tried first.
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// DefaultTagMappings derive Owner and Environment from their common spellings
+var DefaultTagMappings = []TagMapping{
+	{Key: "Owner", Aliases: []string{"team"}},
+	{Key: "Environment", Aliases: []string{"env"}},
+}
+
+// tagMappings are the mappings TagValue resolves normalized keys with
+var tagMappings = DefaultTagMappings
+
+// SetTagMappings replaces the tag mappings. Mappings for the same key as a default
+// mapping replace it; the other defaults are kept.
+func SetTagMappings(mappings []TagMapping) {
+	merged := make([]TagMapping, 0, len(DefaultTagMappings)+len(mappings))
+	for _, mapping := range DefaultTagMappings {
+		if _, replaced := findTagMapping(mappings, mapping.Key); !replaced {
+			merged = append(merged, mapping)
+		}
+	}
+	tagMappings = append(merged, mappings...)
+}
+
+// TagMappings returns the tag mappings in use
+func TagMappings() []TagMapping {
+	return append([]TagMapping(nil), tagMappings...)
+}
+
+// findTagMapping returns the mapping for a normalized key, matched in any case
+func findTagMapping(mappings []TagMapping, key string) (TagMapping, bool) {
+	for _, mapping := range mappings {
+		if strings.EqualFold(mapping.Key, key) {
+			return mapping, true
+		}
+	}
+	return TagMapping{}, false
+}
+
+// TagValue returns the value of a resource's tag. For a normalized key, such as
+// Owner, it is the first non-empty value of the key or its aliases in any case; any
+// other key must match exactly.
+func (r Resource) TagValue(key string) (string, bool) {
+	mapping, normalized := findTagMapping(tagMappings, key)
+	if !normalized {
+		value, exists := r.Tags[key]
+		return value, exists
+	}
+
+	for _, alias := range append([]string{mapping.Key}, mapping.Aliases...) {
+		if value, exists := r.Tags[alias]; exists && value != "" {
+			return value, true
+		}
+		for tagKey, value := range r.Tags {
+			if strings.EqualFold(tagKey, alias) && value != "" {
+				return value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// NormalizedTags returns the values of the normalized tags a resource has
+func (r Resource) NormalizedTags() map[string]string {
+	tags := make(map[string]string)
+	for _, mapping := range tagMappings {
+		if value, exists := r.TagValue(mapping.Key); exists {
+			tags[mapping.Key] = value
+		}
+	}
+	return tags
+}
+
+// TagAllocation is the number and cost of resources with one value of a normalized tag
+type TagAllocation struct {
+	Value     string  `json:"value"` // empty for resources without the tag
+	Resources int     `json:"resources"`
+	Cost      float64 `json:"cost"`
+}
+
+// AllocateByTag groups resources by their value of a normalized tag, with the cost of
+// each group, highest cost first. Resources without the tag form a group without a
+// value.
+func AllocateByTag(key string, resources []Resource, costs map[string]*CostEstimate) []TagAllocation {
+	groups := make(map[string]*TagAllocation)
+	for _, resource := range resources {
+		if !Taggable(resource) {
+			continue
+		}
+		value, _ := resource.TagValue(key)
+		group, exists := groups[value]
+		if !exists {
+			group = &TagAllocation{Value: value}
+			groups[value] = group
+		}
+		group.Resources++
+		if estimate := costs[resource.Key()]; estimate != nil {
+			group.Cost += estimate.Amount
+		}
+	}
+
+	allocations := make([]TagAllocation, 0, len(groups))
+	for _, group := range groups {
+		allocations = append(allocations, *group)
+	}
+	sort.Slice(allocations, func(i, j int) bool {
+		if allocations[i].Cost != allocations[j].Cost {
+			return allocations[i].Cost > allocations[j].Cost
+		}
+		return allocations[i].Value < allocations[j].Value
+	})
+	return allocations
+}
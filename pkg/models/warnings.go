@@ -0,0 +1,45 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Warnings collects the non-fatal problems a collector runs into, such as a single
+// table it could not describe, so they are reported with the inventory rather than
+// printed into it. It is safe for concurrent use.
+type Warnings struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// warningsKey is the context key of the Warnings a collector reports to
+type warningsKey struct{}
+
+// WithWarnings returns a context whose collector warnings are added to w
+func WithWarnings(ctx context.Context, w *Warnings) context.Context {
+	return context.WithValue(ctx, warningsKey{}, w)
+}
+
+// Warnf reports a warning to the Warnings of the context. Without one, as when a
+// collector is called directly, the warning is logged to stderr.
+func Warnf(ctx context.Context, format string, args ...interface{}) {
+	w, ok := ctx.Value(warningsKey{}).(*Warnings)
+	if !ok {
+		log.Printf("Warning: "+format, args...)
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, fmt.Sprintf(format, args...))
+}
+
+// Messages returns the warnings reported so far
+func (w *Warnings) Messages() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.messages...)
+}
@@ -2,28 +2,89 @@ package orchestrator
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"path"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/cache"
 	"github.com/xiaochen/awsinv/pkg/collectors"
 	"github.com/xiaochen/awsinv/pkg/models"
 )
 
+// collectorGracePeriod is how long a collector still running when its timeout or the
+// overall deadline passes is awaited before its slot is given to the next one. The
+// collectors honour the context, so they normally return within the API call that
+// was interrupted.
+const collectorGracePeriod = 5 * time.Second
+
 // Orchestrator manages the collection of AWS resources across services and regions
 type Orchestrator struct {
 	clientManager *awspkg.ClientManager
 	collectors    map[string]models.Collector
+	settings      Settings
+
+	// account is the account of the credentials, once looked up
+	accountMu sync.Mutex
+	account   string
+}
+
+// Settings holds collector configuration that is fixed for the lifetime of an orchestrator
+type Settings struct {
+	// QuotaThreshold is the utilization percentage at which quotas are flagged
+	QuotaThreshold float64
+
+	// CloudControlTypes lists the resource type names (e.g. AWS::SQS::Queue) that the
+	// cloudcontrol collector inventories through the Cloud Control API
+	CloudControlTypes []string
+
+	// UsageMetrics measures the usage of Lambda functions, DynamoDB tables and NAT
+	// gateways from CloudWatch metrics, for cost estimates based on usage
+	UsageMetrics bool
+
+	// Rightsizing measures the CPU and memory utilization of EC2 and RDS instances
+	// from CloudWatch metrics, for rightsizing recommendations
+	Rightsizing bool
+
+	// SkipTags leaves out the tags that take API calls of their own to look up, for
+	// faster collections. Tags returned with the resources are still reported.
+	SkipTags bool
+
+	// SkipBucketDetails leaves out the security settings of S3 buckets, which take
+	// an API call each per bucket
+	SkipBucketDetails bool
+
+	// Logger receives the progress of collections run with CollectOptions.Verbose.
+	// Without it, progress goes to the writer set with SetStderr.
+	Logger io.Writer
 }
 
-// NewOrchestrator creates a new orchestrator
+// DefaultSettings returns the settings used by NewOrchestrator
+func DefaultSettings() Settings {
+	return Settings{
+		QuotaThreshold: collectors.DefaultQuotaThreshold,
+	}
+}
+
+// NewOrchestrator creates a new orchestrator with default settings
 func NewOrchestrator(clientManager *awspkg.ClientManager) *Orchestrator {
+	return NewOrchestratorWithSettings(clientManager, DefaultSettings())
+}
+
+// NewOrchestratorWithSettings creates a new orchestrator with the given collector settings
+func NewOrchestratorWithSettings(clientManager *awspkg.ClientManager, settings Settings) *Orchestrator {
 	o := &Orchestrator{
 		clientManager: clientManager,
 		collectors:    make(map[string]models.Collector),
+		settings:      settings,
 	}
 
 	// Register all collectors
@@ -32,18 +93,52 @@ func NewOrchestrator(clientManager *awspkg.ClientManager) *Orchestrator {
 	return o
 }
 
-// registerCollectors registers all available collectors
+// registerCollectors registers the collectors the project ships, then those added
+// with RegisterCollector
 func (o *Orchestrator) registerCollectors() {
+	o.registerBuiltinCollectors()
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for name, factory := range registry {
+		o.collectors[name] = factory(o.clientManager, o.settings)
+	}
+}
+
+// registerBuiltinCollectors registers the collectors the project ships
+func (o *Orchestrator) registerBuiltinCollectors() {
 	o.collectors["ec2"] = collectors.NewEC2Collector(o.clientManager)
 	o.collectors["rds"] = collectors.NewRDSCollector(o.clientManager)
 	o.collectors["lambda"] = collectors.NewLambdaCollector(o.clientManager)
-	o.collectors["s3"] = collectors.NewS3Collector(o.clientManager)
+	o.collectors["s3"] = collectors.NewS3Collector(o.clientManager, o.settings.SkipBucketDetails)
 	o.collectors["dynamodb"] = collectors.NewDynamoDBCollector(o.clientManager)
 	o.collectors["sfn"] = collectors.NewSFNCollector(o.clientManager)
 	o.collectors["cloudwatch"] = collectors.NewCloudWatchCollector(o.clientManager)
+	o.collectors["eventbridge"] = collectors.NewEventBridgeCollector(o.clientManager)
 	o.collectors["ecs"] = collectors.NewECSCollector(o.clientManager)
 	o.collectors["redis"] = collectors.NewRedisCollector(o.clientManager)
 	o.collectors["efs"] = collectors.NewEFSCollector(o.clientManager)
+	o.collectors["fsx"] = collectors.NewFSxCollector(o.clientManager)
+	o.collectors["governance"] = collectors.NewGovernanceCollector(o.clientManager)
+	o.collectors["waf"] = collectors.NewWAFCollector(o.clientManager)
+	o.collectors["shield"] = collectors.NewShieldCollector(o.clientManager)
+	o.collectors["eip"] = collectors.NewEIPCollector(o.clientManager)
+	o.collectors["ebs"] = collectors.NewEBSCollector(o.clientManager)
+	o.collectors["elb"] = collectors.NewELBCollector(o.clientManager)
+	o.collectors["quotas"] = collectors.NewQuotasCollector(o.clientManager, o.settings.QuotaThreshold)
+	o.collectors["ami"] = collectors.NewAMICollector(o.clientManager)
+	o.collectors["network"] = collectors.NewNetworkCollector(o.clientManager)
+	o.collectors["directconnect"] = collectors.NewDirectConnectCollector(o.clientManager)
+	o.collectors["apprunner"] = collectors.NewAppRunnerCollector(o.clientManager)
+	o.collectors["amplify"] = collectors.NewAmplifyCollector(o.clientManager)
+	o.collectors["backup"] = collectors.NewBackupCollector(o.clientManager)
+	o.collectors["batch"] = collectors.NewBatchCollector(o.clientManager)
+	o.collectors["codebuild"] = collectors.NewCodeBuildCollector(o.clientManager)
+	o.collectors["codepipeline"] = collectors.NewCodePipelineCollector(o.clientManager)
+	o.collectors["reservations"] = collectors.NewReservationsCollector(o.clientManager)
+	o.collectors["savingsplans"] = collectors.NewSavingsPlansCollector(o.clientManager)
+	o.collectors["iam"] = collectors.NewIAMCollector(o.clientManager)
+	o.collectors["cloudcontrol"] = collectors.NewCloudControlCollector(o.clientManager, o.settings.CloudControlTypes)
 }
 
 // GetAvailableServices returns the list of available services
@@ -56,20 +151,188 @@ func (o *Orchestrator) GetAvailableServices() []string {
 	return services
 }
 
+// RequiredPermissions returns the sorted IAM actions needed to collect the given
+// services, or all services when none are given
+func (o *Orchestrator) RequiredPermissions(services []string) ([]string, error) {
+	services, err := o.prepareServices(services)
+	if err != nil {
+		return nil, err
+	}
+
+	actionSet := make(map[string]bool)
+	for _, action := range collectors.CommonPermissions {
+		actionSet[action] = true
+	}
+	if o.settings.UsageMetrics {
+		for _, action := range collectors.UsagePermissions {
+			actionSet[action] = true
+		}
+	}
+	if o.settings.Rightsizing {
+		for _, action := range collectors.UtilizationPermissions {
+			actionSet[action] = true
+		}
+	}
+	for _, service := range services {
+		for _, action := range collectors.Permissions[service] {
+			actionSet[action] = true
+		}
+		if lister, ok := o.collectors[service].(models.PermissionLister); ok {
+			for _, action := range lister.Permissions() {
+				actionSet[action] = true
+			}
+		}
+		if !o.settings.SkipTags {
+			for _, action := range collectors.TagPermissions[service] {
+				actionSet[action] = true
+			}
+		}
+		if service == "s3" && !o.settings.SkipBucketDetails {
+			for _, action := range collectors.BucketDetailPermissions {
+				actionSet[action] = true
+			}
+		}
+	}
+
+	actions := make([]string, 0, len(actionSet))
+	for action := range actionSet {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions, nil
+}
+
 // CollectOptions holds options for the collection process
 type CollectOptions struct {
-	Services   []string
-	Regions    []string
-	Parallel   int
-	FailFast   bool
-	Timeout    time.Duration
-	Verbose    bool
+	Services []string
+	Regions  []string
+	Parallel int
+	Verbose  bool
+
+	// FailFast stops the collection at the first service that fails in a region,
+	// cancelling the work still running, and makes Collect return its error
+	FailFast bool
+
+	// Timeout is the overall deadline of the collection. Work still running or
+	// waiting when it passes is reported as timed out, and the collection returns
+	// what was collected so far.
+	Timeout time.Duration
+
+	// IncludeOptInRegions scans all regions, including those the account has not
+	// opted in to, instead of only the enabled ones
+	IncludeOptInRegions bool
+
+	// ExcludeRegions are left out of the regions discovered or given, by name or by
+	// a shell pattern such as "ap-*"
+	ExcludeRegions []string
+
+	// ItemTimeout bounds the collection of one service in one region, so a slow
+	// region cannot use up the overall deadline
+	ItemTimeout time.Duration
+
+	// Cache, when set, stores the resources of each service and region as they are
+	// collected, and tracks whether the scan finished
+	Cache *cache.Cache
+
+	// CacheTTL reuses cached resources younger than this instead of collecting them
+	CacheTTL time.Duration
+
+	// Resume reuses the resources cached by the last scan, if it did not finish,
+	// so only the services and regions it did not reach are collected
+	Resume bool
+}
+
+// scanCache is the cache of one collection, bound to the account scanned and to
+// the orchestrator settings, which change what some collectors return
+type scanCache struct {
+	cache   *cache.Cache
+	account string
+	variant string
+
+	// Cached resources are used if read is set and they were stored since then
+	read  bool
+	since time.Time
+
+	// run is the scan recorded as unfinished until it completes
+	run cache.Run
+}
+
+// openCache binds the cache of the options to the account of the credentials
+func (o *Orchestrator) openCache(ctx context.Context, opts CollectOptions) (*scanCache, error) {
+	if opts.Cache == nil {
+		return nil, nil
+	}
+
+	account, err := o.accountID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := json.Marshal(o.settings)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(settings)
+
+	resultCache := &scanCache{
+		cache:   opts.Cache,
+		account: account,
+		variant: hex.EncodeToString(sum[:4]),
+	}
+
+	now := time.Now()
+	if opts.CacheTTL > 0 {
+		resultCache.read = true
+		resultCache.since = now.Add(-opts.CacheTTL)
+	}
+
+	// A resumed scan keeps the start of the scan it continues, so it covers
+	// everything collected since then
+	run := cache.Run{StartedAt: now}
+	if opts.Resume {
+		if unfinished := opts.Cache.UnfinishedRun(account); unfinished != nil {
+			run = *unfinished
+			if !resultCache.read || run.StartedAt.Before(resultCache.since) {
+				resultCache.since = run.StartedAt
+			}
+			resultCache.read = true
+		}
+	}
+	resultCache.run = run
+
+	return resultCache, nil
+}
+
+// accountID returns the ID of the account the credentials belong to, asking STS the
+// first time
+func (o *Orchestrator) accountID(ctx context.Context) (string, error) {
+	o.accountMu.Lock()
+	defer o.accountMu.Unlock()
+
+	if o.account == "" {
+		identity, err := o.clientManager.CallerIdentity(ctx)
+		if err != nil {
+			return "", err
+		}
+		o.account = identity.Account
+	}
+	return o.account, nil
 }
 
 // Collect performs the inventory collection across all specified services and regions
 func (o *Orchestrator) Collect(ctx context.Context, opts CollectOptions) (*models.ResourceCollection, error) {
 	startTime := time.Now()
 
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	// API calls are counted for the summary, region discovery included
+	calls := &models.CallRecorder{}
+	ctx = models.WithCallRecorder(ctx, calls)
+
 	// Validate and prepare services
 	services, err := o.prepareServices(opts.Services)
 	if err != nil {
@@ -77,23 +340,121 @@ func (o *Orchestrator) Collect(ctx context.Context, opts CollectOptions) (*model
 	}
 
 	// Discover or validate regions
-	regions, err := o.prepareRegions(ctx, opts.Regions)
+	regions, err := o.prepareRegions(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
 
+	// Scan progress is recorded so the scan can be resumed, which is no reason to
+	// fail it when the cache cannot be written
+	var warnings []string
+	resultCache, err := o.openCache(ctx, opts)
+	switch {
+	case err != nil && (opts.CacheTTL > 0 || opts.Resume):
+		return nil, err
+	case err != nil:
+		warnings = append(warnings, fmt.Sprintf("scan progress not recorded: %v", err))
+	case resultCache != nil:
+		if err := resultCache.cache.StartRun(resultCache.account, resultCache.run); err != nil {
+			warnings = append(warnings, fmt.Sprintf("scan progress not recorded, so it cannot be resumed: %v", err))
+		}
+	}
+
+	// Resources are labeled with their account, so reports can break them down by
+	// account. It is looked up before collecting, as a timed out scan cannot.
+	account, err := o.accountID(ctx)
+	if err != nil {
+		o.verbosef(opts, "Warning: resources are not labeled with their account: %v\n", err)
+	}
+
 	// Create work items
 	workItems := o.createWorkItems(services, regions)
 
 	// Execute collection
-	results := o.executeCollection(ctx, workItems, opts)
+	results, failure := o.executeCollection(ctx, workItems, opts, resultCache)
+	if failure != nil {
+		return nil, failure
+	}
 
 	// Aggregate results
 	collection := o.aggregateResults(results, startTime)
+	collection.Summary.APICalls = calls.Calls()
+	o.logAPICalls(opts, collection.Summary.APICalls)
+	for i := range collection.Resources {
+		if collection.Resources[i].Account == "" {
+			collection.Resources[i].Account = account
+		}
+	}
+
+	// A scan with failures stays unfinished, so it can be resumed to retry them
+	if resultCache != nil && !collection.Summary.Partial && collection.Summary.Errors == 0 {
+		if err := resultCache.cache.FinishRun(resultCache.account); err != nil {
+			warnings = append(warnings, fmt.Sprintf("scan not recorded as finished: %v", err))
+		}
+	}
+	collection.Warnings = append(collection.Warnings, warnings...)
+	collection.Summary.Warnings += len(warnings)
 
 	return collection, nil
 }
 
+// Describe fetches one resource by service, region and ID, returning the raw API
+// response as well when the collector can look the resource up directly. Other
+// resources are found by collecting the service in the region and matching the ID
+// or name.
+func (o *Orchestrator) Describe(ctx context.Context, service, region, id string) (*models.Resource, interface{}, error) {
+	collector, exists := o.collectors[service]
+	if !exists {
+		return nil, nil, fmt.Errorf("invalid service: %s", service)
+	}
+	if collector.Scope() == models.ScopeGlobal {
+		region = models.GlobalRegion
+	}
+
+	if describer, ok := collector.(models.Describer); ok {
+		resource, raw, err := describer.Describe(ctx, region, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if resource != nil {
+			tagged := []models.Resource{*resource}
+			o.addTags(ctx, collector, region, tagged)
+			return &tagged[0], raw, nil
+		}
+	}
+
+	resources, err := collector.Collect(ctx, region)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range resources {
+		if resources[i].ID == id {
+			o.addTags(ctx, collector, region, resources[i:i+1])
+			return &resources[i], nil, nil
+		}
+	}
+	for i := range resources {
+		if resources[i].Name == id {
+			o.addTags(ctx, collector, region, resources[i:i+1])
+			return &resources[i], nil, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("%s resource %s not found in %s", service, id, region)
+}
+
+// addTags looks up the tags of resources a collector returned for a region, unless
+// tags are skipped or the collector's resources come with their tags
+func (o *Orchestrator) addTags(ctx context.Context, collector models.Collector, region string, resources []models.Resource) {
+	if o.settings.SkipTags {
+		return
+	}
+	if tagger, ok := collector.(models.Tagger); ok {
+		tagger.AddTags(ctx, region, resources)
+	}
+}
+
 // prepareServices validates and prepares the list of services to collect
 func (o *Orchestrator) prepareServices(services []string) ([]string, error) {
 	if len(services) == 0 {
@@ -119,15 +480,48 @@ func (o *Orchestrator) prepareServices(services []string) ([]string, error) {
 	return validServices, nil
 }
 
-// prepareRegions discovers or validates regions
-func (o *Orchestrator) prepareRegions(ctx context.Context, regions []string) ([]string, error) {
-	if len(regions) == 0 {
+// prepareRegions discovers or validates regions, leaving out the excluded ones
+func (o *Orchestrator) prepareRegions(ctx context.Context, opts CollectOptions) ([]string, error) {
+	var regions []string
+	var err error
+	if len(opts.Regions) == 0 {
 		// Discover all regions
-		return o.clientManager.DiscoverRegions(ctx)
+		regions, err = o.clientManager.DiscoverRegions(ctx, opts.IncludeOptInRegions)
+	} else {
+		// Validate provided regions
+		regions, err = o.clientManager.ValidateRegions(ctx, opts.Regions, opts.IncludeOptInRegions)
+	}
+	if err != nil || len(opts.ExcludeRegions) == 0 {
+		return regions, err
 	}
 
-	// Validate provided regions
-	return o.clientManager.ValidateRegions(ctx, regions)
+	return excludeRegions(regions, opts.ExcludeRegions)
+}
+
+// excludeRegions removes the regions matching any of the patterns
+func excludeRegions(regions, patterns []string) ([]string, error) {
+	var kept []string
+	for _, region := range regions {
+		excluded := false
+		for _, pattern := range patterns {
+			matched, err := path.Match(pattern, region)
+			if err != nil {
+				return nil, fmt.Errorf("invalid region pattern %q: %w", pattern, err)
+			}
+			if matched {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			kept = append(kept, region)
+		}
+	}
+
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("no regions left to scan: all of %s are excluded", strings.Join(regions, ", "))
+	}
+	return kept, nil
 }
 
 // workItem represents a single collection task
@@ -144,8 +538,11 @@ func (o *Orchestrator) createWorkItems(services, regions []string) []workItem {
 		collector := o.collectors[service]
 		collectorRegions := collector.Regions()
 
-		// If collector specifies regions, use those; otherwise use all regions
-		if len(collectorRegions) > 0 {
+		// Global collectors run once; otherwise, if collector specifies regions, use
+		// those, or else all regions
+		if collector.Scope() == models.ScopeGlobal {
+			items = append(items, workItem{Service: service, Region: models.GlobalRegion})
+		} else if len(collectorRegions) > 0 {
 			for _, region := range collectorRegions {
 				items = append(items, workItem{Service: service, Region: region})
 			}
@@ -159,77 +556,219 @@ func (o *Orchestrator) createWorkItems(services, regions []string) []workItem {
 	return items
 }
 
-// executeCollection executes the collection in parallel
-func (o *Orchestrator) executeCollection(ctx context.Context, workItems []workItem, opts CollectOptions) []models.CollectorResult {
+// executeCollection executes the collection in parallel. With FailFast, the first
+// item that fails stops the others and is returned as the failure.
+func (o *Orchestrator) executeCollection(ctx context.Context, workItems []workItem, opts CollectOptions, resultCache *scanCache) ([]models.CollectorResult, error) {
 	var results []models.CollectorResult
+	var failure error
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
+	// Failures caused by the deadline or an interruption of the whole collection do
+	// not stop it early, so the partial inventory is still returned
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// Create semaphore for parallel execution
-	semaphore := make(chan struct{}, opts.Parallel)
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+	semaphore := make(chan struct{}, parallel)
 
 	for _, item := range workItems {
 		wg.Add(1)
 		go func(item workItem) {
 			defer wg.Done()
 
-			// Acquire semaphore
+			// Acquire semaphore; work that never started is still reported
+			var result models.CollectorResult
 			select {
 			case semaphore <- struct{}{}:
 				defer func() { <-semaphore }()
+
+				// Execute collection, unless it was stopped while waiting
+				if ctx.Err() != nil {
+					result = models.CollectorResult{
+						Service: item.Service,
+						Region:  item.Region,
+						Error:   stoppedError(ctx, opts),
+					}
+				} else {
+					result = o.collectSingle(ctx, item, opts, resultCache)
+				}
 			case <-ctx.Done():
-				return
+				result = models.CollectorResult{
+					Service: item.Service,
+					Region:  item.Region,
+					Error:   stoppedError(ctx, opts),
+				}
 			}
 
-			// Execute collection
-			result := o.collectSingle(ctx, item, opts.Verbose)
-
 			// Add result
 			mu.Lock()
+			defer mu.Unlock()
 			results = append(results, result)
-			mu.Unlock()
 
 			// Handle fail-fast
-			if opts.FailFast && result.Error != nil {
-				// Cancel context to stop other goroutines
-				// Note: This is a simplified approach; in production you might want more sophisticated cancellation
+			if opts.FailFast && result.Error != nil && failure == nil && parent.Err() == nil {
+				failure = fmt.Errorf("failed to collect %s in %s: %w", item.Service, item.Region, result.Error)
+				cancel()
 			}
 		}(item)
 	}
 
 	wg.Wait()
-	return results
+	return results, failure
 }
 
-// collectSingle collects resources for a single service-region combination
-func (o *Orchestrator) collectSingle(ctx context.Context, item workItem, verbose bool) models.CollectorResult {
+// collectSingle collects resources for a single service-region combination, or
+// takes them from the cache. A collector still running when its timeout or the
+// overall deadline passes is awaited for up to collectorGracePeriod, so it does not
+// keep making API calls once its parallel slot is released.
+func (o *Orchestrator) collectSingle(ctx context.Context, item workItem, opts CollectOptions, resultCache *scanCache) models.CollectorResult {
 	collector := o.collectors[item.Service]
 
-	if verbose && stderr != nil {
-		if w, ok := stderr.(interface{ Write([]byte) (int, error) }); ok {
-			fmt.Fprintf(w, "Collecting %s resources in %s...\n", item.Service, item.Region)
+	if resultCache != nil && resultCache.read {
+		resources, warnings, storedAt, ok := resultCache.cache.Get(resultCache.account, resultCache.variant, item.Service, item.Region, resultCache.since)
+		if ok {
+			o.verbosef(opts, "Using cached %s resources in %s from %s\n", item.Service, item.Region, storedAt.Local().Format(time.Kitchen))
+			return models.CollectorResult{
+				Service:   item.Service,
+				Region:    item.Region,
+				Resources: resources,
+				Warnings:  warnings,
+				Cached:    true,
+			}
 		}
 	}
 
-	resources, err := collector.Collect(ctx, item.Region)
+	o.verbosef(opts, "Collecting %s resources in %s...\n", item.Service, item.Region)
+
+	itemCtx := ctx
+	if opts.ItemTimeout > 0 {
+		var cancel context.CancelFunc
+		itemCtx, cancel = context.WithTimeout(ctx, opts.ItemTimeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		resources []models.Resource
+		err       error
+	}
+	warnings := &models.Warnings{}
+	done := make(chan outcome, 1)
+	go func() {
+		collectCtx := models.WithWarnings(itemCtx, warnings)
+		resources, err := collector.Collect(collectCtx, item.Region)
+		// The lookups below are skipped once the context is done, as their results
+		// would be discarded
+		if err == nil && collectCtx.Err() == nil {
+			o.addTags(collectCtx, collector, item.Region, resources)
+		}
+		if err == nil && collectCtx.Err() == nil && o.settings.UsageMetrics {
+			collectors.AddUsageMetrics(collectCtx, o.clientManager, resources)
+		}
+		if err == nil && collectCtx.Err() == nil && o.settings.Rightsizing {
+			collectors.AddUtilizationMetrics(collectCtx, o.clientManager, resources)
+		}
+		done <- outcome{resources, err}
+	}()
+
+	result := models.CollectorResult{
+		Service: item.Service,
+		Region:  item.Region,
+	}
+	select {
+	case out := <-done:
+		result.Resources = out.resources
+		result.Error = out.err
+	case <-itemCtx.Done():
+		result.Error = itemCtx.Err()
+		grace := time.NewTimer(collectorGracePeriod)
+		select {
+		case <-done:
+		case <-grace.C:
+			o.verbosef(opts, "Warning: %s in %s still running %s after it was stopped\n", item.Service, item.Region, collectorGracePeriod)
+		}
+		grace.Stop()
+	}
 
-	return models.CollectorResult{
-		Service:   item.Service,
-		Region:    item.Region,
-		Resources: resources,
-		Error:     err,
+	// A failure after the context ended is down to the deadline, whatever error the
+	// interrupted API call returned
+	if result.Error != nil && itemCtx.Err() != nil {
+		result.Resources = nil
+		result.Error = stoppedError(ctx, opts)
+	}
+
+	result.Error = o.clientManager.ClassifyRegionError(ctx, item.Region, result.Error)
+	result.Warnings = warnings.Messages()
+
+	if resultCache != nil && result.Error == nil {
+		err := resultCache.cache.Put(resultCache.account, resultCache.variant, item.Service, item.Region, result.Resources, result.Warnings)
+		if err != nil {
+			o.verbosef(opts, "Warning: failed to cache %s resources in %s: %v\n", item.Service, item.Region, err)
+		}
+	}
+	return result
+}
+
+// logAPICalls logs the calls made to each API in each region in verbose mode, to help
+// tune --parallel and rate limits
+func (o *Orchestrator) logAPICalls(opts CollectOptions, calls []models.APICalls) {
+	total := 0
+	for _, c := range calls {
+		total += c.Calls
+	}
+	o.verbosef(opts, "API calls: %d\n", total)
+	for _, c := range calls {
+		o.verbosef(opts, "  %-16s %-16s %6d calls %4d failed %10s total %8s average\n", c.API, c.Region, c.Calls, c.Errors,
+			c.Duration.Round(time.Millisecond), (c.Duration / time.Duration(c.Calls)).Round(time.Millisecond))
+	}
+}
+
+// verbosef logs progress to the logger of the settings, or else stderr, in verbose
+// mode
+func (o *Orchestrator) verbosef(opts CollectOptions, format string, args ...interface{}) {
+	if opts.Verbose && o.settings.Logger != nil {
+		fmt.Fprintf(o.settings.Logger, format, args...)
+		return
+	}
+	if opts.Verbose && stderr != nil {
+		if w, ok := stderr.(interface{ Write([]byte) (int, error) }); ok {
+			fmt.Fprintf(w, format, args...)
+		}
+	}
+}
+
+// stoppedError explains why work on an item stopped early, given the context of the
+// whole collection: the item's own timeout, the overall deadline or cancellation
+func stoppedError(ctx context.Context, opts CollectOptions) error {
+	switch {
+	case ctx.Err() == nil:
+		return fmt.Errorf("%w after %s", models.ErrTimeout, opts.ItemTimeout)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) && opts.Timeout > 0:
+		return fmt.Errorf("%w: overall deadline of %s reached", models.ErrTimeout, opts.Timeout)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%w: deadline reached", models.ErrTimeout)
+	default:
+		return fmt.Errorf("%w before collection finished", models.ErrInterrupted)
 	}
 }
 
 // aggregateResults aggregates all collection results into a ResourceCollection
 func (o *Orchestrator) aggregateResults(results []models.CollectorResult, startTime time.Time) *models.ResourceCollection {
 	var allResources []models.Resource
-	var errors []string
+	var errorMessages []string
+	var warnings []string
+	var skipped []string
 	summary := models.Summary{
-		ByService: make(map[string]int),
-		ByRegion:  make(map[string]int),
-		ByState:   make(map[string]int),
-		Duration:  time.Since(startTime),
+		ByService:     make(map[string]int),
+		ByRegion:      make(map[string]int),
+		ByState:       make(map[string]int),
+		ErrorsByClass: make(map[string]int),
+		Duration:      time.Since(startTime),
 	}
 
 	// Track unique regions and services
@@ -237,22 +776,38 @@ func (o *Orchestrator) aggregateResults(results []models.CollectorResult, startT
 	serviceSet := make(map[string]bool)
 
 	for _, result := range results {
-		if result.Error != nil {
+		for _, warning := range result.Warnings {
+			warnings = append(warnings, fmt.Sprintf("%s/%s: %s", result.Service, result.Region, warning))
+		}
+
+		if errors.Is(result.Error, models.ErrRegionDisabled) {
+			// Not an inventory gap: there is nothing to collect in a region the account
+			// or service cannot use
+			skipped = append(skipped, fmt.Sprintf("%s/%s: %v", result.Service, result.Region, result.Error))
+		} else if result.Error != nil {
 			errorMsg := fmt.Sprintf("%s/%s: %v", result.Service, result.Region, result.Error)
-			errors = append(errors, errorMsg)
+			errorMessages = append(errorMessages, errorMsg)
 			summary.Errors++
+			summary.ErrorsByClass[models.ErrorClass(result.Error)]++
+			if errors.Is(result.Error, models.ErrTimeout) || errors.Is(result.Error, models.ErrInterrupted) {
+				summary.Partial = true
+			}
 		} else {
 			allResources = append(allResources, result.Resources...)
+			if result.Cached {
+				summary.Cached++
+			}
 			
 			// Update summary
 			summary.ByService[result.Service] += len(result.Resources)
-			summary.ByRegion[result.Region] += len(result.Resources)
 			
 			regionSet[result.Region] = true
 			serviceSet[result.Service] = true
 
-			// Count by state
+			// Count by region and state. Resources are counted in their own region, as
+			// S3 buckets are listed once for the account but each lives in a region.
 			for _, resource := range result.Resources {
+				summary.ByRegion[resource.Region]++
 				if resource.State != "" {
 					summary.ByState[resource.State]++
 				}
@@ -269,10 +824,15 @@ func (o *Orchestrator) aggregateResults(results []models.CollectorResult, startT
 	}
 
 	summary.TotalResources = len(allResources)
+	summary.ByAge = models.AgeByService(allResources, time.Now())
+	summary.Warnings = len(warnings)
+	summary.Skipped = len(skipped)
 
 	return &models.ResourceCollection{
 		Resources: allResources,
-		Errors:    errors,
+		Errors:    errorMessages,
+		Warnings:  warnings,
+		Skipped:   skipped,
 		Summary:   summary,
 	}
 }
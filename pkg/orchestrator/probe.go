@@ -0,0 +1,121 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/collectors"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// ProbeResult is the outcome of running one collector in a single region before a
+// full scan
+type ProbeResult struct {
+	Service string
+
+	// Region is the region probed
+	Region string
+
+	// Regions is the number of regions a full scan collects this service from
+	Regions int
+
+	// Resources is the number of resources found in the probed region
+	Resources int
+
+	Duration time.Duration
+	Error    error
+	Warnings []string
+
+	// AccessDenied is set when the error is a permissions error, and MissingActions
+	// lists the actions the error names or, failing that, the actions the collector uses
+	AccessDenied   bool
+	MissingActions []string
+}
+
+// deniedActionPattern finds the action in messages such as "... is not authorized to
+// perform: ec2:DescribeInstances on resource ..."
+var deniedActionPattern = regexp.MustCompile(`perform: ([A-Za-z0-9-]+:[A-Za-z0-9]+)`)
+
+// Probe runs each selected collector in one region, to check permissions and to
+// measure how large a full scan would be. It returns the results in service order and
+// the regions a full scan would cover.
+func (o *Orchestrator) Probe(ctx context.Context, opts CollectOptions, probeRegion string) ([]ProbeResult, []string, error) {
+	services, err := o.prepareServices(opts.Services)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	regions, err := o.prepareRegions(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+	semaphore := make(chan struct{}, parallel)
+
+	results := make([]ProbeResult, len(services))
+	var wg sync.WaitGroup
+	for i, service := range services {
+		wg.Add(1)
+		go func(i int, service string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = o.probeService(ctx, service, probeRegion, regions, opts.Timeout)
+		}(i, service)
+	}
+	wg.Wait()
+
+	return results, regions, nil
+}
+
+// probeService runs one collector in the probe region, or once for global
+// collectors and in their own region for collectors that only run in fixed regions
+func (o *Orchestrator) probeService(ctx context.Context, service, probeRegion string, regions []string, timeout time.Duration) ProbeResult {
+	collector := o.collectors[service]
+
+	result := ProbeResult{
+		Service: service,
+		Region:  probeRegion,
+		Regions: len(regions),
+	}
+	if collector.Scope() == models.ScopeGlobal {
+		result.Region = models.GlobalRegion
+		result.Regions = 1
+	} else if fixed := collector.Regions(); len(fixed) > 0 {
+		result.Region = fixed[0]
+		result.Regions = len(fixed)
+	}
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	warnings := &models.Warnings{}
+	start := time.Now()
+	resources, err := collector.Collect(models.WithWarnings(ctx, warnings), result.Region)
+	result.Duration = time.Since(start)
+	result.Resources = len(resources)
+	result.Error = o.clientManager.ClassifyRegionError(ctx, result.Region, err)
+	result.Warnings = warnings.Messages()
+
+	if errors.Is(result.Error, models.ErrAccessDenied) {
+		result.AccessDenied = true
+		if match := deniedActionPattern.FindStringSubmatch(err.Error()); match != nil {
+			result.MissingActions = []string{match[1]}
+		} else {
+			result.MissingActions = collectors.Permissions[service]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,52 @@
+package orchestrator
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// CollectorFactory creates the collector of a service for an orchestrator. It is
+// also called with a nil client manager when only the service names are needed, so
+// it should not make API calls.
+type CollectorFactory func(clientManager *awspkg.ClientManager, settings Settings) models.Collector
+
+// serviceNamePattern is what service names look like. They appear in filters and in
+// "service/region" error messages, so they cannot contain separators.
+var serviceNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// registry holds the collectors added with RegisterCollector
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]CollectorFactory)
+)
+
+// RegisterCollector adds a collector for a service the project does not ship to the
+// orchestrators created afterwards, usually from an init function. Collectors that
+// implement models.PermissionLister have their IAM actions included in the policy
+// iam-policy prints. It panics if the name is invalid or taken, as registering is a
+// programming error rather than a runtime failure.
+func RegisterCollector(name string, factory CollectorFactory) {
+	if !serviceNamePattern.MatchString(name) {
+		panic(fmt.Sprintf("orchestrator: invalid collector name %q", name))
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("orchestrator: nil factory for collector %q", name))
+	}
+
+	builtin := &Orchestrator{collectors: make(map[string]models.Collector)}
+	builtin.registerBuiltinCollectors()
+	if _, exists := builtin.collectors[name]; exists {
+		panic(fmt.Sprintf("orchestrator: collector %q is built in", name))
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("orchestrator: collector %q registered twice", name))
+	}
+	registry[name] = factory
+}
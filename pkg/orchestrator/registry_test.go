@@ -0,0 +1,115 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// testCollector is a collector registered by the tests
+type testCollector struct {
+	settings Settings
+}
+
+func (c *testCollector) Name() string { return "test-inventory" }
+
+func (c *testCollector) Collect(ctx context.Context, region string) ([]models.Resource, error) {
+	return nil, nil
+}
+
+func (c *testCollector) Regions() []string { return nil }
+
+func (c *testCollector) Scope() models.Scope { return models.ScopeGlobal }
+
+func (c *testCollector) Permissions() []string { return []string{"inventory:ListItems"} }
+
+func newTestCollector(clientManager *awspkg.ClientManager, settings Settings) models.Collector {
+	return &testCollector{settings: settings}
+}
+
+// unregister removes a collector added by a test, so the tests can run repeatedly
+func unregister(t *testing.T, name string) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(registry, name)
+	})
+}
+
+// registerPanic returns the panic message of RegisterCollector, or "" if it did not
+// panic
+func registerPanic(name string, factory CollectorFactory) (message string) {
+	defer func() {
+		if r := recover(); r != nil {
+			message, _ = r.(string)
+		}
+	}()
+	RegisterCollector(name, factory)
+	return ""
+}
+
+func TestRegisterCollector(t *testing.T) {
+	unregister(t, "test-inventory")
+	RegisterCollector("test-inventory", newTestCollector)
+
+	o := NewOrchestratorWithSettings(nil, Settings{QuotaThreshold: 50})
+	found := false
+	for _, service := range o.GetAvailableServices() {
+		found = found || service == "test-inventory"
+	}
+	if !found {
+		t.Fatalf("GetAvailableServices() = %v, want it to include test-inventory", o.GetAvailableServices())
+	}
+
+	collector, ok := o.collectors["test-inventory"].(*testCollector)
+	if !ok {
+		t.Fatalf("collector = %T, want *testCollector", o.collectors["test-inventory"])
+	}
+	if collector.settings.QuotaThreshold != 50 {
+		t.Errorf("factory settings QuotaThreshold = %v, want 50", collector.settings.QuotaThreshold)
+	}
+
+	actions, err := o.RequiredPermissions([]string{"test-inventory"})
+	if err != nil {
+		t.Fatalf("RequiredPermissions failed: %v", err)
+	}
+	found = false
+	for _, action := range actions {
+		found = found || action == "inventory:ListItems"
+	}
+	if !found {
+		t.Errorf("RequiredPermissions() = %v, want it to include inventory:ListItems", actions)
+	}
+}
+
+func TestRegisterCollector_Panics(t *testing.T) {
+	unregister(t, "test-duplicate")
+	RegisterCollector("test-duplicate", newTestCollector)
+
+	tests := []struct {
+		name         string
+		service      string
+		factory      CollectorFactory
+		wantContains string
+	}{
+		{"empty name", "", newTestCollector, "invalid collector name"},
+		{"upper case name", "Inventory", newTestCollector, "invalid collector name"},
+		{"name with a separator", "test/inventory", newTestCollector, "invalid collector name"},
+		{"name starting with a digit", "1inventory", newTestCollector, "invalid collector name"},
+		{"nil factory", "test-nil", nil, "nil factory"},
+		{"built-in name", "ec2", newTestCollector, "is built in"},
+		{"duplicate name", "test-duplicate", newTestCollector, "registered twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := registerPanic(tt.service, tt.factory)
+			if !strings.Contains(message, tt.wantContains) {
+				t.Errorf("RegisterCollector(%q) panic = %q, want it to contain %q", tt.service, message, tt.wantContains)
+			}
+		})
+	}
+}
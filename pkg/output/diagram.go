@@ -0,0 +1,324 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/xiaochen/awsinv/pkg/graph"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// DiagramFormatter formats the relationships between resources as a Graphviz DOT or
+// Mermaid diagram, with one group per region
+type DiagramFormatter struct {
+	writer  io.Writer
+	syntax  string
+	options Options
+}
+
+// NewDOTFormatter creates a new Graphviz DOT diagram formatter writing to a file
+func NewDOTFormatter(writer *os.File) *DiagramFormatter {
+	return NewDOTFormatterWithWriter(writer)
+}
+
+// NewDOTFormatterWithWriter creates a new Graphviz DOT diagram formatter writing to any io.Writer
+func NewDOTFormatterWithWriter(writer io.Writer) *DiagramFormatter {
+	return NewDOTFormatterWithOptions(writer, Options{})
+}
+
+// NewDOTFormatterWithOptions creates a new Graphviz DOT diagram formatter with the
+// given report settings, of which only the cost engine applies, to cost filters
+func NewDOTFormatterWithOptions(writer io.Writer, options Options) *DiagramFormatter {
+	return &DiagramFormatter{writer: writer, syntax: "dot", options: options}
+}
+
+// NewMermaidFormatter creates a new Mermaid diagram formatter writing to a file
+func NewMermaidFormatter(writer *os.File) *DiagramFormatter {
+	return NewMermaidFormatterWithWriter(writer)
+}
+
+// NewMermaidFormatterWithWriter creates a new Mermaid diagram formatter writing to any io.Writer
+func NewMermaidFormatterWithWriter(writer io.Writer) *DiagramFormatter {
+	return NewMermaidFormatterWithOptions(writer, Options{})
+}
+
+// NewMermaidFormatterWithOptions creates a new Mermaid diagram formatter with the
+// given report settings, of which only the cost engine applies, to cost filters
+func NewMermaidFormatterWithOptions(writer io.Writer, options Options) *DiagramFormatter {
+	return &DiagramFormatter{writer: writer, syntax: "mermaid", options: options}
+}
+
+// diagramNode is a resource, or a resource only known by reference, in the diagram
+type diagramNode struct {
+	id     string
+	label  string
+	region string
+}
+
+// diagramEdge is a relationship between two nodes
+type diagramEdge struct {
+	from  *diagramNode
+	to    *diagramNode
+	label string
+}
+
+// diagram holds the nodes and edges built from a set of resources
+type diagram struct {
+	nodes []*diagramNode
+	edges []diagramEdge
+	byKey map[string]*diagramNode
+	byARN map[string]models.Resource
+}
+
+// Format formats the collection as a relationship diagram
+func (f *DiagramFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
+	// Apply filters and sort
+	resources, _ := prepareResources(collection, filters, sortField, f.options)
+
+	d := buildDiagram(resources)
+
+	if f.syntax == "mermaid" {
+		_, err := fmt.Fprint(f.writer, d.mermaid())
+		return err
+	}
+	_, err := fmt.Fprint(f.writer, d.dot())
+	return err
+}
+
+// buildDiagram finds the relationships the collectors record in extra fields:
+// ECS services and tasks to their cluster, EC2 instances, RDS instances and Lambda
+// functions to their VPC, Lambda event sources to their function, and EventBridge
+// rules and schedules to their targets, and the links of the relationship graph
+func buildDiagram(resources []models.Resource) *diagram {
+	d := &diagram{
+		byKey: make(map[string]*diagramNode),
+		byARN: make(map[string]models.Resource),
+	}
+
+	for _, resource := range resources {
+		if arn := resourceARN(resource); arn != "" {
+			d.byARN[arn] = resource
+		}
+	}
+
+	for _, resource := range resources {
+		extra := resource.Extra
+
+		switch {
+		case resource.Service == "ecs" && (resource.Type == "service" || resource.Type == "task"):
+			if clusterARN, ok := extra["clusterArn"].(string); ok && clusterARN != "" {
+				d.addEdge(d.resourceNode(resource), d.arnNode(clusterARN, resource.Region), "runs in")
+			}
+		case resource.Service == "lambda" && resource.Type == "event-source-mapping":
+			sourceARN, sourceOk := extra["eventSourceArn"].(string)
+			functionARN, functionOk := extra["functionArn"].(string)
+			if sourceOk && functionOk && sourceARN != "" {
+				d.addEdge(d.arnNode(sourceARN, resource.Region), d.arnNode(functionARN, resource.Region), resource.Class)
+			}
+		case resource.Service == "eventbridge" && resource.Type == "rule":
+			if targets, ok := extra["targetArns"].([]string); ok {
+				for _, target := range targets {
+					d.addEdge(d.resourceNode(resource), d.arnNode(target, resource.Region), "target")
+				}
+			}
+		case resource.Service == "eventbridge" && resource.Type == "schedule":
+			if target, ok := extra["targetArn"].(string); ok && target != "" {
+				d.addEdge(d.resourceNode(resource), d.arnNode(target, resource.Region), "target")
+			}
+		}
+
+		if vpcID, ok := extra["vpcId"].(string); ok && vpcID != "" {
+			d.addEdge(d.resourceNode(resource), d.node("vpc|"+resource.Region+"|"+vpcID, "vpc\n"+vpcID, resource.Region), "in")
+		}
+	}
+
+	// Volumes, network interfaces, snapshots, target groups and Elastic IPs link to
+	// their parents through the relationship graph
+	relationships := graph.Build(resources)
+	for _, link := range relationships.Links() {
+		var parent *diagramNode
+		if resource, exists := relationships.Resource(link.Parent); exists {
+			parent = d.resourceNode(resource)
+		} else {
+			parent = d.node("ref|"+link.Parent.Region+"|"+link.Parent.Service+"|"+link.Parent.ID,
+				link.Parent.Service+"\n"+link.Parent.ID, link.Parent.Region)
+		}
+		d.addEdge(d.resourceNode(link.Child), parent, link.Relation)
+	}
+
+	return d
+}
+
+// resourceARN returns the ARN that identifies a resource itself, for resolving
+// references from other resources
+func resourceARN(resource models.Resource) string {
+	var key string
+	switch resource.Service {
+	case "lambda":
+		if resource.Type != "event-source-mapping" && resource.Type != "layer" {
+			key = "functionArn"
+		}
+	case "ecs":
+		switch resource.Type {
+		case "cluster":
+			key = "clusterArn"
+		case "service":
+			key = "serviceArn"
+		}
+	case "sfn":
+		key = "stateMachineArn"
+	case "dynamodb":
+		key = "tableArn"
+	case "eventbridge":
+		switch resource.Type {
+		case "rule":
+			key = "ruleArn"
+		case "event-bus":
+			key = "eventBusArn"
+		}
+	}
+
+	arn, _ := resource.Extra[key].(string)
+	return arn
+}
+
+// resourceNode returns the node of a collected resource
+func (d *diagram) resourceNode(resource models.Resource) *diagramNode {
+	key := strings.Join([]string{resource.Service, resource.Region, resource.Type, resource.ID}, "|")
+	label := resource.Service
+	if resource.Type != "" {
+		label += " " + resource.Type
+	}
+	return d.node(key, label+"\n"+resource.Name, resource.Region)
+}
+
+// arnNode returns the node of the resource an ARN refers to. DynamoDB stream ARNs
+// resolve to their table; unknown ARNs get a node labelled from the ARN itself.
+func (d *diagram) arnNode(arn, region string) *diagramNode {
+	if index := strings.Index(arn, "/stream/"); index > 0 && strings.HasPrefix(arn, "arn:aws:dynamodb:") {
+		if table, exists := d.byARN[arn[:index]]; exists {
+			return d.resourceNode(table)
+		}
+	}
+	if resource, exists := d.byARN[arn]; exists {
+		return d.resourceNode(resource)
+	}
+	return d.node("arn|"+region+"|"+arn, arnLabel(arn), region)
+}
+
+// node returns the node for key, creating it on first use
+func (d *diagram) node(key, label, region string) *diagramNode {
+	if existing, exists := d.byKey[key]; exists {
+		return existing
+	}
+	n := &diagramNode{
+		id:     fmt.Sprintf("n%d", len(d.nodes)+1),
+		label:  label,
+		region: region,
+	}
+	d.byKey[key] = n
+	d.nodes = append(d.nodes, n)
+	return n
+}
+
+// addEdge adds a relationship between two nodes
+func (d *diagram) addEdge(from, to *diagramNode, label string) {
+	d.edges = append(d.edges, diagramEdge{from: from, to: to, label: label})
+}
+
+// regions returns the nodes grouped by region, in region name order
+func (d *diagram) regions() ([]string, map[string][]*diagramNode) {
+	byRegion := make(map[string][]*diagramNode)
+	for _, n := range d.nodes {
+		byRegion[n.region] = append(byRegion[n.region], n)
+	}
+
+	regions := make([]string, 0, len(byRegion))
+	for region := range byRegion {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	return regions, byRegion
+}
+
+// dot renders the diagram in Graphviz DOT syntax
+func (d *diagram) dot() string {
+	var b strings.Builder
+	b.WriteString("digraph inventory {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=rounded];\n")
+
+	regions, byRegion := d.regions()
+	for i, region := range regions {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(region))
+		for _, n := range byRegion[region] {
+			fmt.Fprintf(&b, "    %s [label=%s];\n", n.id, dotQuote(n.label))
+		}
+		b.WriteString("  }\n")
+	}
+
+	for _, e := range d.edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", e.from.id, e.to.id, dotQuote(e.label))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaid renders the diagram as a Mermaid flowchart
+func (d *diagram) mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	regions, byRegion := d.regions()
+	for i, region := range regions {
+		fmt.Fprintf(&b, "  subgraph region%d[%s]\n", i, mermaidQuote(region))
+		for _, n := range byRegion[region] {
+			fmt.Fprintf(&b, "    %s[%s]\n", n.id, mermaidQuote(n.label))
+		}
+		b.WriteString("  end\n")
+	}
+
+	for _, e := range d.edges {
+		if e.label == "" {
+			fmt.Fprintf(&b, "  %s --> %s\n", e.from.id, e.to.id)
+			continue
+		}
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", e.from.id, mermaidQuote(e.label), e.to.id)
+	}
+
+	return b.String()
+}
+
+// arnLabel builds a node label from an ARN: the service and the last part of the resource
+func arnLabel(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) < 6 {
+		return arn
+	}
+	resource := parts[5]
+	if index := strings.LastIndexAny(resource, "/:"); index >= 0 {
+		resource = resource[index+1:]
+	}
+	return parts[2] + "\n" + resource
+}
+
+// dotQuote quotes a label for DOT, turning newlines into line breaks
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// mermaidQuote quotes a label for Mermaid, turning newlines into line breaks
+func mermaidQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "\n", "<br/>")
+	return `"` + s + `"`
+}
@@ -0,0 +1,686 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// Filter is a parsed filter expression such as
+//
+//	service=ec2 AND (state!=running OR cost>100) AND NOT has(tag:Owner)
+//
+// A plain key=value filter is still a valid expression, so existing filters keep working.
+type Filter struct {
+	Expression string
+	expr       filterExpr
+}
+
+// Matches reports whether a resource matches the filter. Costs are estimated with the
+// built-in prices.
+func (f Filter) Matches(resource models.Resource) bool {
+	if f.expr == nil {
+		return true
+	}
+	return f.expr.match(&filterContext{resource: resource})
+}
+
+// matches evaluates the filter using precomputed cost estimates
+func (f Filter) matches(resource models.Resource, costEstimates map[string]*models.CostEstimate) bool {
+	if f.expr == nil {
+		return true
+	}
+	return f.expr.match(&filterContext{resource: resource, costEstimates: costEstimates})
+}
+
+// ParseFilters parses filter expressions. Resources must match all of them.
+func ParseFilters(filterStrings []string) ([]Filter, error) {
+	var filters []Filter
+
+	for _, filterStr := range filterStrings {
+		filter, err := ParseFilter(filterStr)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// ParseExcludes parses filter expressions that exclude the resources they match
+func ParseExcludes(excludeStrings []string) ([]Filter, error) {
+	filters, err := ParseFilters(excludeStrings)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, filter := range filters {
+		filters[i] = Filter{
+			Expression: "NOT (" + filter.Expression + ")",
+			expr:       notExpr{expr: filter.expr},
+		}
+	}
+
+	return filters, nil
+}
+
+// ParseFilter parses a single filter expression
+func ParseFilter(expression string) (Filter, error) {
+	p := &filterParser{input: expression}
+
+	expr, err := p.parseOr()
+	if err == nil {
+		p.skipSpace()
+		if !p.done() {
+			err = p.errorf("unexpected %q", p.input[p.pos:])
+		}
+	}
+	if err != nil {
+		return Filter{}, fmt.Errorf("invalid filter %q: %w", expression, err)
+	}
+
+	return Filter{Expression: expression, expr: expr}, nil
+}
+
+// applyFilters applies filters to resources
+func applyFilters(resources []models.Resource, filters []Filter, costEstimates map[string]*models.CostEstimate) []models.Resource {
+	if len(filters) == 0 {
+		return resources
+	}
+
+	var filtered []models.Resource
+
+	for _, resource := range resources {
+		if matchesFilters(resource, filters, costEstimates) {
+			filtered = append(filtered, resource)
+		}
+	}
+
+	return filtered
+}
+
+// matchesFilters checks if a resource matches all filters
+func matchesFilters(resource models.Resource, filters []Filter, costEstimates map[string]*models.CostEstimate) bool {
+	for _, filter := range filters {
+		if !filter.matches(resource, costEstimates) {
+			return false
+		}
+	}
+	return true
+}
+
+// timeNow returns the time created and age filters compare with; tests fix it
+var timeNow = time.Now
+
+// resourceSizeFields lists the extra fields holding a resource's storage size in GB
+var resourceSizeFields = []string{"allocatedStorage", "storageSizeGB", "storageCapacityGB", "snapshotSizeGB"}
+
+// resourceSizeByteFields lists the extra fields holding a resource's storage size in bytes
+var resourceSizeByteFields = []string{"tableSizeBytes", "sizeBytes"}
+
+// filterContext evaluates fields of one resource. Without precomputed cost
+// estimates, the cost of the resource is computed at most once.
+type filterContext struct {
+	resource      models.Resource
+	costEstimates map[string]*models.CostEstimate
+	cost          *float64
+}
+
+// field returns the string value of a field and whether the resource has it.
+// Unknown names are looked up as tags, like the original key=value filters.
+func (c *filterContext) field(name string) (string, bool) {
+	switch name {
+	case "service":
+		return c.resource.Service, true
+	case "region":
+		return c.resource.Region, true
+	case "account":
+		return c.resource.Account, c.resource.Account != ""
+	case "id":
+		return c.resource.ID, true
+	case "name":
+		return c.resource.Name, true
+	case "type":
+		return c.resource.Type, true
+	case "state":
+		return c.resource.State, true
+	case "class":
+		return c.resource.Class, true
+	case "created":
+		if c.resource.CreatedAt == nil {
+			return "", false
+		}
+		return c.resource.CreatedAt.UTC().Format(time.RFC3339), true
+	case "cost", "size":
+		number, ok := c.number(name)
+		if !ok {
+			return "", false
+		}
+		return strconv.FormatFloat(number, 'f', -1, 64), true
+	}
+
+	if key, ok := strings.CutPrefix(name, "extra."); ok {
+		value, exists := c.resource.Extra[key]
+		if !exists || value == nil {
+			return "", false
+		}
+		return fmt.Sprint(value), true
+	}
+
+	// Normalized tags, such as Owner, resolve through their aliases
+	return c.resource.TagValue(strings.TrimPrefix(name, "tag:"))
+}
+
+// number returns the numeric value of a field: the monthly cost estimate, the storage
+// size in GB, a numeric extra field, or any other field that parses as a number
+func (c *filterContext) number(name string) (float64, bool) {
+	switch name {
+	case "cost":
+		if c.cost == nil {
+			var cost float64
+			if c.costEstimates != nil {
+				cost = resourceCost(c.resource, c.costEstimates)
+			} else if estimate := builtInCostEngine.Estimate(context.Background(), c.resource); estimate != nil {
+				cost = estimate.Amount
+			}
+			c.cost = &cost
+		}
+		return *c.cost, true
+	case "size":
+		for _, key := range resourceSizeFields {
+			if size, ok := models.ExtraNumber(c.resource.Extra, key); ok {
+				return size, true
+			}
+		}
+		for _, key := range resourceSizeByteFields {
+			if size, ok := models.ExtraNumber(c.resource.Extra, key); ok {
+				return size / (1024 * 1024 * 1024), true
+			}
+		}
+		return 0, false
+	}
+
+	if key, ok := strings.CutPrefix(name, "extra."); ok {
+		if number, ok := models.ExtraNumber(c.resource.Extra, key); ok {
+			return number, true
+		}
+	}
+
+	value, exists := c.field(name)
+	if !exists {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	return number, err == nil
+}
+
+// filterExpr is a node of a parsed filter expression
+type filterExpr interface {
+	match(c *filterContext) bool
+}
+
+// andExpr matches when both sides match
+type andExpr struct {
+	left, right filterExpr
+}
+
+func (e andExpr) match(c *filterContext) bool {
+	return e.left.match(c) && e.right.match(c)
+}
+
+// orExpr matches when either side matches
+type orExpr struct {
+	left, right filterExpr
+}
+
+func (e orExpr) match(c *filterContext) bool {
+	return e.left.match(c) || e.right.match(c)
+}
+
+// notExpr inverts an expression
+type notExpr struct {
+	expr filterExpr
+}
+
+func (e notExpr) match(c *filterContext) bool {
+	return !e.expr.match(c)
+}
+
+// hasExpr matches resources that have a non-empty value for a field, e.g. has(tag:Owner)
+type hasExpr struct {
+	field string
+}
+
+func (e hasExpr) match(c *filterContext) bool {
+	value, exists := c.field(e.field)
+	return exists && value != ""
+}
+
+// compareExpr compares a field with a value
+type compareExpr struct {
+	field    string
+	op       string
+	value    string
+	number   float64
+	isNumber bool
+	pattern  *regexp.Regexp
+	when     *filterTime
+}
+
+func (e compareExpr) match(c *filterContext) bool {
+	if e.when != nil {
+		return e.matchTime(c)
+	}
+
+	switch e.op {
+	case "=", "==":
+		return e.equal(c)
+	case "!=":
+		return !e.equal(c)
+	case "~=":
+		value, exists := c.field(e.field)
+		return exists && e.pattern.MatchString(value)
+	}
+
+	number, ok := c.number(e.field)
+	if !ok {
+		return false
+	}
+	switch e.op {
+	case "<":
+		return number < e.number
+	case "<=":
+		return number <= e.number
+	case ">":
+		return number > e.number
+	case ">=":
+		return number >= e.number
+	}
+	return false
+}
+
+// equal compares numerically when both sides are numbers, otherwise case-insensitively.
+// A trailing * on the value matches any field containing the rest of it.
+func (e compareExpr) equal(c *filterContext) bool {
+	if e.isNumber {
+		if number, ok := c.number(e.field); ok {
+			return number == e.number
+		}
+	}
+
+	value, exists := c.field(e.field)
+	if !exists {
+		return false
+	}
+
+	if prefix, ok := strings.CutSuffix(e.value, "*"); ok {
+		return strings.Contains(strings.ToLower(value), strings.ToLower(prefix))
+	}
+	return strings.EqualFold(value, e.value)
+}
+
+// matchTime compares the creation time (created) or age (age) of a resource.
+// Resources without a creation time only match !=.
+func (e compareExpr) matchTime(c *filterContext) bool {
+	if c.resource.CreatedAt == nil {
+		return e.op == "!="
+	}
+
+	now := timeNow()
+	var cmp int
+	if e.field == "age" {
+		// Older resources have larger ages
+		cmp = compareDurations(now.Sub(*c.resource.CreatedAt), e.when.ago)
+	} else if e.when.dateOnly {
+		cmp = strings.Compare(c.resource.CreatedAt.UTC().Format(time.DateOnly), e.when.at.Format(time.DateOnly))
+	} else {
+		cmp = c.resource.CreatedAt.Compare(e.when.resolve(now))
+	}
+
+	switch e.op {
+	case "=", "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func compareDurations(a, b time.Duration) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// filterTime is a point in time in a created or age filter: either a date or
+// timestamp, or a duration before now such as 30d
+type filterTime struct {
+	at       time.Time
+	ago      time.Duration
+	dateOnly bool
+}
+
+// resolve returns the point in time, resolving durations against now
+func (t filterTime) resolve(now time.Time) time.Time {
+	if t.at.IsZero() {
+		return now.Add(-t.ago)
+	}
+	return t.at
+}
+
+// filterDurationUnits maps the day-based duration suffixes time.ParseDuration lacks
+var filterDurationUnits = map[string]time.Duration{
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+	"y": 365 * 24 * time.Hour,
+}
+
+// parseFilterTime parses a date (2024-01-01), an RFC 3339 timestamp or a duration
+// (30d, 2w, 1y, 12h). Ages only accept durations.
+func parseFilterTime(field, value string) (*filterTime, error) {
+	if field == "created" {
+		if at, err := time.Parse(time.DateOnly, value); err == nil {
+			return &filterTime{at: at, dateOnly: true}, nil
+		}
+		if at, err := time.Parse(time.RFC3339, value); err == nil {
+			return &filterTime{at: at}, nil
+		}
+	}
+
+	for suffix, unit := range filterDurationUnits {
+		if number, ok := strings.CutSuffix(value, suffix); ok {
+			if n, err := strconv.ParseFloat(number, 64); err == nil && n >= 0 {
+				return &filterTime{ago: time.Duration(n * float64(unit))}, nil
+			}
+		}
+	}
+	if ago, err := time.ParseDuration(value); err == nil && ago >= 0 {
+		return &filterTime{ago: ago}, nil
+	}
+
+	if field == "age" {
+		return nil, fmt.Errorf("invalid age %q (expected a duration such as 30d, 2w or 12h)", value)
+	}
+	return nil, fmt.Errorf("invalid time %q (expected a date such as 2024-01-01, an RFC 3339 timestamp or a duration such as 30d)", value)
+}
+
+// filterOperators lists the comparison operators, longest first so "<=" is not read as "<"
+var filterOperators = []string{"==", "!=", "~=", "<=", ">=", "=", "<", ">"}
+
+// filterParser is a recursive descent parser for filter expressions:
+//
+//	or         = and { ("OR" | "||") and }
+//	and        = unary { ("AND" | "&&") unary }
+//	unary      = ("NOT" | "!") unary | "(" or ")" | "has(" field ")" | comparison
+//	comparison = field operator value
+//
+// Values are bare words or quoted strings; quote values containing spaces.
+type filterParser struct {
+	input string
+	pos   int
+}
+
+func (p *filterParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consumeSymbol("||") || p.consumeKeyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.consumeSymbol("&&") || p.consumeKeyword("AND") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *filterParser) parseUnary() (filterExpr, error) {
+	p.skipSpace()
+
+	if p.consumeKeyword("NOT") || (!strings.HasPrefix(p.input[p.pos:], "!=") && p.consumeSymbol("!")) {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{expr: expr}, nil
+	}
+
+	if p.consumeSymbol("(") {
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consumeSymbol(")") {
+			return nil, p.errorf("missing )")
+		}
+		return expr, nil
+	}
+
+	if p.consumeFunction("has") {
+		field, err := p.parseWord()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consumeSymbol(")") {
+			return nil, p.errorf("missing ) after has(%s", field)
+		}
+		return hasExpr{field: field}, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterExpr, error) {
+	field, err := p.parseWord()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	op := ""
+	for _, candidate := range filterOperators {
+		if strings.HasPrefix(p.input[p.pos:], candidate) {
+			op = candidate
+			p.pos += len(candidate)
+			break
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("expected an operator after %q (=, !=, ~=, <, <=, >, >=)", field)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+
+	expr := compareExpr{field: field, op: op, value: value}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		expr.number = number
+		expr.isNumber = true
+	}
+
+	if (field == "created" || field == "age") && op != "~=" {
+		expr.when, err = parseFilterTime(field, value)
+		if err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+
+	switch op {
+	case "~=":
+		expr.pattern, err = regexp.Compile(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", value, err)
+		}
+	case "<", "<=", ">", ">=":
+		if !expr.isNumber {
+			return nil, p.errorf("%s needs a number, got %q", op, value)
+		}
+	}
+
+	return expr, nil
+}
+
+// parseWord reads a field name: a quoted string or a run of characters up to
+// whitespace, a parenthesis or an operator
+func (p *filterParser) parseWord() (string, error) {
+	p.skipSpace()
+	if p.done() {
+		return "", p.errorf("unexpected end of expression")
+	}
+	if p.input[p.pos] == '"' || p.input[p.pos] == '\'' {
+		return p.parseQuoted()
+	}
+
+	start := p.pos
+	for !p.done() && !strings.ContainsRune(" \t()=!<>~&|\"'", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	if p.pos == start {
+		return "", p.errorf("expected a field name, got %q", p.input[p.pos:])
+	}
+	return p.input[start:p.pos], nil
+}
+
+// parseValue reads a comparison value: a quoted string or a run of characters up to
+// whitespace or an unbalanced closing parenthesis, so bare regexes may use groups
+func (p *filterParser) parseValue() (string, error) {
+	p.skipSpace()
+	if p.done() {
+		return "", p.errorf("missing value")
+	}
+	if p.input[p.pos] == '"' || p.input[p.pos] == '\'' {
+		return p.parseQuoted()
+	}
+
+	start := p.pos
+	depth := 0
+	for ; !p.done(); p.pos++ {
+		ch := p.input[p.pos]
+		if ch == ' ' || ch == '\t' || (ch == ')' && depth == 0) {
+			break
+		}
+		if ch == '(' {
+			depth++
+		} else if ch == ')' {
+			depth--
+		}
+	}
+	return p.input[start:p.pos], nil
+}
+
+// parseQuoted reads a single or double quoted string; a backslash escapes the next character
+func (p *filterParser) parseQuoted() (string, error) {
+	quote := p.input[p.pos]
+	p.pos++
+
+	var b strings.Builder
+	for !p.done() {
+		ch := p.input[p.pos]
+		p.pos++
+		switch {
+		case ch == quote:
+			return b.String(), nil
+		case ch == '\\' && !p.done():
+			b.WriteByte(p.input[p.pos])
+			p.pos++
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// consumeSymbol skips whitespace and consumes symbol if it comes next
+func (p *filterParser) consumeSymbol(symbol string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.input[p.pos:], symbol) {
+		p.pos += len(symbol)
+		return true
+	}
+	return false
+}
+
+// consumeKeyword consumes a case-insensitive keyword that stands alone as a word
+func (p *filterParser) consumeKeyword(keyword string) bool {
+	p.skipSpace()
+	end := p.pos + len(keyword)
+	if end > len(p.input) || !strings.EqualFold(p.input[p.pos:end], keyword) {
+		return false
+	}
+	if end < len(p.input) && !strings.ContainsRune(" \t(", rune(p.input[end])) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+// consumeFunction consumes a function name followed by an opening parenthesis
+func (p *filterParser) consumeFunction(name string) bool {
+	p.skipSpace()
+	start := p.pos
+	end := start + len(name)
+	if end > len(p.input) || !strings.EqualFold(p.input[start:end], name) {
+		return false
+	}
+	p.pos = end
+	if p.consumeSymbol("(") {
+		return true
+	}
+	p.pos = start
+	return false
+}
+
+func (p *filterParser) skipSpace() {
+	for !p.done() && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *filterParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.pos+1)
+}
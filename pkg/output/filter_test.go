@@ -0,0 +1,252 @@
+package output
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// filterTestResource is the resource the filter expressions are evaluated against
+var filterTestResource = models.Resource{
+	Service: "ec2",
+	Region:  "us-east-1",
+	ID:      "i-1234567890abcdef0",
+	Name:    "prod-web",
+	Type:    "t3.micro",
+	State:   "running",
+	Tags: map[string]string{
+		"Owner":  "alice",
+		"Team":   "data platform",
+		"NOTE":   "x",
+		"ORIGIN": "eu",
+		"ANDY":   "y",
+	},
+	Extra: map[string]interface{}{
+		"cpu":   2,
+		"motto": `say "hi"`,
+	},
+}
+
+func TestParseFilter_Matches(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		want       bool
+	}{
+		{"plain key=value", "service=ec2", true},
+		{"plain mismatch", "service=rds", false},
+		{"double equals", "state==running", true},
+		{"not equal", "state!=running", false},
+		{"case insensitive value", "state=RUNNING", true},
+		{"tag by key", "Owner=alice", true},
+		{"tag prefix", "tag:Owner=alice", true},
+
+		// AND binds tighter than OR, and NOT tighter than AND
+		{"AND before OR", "service=ec2 OR service=rds AND state=stopped", true},
+		{"AND before OR on the left", "service=rds AND state=stopped OR service=ec2", true},
+		{"OR inside parentheses", "(service=ec2 OR service=rds) AND state=stopped", false},
+		{"NOT before AND", "NOT service=ec2 AND state=stopped", false},
+		{"NOT over parentheses", "NOT (service=ec2 AND state=stopped)", true},
+		{"double NOT", "NOT NOT service=ec2", true},
+		{"symbol operators", "service=ec2 && (state=stopped || name=prod*)", true},
+		{"bang", "!(service=ec2)", false},
+		{"bang before has", "!has(tag:Missing)", true},
+		{"lower case keywords", "service=rds or service=ec2 and not state=stopped", true},
+
+		// Nesting
+		{"nested groups", "((service=rds OR (state=running AND NOT has(tag:Missing))) AND region=us-east-1)", true},
+		{"nested groups mismatch", "(service=ec2 AND (region=eu-west-1 OR (state=stopped AND has(tag:Owner))))", false},
+		{"group without spaces", "(service=ec2)AND(state=running)", true},
+
+		// Quoted values
+		{"double quoted value with a space", `tag:Team="data platform"`, true},
+		{"single quoted value with a space", `tag:Team='data platform'`, true},
+		{"quoted value mismatch", `tag:Team="data"`, false},
+		{"escaped quote", `extra.motto="say \"hi\""`, true},
+		{"quoted field", `"tag:Team"="data platform"`, true},
+
+		// Keywords and functions at the start of field names
+		{"field starting with NOT", "NOTE=x", true},
+		{"field starting with OR", "ORIGIN=eu", true},
+		{"field starting with AND", "ANDY=y", true},
+		{"keyword fields combined", "NOTE=x AND ORIGIN=eu AND NOT ANDY=n", true},
+		{"field starting with has", "hasBackup=yes", false},
+
+		// Wildcards, patterns and numbers
+		{"wildcard", "name=web*", true},
+		{"wildcard mismatch", "name=api*", false},
+		{"lone wildcard", "name=*", true},
+		{"regex with a group", "name~=^prod-(web|api)$", true},
+		{"regex mismatch", "name~=^dev-", false},
+		{"number comparison", "extra.cpu>1", true},
+		{"number equality", "extra.cpu=2.0", true},
+		{"number comparison mismatch", "extra.cpu>=4", false},
+
+		// Missing fields
+		{"has present tag", "has(tag:Owner)", true},
+		{"has missing tag", "has(tag:Missing)", false},
+		{"missing tag does not equal", "Missing=x", false},
+		{"missing tag is not equal", "Missing!=x", true},
+		{"missing number", "extra.memory>1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseFilter(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) failed: %v", tt.expression, err)
+			}
+			if got := filter.Matches(filterTestResource); got != tt.want {
+				t.Errorf("ParseFilter(%q).Matches() = %v, want %v", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{"empty", "", "unexpected end of expression"},
+		{"trailing AND", "service=ec2 AND", "unexpected end of expression"},
+		{"trailing OR", "service=ec2 OR ", "unexpected end of expression"},
+		{"trailing NOT", "NOT", "unexpected end of expression"},
+		{"leading AND", "AND service=ec2", "expected an operator"},
+		{"unbalanced opening parenthesis", "(service=ec2", "missing )"},
+		{"unbalanced nested parenthesis", "((service=ec2) OR state=running", "missing )"},
+		{"unbalanced closing parenthesis", "service=ec2)", `unexpected ")"`},
+		{"empty group", "()", "expected a field name"},
+		{"unclosed has", "has(tag:Owner", "missing ) after has(tag:Owner"},
+		{"missing operator", "service", "expected an operator"},
+		{"missing value", "service=", "missing value"},
+		{"unterminated string", `name="prod`, "unterminated string"},
+		{"number operator on text", "name>web", "> needs a number"},
+		{"invalid regex", "name~=[", "invalid regular expression"},
+		{"two comparisons without a keyword", "service=ec2 state=running", "unexpected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilter(tt.expression)
+			if err == nil {
+				t.Fatalf("ParseFilter(%q) succeeded, want an error containing %q", tt.expression, tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseFilter(%q) error = %q, want it to contain %q", tt.expression, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseExcludes(t *testing.T) {
+	excludes, err := ParseExcludes([]string{"state=stopped OR service=rds", "name=dev*"})
+	if err != nil {
+		t.Fatalf("ParseExcludes failed: %v", err)
+	}
+
+	if got := excludes[0].Expression; got != "NOT (state=stopped OR service=rds)" {
+		t.Errorf("Expression = %q, want the negated expression", got)
+	}
+	for _, exclude := range excludes {
+		if !exclude.Matches(filterTestResource) {
+			t.Errorf("exclude %q dropped a resource it does not match", exclude.Expression)
+		}
+	}
+
+	if _, err := ParseExcludes([]string{"state=stopped OR"}); err == nil {
+		t.Error("ParseExcludes accepted an invalid expression")
+	}
+}
+
+func TestParseFilter_Time(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	defer func(saved func() time.Time) { timeNow = saved }(timeNow)
+	timeNow = func() time.Time { return now }
+
+	// 82.5 days before now
+	created := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	resource := models.Resource{Service: "ec2", ID: "i-1", CreatedAt: &created}
+	undated := models.Resource{Service: "ec2", ID: "i-2"}
+
+	tests := []struct {
+		name        string
+		expression  string
+		want        bool
+		wantUndated bool
+	}{
+		// Dates compare by day
+		{"after a date", "created>2024-01-01", true, false},
+		{"before a date", "created<2024-01-01", false, false},
+		{"on the day", "created=2024-03-10", true, false},
+		{"on or after the day", "created>=2024-03-10", true, false},
+		{"before the day", "created<2024-03-10", false, false},
+		{"after the day", "created>2024-03-10", false, false},
+		{"not on the day", "created!=2024-03-10", false, true},
+
+		// Timestamps compare exactly, in any time zone
+		{"after a timestamp", "created>2024-03-10T11:00:00Z", true, false},
+		{"before a timestamp", "created<2024-03-10T11:00:00Z", false, false},
+		{"timestamp with an offset", "created<2024-03-10T13:00:00+02:00", false, false},
+		{"timestamp with an offset after", "created<=2024-03-10T14:00:00+02:00", true, false},
+
+		// Durations are before now
+		{"created within 30 days", "created>30d", false, false},
+		{"created more than 30 days ago", "created<30d", true, false},
+		{"created within 120 days", "created>120d", true, false},
+		{"created within 12 weeks", "created>12w", true, false},
+		{"created within 1000 hours", "created>1000h", false, false},
+
+		// Ages are the time since creation
+		{"older than 30 days", "age>30d", true, false},
+		{"older than a year", "age>1y", false, false},
+		{"younger than a year", "age<1y", true, false},
+		{"younger than 12 weeks", "age<12w", true, false},
+		{"older than 11 weeks", "age>=11w", true, false},
+		{"older than 2000 hours", "age>2000h", false, false},
+		{"fractional days", "age<82.6d", true, false},
+		{"not exactly a day old", "age!=1d", true, true},
+
+		// Combined with other fields
+		{"age and service", "service=ec2 AND age>30d", true, false},
+		{"age or has", "age<1d OR NOT has(created)", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseFilter(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) failed: %v", tt.expression, err)
+			}
+			if got := filter.Matches(resource); got != tt.want {
+				t.Errorf("ParseFilter(%q).Matches(created %s) = %v, want %v", tt.expression, created.Format(time.RFC3339), got, tt.want)
+			}
+			if got := filter.Matches(undated); got != tt.wantUndated {
+				t.Errorf("ParseFilter(%q).Matches(no creation time) = %v, want %v", tt.expression, got, tt.wantUndated)
+			}
+		})
+	}
+}
+
+func TestParseFilter_TimeErrors(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantErr    string
+	}{
+		{"age>2024-01-01", "invalid age"},
+		{"age>old", "invalid age"},
+		{"age>-3d", "invalid age"},
+		{"created>yesterday", "invalid time"},
+		{"created<2024-13-01", "invalid time"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseFilter(tt.expression)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ParseFilter(%q) error = %v, want it to contain %q", tt.expression, err, tt.wantErr)
+		}
+	}
+}
@@ -5,207 +5,328 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/jmespath/go-jmespath"
+	"github.com/xiaochen/awsinv/pkg/findings"
 	"github.com/xiaochen/awsinv/pkg/models"
 	"github.com/xiaochen/awsinv/pkg/pricing"
 )
 
-// CostEstimate represents a cost estimate for a resource
-type CostEstimate struct {
-	Amount       float64
-	Explanation  string
-	Formula      string
-	FormulaExplanation string
-	Breakdown    map[string]float64
-	Assumptions  []string
-	Examples     []string
-	Accuracy     string // "High", "Medium", "Low" - indicates estimation accuracy
-	FreeTierCovered bool   // Whether this resource is covered by free tier
-	FreeTierSavings float64 // Amount saved by free tier
-	Source       string // "api", "cache", "fallback"
+// Formatter defines the interface for output formatters
+type Formatter interface {
+	Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error
 }
 
-// Global pricing service instance
-var globalPricingService *pricing.PricingService
+// prepareResources prepares the resources of a report like PrepareResources, with the
+// cost estimates in the cost period of the options
+func prepareResources(collection *models.ResourceCollection, filters []Filter, sortField string, options Options) ([]models.Resource, map[string]*models.CostEstimate) {
+	resources, costEstimates := PrepareResources(collection, filters, sortField, options)
+	return resources, scaleEstimates(costEstimates, options.costPeriod())
+}
 
-// InitializePricingService initializes the global pricing service
-func InitializePricingService(ctx context.Context) error {
-	var err error
-	globalPricingService, err = pricing.NewPricingService(ctx)
-	if err != nil {
-		log.Printf("Warning: Failed to initialize pricing service: %v", err)
-		globalPricingService = nil
-		return err
+// PrepareResources estimates costs with the cost engine of the options, then filters
+// and sorts the resources of a collection the way the formatters do, so cost can be
+// used as a filter and sort field, for front ends that render them itself. It returns the monthly cost
+// estimates of the resources kept, keyed by Resource.Key: the service, region and
+// ID, prefixed with the account when there is one.
+func PrepareResources(collection *models.ResourceCollection, filters []Filter, sortField string, options Options) ([]models.Resource, map[string]*models.CostEstimate) {
+	// Collections are usually annotated once collected; others are annotated here,
+	// so every formatter of a run shows the same figures
+	options.costEngine().Annotate(context.Background(), collection)
+	costEstimates := collection.Costs
+
+	// Apply filters
+	resources := applyFilters(collection.Resources, filters, costEstimates)
+
+	// Sort resources
+	sortResources(resources, sortField, costEstimates)
+
+	// Keep only the estimates of the remaining resources, so totals match the output
+	filteredEstimates := make(map[string]*models.CostEstimate, len(resources))
+	for _, resource := range resources {
+		if estimate, exists := costEstimates[resource.Key()]; exists {
+			filteredEstimates[resource.Key()] = estimate
+		}
 	}
-	return nil
+
+	return resources, filteredEstimates
 }
 
-// Formatter defines the interface for output formatters
-type Formatter interface {
-	Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error
+// filteredComparison applies the report's filters to the new and disappeared
+// resources of a snapshot comparison, so they match the rest of the report. Their
+// costs are estimated with the cost engine of the options.
+func filteredComparison(collection *models.ResourceCollection, filters []Filter, options Options) *models.Comparison {
+	if collection.Comparison == nil {
+		return nil
+	}
+
+	comparison := *collection.Comparison
+	compared := &models.ResourceCollection{Resources: append(append([]models.Resource(nil), comparison.New...), comparison.Disappeared...)}
+	if len(filters) > 0 {
+		options.costEngine().Annotate(context.Background(), compared)
+	}
+	comparison.New = applyFilters(comparison.New, filters, compared.Costs)
+	comparison.Disappeared = applyFilters(comparison.Disappeared, filters, compared.Costs)
+	sortResources(comparison.New, "service,region,id", nil)
+	sortResources(comparison.Disappeared, "service,region,id", nil)
+	return &comparison
 }
 
-// Filter represents a filter condition
-type Filter struct {
-	Key   string
-	Value string
+// sortKey is one field of a sort specification
+type sortKey struct {
+	field      string
+	descending bool
 }
 
-// ParseFilters parses filter strings in the format "key=value"
-func ParseFilters(filterStrings []string) ([]Filter, error) {
-	var filters []Filter
+// sortValue is the value of a sort key for one resource
+type sortValue struct {
+	text     string
+	number   float64
+	isNumber bool
+	exists   bool
+}
 
-	for _, filterStr := range filterStrings {
-		parts := strings.SplitN(filterStr, "=", 2)
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid filter format: %s (expected key=value)", filterStr)
+// parseSortKeys parses a comma-separated sort specification such as region,-cost,name.
+// A leading "-" sorts that field in descending order.
+func parseSortKeys(sortField string) []sortKey {
+	var keys []sortKey
+	for _, field := range strings.Split(sortField, ",") {
+		field = strings.TrimSpace(field)
+		descending := strings.HasPrefix(field, "-")
+		field = strings.TrimSpace(strings.TrimPrefix(field, "-"))
+		if field == "" {
+			continue
 		}
+		keys = append(keys, sortKey{field: field, descending: descending})
+	}
+	if len(keys) == 0 {
+		keys = append(keys, sortKey{field: "service"})
+	}
+	return keys
+}
 
-		filters = append(filters, Filter{
-			Key:   strings.TrimSpace(parts[0]),
-			Value: strings.TrimSpace(parts[1]),
-		})
+// sortResources sorts resources by a comma-separated list of fields, e.g.
+// region,-cost,name. Any field usable in a filter can be sorted on; numbers compare
+// numerically, resources without a value sort last, and ties are broken by ID.
+func sortResources(resources []models.Resource, sortField string, costEstimates map[string]*models.CostEstimate) {
+	keys := parseSortKeys(sortField)
+
+	type sortEntry struct {
+		resource models.Resource
+		values   []sortValue
+	}
+	entries := make([]sortEntry, len(resources))
+	for i, resource := range resources {
+		c := &filterContext{resource: resource, costEstimates: costEstimates}
+		values := make([]sortValue, len(keys))
+		for k, key := range keys {
+			values[k].text, values[k].exists = c.field(key.field)
+			values[k].number, values[k].isNumber = c.number(key.field)
+		}
+		entries[i] = sortEntry{resource: resource, values: values}
 	}
 
-	return filters, nil
+	sort.SliceStable(entries, func(i, j int) bool {
+		for k, key := range keys {
+			if cmp := compareSortValues(entries[i].values[k], entries[j].values[k], key.descending); cmp != 0 {
+				return cmp < 0
+			}
+		}
+		// Secondary sort by ID
+		return entries[i].resource.ID < entries[j].resource.ID
+	})
+
+	for i, entry := range entries {
+		resources[i] = entry.resource
+	}
 }
 
-// applyFilters applies filters to resources
-func applyFilters(resources []models.Resource, filters []Filter) []models.Resource {
-	if len(filters) == 0 {
-		return resources
+// compareSortValues orders two values of the same key. Missing values sort last
+// in either direction.
+func compareSortValues(a, b sortValue, descending bool) int {
+	if a.exists != b.exists {
+		if a.exists {
+			return -1
+		}
+		return 1
 	}
 
-	var filtered []models.Resource
+	var cmp int
+	if a.isNumber && b.isNumber {
+		switch {
+		case a.number < b.number:
+			cmp = -1
+		case a.number > b.number:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(a.text, b.text)
+	}
+
+	if descending {
+		return -cmp
+	}
+	return cmp
+}
+
+// resourceCost returns the estimated monthly cost of a resource, or 0 without an estimate
+func resourceCost(resource models.Resource, costEstimates map[string]*models.CostEstimate) float64 {
+	if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
+		return estimate.Amount
+	}
+	return 0
+}
 
+// topCostCount is how many resources the most expensive resources section lists
+const topCostCount = 20
+
+// topCost is a resource in the most expensive resources section
+type topCost struct {
+	Resource models.Resource
+	Cost     float64
+	Owner    string
+}
+
+// topCosts returns the n most expensive resources that cost anything, most expensive
+// first, with their Owner tag
+func topCosts(resources []models.Resource, costEstimates map[string]*models.CostEstimate, n int) []topCost {
+	var top []topCost
 	for _, resource := range resources {
-		if matchesFilters(resource, filters) {
-			filtered = append(filtered, resource)
+		if cost := resourceCost(resource, costEstimates); cost > 0 {
+			top = append(top, topCost{Resource: resource, Cost: cost, Owner: ownerTag(resource)})
 		}
 	}
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Cost > top[j].Cost
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
 
-	return filtered
+// serviceAge is the number of resources of a service in each age range
+type serviceAge struct {
+	Service string
+	Counts  []int // in the order of models.AgeBuckets
 }
 
-// matchesFilters checks if a resource matches all filters
-func matchesFilters(resource models.Resource, filters []Filter) bool {
-	for _, filter := range filters {
-		if !matchesFilter(resource, filter) {
-			return false
+// servicesByAge returns the age ranges of the resources of each service, sorted by
+// service, so long-forgotten resources stand out
+func servicesByAge(resources []models.Resource) []serviceAge {
+	ages := models.AgeByService(resources, time.Now())
+	list := make([]serviceAge, 0, len(ages))
+	for service, buckets := range ages {
+		counts := make([]int, len(models.AgeBuckets))
+		for i, bucket := range models.AgeBuckets {
+			counts[i] = buckets[bucket]
 		}
+		list = append(list, serviceAge{Service: service, Counts: counts})
 	}
-	return true
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Service < list[j].Service
+	})
+	return list
 }
 
-// matchesFilter checks if a resource matches a single filter
-func matchesFilter(resource models.Resource, filter Filter) bool {
-	var value string
-	var isSubstring bool
+// ownerTag returns the value of a resource's normalized Owner tag
+func ownerTag(resource models.Resource) string {
+	owner, _ := resource.TagValue("Owner")
+	return owner
+}
 
-	// Check if it's a substring match
-	if strings.HasSuffix(filter.Value, "*") {
-		value = strings.TrimSuffix(filter.Value, "*")
-		isSubstring = true
-	} else {
-		value = filter.Value
-		isSubstring = false
+// freeTierSavings returns how much the free tier takes off the estimates
+func freeTierSavings(costEstimates map[string]*models.CostEstimate) float64 {
+	savings := 0.0
+	for _, estimate := range costEstimates {
+		if estimate != nil {
+			savings += estimate.FreeTierSavings
+		}
 	}
+	return savings
+}
 
-	// Get the field value
-	var fieldValue string
-	switch filter.Key {
-	case "service":
-		fieldValue = resource.Service
-	case "region":
-		fieldValue = resource.Region
-	case "id":
-		fieldValue = resource.ID
-	case "name":
-		fieldValue = resource.Name
-	case "type":
-		fieldValue = resource.Type
-	case "state":
-		fieldValue = resource.State
-	case "class":
-		fieldValue = resource.Class
-	default:
-		// Check tags
-		if tagValue, exists := resource.Tags[filter.Key]; exists {
-			fieldValue = tagValue
-		} else {
-			return false
+// severitySummary counts findings by severity, most serious first, as in
+// "2 critical, 1 medium"
+func severitySummary(found []findings.Finding) string {
+	counts := findings.Count(found)
+	var parts []string
+	for _, severity := range findings.Severities {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
 		}
 	}
+	return strings.Join(parts, ", ")
+}
 
-	// Perform comparison
-	if isSubstring {
-		return strings.Contains(strings.ToLower(fieldValue), strings.ToLower(value))
-	} else {
-		return strings.EqualFold(fieldValue, value)
+// findingKey identifies the resource of a finding by the Key of the resource, as IDs
+// are only unique within an account, service and region
+func findingKey(account, service, region, id string) string {
+	return models.Resource{Account: account, Service: service, Region: region, ID: id}.Key()
+}
+
+// findingsByResource groups findings by their resource's findingKey
+func findingsByResource(found []findings.Finding) map[string][]findings.Finding {
+	grouped := make(map[string][]findings.Finding)
+	for _, finding := range found {
+		key := findingKey(finding.Account, finding.Service, finding.Region, finding.ResourceID)
+		grouped[key] = append(grouped[key], finding)
 	}
+	return grouped
 }
 
-// sortResources sorts resources by the specified field
-func sortResources(resources []models.Resource, sortField string) {
-	sort.Slice(resources, func(i, j int) bool {
-		var a, b string
-
-		switch sortField {
-		case "service":
-			a, b = resources[i].Service, resources[j].Service
-		case "region":
-			a, b = resources[i].Region, resources[j].Region
-		case "id":
-			a, b = resources[i].ID, resources[j].ID
-		case "name":
-			a, b = resources[i].Name, resources[j].Name
-		case "type":
-			a, b = resources[i].Type, resources[j].Type
-		case "state":
-			a, b = resources[i].State, resources[j].State
-		default:
-			a, b = resources[i].Service, resources[j].Service
-		}
+// findingsCell lists a resource's findings in one cell, as in
+// "high: Port 22 (SSH) is open to the internet; medium: ..."
+func findingsCell(found []findings.Finding) string {
+	parts := make([]string, 0, len(found))
+	for _, finding := range found {
+		parts = append(parts, fmt.Sprintf("%s: %s", finding.Severity, finding.Message))
+	}
+	return strings.Join(parts, "; ")
+}
 
-		if a == b {
-			// Secondary sort by ID
-			return resources[i].ID < resources[j].ID
-		}
-		return a < b
-	})
+// coverageText describes the tag coverage of a group, as in "45/60 (75.0%)"
+func coverageText(coverage models.TagCoverage) string {
+	return fmt.Sprintf("%d/%d (%.1f%%)", coverage.Compliant, coverage.Resources, coverage.Coverage)
 }
 
 // TableFormatter formats output as a table
 type TableFormatter struct {
-	writer *os.File
+	writer  io.Writer
+	options Options
 }
 
-// NewTableFormatter creates a new table formatter
+// NewTableFormatter creates a new table formatter writing to a file
 func NewTableFormatter(writer *os.File) *TableFormatter {
-	return &TableFormatter{writer: writer}
+	return NewTableFormatterWithWriter(writer)
 }
 
-// Format formats the collection as a table
-func (f *TableFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
-	// Apply filters
-	resources := applyFilters(collection.Resources, filters)
+// NewTableFormatterWithWriter creates a new table formatter writing to any io.Writer
+func NewTableFormatterWithWriter(writer io.Writer) *TableFormatter {
+	return NewTableFormatterWithOptions(writer, Options{})
+}
 
-	// Sort resources
-	sortResources(resources, sortField)
+// NewTableFormatterWithOptions creates a new table formatter with the given report settings
+func NewTableFormatterWithOptions(writer io.Writer, options Options) *TableFormatter {
+	return &TableFormatter{writer: writer, options: options}
+}
 
-	// Calculate cost estimates
-	costEstimates := calculateCostEstimates(resources)
+// Format formats the collection as a table
+func (f *TableFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
+	// Calculate cost estimates, apply filters and sort
+	costPeriod := f.options.costPeriod()
+	resources, costEstimates := prepareResources(collection, filters, sortField, f.options)
 	
-	// Calculate total monthly cost
-	totalMonthlyCost := 0.0
+	// Calculate total cost
+	totalCost := 0.0
 	for _, estimate := range costEstimates {
 		if estimate != nil {
-			totalMonthlyCost += estimate.Amount
+			totalCost += estimate.Amount
 		}
 	}
 
@@ -213,9 +334,25 @@ func (f *TableFormatter) Format(collection *models.ResourceCollection, filters [
 	fmt.Fprintf(f.writer, "\nAWS Resource Inventory Summary\n")
 	fmt.Fprintf(f.writer, "==============================\n")
 	fmt.Fprintf(f.writer, "Total Resources: %d\n", len(resources))
-	fmt.Fprintf(f.writer, "Estimated Monthly Cost: $%.2f\n", totalMonthlyCost)
+	fmt.Fprintf(f.writer, "Estimated %s Cost: %s\n", costPeriod.Title(), costPeriod.Format(totalCost))
+	if split := models.SplitCosts(resources, costEstimates); split.Covered > 0 || split.Commitments > 0 {
+		fmt.Fprintf(f.writer, "  On-demand: %s, covered by reservations and Savings Plans: %s, commitments: %s\n",
+			costPeriod.Format(split.OnDemand), costPeriod.Format(split.Covered), costPeriod.Format(split.Commitments))
+	}
+	if savings := freeTierSavings(costEstimates); savings > 0 {
+		fmt.Fprintf(f.writer, "  Free tier savings: %s\n", costPeriod.Format(savings))
+	}
 	fmt.Fprintf(f.writer, "Duration: %v\n", collection.Summary.Duration)
-	fmt.Fprintf(f.writer, "Errors: %d\n", len(collection.Errors))
+	fmt.Fprintf(f.writer, "Errors: %d%s\n", len(collection.Errors), errorBreakdown(collection.Summary))
+	if collection.Summary.Partial {
+		fmt.Fprintf(f.writer, "PARTIAL INVENTORY: collection stopped before every service and region finished\n")
+	}
+	if len(collection.Warnings) > 0 {
+		fmt.Fprintf(f.writer, "Warnings: %d\n", len(collection.Warnings))
+	}
+	if len(collection.Skipped) > 0 {
+		fmt.Fprintf(f.writer, "Skipped: %d service/region pairs not enabled\n", len(collection.Skipped))
+	}
 
 	if len(collection.Summary.ByService) > 0 {
 		fmt.Fprintf(f.writer, "\nBy Service:\n")
@@ -230,7 +367,7 @@ func (f *TableFormatter) Format(collection *models.ResourceCollection, filters [
 			serviceCost := 0.0
 			for _, resource := range resources {
 				if resource.Service == service {
-					if estimate, exists := costEstimates[resource.ID]; exists && estimate != nil {
+					if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
 						serviceCost += estimate.Amount
 					}
 				}
@@ -248,7 +385,7 @@ func (f *TableFormatter) Format(collection *models.ResourceCollection, filters [
 		})
 		
 		for _, item := range serviceCosts {
-			fmt.Fprintf(f.writer, "  %s: %d ($%.2f/month)\n", item.Service, item.Count, item.Cost)
+			fmt.Fprintf(f.writer, "  %s: %d (%s%s)\n", item.Service, item.Count, costPeriod.Format(item.Cost), costPeriod.Unit())
 		}
 	}
 
@@ -258,17 +395,165 @@ func (f *TableFormatter) Format(collection *models.ResourceCollection, filters [
 			regionCost := 0.0
 			for _, resource := range resources {
 				if resource.Region == region {
-					if estimate, exists := costEstimates[resource.ID]; exists && estimate != nil {
+					if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
 						regionCost += estimate.Amount
 					}
 				}
 			}
-			fmt.Fprintf(f.writer, "  %s: %d ($%.2f/month)\n", region, count, regionCost)
+			fmt.Fprintf(f.writer, "  %s: %d (%s%s)\n", region, count, costPeriod.Format(regionCost), costPeriod.Unit())
+		}
+	}
+
+	// Print the age of each service's resources, to spot long-forgotten ones
+	if ages := servicesByAge(resources); len(ages) > 0 {
+		fmt.Fprintf(f.writer, "\nBy Age:\n")
+		fmt.Fprintf(f.writer, "  %-15s", "SERVICE")
+		for _, bucket := range models.AgeBuckets {
+			fmt.Fprintf(f.writer, " %8s", strings.ToUpper(bucket))
+		}
+		fmt.Fprintln(f.writer)
+		for _, age := range ages {
+			fmt.Fprintf(f.writer, "  %-15s", truncate(age.Service, 15))
+			for _, count := range age.Counts {
+				fmt.Fprintf(f.writer, " %8d", count)
+			}
+			fmt.Fprintln(f.writer)
+		}
+	}
+
+	// Print the cost per owner, environment and other normalized tags
+	for _, allocation := range costAllocations(resources, costEstimates) {
+		fmt.Fprintf(f.writer, "\nBy %s:\n", allocation.Key)
+		for _, group := range allocation.Allocations {
+			fmt.Fprintf(f.writer, "  %s: %d (%s%s)\n", allocationValue(group.Value), group.Resources, costPeriod.Format(group.Cost), costPeriod.Unit())
+		}
+	}
+
+	// Print budgets, flagging those the reported resources exceed
+	if budgets := budgetStatuses(collection, resources, costPeriod); len(budgets) > 0 {
+		fmt.Fprintf(f.writer, "\nBudgets:\n")
+		for _, budget := range budgets {
+			status := "ok"
+			if budget.Over {
+				status = "OVER BUDGET"
+			}
+			fmt.Fprintf(f.writer, "  %s: %s of %s%s (%.0f%%) %s\n",
+				budget.Label(), costPeriod.Format(budget.Spend), costPeriod.Format(budget.Limit), costPeriod.Unit(), budget.Spend/budget.Limit*100, status)
+		}
+	}
+
+	// Print the most expensive resources, the first question of a cost review
+	if top := topCosts(resources, costEstimates, topCostCount); len(top) > 0 {
+		fmt.Fprintf(f.writer, "\nTop %d Most Expensive Resources:\n", len(top))
+		fmt.Fprintf(f.writer, "  %-12s %-15s %-20s %-20s %-15s %12s\n", "SERVICE", "REGION", "ID", "NAME", "OWNER", strings.ToUpper(costPeriod.Title())+" COST")
+		for _, item := range top {
+			owner := item.Owner
+			if owner == "" {
+				owner = "-"
+			}
+			fmt.Fprintf(f.writer, "  %-12s %-15s %-20s %-20s %-15s %12s\n",
+				truncate(item.Resource.Service, 12),
+				truncate(item.Resource.Region, 15),
+				truncate(item.Resource.ID, 20),
+				truncate(item.Resource.Name, 20),
+				truncate(owner, 15),
+				costPeriod.Format(item.Cost))
+		}
+	}
+
+	// Print rightsizing recommendations for instances measured with --rightsizing
+	if recommendations := pricing.Rightsize(resources, costEstimates); len(recommendations) > 0 {
+		savings := 0.0
+		for _, recommendation := range recommendations {
+			savings += recommendation.Savings
+		}
+		fmt.Fprintf(f.writer, "\nRightsizing Recommendations (projected savings %s%s):\n", costPeriod.Format(savings), costPeriod.Unit())
+		for _, recommendation := range recommendations {
+			fmt.Fprintf(f.writer, "  %-12s %-15s %-20s %s -> %s, saves %s (%s)\n",
+				truncate(recommendation.Service, 12),
+				truncate(recommendation.Region, 15),
+				truncate(recommendation.ID, 20),
+				recommendation.Current,
+				recommendation.Recommended,
+				costPeriod.Format(recommendation.Savings),
+				recommendation.Reason)
+		}
+	}
+
+	// Print security findings, most serious first
+	if found := findings.Evaluate(resources); len(found) > 0 {
+		fmt.Fprintf(f.writer, "\nSecurity Findings (%s):\n", severitySummary(found))
+		for _, finding := range found {
+			fmt.Fprintf(f.writer, "  %-8s %-12s %-15s %-20s %s\n",
+				strings.ToUpper(string(finding.Severity)),
+				truncate(finding.Service, 12),
+				truncate(finding.Region, 15),
+				truncate(finding.ResourceID, 20),
+				finding.Message)
+		}
+	}
+
+	// Print orphaned resources, which keep costing money without doing anything
+	if orphans, wasted := orphanedResources(collection, resources, costEstimates); len(orphans) > 0 {
+		fmt.Fprintf(f.writer, "\nOrphaned Resources (%d, %s%s wasted):\n", len(orphans), costPeriod.Format(wasted), costPeriod.Unit())
+		for _, item := range orphans {
+			fmt.Fprintf(f.writer, "  %-12s %-15s %-20s %10s  %s\n",
+				truncate(item.Service, 12),
+				truncate(item.Region, 15),
+				truncate(item.ID, 20),
+				costPeriod.Format(item.Cost),
+				item.Reason)
+		}
+	}
+
+	// Print the tag audit when tags are required
+	if compliance := tagCompliance(resources, f.options.requiredTags()); compliance != nil {
+		fmt.Fprintf(f.writer, "\nTag Compliance (%s): %s\n", strings.Join(compliance.RequiredTags, ", "), coverageText(compliance.Total))
+		for _, group := range tagCoverageGroups(compliance) {
+			if len(group.Coverage) == 0 {
+				continue
+			}
+			fmt.Fprintf(f.writer, "  By %s:\n", group.Title)
+			for _, coverage := range group.Coverage {
+				fmt.Fprintf(f.writer, "    %-15s %s\n", truncate(coverage.Name, 15), coverageText(coverage))
+			}
+		}
+		if len(compliance.NonCompliant) > 0 {
+			fmt.Fprintf(f.writer, "  Missing Tags:\n")
+			for _, resource := range compliance.NonCompliant {
+				fmt.Fprintf(f.writer, "    %-12s %-15s %-20s %s\n",
+					truncate(resource.Service, 12),
+					truncate(resource.Region, 15),
+					truncate(resource.ID, 20),
+					strings.Join(resource.Missing, ", "))
+			}
 		}
 	}
 
 
 
+	// Print changes since the compared snapshot
+	if comparison := filteredComparison(collection, filters, f.options); comparison != nil {
+		since := comparison.ComparedTo.Format(time.RFC3339)
+		sections := []struct {
+			title     string
+			resources []models.Resource
+		}{
+			{"New Since Last Scan", comparison.New},
+			{"Disappeared Since Last Scan", comparison.Disappeared},
+		}
+		for _, section := range sections {
+			fmt.Fprintf(f.writer, "\n%s (%s): %d\n", section.title, since, len(section.resources))
+			for _, resource := range section.resources {
+				fmt.Fprintf(f.writer, "  %-12s %-15s %-20s %s\n",
+					truncate(resource.Service, 12),
+					truncate(resource.Region, 15),
+					truncate(resource.ID, 20),
+					resource.Name)
+			}
+		}
+	}
+
 	// Print errors if any
 	if len(collection.Errors) > 0 {
 		fmt.Fprintf(f.writer, "\nErrors:\n")
@@ -276,17 +561,23 @@ func (f *TableFormatter) Format(collection *models.ResourceCollection, filters [
 			fmt.Fprintf(f.writer, "  %s\n", err)
 		}
 	}
+	if len(collection.Warnings) > 0 {
+		fmt.Fprintf(f.writer, "\nWarnings:\n")
+		for _, warning := range collection.Warnings {
+			fmt.Fprintf(f.writer, "  %s\n", warning)
+		}
+	}
 
 	// Print resources table
 	if len(resources) > 0 {
-		fmt.Fprintf(f.writer, "\nResources Inventory (Total Cost: $%.2f/month):\n", totalMonthlyCost)
-		fmt.Fprintf(f.writer, "%-12s %-15s %-20s %-15s %-10s %-10s %-10s %-12s\n", "SERVICE", "REGION", "ID", "NAME", "TYPE", "STATE", "CLASS", "MONTHLY COST")
+		fmt.Fprintf(f.writer, "\nResources Inventory (Total Cost: %s%s):\n", costPeriod.Format(totalCost), costPeriod.Unit())
+		fmt.Fprintf(f.writer, "%-12s %-15s %-20s %-15s %-10s %-10s %-10s %-12s\n", "SERVICE", "REGION", "ID", "NAME", "TYPE", "STATE", "CLASS", strings.ToUpper(costPeriod.Title())+" COST")
 		fmt.Fprintf(f.writer, "%-12s %-15s %-20s %-15s %-10s %-10s %-10s %-12s\n", "-------", "------", "--", "----", "----", "-----", "-----", "------------")
 
 		for _, resource := range resources {
 			costStr := "-"
-			if estimate, exists := costEstimates[resource.ID]; exists && estimate != nil {
-				costStr = fmt.Sprintf("$%.2f", estimate.Amount)
+			if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
+				costStr = costPeriod.Format(estimate.Amount)
 			}
 			
 			fmt.Fprintf(f.writer, "%-12s %-15s %-20s %-15s %-10s %-10s %-10s %-12s\n",
@@ -306,101 +597,194 @@ func (f *TableFormatter) Format(collection *models.ResourceCollection, filters [
 
 // JSONFormatter formats output as JSON
 type JSONFormatter struct {
-	writer *os.File
+	writer  io.Writer
+	query   *jmespath.JMESPath
+	options Options
 }
 
-// NewJSONFormatter creates a new JSON formatter
+// JSONOptions controls the JSON report
+type JSONOptions struct {
+	Options
+
+	// Query is a JMESPath query over the output, such as
+	// "resources[?state=='running'].id". Only its result is written.
+	Query string
+}
+
+// NewJSONFormatter creates a new JSON formatter writing to a file
 func NewJSONFormatter(writer *os.File) *JSONFormatter {
+	return NewJSONFormatterWithWriter(writer)
+}
+
+// NewJSONFormatterWithWriter creates a new JSON formatter writing to any io.Writer
+func NewJSONFormatterWithWriter(writer io.Writer) *JSONFormatter {
 	return &JSONFormatter{writer: writer}
 }
 
+// NewJSONFormatterWithQuery creates a new JSON formatter that writes only the result
+// of a JMESPath query over the output, e.g. "resources[?state=='running'].id"
+func NewJSONFormatterWithQuery(writer io.Writer, query string) (*JSONFormatter, error) {
+	return NewJSONFormatterWithOptions(writer, JSONOptions{Query: query})
+}
+
+// NewJSONFormatterWithOptions creates a new JSON formatter with the given report
+// settings and query
+func NewJSONFormatterWithOptions(writer io.Writer, options JSONOptions) (*JSONFormatter, error) {
+	formatter := &JSONFormatter{writer: writer, options: options.Options}
+	if options.Query != "" {
+		compiled, err := jmespath.Compile(options.Query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid query %q: %w", options.Query, err)
+		}
+		formatter.query = compiled
+	}
+	return formatter, nil
+}
+
 // ResourceWithCost represents a resource with its cost estimate
 type ResourceWithCost struct {
 	models.Resource
-	CostEstimate *CostEstimate `json:"costEstimate,omitempty"`
+	NormalizedTags map[string]string    `json:"normalizedTags,omitempty"`
+	CostEstimate   *models.CostEstimate `json:"costEstimate,omitempty"`
 }
 
 // Format formats the collection as JSON
 func (f *JSONFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
-	// Apply filters
-	resources := applyFilters(collection.Resources, filters)
-
-	// Sort resources
-	sortResources(resources, sortField)
-
-	// Calculate cost estimates
-	costEstimates := calculateCostEstimates(resources)
+	// Calculate cost estimates, apply filters and sort
+	costPeriod := f.options.costPeriod()
+	resources, monthlyEstimates := PrepareResources(collection, filters, sortField, f.options)
+	costEstimates := scaleEstimates(monthlyEstimates, costPeriod)
 	
-	// Calculate total monthly cost
+	// Calculate total monthly cost, and the total in the cost period
 	totalMonthlyCost := 0.0
-	for _, estimate := range costEstimates {
+	for _, estimate := range monthlyEstimates {
 		if estimate != nil {
 			totalMonthlyCost += estimate.Amount
 		}
 	}
+	totalCost := costPeriod.Scale(totalMonthlyCost)
 
 	// Create resources with cost estimates
 	resourcesWithCost := make([]ResourceWithCost, len(resources))
 	for i, resource := range resources {
 		resourcesWithCost[i] = ResourceWithCost{
-			Resource: resource,
+			Resource:       resource,
+			NormalizedTags: resource.NormalizedTags(),
 		}
-		if estimate, exists := costEstimates[resource.ID]; exists && estimate != nil {
+		if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
 			resourcesWithCost[i].CostEstimate = estimate
 		}
 	}
 
+	orphans, _ := orphanedResources(collection, resources, costEstimates)
+
 	// Create output structure
 	output := struct {
 		Resources         []ResourceWithCost `json:"resources"`
 		Summary           models.Summary     `json:"summary"`
 		TotalMonthlyCost  float64            `json:"totalMonthlyCost"`
+		CostPeriod        CostPeriod         `json:"costPeriod"`
+		TotalCost         float64            `json:"totalCost"`
+		CostSplit         models.CostSplit   `json:"costSplit"`
+		Budgets           []models.BudgetStatus `json:"budgets,omitempty"`
+		Recommendations   []models.Recommendation `json:"recommendations,omitempty"`
+		Findings          []findings.Finding `json:"findings,omitempty"`
+		TagCompliance     *models.TagCompliance `json:"tagCompliance,omitempty"`
+		CostAllocation    map[string][]models.TagAllocation `json:"costAllocation,omitempty"`
+		Orphans           []orphan           `json:"orphans,omitempty"`
 		Errors            []string           `json:"errors,omitempty"`
+		Warnings          []string           `json:"warnings,omitempty"`
+		Skipped           []string           `json:"skipped,omitempty"`
+		Comparison        *models.Comparison `json:"comparison,omitempty"`
 	}{
 		Resources:        resourcesWithCost,
 		Summary:          collection.Summary,
 		TotalMonthlyCost: totalMonthlyCost,
+		CostPeriod:       costPeriod,
+		TotalCost:        totalCost,
+		CostSplit:        models.SplitCosts(resources, costEstimates),
+		Budgets:          budgetStatuses(collection, resources, costPeriod),
+		Recommendations:  pricing.Rightsize(resources, costEstimates),
+		Findings:         findings.Evaluate(resources),
+		TagCompliance:    tagCompliance(resources, f.options.requiredTags()),
+		CostAllocation:   costAllocationMap(resources, costEstimates),
+		Orphans:          orphans,
 		Errors:           collection.Errors,
+		Warnings:         collection.Warnings,
+		Skipped:          collection.Skipped,
+		Comparison:       filteredComparison(collection, filters, f.options),
 	}
 
 	// Update summary with filtered count
 	output.Summary.TotalResources = len(resources)
+	output.Summary.ByAge = models.AgeByService(resources, time.Now())
 
 	encoder := json.NewEncoder(f.writer)
 	encoder.SetIndent("", "  ")
-	return encoder.Encode(output)
+
+	if f.query == nil {
+		return encoder.Encode(output)
+	}
+
+	// Queries run against the JSON document, so they use its field names
+	data, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	result, err := f.query.Search(document)
+	if err != nil {
+		return fmt.Errorf("query failed: %w", err)
+	}
+	return encoder.Encode(result)
 }
 
 // CSVFormatter formats output as CSV
 type CSVFormatter struct {
-	writer *os.File
+	writer  io.Writer
+	options Options
 }
 
-// NewCSVFormatter creates a new CSV formatter
+// NewCSVFormatter creates a new CSV formatter writing to a file
 func NewCSVFormatter(writer *os.File) *CSVFormatter {
-	return &CSVFormatter{writer: writer}
+	return NewCSVFormatterWithWriter(writer)
 }
 
-// Format formats the collection as CSV
-func (f *CSVFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
-	// Apply filters
-	resources := applyFilters(collection.Resources, filters)
+// NewCSVFormatterWithWriter creates a new CSV formatter writing to any io.Writer
+func NewCSVFormatterWithWriter(writer io.Writer) *CSVFormatter {
+	return NewCSVFormatterWithOptions(writer, Options{})
+}
 
-	// Sort resources
-	sortResources(resources, sortField)
+// NewCSVFormatterWithOptions creates a new CSV formatter with the given report settings
+func NewCSVFormatterWithOptions(writer io.Writer, options Options) *CSVFormatter {
+	return &CSVFormatter{writer: writer, options: options}
+}
 
-	// Calculate cost estimates
-	costEstimates := calculateCostEstimates(resources)
+// Format formats the collection as CSV
+func (f *CSVFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
+	// Calculate cost estimates, apply filters and sort
+	resources, costEstimates := prepareResources(collection, filters, sortField, f.options)
+	costPeriod := f.options.costPeriod()
+	requiredTags := f.options.requiredTags()
 
 	writer := csv.NewWriter(f.writer)
 	defer writer.Flush()
 
 	// Write header
-	header := []string{"Service", "Region", "ID", "Name", "Type", "State", "Class", "MonthlyCost", "CreatedAt", "Tags"}
+	header := []string{"Service", "Region", "ID", "Name", "Type", "State", "Class", costPeriod.Title() + "Cost", "CreatedAt", "Tags", "Findings"}
+	// The tag audit adds the required tags each resource is missing
+	if len(requiredTags) > 0 {
+		header = append(header, "MissingTags")
+	}
 	if err := writer.Write(header); err != nil {
 		return err
 	}
 
+	resourceFindings := findingsByResource(findings.Evaluate(resources))
+
 	// Write data
 	for _, resource := range resources {
 		// Convert tags to string
@@ -421,8 +805,8 @@ func (f *CSVFormatter) Format(collection *models.ResourceCollection, filters []F
 
 		// Get cost estimate
 		costStr := ""
-		if estimate, exists := costEstimates[resource.ID]; exists && estimate != nil {
-			costStr = fmt.Sprintf("%.2f", estimate.Amount)
+		if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
+			costStr = strings.TrimPrefix(costPeriod.Format(estimate.Amount), "$")
 		}
 
 		row := []string{
@@ -436,6 +820,14 @@ func (f *CSVFormatter) Format(collection *models.ResourceCollection, filters []F
 			costStr,
 			createdAtStr,
 			tagsStr,
+			findingsCell(resourceFindings[resource.Key()]),
+		}
+		if len(requiredTags) > 0 {
+			missing := ""
+			if models.Taggable(resource) {
+				missing = strings.Join(models.MissingTags(resource, requiredTags), ",")
+			}
+			row = append(row, missing)
 		}
 
 		if err := writer.Write(row); err != nil {
@@ -446,6 +838,19 @@ func (f *CSVFormatter) Format(collection *models.ResourceCollection, filters []F
 	return nil
 }
 
+// errorBreakdown formats the error count of each class, e.g. " (access-denied 2, throttled 1)"
+func errorBreakdown(summary models.Summary) string {
+	if len(summary.ErrorsByClass) == 0 {
+		return ""
+	}
+
+	var parts []string
+	for _, class := range sortedKeys(summary.ErrorsByClass) {
+		parts = append(parts, fmt.Sprintf("%s %d", class, summary.ErrorsByClass[class]))
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
+}
+
 // truncate truncates a string to the specified length
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -461,585 +866,3 @@ var stderr *os.File
 func SetStderr(file *os.File) {
 	stderr = file
 }
-
-// calculateCostEstimates calculates cost estimates for individual resources
-func calculateCostEstimates(resources []models.Resource) map[string]*CostEstimate {
-	costs := make(map[string]*CostEstimate)
-
-	for _, resource := range resources {
-		var estimate *CostEstimate
-		switch resource.Service {
-		case "ec2":
-			estimate = estimateEC2Cost(resource)
-		case "rds":
-			estimate = estimateRDSCost(resource)
-		case "lambda":
-			estimate = estimateLambdaCost(resource)
-		case "s3":
-			estimate = estimateS3Cost(resource)
-		case "dynamodb":
-			estimate = estimateDynamoDBCost(resource)
-		case "sfn":
-			estimate = estimateSFNCost(resource)
-		case "cloudwatch":
-			estimate = estimateCloudWatchCost(resource)
-		case "ecs":
-			estimate = estimateECSCost(resource)
-		case "redis":
-			estimate = estimateRedisCost(resource)
-		case "efs":
-			estimate = estimateEFSCost(resource)
-		default:
-			estimate = &CostEstimate{Amount: 0}
-		}
-		
-		if estimate != nil {
-			costs[resource.ID] = estimate
-		}
-	}
-
-	return costs
-}
-
-// estimateEC2Cost estimates EC2 instance cost using real-time pricing
-func estimateEC2Cost(resource models.Resource) *CostEstimate {
-	// Only charge for running instances
-	if resource.State != "running" {
-		return &CostEstimate{
-			Amount:      0,
-			Explanation: fmt.Sprintf("EC2 %s instance: $0.00/month (not running)", resource.Type),
-			Formula:     "Monthly Cost = $0 (stopped instances)",
-			FormulaExplanation: "Stopped EC2 instances are not charged for compute time.",
-			Breakdown:   make(map[string]float64),
-			Accuracy:    "High",
-			Source:      "state-check",
-		}
-	}
-
-	// Try to get real-time pricing
-	if globalPricingService != nil {
-		ctx := context.Background()
-		result, err := globalPricingService.GetPricing(ctx, "ec2", resource.Region, resource.Type)
-		if err == nil {
-			estimate := &CostEstimate{
-				Amount:      result.MonthlyPrice,
-				Explanation: fmt.Sprintf("EC2 %s instance: $%.2f/month", resource.Type, result.MonthlyPrice),
-				Formula:     "Monthly Cost = Hourly Rate × 730 hours",
-				FormulaExplanation: "AWS charges per hour for running instances. We multiply by 730 hours for monthly cost.",
-				Breakdown:   map[string]float64{resource.Type: result.MonthlyPrice},
-				Accuracy:    result.Accuracy,
-				Source:      result.Source,
-				FreeTierCovered: result.FreeTierCovered,
-				FreeTierSavings: result.FreeTierSavings,
-				Assumptions: []string{
-					fmt.Sprintf("Pricing from %s", result.Source),
-					"Only running instances are charged",
-					"Excludes data transfer, storage, and other costs",
-					"Assumes 24/7 usage (730 hours/month)",
-				},
-				Examples: []string{
-					"t3.micro: $0.0116/hour × 730 hours = $8.47/month",
-					"t3.small: $0.0232/hour × 730 hours = $16.94/month",
-					"m5.large: $0.1184/hour × 730 hours = $86.40/month",
-				},
-			}
-
-			// Update explanation for free tier
-			if result.FreeTierCovered {
-				estimate.Explanation = fmt.Sprintf("EC2 %s instance: $0.00/month (FREE TIER)", resource.Type)
-				estimate.Amount = 0
-				estimate.Assumptions = append(estimate.Assumptions, "FREE TIER: t2.micro instances are free for 750 hours/month during first 12 months")
-			} else if result.FreeTierSavings > 0 {
-				estimate.Explanation = fmt.Sprintf("EC2 %s instance: $%.2f/month (FREE TIER saves $%.2f)", resource.Type, result.MonthlyPrice-result.FreeTierSavings, result.FreeTierSavings)
-				estimate.Amount = result.MonthlyPrice - result.FreeTierSavings
-				estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("FREE TIER: Partial coverage saves $%.2f/month", result.FreeTierSavings))
-			}
-
-			return estimate
-		}
-	}
-
-	// Fallback to hardcoded estimates
-	return getFallbackEC2Cost(resource)
-}
-
-// getFallbackEC2Cost provides fallback pricing when API is unavailable
-func getFallbackEC2Cost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      0,
-		Explanation: "EC2 costs are based on instance type and running state",
-		Formula:     "Monthly Cost = Hourly Rate × 730 hours",
-		FormulaExplanation: "AWS charges per hour, so we multiply the hourly rate by 730 hours (average hours per month) to get monthly cost.",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "Medium",
-		Source:      "fallback",
-		Assumptions: []string{
-			"Based on us-east-1 on-demand pricing (fallback estimates)",
-			"Only running instances are charged",
-			"Excludes data transfer, storage, and other costs",
-			"Assumes 24/7 usage (730 hours/month)",
-		},
-		Examples: []string{
-			"t3.micro: $0.0116/hour × 730 hours = $8.47/month",
-			"t3.small: $0.0232/hour × 730 hours = $16.94/month",
-			"m5.large: $0.1184/hour × 730 hours = $86.40/month",
-		},
-	}
-
-	// Rough cost estimates per month (us-east-1 pricing)
-	costMap := map[string]float64{
-		"t3.micro":     8.47,
-		"t3.small":     16.94,
-		"t3a.medium":   27.07,
-		"t3.medium":    33.88,
-		"t3.large":     67.76,
-		"m5.large":     86.40,
-		"m5.xlarge":    172.80,
-		"c5.large":     68.00,
-		"c5.xlarge":    136.00,
-		"r5.large":     126.00,
-		"r5.xlarge":    252.00,
-	}
-
-	if cost, exists := costMap[resource.Type]; exists {
-		estimate.Amount = cost
-		estimate.Breakdown[resource.Type] = cost
-		estimate.Explanation = fmt.Sprintf("EC2 %s instance: $%.2f/month", resource.Type, cost)
-	} else {
-		estimate.Amount = 50.0
-		estimate.Breakdown["unknown"] = 50.0
-		estimate.Explanation = fmt.Sprintf("EC2 %s instance: $50.00/month (estimated for unknown instance type)", resource.Type)
-		estimate.Assumptions = append(estimate.Assumptions, "Unknown instance type - using conservative estimate")
-	}
-
-	// Check free tier for fallback
-	if globalPricingService != nil && resource.Type == "t2.micro" && globalPricingService.IsFreeTierEligible() {
-		estimate.FreeTierCovered = true
-		estimate.Amount = 0
-		estimate.Explanation = fmt.Sprintf("EC2 %s instance: $0.00/month (FREE TIER)", resource.Type)
-		estimate.Assumptions = append(estimate.Assumptions, "FREE TIER: t2.micro instances are free for 750 hours/month during first 12 months")
-	}
-
-	return estimate
-}
-
-// estimateRDSCost estimates RDS instance cost using real-time pricing
-func estimateRDSCost(resource models.Resource) *CostEstimate {
-	// Only charge for available instances
-	if resource.State != "available" {
-		return &CostEstimate{
-			Amount:      0,
-			Explanation: fmt.Sprintf("RDS %s instance: $0.00/month (not available)", resource.Class),
-			Formula:     "Monthly Cost = $0 (stopped instances)",
-			FormulaExplanation: "Stopped RDS instances are not charged for compute time.",
-			Breakdown:   make(map[string]float64),
-			Accuracy:    "High",
-			Source:      "state-check",
-		}
-	}
-
-	// Try to get real-time pricing
-	if globalPricingService != nil {
-		ctx := context.Background()
-		result, err := globalPricingService.GetPricing(ctx, "rds", resource.Region, resource.Class)
-		if err == nil {
-			estimate := &CostEstimate{
-				Amount:      result.MonthlyPrice,
-				Explanation: fmt.Sprintf("RDS %s instance: $%.2f/month", resource.Class, result.MonthlyPrice),
-				Formula:     "Monthly Cost = Hourly Rate × 730 hours",
-				FormulaExplanation: "RDS instances are charged per hour, similar to EC2. We multiply by 730 hours for monthly cost.",
-				Breakdown:   map[string]float64{resource.Class: result.MonthlyPrice},
-				Accuracy:    result.Accuracy,
-				Source:      result.Source,
-				FreeTierCovered: result.FreeTierCovered,
-				FreeTierSavings: result.FreeTierSavings,
-				Assumptions: []string{
-					fmt.Sprintf("Pricing from %s", result.Source),
-					"Only available instances are charged",
-					"Excludes storage, backup, and data transfer costs",
-					"Assumes 24/7 usage (730 hours/month)",
-					"Single-AZ deployment pricing",
-				},
-				Examples: []string{
-					"db.t3.micro: $0.0205/hour × 730 hours = $15.00/month",
-					"db.m5.large: $0.234/hour × 730 hours = $171.00/month",
-					"db.r5.large: $0.312/hour × 730 hours = $228.00/month",
-				},
-			}
-
-			// Update explanation for free tier
-			if result.FreeTierCovered {
-				estimate.Explanation = fmt.Sprintf("RDS %s instance: $0.00/month (FREE TIER)", resource.Class)
-				estimate.Amount = 0
-				estimate.Assumptions = append(estimate.Assumptions, "FREE TIER: db.t2.micro instances are free for 750 hours/month during first 12 months")
-			} else if result.FreeTierSavings > 0 {
-				estimate.Explanation = fmt.Sprintf("RDS %s instance: $%.2f/month (FREE TIER saves $%.2f)", resource.Class, result.MonthlyPrice-result.FreeTierSavings, result.FreeTierSavings)
-				estimate.Amount = result.MonthlyPrice - result.FreeTierSavings
-				estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("FREE TIER: Partial coverage saves $%.2f/month", result.FreeTierSavings))
-			}
-
-			return estimate
-		}
-	}
-
-	// Fallback to hardcoded estimates
-	return getFallbackRDSCost(resource)
-}
-
-// getFallbackRDSCost provides fallback pricing when API is unavailable
-func getFallbackRDSCost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      0,
-		Explanation: "RDS costs are based on instance class and availability",
-		Formula:     "Monthly Cost = Hourly Rate × 730 hours",
-		FormulaExplanation: "RDS instances are charged per hour, similar to EC2. We multiply the hourly rate by 730 hours for monthly cost.",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "High",
-		Assumptions: []string{
-			"Based on us-east-1 on-demand pricing",
-			"Only available instances are charged",
-			"Excludes storage, backup, and data transfer costs",
-			"Assumes 24/7 usage (730 hours/month)",
-			"Single-AZ deployment pricing",
-		},
-		Examples: []string{
-			"db.t3.micro: $0.0205/hour × 730 hours = $15.00/month",
-			"db.m5.large: $0.234/hour × 730 hours = $171.00/month",
-			"db.r5.large: $0.312/hour × 730 hours = $228.00/month",
-		},
-	}
-
-	if resource.State != "available" {
-		return estimate
-	}
-
-	// Rough cost estimates per month (us-east-1 pricing)
-	costMap := map[string]float64{
-		"db.t3.micro":    15.00,
-		"db.t3.small":    30.00,
-		"db.t3.medium":   60.00,
-		"db.t3.large":    120.00,
-		"db.m5.large":    171.00,
-		"db.m5.xlarge":   342.00,
-		"db.r5.large":    228.00,
-		"db.r5.xlarge":   456.00,
-	}
-
-	if cost, exists := costMap[resource.Class]; exists {
-		estimate.Amount = cost
-		estimate.Breakdown[resource.Class] = cost
-		estimate.Explanation = fmt.Sprintf("RDS %s instance: $%.2f/month", resource.Class, cost)
-	} else {
-		estimate.Amount = 100.0
-		estimate.Breakdown["unknown"] = 100.0
-		estimate.Explanation = fmt.Sprintf("RDS %s instance: $100.00/month (estimated for unknown instance class)", resource.Class)
-		estimate.Assumptions = append(estimate.Assumptions, "Unknown instance class - using conservative estimate")
-	}
-
-	return estimate
-}
-
-// estimateLambdaCost estimates Lambda function cost (rough monthly estimate)
-func estimateLambdaCost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      5.0, // Conservative estimate
-		Explanation: "Lambda costs are based on function execution and memory usage",
-		Formula:     "Monthly Cost = $5.00 (estimated moderate usage)",
-		FormulaExplanation: "Lambda pricing is complex (requests + duration + memory). Using conservative estimate for moderate usage.",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "Medium",
-		Assumptions: []string{
-			"Estimated moderate usage (1000 requests/month)",
-			"128MB memory allocation",
-			"100ms average execution time",
-			"Conservative estimate for unknown usage patterns",
-		},
-		Examples: []string{
-			"Low usage: $1-3/month",
-			"Moderate usage: $5-10/month",
-			"High usage: $20-50/month",
-		},
-	}
-
-	estimate.Breakdown["estimated"] = estimate.Amount
-	estimate.Explanation = fmt.Sprintf("Lambda function %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
-
-	return estimate
-}
-
-// estimateS3Cost estimates S3 bucket cost (rough monthly estimate)
-func estimateS3Cost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      1.0, // Minimal usage estimate
-		Explanation: "S3 costs are based on storage, requests, and data transfer",
-		Formula:     "Monthly Cost = $1.00 (estimated minimal usage)",
-		FormulaExplanation: "S3 pricing includes storage, requests, and data transfer. Using conservative estimate for minimal usage.",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "Low",
-		Assumptions: []string{
-			"Estimated minimal usage (1GB storage)",
-			"Standard storage class",
-			"Low request volume",
-			"Conservative estimate for unknown usage patterns",
-		},
-		Examples: []string{
-			"Minimal usage: $1-3/month",
-			"Moderate usage: $5-15/month",
-			"High usage: $20-100/month",
-		},
-	}
-
-	estimate.Breakdown["estimated"] = estimate.Amount
-	estimate.Explanation = fmt.Sprintf("S3 bucket %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
-
-	return estimate
-}
-
-// estimateDynamoDBCost estimates DynamoDB table cost (rough monthly estimate)
-func estimateDynamoDBCost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      10.0, // Conservative estimate
-		Explanation: "DynamoDB costs are based on read/write capacity and storage",
-		Formula:     "Monthly Cost = $10.00 (estimated moderate usage)",
-		FormulaExplanation: "DynamoDB pricing includes read/write capacity units and storage. Using conservative estimate for moderate usage.",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "Low",
-		Assumptions: []string{
-			"Estimated moderate read/write capacity",
-			"On-demand billing mode",
-			"Conservative estimate for unknown usage patterns",
-		},
-		Examples: []string{
-			"Low usage: $5-10/month",
-			"Moderate usage: $10-25/month",
-			"High usage: $50-200/month",
-		},
-	}
-
-	estimate.Breakdown["estimated"] = estimate.Amount
-	estimate.Explanation = fmt.Sprintf("DynamoDB table %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
-
-	return estimate
-}
-
-// estimateSFNCost estimates Step Functions cost (rough monthly estimate)
-func estimateSFNCost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      5.0, // Conservative estimate
-		Explanation: "Step Functions costs are based on state transitions and execution time",
-		Formula:     "Monthly Cost = $5.00 (estimated moderate usage)",
-		FormulaExplanation: "Step Functions pricing is based on state transitions and execution time. Using conservative estimate for moderate usage.",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "Low",
-		Assumptions: []string{
-			"Estimated moderate workflow complexity",
-			"Standard workflow execution",
-			"Conservative estimate for unknown usage patterns",
-		},
-		Examples: []string{
-			"Low usage: $2-5/month",
-			"Moderate usage: $5-15/month",
-			"High usage: $20-100/month",
-		},
-	}
-
-	estimate.Breakdown["estimated"] = estimate.Amount
-	estimate.Explanation = fmt.Sprintf("Step Function %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
-
-	return estimate
-}
-
-// estimateCloudWatchCost estimates CloudWatch cost (rough monthly estimate)
-func estimateCloudWatchCost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      2.0, // Conservative estimate
-		Explanation: "CloudWatch costs are based on metrics, logs, and alarms",
-		Formula:     "Monthly Cost = $2.00 (estimated moderate usage)",
-		FormulaExplanation: "CloudWatch pricing includes metrics, logs, and alarms. Using conservative estimate for moderate usage.",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "Low",
-		Assumptions: []string{
-			"Estimated moderate metric resolution",
-			"Standard resolution metrics",
-			"Conservative estimate for unknown usage patterns",
-		},
-		Examples: []string{
-			"Low usage: $1-3/month",
-			"Moderate usage: $2-8/month",
-			"High usage: $10-50/month",
-		},
-	}
-
-	estimate.Breakdown["estimated"] = estimate.Amount
-	estimate.Explanation = fmt.Sprintf("CloudWatch %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
-
-	return estimate
-}
-
-// estimateECSCost estimates ECS cost (rough monthly estimate)
-func estimateECSCost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      0,
-		Explanation: "ECS costs depend on underlying infrastructure (EC2/Fargate)",
-		Formula:     "Monthly Cost = Infrastructure costs + ECS management",
-		FormulaExplanation: "ECS itself is free, but you pay for the underlying infrastructure (EC2 instances or Fargate tasks) plus ECS management overhead.",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "Medium",
-		Assumptions: []string{
-			"ECS service management overhead",
-			"Infrastructure costs handled separately",
-			"Conservative estimate for management overhead",
-		},
-		Examples: []string{
-			"Cluster management: $5-10/month",
-			"Service management: $5-15/month",
-			"Infrastructure: $50-500/month (depends on EC2/Fargate)",
-		},
-	}
-
-	// Different estimates based on resource type
-	switch resource.Type {
-	case "cluster":
-		estimate.Amount = 5.0 // Cluster management overhead
-		estimate.Explanation = fmt.Sprintf("ECS cluster %s: $%.2f/month (management overhead)", resource.Name, estimate.Amount)
-	case "service":
-		estimate.Amount = 15.0 // Service management overhead
-		estimate.Explanation = fmt.Sprintf("ECS service %s: $%.2f/month (management overhead)", resource.Name, estimate.Amount)
-	default:
-		estimate.Amount = 10.0 // Default estimate
-		estimate.Explanation = fmt.Sprintf("ECS %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
-	}
-
-	estimate.Breakdown["management"] = estimate.Amount
-
-	return estimate
-}
-
-// estimateRedisCost estimates Redis (ElastiCache) cost (rough monthly estimate)
-func estimateRedisCost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      0,
-		Explanation: "Redis costs are based on node type and availability",
-		Formula:     "Monthly Cost = Hourly Rate × 730 hours",
-		FormulaExplanation: "ElastiCache Redis instances are charged per hour, similar to EC2. We multiply the hourly rate by 730 hours for monthly cost.",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "High",
-		Assumptions: []string{
-			"Based on us-east-1 on-demand pricing",
-			"Only available instances are charged",
-			"Excludes data transfer and backup costs",
-			"Assumes 24/7 usage (730 hours/month)",
-			"Single-node deployment pricing",
-		},
-		Examples: []string{
-			"cache.t3.micro: $0.017/hour × 730 hours = $12.41/month",
-			"cache.t3.small: $0.034/hour × 730 hours = $24.82/month",
-			"cache.m5.large: $0.136/hour × 730 hours = $99.28/month",
-		},
-	}
-
-	if resource.State != "available" {
-		return estimate
-	}
-
-	// Rough cost estimates per month (us-east-1 pricing)
-	costMap := map[string]float64{
-		"cache.t3.micro":    12.41,
-		"cache.t3.small":    24.82,
-		"cache.t3.medium":   49.64,
-		"cache.t3.large":    99.28,
-		"cache.m5.large":    99.28,
-		"cache.m5.xlarge":   198.56,
-		"cache.r5.large":    145.60,
-		"cache.r5.xlarge":   291.20,
-		"cache.c5.large":    81.60,
-		"cache.c5.xlarge":   163.20,
-	}
-
-	if cost, exists := costMap[resource.Class]; exists {
-		estimate.Amount = cost
-		estimate.Breakdown[resource.Class] = cost
-		estimate.Explanation = fmt.Sprintf("Redis %s instance: $%.2f/month", resource.Class, cost)
-	} else {
-		estimate.Amount = 50.0
-		estimate.Breakdown["unknown"] = 50.0
-		estimate.Explanation = fmt.Sprintf("Redis %s instance: $50.00/month (estimated for unknown node type)", resource.Class)
-		estimate.Assumptions = append(estimate.Assumptions, "Unknown node type - using conservative estimate")
-	}
-
-	return estimate
-}
-
-// estimateEFSCost estimates EFS file system cost (rough monthly estimate)
-func estimateEFSCost(resource models.Resource) *CostEstimate {
-	estimate := &CostEstimate{
-		Amount:      0,
-		Explanation: "EFS costs are based on storage usage and throughput mode",
-		Formula:     "Monthly Cost = Storage × $0.30/GB + Throughput costs",
-		FormulaExplanation: "EFS pricing includes storage costs ($0.30/GB/month) plus throughput costs based on mode (Provisioned or Bursting).",
-		Breakdown:   make(map[string]float64),
-		Accuracy:    "Medium",
-		Source:      "fallback",
-		Assumptions: []string{
-			"Based on us-east-1 pricing",
-			"Standard storage class",
-			"Estimated storage usage",
-			"Conservative throughput estimate",
-		},
-		Examples: []string{
-			"Small file system (10GB): $3.00/month",
-			"Medium file system (100GB): $30.00/month",
-			"Large file system (1TB): $300.00/month",
-		},
-	}
-
-	// Only charge for available file systems
-	if resource.State != "available" {
-		estimate.Explanation = fmt.Sprintf("EFS %s: $0.00/month (not available)", resource.Name)
-		estimate.Source = "state-check"
-		return estimate
-	}
-
-	// Estimate storage size based on extra data or use default
-	var storageGB float64 = 50.0 // Default estimate
-	
-	if sizeBytes, exists := resource.Extra["sizeBytes"]; exists {
-		if size, ok := sizeBytes.(map[string]interface{}); ok {
-			if value, ok := size["Value"]; ok {
-				if val, ok := value.(float64); ok {
-					storageGB = val / (1024 * 1024 * 1024) // Convert bytes to GB
-				}
-			}
-		}
-	}
-
-	// Calculate storage cost ($0.30/GB/month)
-	storageCost := storageGB * 0.30
-
-	// Add throughput cost based on mode
-	var throughputCost float64
-	switch resource.Class {
-	case "provisioned":
-		throughputCost = 5.0 // Provisioned throughput has additional cost
-	case "bursting":
-		throughputCost = 0.0 // Bursting is included in storage cost
-	default:
-		throughputCost = 2.0 // Conservative estimate for unknown mode
-	}
-
-	totalCost := storageCost + throughputCost
-
-	estimate.Amount = totalCost
-	estimate.Breakdown["storage"] = storageCost
-	estimate.Breakdown["throughput"] = throughputCost
-	estimate.Explanation = fmt.Sprintf("EFS %s: $%.2f/month (%.0fGB storage)", resource.Name, totalCost, storageGB)
-
-	// Add performance mode info
-	if resource.Type == "maxIO" {
-		estimate.Assumptions = append(estimate.Assumptions, "Max I/O performance mode")
-	} else if resource.Type == "generalPurpose" {
-		estimate.Assumptions = append(estimate.Assumptions, "General Purpose performance mode")
-	}
-
-	return estimate
-} 
\ No newline at end of file
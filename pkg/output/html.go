@@ -1,33 +1,110 @@
 package output
 
 import (
+	"encoding/base64"
+	"fmt"
 	"html/template"
+	"io"
+	"net/http"
 	"os"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/xiaochen/awsinv/pkg/findings"
 	"github.com/xiaochen/awsinv/pkg/models"
 	"github.com/xiaochen/awsinv/pkg/pricing"
 )
 
+// HTML report themes
+const (
+	HTMLThemeLight = "light"
+	HTMLThemeDark  = "dark"
+	HTMLThemeAuto  = "auto" // follows the viewer's system preference
+)
+
+// defaultHTMLTitle is the report title used when no title is configured
+const defaultHTMLTitle = "AWS Resource Inventory"
+
+// HTMLOptions controls the appearance of the HTML report
+type HTMLOptions struct {
+	Options
+
+	// Theme is the initial theme: light, dark or auto. Viewers can switch themes with
+	// the toggle in the report header.
+	Theme string
+
+	// Title replaces the report title
+	Title string
+
+	// Logo is an image URL, or a local image file that is embedded in the report
+	Logo string
+}
+
 // HTMLFormatter formats output as HTML
 type HTMLFormatter struct {
-	writer *os.File
+	writer  io.Writer
+	options HTMLOptions
 }
 
-// NewHTMLFormatter creates a new HTML formatter
+// NewHTMLFormatter creates a new HTML formatter writing to a file
 func NewHTMLFormatter(writer *os.File) *HTMLFormatter {
+	return NewHTMLFormatterWithWriter(writer)
+}
+
+// NewHTMLFormatterWithWriter creates a new HTML formatter writing to any io.Writer
+func NewHTMLFormatterWithWriter(writer io.Writer) *HTMLFormatter {
 	return &HTMLFormatter{writer: writer}
 }
 
+// NewHTMLFormatterWithOptions creates a new HTML formatter with the given theme and branding
+func NewHTMLFormatterWithOptions(writer io.Writer, options HTMLOptions) (*HTMLFormatter, error) {
+	switch options.Theme {
+	case "":
+		options.Theme = HTMLThemeLight
+	case HTMLThemeLight, HTMLThemeDark, HTMLThemeAuto:
+	default:
+		return nil, fmt.Errorf("invalid HTML theme: %s (expected light, dark or auto)", options.Theme)
+	}
+
+	// Local logos are embedded so the report stays a single self-contained file
+	if options.Logo != "" && !strings.Contains(options.Logo, "://") && !strings.HasPrefix(options.Logo, "data:") {
+		logo, err := embedLogo(options.Logo)
+		if err != nil {
+			return nil, err
+		}
+		options.Logo = logo
+	}
+
+	return &HTMLFormatter{writer: writer, options: options}, nil
+}
+
+// embedLogo reads an image file and returns it as a data URL
+func embedLogo(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read logo: %w", err)
+	}
+
+	contentType := http.DetectContentType(data)
+	if strings.HasSuffix(strings.ToLower(path), ".svg") {
+		contentType = "image/svg+xml"
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return "", fmt.Errorf("logo %s is not an image (%s)", path, contentType)
+	}
+
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
+
 // Format formats the collection as HTML
 func (f *HTMLFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
-	// Apply filters
-	resources := applyFilters(collection.Resources, filters)
-
-	// Sort resources
-	sortResources(resources, sortField)
+	// Calculate cost estimates, apply filters and sort
+	costPeriod := f.options.costPeriod()
+	resources, costEstimates := prepareResources(collection, filters, sortField, f.options.Options)
+	found := findings.Evaluate(resources)
+	compliance := tagCompliance(resources, f.options.requiredTags())
+	orphans, wasted := orphanedResources(collection, resources, costEstimates)
 
 	// Create template with custom functions
 	funcMap := template.FuncMap{
@@ -55,6 +132,14 @@ func (f *HTMLFormatter) Format(collection *models.ResourceCollection, filters []
 			return result
 		},
 		"upper": strings.ToUpper,
+		"join":  strings.Join,
+		// Costs are in the cost period
+		"money": costPeriod.Format,
+		"amount": func(amount float64) string {
+			return strings.TrimPrefix(costPeriod.Format(amount), "$")
+		},
+		"costTitle": costPeriod.Title,
+		"costUnit":  costPeriod.Unit,
 		"eq": func(a, b string) bool {
 			return a == b
 		},
@@ -86,42 +171,17 @@ func (f *HTMLFormatter) Format(collection *models.ResourceCollection, filters []
 	// Create resource data with cost estimates
 	type ResourceWithCost struct {
 		models.Resource
-		CostEstimate *CostEstimate
+		CostEstimate *models.CostEstimate
 	}
 	
 	var resourcesWithCost []ResourceWithCost
 	for _, resource := range resources {
-		var costEstimate *CostEstimate
-		switch resource.Service {
-		case "ec2":
-			costEstimate = estimateEC2Cost(resource)
-		case "rds":
-			costEstimate = estimateRDSCost(resource)
-		case "lambda":
-			costEstimate = estimateLambdaCost(resource)
-		case "s3":
-			costEstimate = estimateS3Cost(resource)
-		case "dynamodb":
-			costEstimate = estimateDynamoDBCost(resource)
-		case "sfn":
-			costEstimate = estimateSFNCost(resource)
-		case "cloudwatch":
-			costEstimate = estimateCloudWatchCost(resource)
-		case "ecs":
-			costEstimate = estimateECSCost(resource)
-		case "redis":
-			costEstimate = estimateRedisCost(resource)
-		}
-		
 		resourcesWithCost = append(resourcesWithCost, ResourceWithCost{
 			Resource:     resource,
-			CostEstimate: costEstimate,
+			CostEstimate: costEstimates[resource.Key()],
 		})
 	}
 
-	// Calculate cost estimates for summary
-	costEstimates := calculateCostEstimates(resources)
-
 	// Calculate unique regions with resources
 	uniqueRegions := make(map[string]bool)
 	for _, resource := range resources {
@@ -162,11 +222,15 @@ func (f *HTMLFormatter) Format(collection *models.ResourceCollection, filters []
 	})
 
 	// Get free tier information
-	var freeTierInfo map[string]pricing.FreeTierUsage
-	var freeTierEligible bool
-	if globalPricingService != nil {
-		freeTierInfo = globalPricingService.GetFreeTierInfo()
-		freeTierEligible = globalPricingService.IsFreeTierEligible()
+	freeTierInfo, freeTierEligible := f.options.costEngine().FreeTier()
+
+	theme := f.options.Theme
+	if theme == "" {
+		theme = HTMLThemeLight
+	}
+	title := f.options.Title
+	if title == "" {
+		title = defaultHTMLTitle
 	}
 
 	// Prepare data for template
@@ -174,37 +238,86 @@ func (f *HTMLFormatter) Format(collection *models.ResourceCollection, filters []
 		Resources           []ResourceWithCost
 		Summary            models.Summary
 		Errors             []string
-		CostEstimates      map[string]*CostEstimate
+		Warnings           []string
+		CostEstimates      map[string]*models.CostEstimate
 		GeneratedAt        time.Time
 		RegionsWithResources int
 		SortedServiceCosts []ServiceCost
 		FreeTierInfo       map[string]pricing.FreeTierUsage
 		FreeTierEligible   bool
+		FreeTierSavings    float64
+		Theme              string
+		Title              string
+		Logo               template.URL
+		Comparison         *models.Comparison
+		Budgets            []models.BudgetStatus
+		TopCosts           []topCost
+		Ages               []serviceAge
+		AgeBuckets         []string
+		Recommendations    []models.Recommendation
+		Findings           []findings.Finding
+		FindingSummary     string
+		TagCompliance      *models.TagCompliance
+		TagCoverage        []tagCoverageGroup
+		Orphans            []orphan
+		OrphanCost         float64
 	}{
 		Resources:           resourcesWithCost,
 		Summary:            collection.Summary,
 		Errors:             collection.Errors,
+		Warnings:           collection.Warnings,
 		CostEstimates:      costEstimates,
 		GeneratedAt:        time.Now(),
 		RegionsWithResources: regionsWithResources,
 		SortedServiceCosts: sortedServiceCosts,
 		FreeTierInfo:       freeTierInfo,
 		FreeTierEligible:   freeTierEligible,
+		FreeTierSavings:    freeTierSavings(costEstimates),
+		Theme:              theme,
+		Title:              title,
+		// The logo is configured by the user running the report, not by resource data
+		Logo:       template.URL(f.options.Logo),
+		Comparison: filteredComparison(collection, filters, f.options.Options),
+		Budgets:    budgetStatuses(collection, resources, costPeriod),
+		TopCosts:   topCosts(resources, costEstimates, topCostCount),
+		Ages:       servicesByAge(resources),
+		AgeBuckets: models.AgeBuckets,
+		// Recommendations scale with the estimates, so they are in the cost period
+		Recommendations: pricing.Rightsize(resources, costEstimates),
+		Findings:        found,
+		FindingSummary:  severitySummary(found),
+		TagCompliance:   compliance,
+		TagCoverage:     tagCoverageGroups(compliance),
+		Orphans:         orphans,
+		OrphanCost:      wasted,
 	}
 
 	// Execute template
 	return tmpl.Execute(f.writer, data)
 }
 
-// CostEstimate represents a cost estimate with explanation
-
 // HTML template for the inventory report
 const htmlTemplate = `<!DOCTYPE html>
-<html lang="en">
+<html lang="en" data-theme="light">
 <head>
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <title>AWS Resource Inventory</title>
+    <title>{{.Title}}</title>
+    <script>
+        // Apply the saved or configured theme before the page renders
+        (function() {
+            var theme = {{.Theme}};
+            try {
+                theme = localStorage.getItem('awsinv-theme') || theme;
+            } catch (e) {
+                // Storage can be unavailable for files opened from disk
+            }
+            if (theme === 'auto') {
+                theme = window.matchMedia && window.matchMedia('(prefers-color-scheme: dark)').matches ? 'dark' : 'light';
+            }
+            document.documentElement.setAttribute('data-theme', theme);
+        })();
+    </script>
     <style>
         body {
             font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif;
@@ -236,6 +349,29 @@ const htmlTemplate = `<!DOCTYPE html>
             margin: 10px 0 0 0;
             opacity: 0.9;
         }
+        .header {
+            position: relative;
+        }
+        .header-logo {
+            max-height: 60px;
+            max-width: 240px;
+            margin-bottom: 10px;
+        }
+        .theme-toggle {
+            position: absolute;
+            top: 15px;
+            right: 15px;
+            background: rgba(255,255,255,0.2);
+            color: white;
+            border: 1px solid rgba(255,255,255,0.4);
+            border-radius: 4px;
+            padding: 4px 10px;
+            cursor: pointer;
+            font-size: 0.9em;
+        }
+        .theme-toggle:hover {
+            background: rgba(255,255,255,0.3);
+        }
         .summary {
             padding: 30px;
             border-bottom: 1px solid #eee;
@@ -486,6 +622,15 @@ const htmlTemplate = `<!DOCTYPE html>
         .btn:hover {
             opacity: 0.8;
         }
+        .btn-small {
+            padding: 4px 10px;
+            font-size: 0.8em;
+        }
+        .group-actions {
+            display: flex;
+            align-items: center;
+            gap: 12px;
+        }
         .resource-groups {
             display: flex;
             flex-direction: column;
@@ -890,6 +1035,7 @@ const htmlTemplate = `<!DOCTYPE html>
         .state-running, .state-available { background: #d4edda; color: #155724; }
         .state-stopped, .state-stopping { background: #f8d7da; color: #721c24; }
         .state-pending { background: #fff3cd; color: #856404; }
+        .state-unassociated { background: #fff3cd; color: #856404; }
         .errors {
             background: #f8d7da;
             color: #721c24;
@@ -904,6 +1050,99 @@ const htmlTemplate = `<!DOCTYPE html>
             margin: 0;
             padding-left: 20px;
         }
+        .errors.warnings {
+            background: #fff3cd;
+            color: #856404;
+        }
+        .budgets {
+            background: #e8f5e9;
+            padding: 20px;
+            border-radius: 6px;
+            margin: 20px 0;
+        }
+        .budgets h3 {
+            margin: 0 0 15px 0;
+        }
+        .budgets ul {
+            margin: 0;
+            padding-left: 20px;
+        }
+        .budgets .over-budget {
+            color: #721c24;
+            font-weight: bold;
+        }
+        .top-costs {
+            background: #fdf2e9;
+            padding: 20px;
+            border-radius: 6px;
+            margin: 20px 0;
+        }
+        .top-costs h3 {
+            margin: 0 0 15px 0;
+        }
+        .top-costs table {
+            width: 100%;
+            border-collapse: collapse;
+        }
+        .top-costs th,
+        .top-costs td {
+            text-align: left;
+            padding: 6px 10px;
+            border-bottom: 1px solid rgba(0, 0, 0, 0.08);
+        }
+        .top-costs td.cost {
+            text-align: right;
+            font-weight: bold;
+        }
+        .rightsizing {
+            background: #eef7ee;
+        }
+        .findings {
+            background: #fdecea;
+        }
+        .findings .severity {
+            font-weight: bold;
+            text-transform: uppercase;
+        }
+        .findings .severity-critical,
+        .findings .severity-high {
+            color: #721c24;
+        }
+        .tag-compliance {
+            background: #fff8e1;
+        }
+        .tag-compliance h4 {
+            margin: 15px 0 8px 0;
+        }
+        .comparison {
+            background: #e7f1ff;
+            padding: 20px;
+            border-radius: 6px;
+            margin: 20px 0;
+        }
+        .comparison h3 {
+            margin: 0 0 15px 0;
+            color: #0b5394;
+        }
+        .comparison-grid {
+            display: grid;
+            grid-template-columns: repeat(auto-fit, minmax(300px, 1fr));
+            gap: 20px;
+        }
+        .comparison h4 {
+            margin: 0 0 10px 0;
+        }
+        .comparison ul {
+            margin: 0;
+            padding-left: 20px;
+            max-height: 300px;
+            overflow-y: auto;
+            font-size: 0.9em;
+        }
+        .comparison .none {
+            color: #6c757d;
+            font-style: italic;
+        }
         .footer {
             background: #f8f9fa;
             padding: 20px 30px;
@@ -911,12 +1150,129 @@ const htmlTemplate = `<!DOCTYPE html>
             color: #6c757d;
             font-size: 0.9em;
         }
+
+        /* Dark theme */
+        [data-theme="dark"] body {
+            background-color: #121212;
+            color: #e0e0e0;
+        }
+        [data-theme="dark"] .container,
+        [data-theme="dark"] .cost-summary,
+        [data-theme="dark"] .cost-item,
+        [data-theme="dark"] .cost-breakdown-by-service,
+        [data-theme="dark"] .resource-table,
+        [data-theme="dark"] .free-tier-service {
+            background: #1e1e1e;
+            color: #e0e0e0;
+        }
+        [data-theme="dark"] .summary {
+            border-bottom-color: #333;
+        }
+        [data-theme="dark"] .summary-card,
+        [data-theme="dark"] .group-header,
+        [data-theme="dark"] .accuracy-legend,
+        [data-theme="dark"] .cost-item .assumptions,
+        [data-theme="dark"] .formula,
+        [data-theme="dark"] .footer,
+        [data-theme="dark"] .resource-table th {
+            background: #2a2a2a;
+            color: #e0e0e0;
+        }
+        [data-theme="dark"] .group-header:hover,
+        [data-theme="dark"] .resource-table th:hover,
+        [data-theme="dark"] .resource-table tbody tr:hover {
+            background: #333;
+        }
+        [data-theme="dark"] .resource-group {
+            border-color: #333;
+        }
+        [data-theme="dark"] .resource-table td {
+            border-bottom-color: #333;
+        }
+        [data-theme="dark"] .cost-estimates,
+        [data-theme="dark"] .free-tier-info {
+            background: #1f2d1f;
+        }
+        [data-theme="dark"] .summary-card h3,
+        [data-theme="dark"] .summary-card .value,
+        [data-theme="dark"] .cost-item .service,
+        [data-theme="dark"] .total-cost .label,
+        [data-theme="dark"] .resources h2,
+        [data-theme="dark"] .accuracy-legend h4,
+        [data-theme="dark"] .legend-text,
+        [data-theme="dark"] .cost-breakdown-by-service h4,
+        [data-theme="dark"] .formula-section h4,
+        [data-theme="dark"] .examples-section h4,
+        [data-theme="dark"] .assumptions-section h4,
+        [data-theme="dark"] .examples-list li {
+            color: #e0e0e0;
+        }
+        [data-theme="dark"] .resource-count,
+        [data-theme="dark"] .cost-item .explanation,
+        [data-theme="dark"] .formula-explanation,
+        [data-theme="dark"] .free-tier-service .free-tier-note,
+        [data-theme="dark"] .footer {
+            color: #9e9e9e;
+        }
+        [data-theme="dark"] .cost-estimates h3,
+        [data-theme="dark"] .free-tier-info h4,
+        [data-theme="dark"] .free-tier-eligible,
+        [data-theme="dark"] .free-tier-services h5,
+        [data-theme="dark"] .free-tier-service {
+            color: #81c784;
+        }
+        [data-theme="dark"] .cost-cell,
+        [data-theme="dark"] .cost-item .amount,
+        [data-theme="dark"] .total-cost .amount,
+        [data-theme="dark"] .service-cost {
+            color: #66bb6a;
+        }
+        [data-theme="dark"] .budgets {
+            background: #1b2a1c;
+        }
+        [data-theme="dark"] .budgets .over-budget {
+            color: #ef9a9a;
+        }
+        [data-theme="dark"] .top-costs {
+            background: #2d2218;
+        }
+        [data-theme="dark"] .rightsizing {
+            background: #1b2a1c;
+        }
+        [data-theme="dark"] .findings {
+            background: #2e1c1c;
+        }
+        [data-theme="dark"] .findings .severity-critical,
+        [data-theme="dark"] .findings .severity-high {
+            color: #ef9a9a;
+        }
+        [data-theme="dark"] .tag-compliance {
+            background: #2d2a18;
+        }
+        [data-theme="dark"] .top-costs th,
+        [data-theme="dark"] .top-costs td {
+            border-bottom-color: rgba(255, 255, 255, 0.1);
+        }
+        [data-theme="dark"] .comparison {
+            background: #1a2633;
+        }
+        [data-theme="dark"] .comparison h3 {
+            color: #90caf9;
+        }
+        [data-theme="dark"] .resource-table::-webkit-scrollbar-track {
+            background: #2a2a2a;
+        }
+        [data-theme="dark"] .resource-table::-webkit-scrollbar-thumb {
+            background: #555;
+        }
     </style>
 </head>
 <body>
     <div class="container">
         <div class="header">
-            <h1>AWS Resource Inventory</h1>
+            <button class="theme-toggle" onclick="toggleTheme()" title="Switch between light and dark mode">◐ Theme</button>
+            {{if .Logo}}<img class="header-logo" src="{{.Logo}}" alt="Logo">{{end}}
+            <h1>{{.Title}}</h1>
             <p>Generated on {{.GeneratedAt.Format "January 2, 2006 at 3:04 PM MST"}}</p>
         </div>
 
@@ -970,7 +1326,10 @@ const htmlTemplate = `<!DOCTYPE html>
                         {{if .FreeTierEligible}}
                         <p class="free-tier-eligible">✅ <strong>Your account is eligible for AWS Free Tier benefits!</strong></p>
                         {{else}}
-                        <p class="free-tier-not-eligible">❌ <strong>Your account is not eligible for free tier benefits</strong> (account is over 12 months old)</p>
+                        <p class="free-tier-not-eligible">❌ <strong>Your account has no 12-month free tier benefits left</strong></p>
+                        {{end}}
+                        {{if gt .FreeTierSavings 0}}
+                        <p>The free tier saves <strong>{{money .FreeTierSavings}}{{costUnit}}</strong> on the estimates below.</p>
                         {{end}}
                     </div>
                     
@@ -982,21 +1341,17 @@ const htmlTemplate = `<!DOCTYPE html>
                             <div class="free-tier-service">
                                 <div class="service-name">{{.Service | upper}}</div>
                                 {{if gt .RemainingHours 0}}
-                                <div class="remaining">{{printf "%.0f" .RemainingHours}} hours/month</div>
+                                <div class="remaining">{{printf "%.0f" .RemainingHours}} hours left this month</div>
                                 {{end}}
                                 {{if gt .RemainingGB 0}}
-                                <div class="remaining">{{printf "%.0f" .RemainingGB}} GB storage</div>
+                                <div class="remaining">{{printf "%.0f" .RemainingGB}} GB-months of storage left</div>
                                 {{end}}
                                 {{if gtInt .RemainingRequests 0}}
-                                <div class="remaining">{{.RemainingRequests}} requests/month</div>
+                                <div class="remaining">{{.RemainingRequests}} requests left this month</div>
+                                {{end}}
+                                {{range .Allowances}}
+                                <div class="free-tier-note">{{.Description}}: {{printf "%.0f" .Used}} of {{printf "%.0f" .Limit}} {{.Unit}} used</div>
                                 {{end}}
-                                <div class="free-tier-note">
-                                    {{if eq .Service "ec2"}}Free t2.micro instances{{end}}
-                                    {{if eq .Service "rds"}}Free db.t2.micro instances{{end}}
-                                    {{if eq .Service "lambda"}}Free function executions{{end}}
-                                    {{if eq .Service "s3"}}Free storage & requests{{end}}
-                                    {{if eq .Service "dynamodb"}}Free storage & throughput{{end}}
-                                </div>
                             </div>
                             {{end}}
                         </div>
@@ -1007,8 +1362,8 @@ const htmlTemplate = `<!DOCTYPE html>
                 
                 <div class="cost-summary">
                     <div class="total-cost">
-                        <span class="label">Total Estimated Monthly Cost:</span>
-                        <span class="amount">${{$total := 0.0}}{{range $service, $estimate := .CostEstimates}}{{$total = add $total $estimate.Amount}}{{end}}{{printf "%.2f" $total}}</span>
+                        <span class="label">Total Estimated {{costTitle}} Cost:</span>
+                        <span class="amount">${{$total := 0.0}}{{range $service, $estimate := .CostEstimates}}{{$total = add $total $estimate.Amount}}{{end}}{{amount $total}}</span>
                     </div>
                 </div>
                 
@@ -1018,7 +1373,7 @@ const htmlTemplate = `<!DOCTYPE html>
                         {{range .SortedServiceCosts}}
                         <div class="cost-service-card">
                             <div class="service-name">{{.Service | upper}}</div>
-                            <div class="service-amount">${{printf "%.2f" .Amount}}</div>
+                            <div class="service-amount">{{money .Amount}}</div>
                             <div class="service-count">{{.Count}} resources</div>
                             {{$accuracy := "Low"}}
                             {{if eq .Service "ec2"}}{{$accuracy = "High"}}{{else if eq .Service "rds"}}{{$accuracy = "High"}}{{else if eq .Service "redis"}}{{$accuracy = "High"}}{{else if eq .Service "lambda"}}{{$accuracy = "Medium"}}{{else if eq .Service "ecs"}}{{$accuracy = "Medium"}}{{else}}{{$accuracy = "Low"}}{{end}}
@@ -1040,6 +1395,259 @@ const htmlTemplate = `<!DOCTYPE html>
             </div>
             {{end}}
 
+            {{if .Budgets}}
+            <div class="budgets">
+                <h3>💰 Budgets</h3>
+                <ul>
+                    {{range .Budgets}}
+                    <li{{if .Over}} class="over-budget"{{end}}><strong>{{.Label}}</strong>: {{money .Spend}} of {{money .Limit}}{{costUnit}}, {{.Resources}} resources{{if .Over}} — over budget{{end}}</li>
+                    {{end}}
+                </ul>
+            </div>
+            {{end}}
+
+            {{if .TopCosts}}
+            <div class="top-costs">
+                <h3>💸 Top {{len .TopCosts}} Most Expensive Resources</h3>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Service</th>
+                            <th>Region</th>
+                            <th>ID</th>
+                            <th>Name</th>
+                            <th>Owner</th>
+                            <th>{{costTitle}} Cost</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .TopCosts}}
+                        <tr>
+                            <td>{{.Resource.Service}}</td>
+                            <td>{{.Resource.Region}}</td>
+                            <td>{{.Resource.ID}}</td>
+                            <td>{{.Resource.Name}}</td>
+                            <td>{{if .Owner}}{{.Owner}}{{else}}-{{end}}</td>
+                            <td class="cost">{{money .Cost}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+            {{end}}
+
+            {{if .Ages}}
+            <div class="top-costs">
+                <h3>⏳ Resource Age</h3>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Service</th>
+                            {{range $.AgeBuckets}}
+                            <th>{{.}}</th>
+                            {{end}}
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .Ages}}
+                        <tr>
+                            <td>{{.Service}}</td>
+                            {{range .Counts}}
+                            <td>{{.}}</td>
+                            {{end}}
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+            {{end}}
+
+            {{if .Recommendations}}
+            <div class="top-costs rightsizing">
+                <h3>📉 Rightsizing Recommendations</h3>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Service</th>
+                            <th>Region</th>
+                            <th>ID</th>
+                            <th>Current</th>
+                            <th>Recommended</th>
+                            <th>Reason</th>
+                            <th>{{costTitle}} Savings</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .Recommendations}}
+                        <tr>
+                            <td>{{.Service}}</td>
+                            <td>{{.Region}}</td>
+                            <td>{{.ID}}</td>
+                            <td>{{.Current}}</td>
+                            <td>{{.Recommended}}</td>
+                            <td>{{.Reason}}</td>
+                            <td class="cost">{{money .Savings}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+            {{end}}
+
+            {{if .Findings}}
+            <div class="top-costs findings">
+                <h3>🔒 Security Findings ({{.FindingSummary}})</h3>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Severity</th>
+                            <th>Rule</th>
+                            <th>Service</th>
+                            <th>Region</th>
+                            <th>ID</th>
+                            <th>Finding</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .Findings}}
+                        <tr>
+                            <td class="severity severity-{{.Severity}}">{{.Severity}}</td>
+                            <td>{{.RuleID}}</td>
+                            <td>{{.Service}}</td>
+                            <td>{{.Region}}</td>
+                            <td>{{.ResourceID}}{{if and .ResourceName (ne .ResourceName .ResourceID)}} ({{.ResourceName}}){{end}}</td>
+                            <td>{{.Message}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+            {{end}}
+
+            {{if .Orphans}}
+            <div class="top-costs orphans">
+                <h3>🧹 Orphaned Resources ({{len .Orphans}}, {{money .OrphanCost}}{{costUnit}} wasted)</h3>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Service</th>
+                            <th>Region</th>
+                            <th>ID</th>
+                            <th>Name</th>
+                            <th>Reason</th>
+                            <th>{{costTitle}} Cost</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .Orphans}}
+                        <tr>
+                            <td>{{.Service}}</td>
+                            <td>{{.Region}}</td>
+                            <td>{{.ID}}</td>
+                            <td>{{.Name}}</td>
+                            <td>{{.Reason}}</td>
+                            <td class="cost">{{money .Cost}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+            </div>
+            {{end}}
+
+            {{with .TagCompliance}}
+            <div class="top-costs tag-compliance">
+                <h3>🏷️ Tag Compliance: {{.Total.Compliant}}/{{.Total.Resources}} ({{printf "%.1f" .Total.Coverage}}%)</h3>
+                <p>Required tags: {{join .RequiredTags ", "}}</p>
+                {{range $.TagCoverage}}
+                {{if .Coverage}}
+                <h4>By {{.Title}}</h4>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>{{.Title}}</th>
+                            <th>Resources</th>
+                            <th>Compliant</th>
+                            <th>Coverage</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .Coverage}}
+                        <tr>
+                            <td>{{.Name}}</td>
+                            <td>{{.Resources}}</td>
+                            <td>{{.Compliant}}</td>
+                            <td class="cost">{{printf "%.1f" .Coverage}}%</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+                {{end}}
+                {{end}}
+                {{if .NonCompliant}}
+                <h4>Missing Tags ({{len .NonCompliant}})</h4>
+                <table>
+                    <thead>
+                        <tr>
+                            <th>Service</th>
+                            <th>Region</th>
+                            <th>ID</th>
+                            <th>Missing Tags</th>
+                        </tr>
+                    </thead>
+                    <tbody>
+                        {{range .NonCompliant}}
+                        <tr>
+                            <td>{{.Service}}</td>
+                            <td>{{.Region}}</td>
+                            <td>{{.ID}}{{if and .Name (ne .Name .ID)}} ({{.Name}}){{end}}</td>
+                            <td>{{join .Missing ", "}}</td>
+                        </tr>
+                        {{end}}
+                    </tbody>
+                </table>
+                {{end}}
+            </div>
+            {{end}}
+
+            {{with .Comparison}}
+            <div class="comparison">
+                <h3>🔄 Changes since {{.Snapshot}} ({{.ComparedTo.Format "2006-01-02 15:04 MST"}})</h3>
+                <div class="comparison-grid">
+                    <div>
+                        <h4>🆕 New since last scan ({{len .New}})</h4>
+                        {{if .New}}
+                        <ul>
+                            {{range .New}}
+                            <li><strong>{{.Service}}</strong> {{.Region}} {{.ID}}{{if and .Name (ne .Name .ID)}} ({{.Name}}){{end}}</li>
+                            {{end}}
+                        </ul>
+                        {{else}}
+                        <p class="none">None</p>
+                        {{end}}
+                    </div>
+                    <div>
+                        <h4>👻 Disappeared since last scan ({{len .Disappeared}})</h4>
+                        {{if .Disappeared}}
+                        <ul>
+                            {{range .Disappeared}}
+                            <li><strong>{{.Service}}</strong> {{.Region}} {{.ID}}{{if and .Name (ne .Name .ID)}} ({{.Name}}){{end}}</li>
+                            {{end}}
+                        </ul>
+                        {{else}}
+                        <p class="none">None</p>
+                        {{end}}
+                    </div>
+                </div>
+            </div>
+            {{end}}
+
+            {{if .Summary.Partial}}
+            <div class="errors">
+                <h3>Partial inventory</h3>
+                <p>Collection stopped before every service and region finished; see the errors below.</p>
+            </div>
+            {{end}}
+
             {{if .Errors}}
             <div class="errors">
                 <h3>Errors ({{len .Errors}})</h3>
@@ -1050,6 +1658,17 @@ const htmlTemplate = `<!DOCTYPE html>
                 </ul>
             </div>
             {{end}}
+
+            {{if .Warnings}}
+            <div class="errors warnings">
+                <h3>Warnings ({{len .Warnings}})</h3>
+                <ul>
+                    {{range .Warnings}}
+                    <li>{{.}}</li>
+                    {{end}}
+                </ul>
+            </div>
+            {{end}}
         </div>
 
         {{if .Resources}}
@@ -1059,6 +1678,7 @@ const htmlTemplate = `<!DOCTYPE html>
                 <div class="resource-controls">
                     <button class="btn btn-primary" onclick="expandAll()">Expand All</button>
                     <button class="btn btn-secondary" onclick="collapseAll()">Collapse All</button>
+                    <button class="btn btn-primary" onclick="exportAllCSV()" title="Download all rows currently shown as CSV">⬇ Export CSV</button>
                 </div>
             </div>
             
@@ -1071,14 +1691,17 @@ const htmlTemplate = `<!DOCTYPE html>
                         <span class="service-badge service-{{$service}}">{{$service | upper}}</span>
                         <span class="resource-count">{{$count := 0}}{{range $.Resources}}{{if eq .Service $service}}{{$count = addInt $count 1}}{{end}}{{end}}({{$count}} resources)</span>
                         {{$serviceCost := 0.0}}{{range $.Resources}}{{if eq .Service $service}}{{if .CostEstimate}}{{$serviceCost = add $serviceCost .CostEstimate.Amount}}{{end}}{{end}}{{end}}
-                        <span class="service-cost">${{printf "%.2f" $serviceCost}}/month</span>
+                        <span class="service-cost">{{money $serviceCost}}{{costUnit}}</span>
+                    </div>
+                    <div class="group-actions">
+                        <button class="btn btn-secondary btn-small" onclick="event.stopPropagation(); exportGroupCSV('{{$service}}')" title="Download this group as CSV">⬇ CSV</button>
+                        <div class="group-toggle">▼</div>
                     </div>
-                    <div class="group-toggle">▼</div>
                 </div>
                     <div class="group-content" id="group-{{$service}}">
                         <div class="resource-table" style="position: relative;">
                             <div class="table-scroll-hint">← Scroll to see more columns →</div>
-                            <table>
+                            <table data-service="{{$service}}">
                                 <thead>
                                     <tr>
                                         <th>Region</th>
@@ -1088,7 +1711,7 @@ const htmlTemplate = `<!DOCTYPE html>
                                         <th>State</th>
                                         <th>Class</th>
                                         <th>Created</th>
-                                        <th>Monthly Cost</th>
+                                        <th>{{costTitle}} Cost</th>
                                     </tr>
                                 </thead>
                                 <tbody>
@@ -1101,15 +1724,15 @@ const htmlTemplate = `<!DOCTYPE html>
                                         <td>{{.Type}}</td>
                                         <td><span class="state-badge state-{{.State}}">{{.State}}</span></td>
                                         <td>{{.Class}}</td>
-                                        <td>{{if .CreatedAt}}{{.CreatedAt.Format "2006-01-02"}}{{else}}-{{end}}</td>
-                                        <td>
+                                        <td data-created="{{if .CreatedAt}}{{.CreatedAt.Format "2006-01-02T15:04:05Z07:00"}}{{end}}">{{if .CreatedAt}}{{.CreatedAt.Format "2006-01-02"}}{{else}}-{{end}}</td>
+                                        <td data-cost="{{if .CostEstimate}}{{amount .CostEstimate.Amount}}{{end}}">
                                             {{if .CostEstimate}}
                                             <span class="cost-cell" 
                                                   data-formula="{{.CostEstimate.Formula}}"
                                                   data-explanation="{{.CostEstimate.FormulaExplanation}}"
                                                   data-examples="{{range .CostEstimate.Examples}}{{.}}|{{end}}"
                                                   data-assumptions="{{range .CostEstimate.Assumptions}}{{.}}|{{end}}">
-                                                ${{printf "%.2f" .CostEstimate.Amount}}
+                                                {{money .CostEstimate.Amount}}
                                                 {{if eq .CostEstimate.Accuracy "High"}}
                                                 <span class="accuracy-badge accuracy-high" title="High accuracy estimate - Based on hourly billing with known pricing (EC2, RDS, Redis)">✓</span>
                                                 {{else if eq .CostEstimate.Accuracy "Medium"}}
@@ -1141,6 +1764,18 @@ const htmlTemplate = `<!DOCTYPE html>
     </div>
     
     <script>
+        // Theme toggle; the choice is remembered for this browser
+        function toggleTheme() {
+            const current = document.documentElement.getAttribute('data-theme');
+            const next = current === 'dark' ? 'light' : 'dark';
+            document.documentElement.setAttribute('data-theme', next);
+            try {
+                localStorage.setItem('awsinv-theme', next);
+            } catch (e) {
+                // The theme still applies to this page view
+            }
+        }
+
         // Collapsible resource groups functionality
         function toggleGroup(serviceName) {
             const content = document.getElementById('group-' + serviceName);
@@ -1171,6 +1806,68 @@ const htmlTemplate = `<!DOCTYPE html>
             headers.forEach(header => header.classList.add('collapsed'));
         }
         
+        // CSV export of the rows currently shown, in their current sort order
+        function csvValue(value) {
+            let text = String(value);
+            // Keep spreadsheet applications from evaluating cell contents as formulas
+            if (/^[=+\-@]/.test(text)) {
+                text = "'" + text;
+            }
+            if (/[",\r\n]/.test(text)) {
+                text = '"' + text.replace(/"/g, '""') + '"';
+            }
+            return text;
+        }
+
+        function tableRowsToCSV(table) {
+            const service = table.getAttribute('data-service');
+            const lines = [];
+            table.querySelectorAll('tbody tr').forEach(row => {
+                if (row.style.display === 'none') return;
+                const cells = row.cells;
+                lines.push([
+                    service,
+                    cells[0].textContent.trim(),
+                    cells[1].textContent.trim(),
+                    cells[2].textContent.trim(),
+                    cells[3].textContent.trim(),
+                    cells[4].textContent.trim(),
+                    cells[5].textContent.trim(),
+                    cells[6].getAttribute('data-created') || '',
+                    cells[7].getAttribute('data-cost') || ''
+                ].map(csvValue).join(','));
+            });
+            return lines;
+        }
+
+        function downloadCSV(tables, filename) {
+            const header = 'Service,Region,ID,Name,Type,State,Class,CreatedAt,{{costTitle}}Cost';
+            let lines = [header];
+            tables.forEach(table => {
+                lines = lines.concat(tableRowsToCSV(table));
+            });
+
+            const blob = new Blob([lines.join('\r\n') + '\r\n'], { type: 'text/csv;charset=utf-8' });
+            const link = document.createElement('a');
+            link.href = URL.createObjectURL(blob);
+            link.download = filename;
+            document.body.appendChild(link);
+            link.click();
+            document.body.removeChild(link);
+            URL.revokeObjectURL(link.href);
+        }
+
+        function exportAllCSV() {
+            downloadCSV(Array.from(document.querySelectorAll('.resource-table table')), 'awsinv-inventory.csv');
+        }
+
+        function exportGroupCSV(serviceName) {
+            const table = document.querySelector('#group-' + serviceName + ' table');
+            if (table) {
+                downloadCSV([table], 'awsinv-' + serviceName + '.csv');
+            }
+        }
+
         // Cost tooltip functionality
         function showCostTooltip(event, element) {
             // Remove any existing tooltips first
@@ -0,0 +1,298 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/findings"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/pricing"
+)
+
+// MarkdownFormatter formats output as Markdown, with a summary and one table per service
+type MarkdownFormatter struct {
+	writer  io.Writer
+	options Options
+}
+
+// NewMarkdownFormatter creates a new Markdown formatter writing to a file
+func NewMarkdownFormatter(writer *os.File) *MarkdownFormatter {
+	return NewMarkdownFormatterWithWriter(writer)
+}
+
+// NewMarkdownFormatterWithWriter creates a new Markdown formatter writing to any io.Writer
+func NewMarkdownFormatterWithWriter(writer io.Writer) *MarkdownFormatter {
+	return NewMarkdownFormatterWithOptions(writer, Options{})
+}
+
+// NewMarkdownFormatterWithOptions creates a new Markdown formatter with the given report settings
+func NewMarkdownFormatterWithOptions(writer io.Writer, options Options) *MarkdownFormatter {
+	return &MarkdownFormatter{writer: writer, options: options}
+}
+
+// Format formats the collection as Markdown
+func (f *MarkdownFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
+	// Calculate cost estimates, apply filters and sort
+	costPeriod := f.options.costPeriod()
+	resources, costEstimates := prepareResources(collection, filters, sortField, f.options)
+
+	byService := make(map[string][]models.Resource)
+	serviceCosts := make(map[string]float64)
+	totalCost := 0.0
+	for _, resource := range resources {
+		byService[resource.Service] = append(byService[resource.Service], resource)
+		if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
+			serviceCosts[resource.Service] += estimate.Amount
+			totalCost += estimate.Amount
+		}
+	}
+
+	services := make([]string, 0, len(byService))
+	for service := range byService {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	var b strings.Builder
+	b.WriteString("# AWS Resource Inventory\n\n")
+	if collection.Summary.Partial {
+		b.WriteString("> **Partial inventory:** collection stopped before every service and region finished.\n\n")
+	}
+	fmt.Fprintf(&b, "- **Total Resources:** %d\n", len(resources))
+	fmt.Fprintf(&b, "- **Estimated %s Cost:** %s\n", costPeriod.Title(), costPeriod.Format(totalCost))
+	if split := models.SplitCosts(resources, costEstimates); split.Covered > 0 || split.Commitments > 0 {
+		fmt.Fprintf(&b, "  - On-demand: %s, covered by reservations and Savings Plans: %s, commitments: %s\n",
+			costPeriod.Format(split.OnDemand), costPeriod.Format(split.Covered), costPeriod.Format(split.Commitments))
+	}
+	if savings := freeTierSavings(costEstimates); savings > 0 {
+		fmt.Fprintf(&b, "  - Free tier savings: %s\n", costPeriod.Format(savings))
+	}
+	fmt.Fprintf(&b, "- **Duration:** %v\n", collection.Summary.Duration)
+	fmt.Fprintf(&b, "- **Errors:** %d%s\n", len(collection.Errors), errorBreakdown(collection.Summary))
+	if len(collection.Warnings) > 0 {
+		fmt.Fprintf(&b, "- **Warnings:** %d\n", len(collection.Warnings))
+	}
+	if len(collection.Skipped) > 0 {
+		fmt.Fprintf(&b, "- **Skipped:** %d service/region pairs not enabled\n", len(collection.Skipped))
+	}
+
+	if len(services) > 0 {
+		b.WriteString("\n## By Service\n\n")
+		fmt.Fprintf(&b, "| Service | Resources | %s Cost |\n", costPeriod.Title())
+		b.WriteString("|---|---:|---:|\n")
+		for _, service := range services {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", markdownEscape(service), len(byService[service]), costPeriod.Format(serviceCosts[service]))
+		}
+	}
+
+	if ages := servicesByAge(resources); len(ages) > 0 {
+		b.WriteString("\n## By Age\n\n")
+		b.WriteString("| Service |")
+		for _, bucket := range models.AgeBuckets {
+			fmt.Fprintf(&b, " %s |", markdownEscape(bucket))
+		}
+		b.WriteString("\n|---|" + strings.Repeat("---:|", len(models.AgeBuckets)) + "\n")
+		for _, age := range ages {
+			fmt.Fprintf(&b, "| %s |", markdownEscape(age.Service))
+			for _, count := range age.Counts {
+				fmt.Fprintf(&b, " %d |", count)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	for _, allocation := range costAllocations(resources, costEstimates) {
+		fmt.Fprintf(&b, "\n## By %s\n\n", markdownEscape(allocation.Key))
+		fmt.Fprintf(&b, "| %s | Resources | %s Cost |\n", markdownEscape(allocation.Key), costPeriod.Title())
+		b.WriteString("|---|---:|---:|\n")
+		for _, group := range allocation.Allocations {
+			fmt.Fprintf(&b, "| %s | %d | %s |\n", markdownEscape(allocationValue(group.Value)), group.Resources, costPeriod.Format(group.Cost))
+		}
+	}
+
+	if budgets := budgetStatuses(collection, resources, costPeriod); len(budgets) > 0 {
+		b.WriteString("\n## Budgets\n\n")
+		fmt.Fprintf(&b, "| Budget | Resources | %s Cost | Limit | Status |\n", costPeriod.Title())
+		b.WriteString("|---|---:|---:|---:|---|\n")
+		for _, budget := range budgets {
+			status := "OK"
+			if budget.Over {
+				status = "**Over budget**"
+			}
+			fmt.Fprintf(&b, "| %s | %d | %s | %s | %s |\n",
+				markdownEscape(budget.Label()), budget.Resources, costPeriod.Format(budget.Spend), costPeriod.Format(budget.Limit), status)
+		}
+	}
+
+	if top := topCosts(resources, costEstimates, topCostCount); len(top) > 0 {
+		fmt.Fprintf(&b, "\n## Top %d Most Expensive Resources\n\n", len(top))
+		fmt.Fprintf(&b, "| Service | Region | ID | Name | Owner | %s Cost |\n", costPeriod.Title())
+		b.WriteString("|---|---|---|---|---|---:|\n")
+		for _, item := range top {
+			owner := item.Owner
+			if owner == "" {
+				owner = "-"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				markdownEscape(item.Resource.Service),
+				markdownEscape(item.Resource.Region),
+				markdownEscape(item.Resource.ID),
+				markdownEscape(item.Resource.Name),
+				markdownEscape(owner),
+				costPeriod.Format(item.Cost))
+		}
+	}
+
+	if recommendations := pricing.Rightsize(resources, costEstimates); len(recommendations) > 0 {
+		b.WriteString("\n## Rightsizing Recommendations\n\n")
+		fmt.Fprintf(&b, "| Service | Region | ID | Current | Recommended | %s Savings | Reason |\n", costPeriod.Title())
+		b.WriteString("|---|---|---|---|---|---:|---|\n")
+		for _, recommendation := range recommendations {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				markdownEscape(recommendation.Service),
+				markdownEscape(recommendation.Region),
+				markdownEscape(recommendation.ID),
+				markdownEscape(recommendation.Current),
+				markdownEscape(recommendation.Recommended),
+				costPeriod.Format(recommendation.Savings),
+				markdownEscape(recommendation.Reason))
+		}
+	}
+
+	if found := findings.Evaluate(resources); len(found) > 0 {
+		fmt.Fprintf(&b, "\n## Security Findings\n\nFindings by severity: %s.\n\n", severitySummary(found))
+		b.WriteString("| Severity | Rule | Service | Region | ID | Finding |\n")
+		b.WriteString("|---|---|---|---|---|---|\n")
+		for _, finding := range found {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				finding.Severity,
+				markdownEscape(finding.RuleID),
+				markdownEscape(finding.Service),
+				markdownEscape(finding.Region),
+				markdownEscape(finding.ResourceID),
+				markdownEscape(finding.Message))
+		}
+	}
+
+	if orphans, wasted := orphanedResources(collection, resources, costEstimates); len(orphans) > 0 {
+		fmt.Fprintf(&b, "\n## Orphaned Resources\n\n%d resources cost %s%s without doing anything.\n\n", len(orphans), costPeriod.Format(wasted), costPeriod.Unit())
+		fmt.Fprintf(&b, "| Service | Region | ID | Name | Reason | %s Cost |\n", costPeriod.Title())
+		b.WriteString("|---|---|---|---|---|---:|\n")
+		for _, item := range orphans {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+				markdownEscape(item.Service),
+				markdownEscape(item.Region),
+				markdownEscape(item.ID),
+				markdownEscape(item.Name),
+				markdownEscape(item.Reason),
+				costPeriod.Format(item.Cost))
+		}
+	}
+
+	if compliance := tagCompliance(resources, f.options.requiredTags()); compliance != nil {
+		b.WriteString("\n## Tag Compliance\n\n")
+		fmt.Fprintf(&b, "Required tags: %s. %s resources have all of them.\n\n",
+			markdownEscape(strings.Join(compliance.RequiredTags, ", ")), coverageText(compliance.Total))
+		for _, group := range tagCoverageGroups(compliance) {
+			if len(group.Coverage) == 0 {
+				continue
+			}
+			fmt.Fprintf(&b, "| %s | Resources | Compliant | Coverage |\n", group.Title)
+			b.WriteString("|---|---:|---:|---:|\n")
+			for _, coverage := range group.Coverage {
+				fmt.Fprintf(&b, "| %s | %d | %d | %.1f%% |\n",
+					markdownEscape(coverage.Name), coverage.Resources, coverage.Compliant, coverage.Coverage)
+			}
+			b.WriteString("\n")
+		}
+		if len(compliance.NonCompliant) > 0 {
+			b.WriteString("| Service | Region | ID | Name | Missing Tags |\n")
+			b.WriteString("|---|---|---|---|---|\n")
+			for _, resource := range compliance.NonCompliant {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+					markdownEscape(resource.Service),
+					markdownEscape(resource.Region),
+					markdownEscape(resource.ID),
+					markdownEscape(resource.Name),
+					markdownEscape(strings.Join(resource.Missing, ", ")))
+			}
+		}
+	}
+
+	for _, service := range services {
+		fmt.Fprintf(&b, "\n## %s\n\n", markdownEscape(service))
+		fmt.Fprintf(&b, "| Region | ID | Name | Type | State | Class | %s Cost |\n", costPeriod.Title())
+		b.WriteString("|---|---|---|---|---|---|---:|\n")
+		for _, resource := range byService[service] {
+			costStr := "-"
+			if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
+				costStr = costPeriod.Format(estimate.Amount)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s |\n",
+				markdownEscape(resource.Region),
+				markdownEscape(resource.ID),
+				markdownEscape(resource.Name),
+				markdownEscape(resource.Type),
+				markdownEscape(resource.State),
+				markdownEscape(resource.Class),
+				costStr)
+		}
+	}
+
+	if comparison := filteredComparison(collection, filters, f.options); comparison != nil {
+		sections := []struct {
+			title     string
+			resources []models.Resource
+		}{
+			{"New Since Last Scan", comparison.New},
+			{"Disappeared Since Last Scan", comparison.Disappeared},
+		}
+		for _, section := range sections {
+			fmt.Fprintf(&b, "\n## %s (%d)\n\n", section.title, len(section.resources))
+			fmt.Fprintf(&b, "Compared to `%s` from %s.\n", comparison.Snapshot, comparison.ComparedTo.Format(time.RFC3339))
+			if len(section.resources) == 0 {
+				continue
+			}
+			b.WriteString("\n| Service | Region | ID | Name | Type | State |\n")
+			b.WriteString("|---|---|---|---|---|---|\n")
+			for _, resource := range section.resources {
+				fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+					markdownEscape(resource.Service),
+					markdownEscape(resource.Region),
+					markdownEscape(resource.ID),
+					markdownEscape(resource.Name),
+					markdownEscape(resource.Type),
+					markdownEscape(resource.State))
+			}
+		}
+	}
+
+	if len(collection.Errors) > 0 {
+		b.WriteString("\n## Errors\n\n")
+		for _, err := range collection.Errors {
+			fmt.Fprintf(&b, "- %s\n", markdownEscape(err))
+		}
+	}
+
+	if len(collection.Warnings) > 0 {
+		b.WriteString("\n## Warnings\n\n")
+		for _, warning := range collection.Warnings {
+			fmt.Fprintf(&b, "- %s\n", markdownEscape(warning))
+		}
+	}
+
+	_, err := io.WriteString(f.writer, b.String())
+	return err
+}
+
+// markdownEscape keeps a value from breaking a Markdown table cell
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "|", `\|`)
+	s = strings.ReplaceAll(s, "\n", " ")
+	return s
+}
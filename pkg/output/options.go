@@ -0,0 +1,56 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/xiaochen/awsinv/pkg/pricing"
+)
+
+// builtInCostEngine estimates costs with the built-in prices, for reports whose
+// options have no cost engine
+var builtInCostEngine = pricing.NewEngine(nil)
+
+// Options are the report settings of the built-in formatters, given to their
+// WithOptions constructors. The zero value reports monthly costs estimated with the
+// built-in prices.
+type Options struct {
+	// CostPeriod is the period reports show costs for. Filters, thresholds and
+	// budgets keep working on monthly costs.
+	CostPeriod CostPeriod
+
+	// RequiredTags are the tag keys every resource should have. Reports then include
+	// a tag compliance section with the coverage and the resources missing any of
+	// them.
+	RequiredTags []string
+
+	// CostEngine estimates the costs of collections that were not annotated when
+	// they were collected, such as with live prices from the Pricing API
+	CostEngine *pricing.Engine
+}
+
+// costPeriod returns the period reports show costs for, monthly unless set
+func (o Options) costPeriod() CostPeriod {
+	if o.CostPeriod == "" {
+		return CostPeriodMonthly
+	}
+	return o.CostPeriod
+}
+
+// requiredTags returns the tag keys resources are audited for, without blank ones
+func (o Options) requiredTags() []string {
+	var keys []string
+	for _, key := range o.RequiredTags {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// costEngine returns the engine that estimates costs, the built-in prices unless set
+func (o Options) costEngine() *pricing.Engine {
+	if o.CostEngine == nil {
+		return builtInCostEngine
+	}
+	return o.CostEngine
+}
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"strings"
+
+	"github.com/xiaochen/awsinv/pkg/graph"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// orphan is an orphaned resource with what it costs
+type orphan struct {
+	graph.Orphan
+	Cost float64 `json:"cost"`
+}
+
+// collectedPairs reports whether a service was collected in a region without error,
+// so a parent missing from the collection is known to be gone rather than unlooked for
+func collectedPairs(collection *models.ResourceCollection) func(service, region string) bool {
+	failed := make(map[string]bool, len(collection.Errors))
+	for _, err := range collection.Errors {
+		// Errors are reported as "service/region: message"
+		if pair, _, found := strings.Cut(err, ": "); found {
+			failed[pair] = true
+		}
+	}
+
+	services := make(map[string]bool, len(collection.Summary.Services))
+	for _, service := range collection.Summary.Services {
+		services[service] = true
+	}
+	regions := make(map[string]bool, len(collection.Summary.Regions))
+	for _, region := range collection.Summary.Regions {
+		regions[region] = true
+	}
+
+	return func(service, region string) bool {
+		return services[service] && regions[region] && !failed[service+"/"+region]
+	}
+}
+
+// orphanedResources returns the reported resources whose parents no longer exist, or
+// that have none although they need one, with their cost and the total of it. The
+// relationships are taken from the whole collection so filtered-out parents count.
+func orphanedResources(collection *models.ResourceCollection, resources []models.Resource, costEstimates map[string]*models.CostEstimate) ([]orphan, float64) {
+	found := graph.Build(collection.Resources).Orphans(resources, collectedPairs(collection))
+	if len(found) == 0 {
+		return nil, 0
+	}
+
+	costs := make(map[string]float64, len(resources))
+	for _, resource := range resources {
+		costs[resource.Key()] = resourceCost(resource, costEstimates)
+	}
+
+	orphans := make([]orphan, 0, len(found))
+	total := 0.0
+	for _, item := range found {
+		cost := costs[findingKey(item.Account, item.Service, item.Region, item.ID)]
+		orphans = append(orphans, orphan{Orphan: item, Cost: cost})
+		total += cost
+	}
+	return orphans, total
+}
@@ -0,0 +1,125 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// CostPeriod is the period reports show cost figures for. Estimates are monthly and
+// are rescaled for the others.
+type CostPeriod string
+
+// Cost periods
+const (
+	CostPeriodHourly  CostPeriod = "hourly"
+	CostPeriodDaily   CostPeriod = "daily"
+	CostPeriodMonthly CostPeriod = "monthly"
+	CostPeriodAnnual  CostPeriod = "annual"
+)
+
+// CostPeriods are the periods costs can be reported for
+var CostPeriods = []CostPeriod{CostPeriodHourly, CostPeriodDaily, CostPeriodMonthly, CostPeriodAnnual}
+
+// hoursPerMonth is the length of the month cost estimates are made for
+const hoursPerMonth = 730
+
+// ParseCostPeriod parses a cost period name
+func ParseCostPeriod(value string) (CostPeriod, error) {
+	for _, period := range CostPeriods {
+		if string(period) == strings.ToLower(value) {
+			return period, nil
+		}
+	}
+	return "", fmt.Errorf("invalid cost period: %s (expected hourly, daily, monthly or annual)", value)
+}
+
+// Scale converts a monthly amount to the period, counting 730 hours in a month
+func (p CostPeriod) Scale(monthly float64) float64 {
+	switch p {
+	case CostPeriodHourly:
+		return monthly / hoursPerMonth
+	case CostPeriodDaily:
+		return monthly / hoursPerMonth * 24
+	case CostPeriodAnnual:
+		return monthly * 12
+	default:
+		return monthly
+	}
+}
+
+// Title names the period in labels such as "Annual Cost"
+func (p CostPeriod) Title() string {
+	switch p {
+	case CostPeriodHourly:
+		return "Hourly"
+	case CostPeriodDaily:
+		return "Daily"
+	case CostPeriodAnnual:
+		return "Annual"
+	default:
+		return "Monthly"
+	}
+}
+
+// Unit is the suffix of amounts for the period, such as "/year"
+func (p CostPeriod) Unit() string {
+	switch p {
+	case CostPeriodHourly:
+		return "/hour"
+	case CostPeriodDaily:
+		return "/day"
+	case CostPeriodAnnual:
+		return "/year"
+	default:
+		return "/month"
+	}
+}
+
+// Format formats an amount already scaled to the period in dollars. Hourly amounts
+// keep four decimals, as most resources cost cents an hour.
+func (p CostPeriod) Format(amount float64) string {
+	if p == CostPeriodHourly {
+		return fmt.Sprintf("$%.4f", amount)
+	}
+	return fmt.Sprintf("$%.2f", amount)
+}
+
+// scaleEstimates returns copies of monthly estimates with their amounts in the cost
+// period. Explanations and formulas still describe the monthly estimate.
+func scaleEstimates(costEstimates map[string]*models.CostEstimate, costPeriod CostPeriod) map[string]*models.CostEstimate {
+	if costPeriod == CostPeriodMonthly {
+		return costEstimates
+	}
+
+	scaled := make(map[string]*models.CostEstimate, len(costEstimates))
+	for id, estimate := range costEstimates {
+		if estimate == nil {
+			continue
+		}
+		copied := *estimate
+		copied.Amount = costPeriod.Scale(estimate.Amount)
+		copied.FreeTierSavings = costPeriod.Scale(estimate.FreeTierSavings)
+		copied.OnDemandAmount = costPeriod.Scale(estimate.OnDemandAmount)
+		if estimate.Breakdown != nil {
+			copied.Breakdown = make(map[string]float64, len(estimate.Breakdown))
+			for key, amount := range estimate.Breakdown {
+				copied.Breakdown[key] = costPeriod.Scale(amount)
+			}
+		}
+		scaled[id] = &copied
+	}
+	return scaled
+}
+
+// budgetStatuses checks the collection's budgets against the monthly cost of the
+// reported resources, with the spend and limit scaled to the cost period
+func budgetStatuses(collection *models.ResourceCollection, resources []models.Resource, costPeriod CostPeriod) []models.BudgetStatus {
+	statuses := models.CheckBudgets(collection.Budgets, resources, collection.Costs)
+	for i := range statuses {
+		statuses[i].Spend = costPeriod.Scale(statuses[i].Spend)
+		statuses[i].Limit = costPeriod.Scale(statuses[i].Limit)
+	}
+	return statuses
+}
@@ -0,0 +1,71 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// BuiltinFormats are the output formats the project ships
+var BuiltinFormats = []string{"table", "json", "csv", "html", "markdown", "xlsx", "sqlite", "dot", "mermaid"}
+
+// FormatterFactory creates a formatter that writes reports to w
+type FormatterFactory func(w io.Writer) (Formatter, error)
+
+// formatNamePattern is what format names look like, as given to --output
+var formatNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// registry holds the formatters added with RegisterFormatter
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]FormatterFactory)
+)
+
+// RegisterFormatter adds an output format the project does not ship, such as the
+// import format of an internal CMDB, usually from an init function. The CLI selects
+// it with --output like the built-in ones. It panics if the name is invalid or taken,
+// as registering is a programming error rather than a runtime failure.
+func RegisterFormatter(name string, factory FormatterFactory) {
+	if !formatNamePattern.MatchString(name) {
+		panic(fmt.Sprintf("output: invalid format name %q", name))
+	}
+	if factory == nil {
+		panic(fmt.Sprintf("output: nil factory for format %q", name))
+	}
+	for _, builtin := range BuiltinFormats {
+		if name == builtin {
+			panic(fmt.Sprintf("output: format %q is built in", name))
+		}
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("output: format %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+// LookupFormatter returns the factory of a format added with RegisterFormatter
+func LookupFormatter(name string) (FormatterFactory, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	factory, exists := registry[name]
+	return factory, exists
+}
+
+// Formats returns the names of the built-in formats, then of the registered ones in
+// alphabetical order
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	registered := make([]string, 0, len(registry))
+	for name := range registry {
+		registered = append(registered, name)
+	}
+	sort.Strings(registered)
+	return append(append([]string(nil), BuiltinFormats...), registered...)
+}
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// testFormatter writes the number of resources of a report
+type testFormatter struct {
+	w io.Writer
+}
+
+func (f *testFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
+	_, err := fmt.Fprintf(f.w, "%d resources\n", len(collection.Resources))
+	return err
+}
+
+func newTestFormatter(w io.Writer) (Formatter, error) {
+	return &testFormatter{w: w}, nil
+}
+
+// unregister removes a format added by a test, so the tests can run repeatedly
+func unregister(t *testing.T, name string) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(registry, name)
+	})
+}
+
+// registerPanic returns the panic message of RegisterFormatter, or "" if it did not
+// panic
+func registerPanic(name string, factory FormatterFactory) (message string) {
+	defer func() {
+		if r := recover(); r != nil {
+			message, _ = r.(string)
+		}
+	}()
+	RegisterFormatter(name, factory)
+	return ""
+}
+
+func TestRegisterFormatter(t *testing.T) {
+	unregister(t, "test-cmdb")
+	unregister(t, "test-audit")
+	RegisterFormatter("test-cmdb", newTestFormatter)
+	RegisterFormatter("test-audit", newTestFormatter)
+
+	factory, ok := LookupFormatter("test-cmdb")
+	if !ok {
+		t.Fatal("LookupFormatter(test-cmdb) found nothing after RegisterFormatter")
+	}
+	var buf bytes.Buffer
+	formatter, err := factory(&buf)
+	if err != nil {
+		t.Fatalf("factory failed: %v", err)
+	}
+	collection := &models.ResourceCollection{Resources: []models.Resource{{Service: "ec2", ID: "i-1"}}}
+	if err := formatter.Format(collection, nil, "", true); err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if got := buf.String(); got != "1 resources\n" {
+		t.Errorf("Format wrote %q, want %q", got, "1 resources\n")
+	}
+
+	// Built-in formats come first, then the registered ones in alphabetical order
+	want := append(append([]string(nil), BuiltinFormats...), "test-audit", "test-cmdb")
+	if diff := cmp.Diff(want, Formats()); diff != "" {
+		t.Errorf("Formats() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLookupFormatter_NotRegistered(t *testing.T) {
+	for _, name := range []string{"test-missing", "table", ""} {
+		if _, ok := LookupFormatter(name); ok {
+			t.Errorf("LookupFormatter(%q) found a format that was not registered", name)
+		}
+	}
+}
+
+func TestRegisterFormatter_Panics(t *testing.T) {
+	unregister(t, "test-duplicate")
+	RegisterFormatter("test-duplicate", newTestFormatter)
+
+	tests := []struct {
+		name         string
+		format       string
+		factory      FormatterFactory
+		wantContains string
+	}{
+		{"empty name", "", newTestFormatter, "invalid format name"},
+		{"upper case name", "CMDB", newTestFormatter, "invalid format name"},
+		{"name with a separator", "cmdb,json", newTestFormatter, "invalid format name"},
+		{"name starting with a digit", "1cmdb", newTestFormatter, "invalid format name"},
+		{"nil factory", "test-nil", nil, "nil factory"},
+		{"built-in name", "json", newTestFormatter, "is built in"},
+		{"duplicate name", "test-duplicate", newTestFormatter, "registered twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message := registerPanic(tt.format, tt.factory)
+			if !strings.Contains(message, tt.wantContains) {
+				t.Errorf("RegisterFormatter(%q) panic = %q, want it to contain %q", tt.format, message, tt.wantContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package output
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/findings"
+	"github.com/xiaochen/awsinv/pkg/models"
+	_ "modernc.org/sqlite" // pure Go driver keeps the binary static
+)
+
+// sqliteSchema creates the tables and indexes of an inventory database
+const sqliteSchema = `
+CREATE TABLE resources (
+	id           INTEGER PRIMARY KEY,
+	service      TEXT NOT NULL,
+	region       TEXT NOT NULL,
+	account      TEXT,
+	resource_id  TEXT NOT NULL,
+	name         TEXT,
+	type         TEXT,
+	state        TEXT,
+	class        TEXT,
+	created_at   TEXT,
+	monthly_cost REAL,
+	extra        TEXT
+);
+CREATE INDEX idx_resources_service ON resources(service);
+CREATE INDEX idx_resources_region ON resources(region);
+CREATE INDEX idx_resources_resource_id ON resources(resource_id);
+CREATE INDEX idx_resources_state ON resources(state);
+
+CREATE TABLE tags (
+	resource INTEGER NOT NULL REFERENCES resources(id),
+	key      TEXT NOT NULL,
+	value    TEXT NOT NULL,
+	PRIMARY KEY (resource, key)
+);
+CREATE INDEX idx_tags_key_value ON tags(key, value);
+
+CREATE TABLE cost_estimates (
+	resource          INTEGER PRIMARY KEY REFERENCES resources(id),
+	amount            REAL NOT NULL,
+	accuracy          TEXT,
+	source            TEXT,
+	explanation       TEXT,
+	formula           TEXT,
+	free_tier_covered INTEGER NOT NULL DEFAULT 0,
+	free_tier_savings REAL NOT NULL DEFAULT 0
+);
+
+CREATE TABLE findings (
+	resource INTEGER NOT NULL REFERENCES resources(id),
+	rule_id  TEXT NOT NULL,
+	severity TEXT NOT NULL,
+	message  TEXT NOT NULL
+);
+CREATE INDEX idx_findings_severity ON findings(severity);
+
+CREATE TABLE missing_tags (
+	resource INTEGER NOT NULL REFERENCES resources(id),
+	key      TEXT NOT NULL,
+	PRIMARY KEY (resource, key)
+);
+
+CREATE TABLE summary (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+
+CREATE TABLE errors (
+	message TEXT NOT NULL
+);
+
+CREATE TABLE warnings (
+	message TEXT NOT NULL
+);
+`
+
+// SQLiteFormatter writes the inventory as a SQLite database
+type SQLiteFormatter struct {
+	writer  io.Writer
+	options Options
+}
+
+// NewSQLiteFormatter creates a new SQLite formatter writing to a file
+func NewSQLiteFormatter(writer *os.File) *SQLiteFormatter {
+	return NewSQLiteFormatterWithWriter(writer)
+}
+
+// NewSQLiteFormatterWithWriter creates a new SQLite formatter writing to any io.Writer
+func NewSQLiteFormatterWithWriter(writer io.Writer) *SQLiteFormatter {
+	return NewSQLiteFormatterWithOptions(writer, Options{})
+}
+
+// NewSQLiteFormatterWithOptions creates a new SQLite formatter with the given report
+// settings. The cost period does not apply: the database always holds monthly costs.
+func NewSQLiteFormatterWithOptions(writer io.Writer, options Options) *SQLiteFormatter {
+	return &SQLiteFormatter{writer: writer, options: options}
+}
+
+// Format formats the collection as a SQLite database. SQLite needs a seekable file,
+// so the database is built in a temporary file and then copied to the writer.
+func (f *SQLiteFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
+	// Calculate cost estimates, apply filters and sort. The database always holds
+	// monthly costs, whatever the cost period, so reports can be compared.
+	resources, costEstimates := PrepareResources(collection, filters, sortField, f.options)
+
+	tmp, err := os.CreateTemp("", "awsinv-*.db")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary database: %w", err)
+	}
+	path := tmp.Name()
+	tmp.Close()
+	defer os.Remove(path)
+
+	if err := writeSQLiteDatabase(path, collection, resources, costEstimates, f.options.requiredTags()); err != nil {
+		return err
+	}
+
+	db, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	_, err = io.Copy(f.writer, db)
+	return err
+}
+
+// writeSQLiteDatabase creates the schema and loads the inventory into the database at path
+func writeSQLiteDatabase(path string, collection *models.ResourceCollection, resources []models.Resource, costEstimates map[string]*models.CostEstimate, requiredTags []string) error {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	insertResource, err := tx.Prepare(`INSERT INTO resources (service, region, account, resource_id, name, type, state, class, created_at, monthly_cost, extra) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertResource.Close()
+
+	insertTag, err := tx.Prepare(`INSERT INTO tags (resource, key, value) VALUES (?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertTag.Close()
+
+	insertCost, err := tx.Prepare(`INSERT INTO cost_estimates (resource, amount, accuracy, source, explanation, formula, free_tier_covered, free_tier_savings) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insertCost.Close()
+
+	// Findings refer to the rows of their resources
+	rowIDs := make(map[string]int64, len(resources))
+
+	totalMonthlyCost := 0.0
+	for _, resource := range resources {
+		var createdAt, extra, monthlyCost interface{}
+		if resource.CreatedAt != nil {
+			createdAt = resource.CreatedAt.UTC().Format(time.RFC3339)
+		}
+		if len(resource.Extra) > 0 {
+			data, err := json.Marshal(resource.Extra)
+			if err != nil {
+				return fmt.Errorf("failed to encode extra fields of %s: %w", resource.ID, err)
+			}
+			extra = string(data)
+		}
+		estimate := costEstimates[resource.Key()]
+		if estimate != nil {
+			monthlyCost = estimate.Amount
+			totalMonthlyCost += estimate.Amount
+		}
+
+		result, err := insertResource.Exec(resource.Service, resource.Region, resource.Account, resource.ID, resource.Name,
+			resource.Type, resource.State, resource.Class, createdAt, monthlyCost, extra)
+		if err != nil {
+			return fmt.Errorf("failed to insert resource %s: %w", resource.ID, err)
+		}
+		rowID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		rowIDs[resource.Key()] = rowID
+
+		for key, value := range resource.Tags {
+			if _, err := insertTag.Exec(rowID, key, value); err != nil {
+				return fmt.Errorf("failed to insert tag %s of %s: %w", key, resource.ID, err)
+			}
+		}
+
+		if estimate != nil {
+			if _, err := insertCost.Exec(rowID, estimate.Amount, estimate.Accuracy, estimate.Source,
+				estimate.Explanation, estimate.Formula, estimate.FreeTierCovered, estimate.FreeTierSavings); err != nil {
+				return fmt.Errorf("failed to insert cost estimate of %s: %w", resource.ID, err)
+			}
+		}
+
+		if len(requiredTags) > 0 && models.Taggable(resource) {
+			for _, key := range models.MissingTags(resource, requiredTags) {
+				if _, err := tx.Exec(`INSERT INTO missing_tags (resource, key) VALUES (?, ?)`, rowID, key); err != nil {
+					return fmt.Errorf("failed to insert missing tag %s of %s: %w", key, resource.ID, err)
+				}
+			}
+		}
+	}
+
+	for _, finding := range findings.Evaluate(resources) {
+		rowID := rowIDs[findingKey(finding.Account, finding.Service, finding.Region, finding.ResourceID)]
+		if _, err := tx.Exec(`INSERT INTO findings (resource, rule_id, severity, message) VALUES (?, ?, ?, ?)`,
+			rowID, finding.RuleID, string(finding.Severity), finding.Message); err != nil {
+			return fmt.Errorf("failed to insert finding of %s: %w", finding.ResourceID, err)
+		}
+	}
+
+	summary := map[string]string{
+		"total_resources":    strconv.Itoa(len(resources)),
+		"total_monthly_cost": strconv.FormatFloat(totalMonthlyCost, 'f', 2, 64),
+		"duration":           collection.Summary.Duration.String(),
+		"errors":             strconv.Itoa(len(collection.Errors)),
+		"warnings":           strconv.Itoa(len(collection.Warnings)),
+		"partial":            strconv.FormatBool(collection.Summary.Partial),
+		"skipped":            strconv.Itoa(len(collection.Skipped)),
+		"generated_at":       time.Now().UTC().Format(time.RFC3339),
+	}
+	for key, value := range summary {
+		if _, err := tx.Exec(`INSERT INTO summary (key, value) VALUES (?, ?)`, key, value); err != nil {
+			return err
+		}
+	}
+
+	for _, message := range collection.Errors {
+		if _, err := tx.Exec(`INSERT INTO errors (message) VALUES (?)`, message); err != nil {
+			return err
+		}
+	}
+	for _, message := range collection.Warnings {
+		if _, err := tx.Exec(`INSERT INTO warnings (message) VALUES (?)`, message); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,72 @@
+package output
+
+import "github.com/xiaochen/awsinv/pkg/models"
+
+// tagCompliance audits the reported resources for the required tags, or returns nil
+// when none are required
+func tagCompliance(resources []models.Resource, requiredTags []string) *models.TagCompliance {
+	if len(requiredTags) == 0 {
+		return nil
+	}
+	return models.CheckTagCompliance(requiredTags, resources)
+}
+
+// tagCoverageGroup is the tag coverage of one grouping, such as per service
+type tagCoverageGroup struct {
+	Title    string
+	Coverage []models.TagCoverage
+}
+
+// tagCoverageGroups returns the coverage per service, region and account
+func tagCoverageGroups(compliance *models.TagCompliance) []tagCoverageGroup {
+	if compliance == nil {
+		return nil
+	}
+	return []tagCoverageGroup{
+		{"Service", compliance.ByService},
+		{"Region", compliance.ByRegion},
+		{"Account", compliance.ByAccount},
+	}
+}
+
+// tagAllocation is the cost of the resources per value of one normalized tag
+type tagAllocation struct {
+	Key         string
+	Allocations []models.TagAllocation
+}
+
+// costAllocations groups the resources by each normalized tag, such as Owner, that
+// any of them has
+func costAllocations(resources []models.Resource, costEstimates map[string]*models.CostEstimate) []tagAllocation {
+	var allocations []tagAllocation
+	for _, mapping := range models.TagMappings() {
+		groups := models.AllocateByTag(mapping.Key, resources, costEstimates)
+		if len(groups) == 0 || (len(groups) == 1 && groups[0].Value == "") {
+			continue
+		}
+		allocations = append(allocations, tagAllocation{Key: mapping.Key, Allocations: groups})
+	}
+	return allocations
+}
+
+// allocationValue labels a cost allocation group, naming the one without the tag
+func allocationValue(value string) string {
+	if value == "" {
+		return "(untagged)"
+	}
+	return value
+}
+
+// costAllocationMap returns the cost allocations keyed by normalized tag, or nil
+// when no resource has any of them
+func costAllocationMap(resources []models.Resource, costEstimates map[string]*models.CostEstimate) map[string][]models.TagAllocation {
+	allocations := costAllocations(resources, costEstimates)
+	if len(allocations) == 0 {
+		return nil
+	}
+	byKey := make(map[string][]models.TagAllocation, len(allocations))
+	for _, allocation := range allocations {
+		byKey[allocation.Key] = allocation.Allocations
+	}
+	return byKey
+}
@@ -0,0 +1,518 @@
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/findings"
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// Cell styles defined in xlsxStyles
+const (
+	xlsxStyleDefault = 0
+	xlsxStyleHeader  = 1
+	xlsxStyleMoney   = 2
+)
+
+// xlsxMaxColumnWidth caps the auto-sized column width in characters
+const xlsxMaxColumnWidth = 60
+
+// XLSXFormatter formats output as an Excel workbook with a summary sheet, one
+// sheet per service, a costs sheet and, when there are any, findings and tag
+// compliance sheets
+type XLSXFormatter struct {
+	writer  io.Writer
+	options Options
+}
+
+// NewXLSXFormatter creates a new xlsx formatter writing to a file
+func NewXLSXFormatter(writer *os.File) *XLSXFormatter {
+	return NewXLSXFormatterWithWriter(writer)
+}
+
+// NewXLSXFormatterWithWriter creates a new xlsx formatter writing to any io.Writer
+func NewXLSXFormatterWithWriter(writer io.Writer) *XLSXFormatter {
+	return NewXLSXFormatterWithOptions(writer, Options{})
+}
+
+// NewXLSXFormatterWithOptions creates a new xlsx formatter with the given report settings
+func NewXLSXFormatterWithOptions(writer io.Writer, options Options) *XLSXFormatter {
+	return &XLSXFormatter{writer: writer, options: options}
+}
+
+// xlsxCell is a single worksheet cell, either text or a number
+type xlsxCell struct {
+	text     string
+	number   float64
+	isNumber bool
+	style    int
+}
+
+// xlsxSheet is a worksheet whose first row is a frozen header
+type xlsxSheet struct {
+	name string
+	rows [][]xlsxCell
+}
+
+// Format formats the collection as an xlsx workbook
+func (f *XLSXFormatter) Format(collection *models.ResourceCollection, filters []Filter, sortField string, noColor bool) error {
+	// Calculate cost estimates, apply filters and sort
+	costPeriod := f.options.costPeriod()
+	resources, costEstimates := prepareResources(collection, filters, sortField, f.options)
+
+	sheets := []xlsxSheet{buildSummarySheet(collection, resources, costEstimates, costPeriod)}
+	sheets = append(sheets, buildServiceSheets(resources, costEstimates, costPeriod)...)
+	sheets = append(sheets, buildCostsSheet(resources, costEstimates, costPeriod))
+	if found := findings.Evaluate(resources); len(found) > 0 {
+		sheets = append(sheets, buildFindingsSheet(found))
+	}
+	if compliance := tagCompliance(resources, f.options.requiredTags()); compliance != nil {
+		sheets = append(sheets, buildTagComplianceSheet(compliance))
+	}
+
+	return writeWorkbook(f.writer, sheets)
+}
+
+// buildSummarySheet lists totals and per-service and per-region resource counts and costs
+func buildSummarySheet(collection *models.ResourceCollection, resources []models.Resource, costEstimates map[string]*models.CostEstimate, costPeriod CostPeriod) xlsxSheet {
+	serviceCounts := make(map[string]int)
+	serviceCosts := make(map[string]float64)
+	regionCounts := make(map[string]int)
+	regionCosts := make(map[string]float64)
+	totalCost := 0.0
+
+	for _, resource := range resources {
+		cost := 0.0
+		if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
+			cost = estimate.Amount
+		}
+		serviceCounts[resource.Service]++
+		serviceCosts[resource.Service] += cost
+		regionCounts[resource.Region]++
+		regionCosts[resource.Region] += cost
+		totalCost += cost
+	}
+
+	sheet := xlsxSheet{name: "Summary"}
+	sheet.rows = append(sheet.rows,
+		[]xlsxCell{xlsxHeader("Metric"), xlsxHeader("Value")},
+		[]xlsxCell{xlsxText("Total Resources"), xlsxNumber(float64(len(resources)))},
+		[]xlsxCell{xlsxText("Estimated " + costPeriod.Title() + " Cost"), xlsxMoney(totalCost)},
+		[]xlsxCell{xlsxText("Duration"), xlsxText(collection.Summary.Duration.String())},
+		[]xlsxCell{xlsxText("Errors"), xlsxNumber(float64(len(collection.Errors)))},
+		[]xlsxCell{xlsxText("Warnings"), xlsxNumber(float64(len(collection.Warnings)))},
+		[]xlsxCell{xlsxText("Skipped"), xlsxNumber(float64(len(collection.Skipped)))},
+		[]xlsxCell{xlsxText("Partial"), xlsxText(strconv.FormatBool(collection.Summary.Partial))},
+		nil,
+		[]xlsxCell{xlsxHeader("Service"), xlsxHeader("Resources"), xlsxHeader(costPeriod.Title() + " Cost")},
+	)
+	for _, service := range sortedKeys(serviceCounts) {
+		sheet.rows = append(sheet.rows, []xlsxCell{
+			xlsxText(service), xlsxNumber(float64(serviceCounts[service])), xlsxMoney(serviceCosts[service]),
+		})
+	}
+
+	sheet.rows = append(sheet.rows, nil, []xlsxCell{xlsxHeader("Region"), xlsxHeader("Resources"), xlsxHeader(costPeriod.Title() + " Cost")})
+	for _, region := range sortedKeys(regionCounts) {
+		sheet.rows = append(sheet.rows, []xlsxCell{
+			xlsxText(region), xlsxNumber(float64(regionCounts[region])), xlsxMoney(regionCosts[region]),
+		})
+	}
+
+	if len(collection.Errors) > 0 {
+		sheet.rows = append(sheet.rows, nil, []xlsxCell{xlsxHeader("Errors")})
+		for _, err := range collection.Errors {
+			sheet.rows = append(sheet.rows, []xlsxCell{xlsxText(err)})
+		}
+	}
+	if len(collection.Warnings) > 0 {
+		sheet.rows = append(sheet.rows, nil, []xlsxCell{xlsxHeader("Warnings")})
+		for _, warning := range collection.Warnings {
+			sheet.rows = append(sheet.rows, []xlsxCell{xlsxText(warning)})
+		}
+	}
+
+	return sheet
+}
+
+// buildServiceSheets creates one sheet per service, in service name order
+func buildServiceSheets(resources []models.Resource, costEstimates map[string]*models.CostEstimate, costPeriod CostPeriod) []xlsxSheet {
+	byService := make(map[string][]models.Resource)
+	for _, resource := range resources {
+		byService[resource.Service] = append(byService[resource.Service], resource)
+	}
+
+	var sheets []xlsxSheet
+	for _, service := range sortedKeys(byService) {
+		sheet := xlsxSheet{name: service}
+		sheet.rows = append(sheet.rows, []xlsxCell{
+			xlsxHeader("Region"), xlsxHeader("ID"), xlsxHeader("Name"), xlsxHeader("Type"),
+			xlsxHeader("State"), xlsxHeader("Class"), xlsxHeader(costPeriod.Title() + "Cost"), xlsxHeader("CreatedAt"), xlsxHeader("Tags"),
+		})
+
+		for _, resource := range byService[service] {
+			cost := xlsxText("")
+			if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil {
+				cost = xlsxMoney(estimate.Amount)
+			}
+
+			createdAt := ""
+			if resource.CreatedAt != nil {
+				createdAt = resource.CreatedAt.Format(time.RFC3339)
+			}
+
+			var tagPairs []string
+			for _, key := range sortedKeys(resource.Tags) {
+				tagPairs = append(tagPairs, fmt.Sprintf("%s=%s", key, resource.Tags[key]))
+			}
+
+			sheet.rows = append(sheet.rows, []xlsxCell{
+				xlsxText(resource.Region),
+				xlsxText(resource.ID),
+				xlsxText(resource.Name),
+				xlsxText(resource.Type),
+				xlsxText(resource.State),
+				xlsxText(resource.Class),
+				cost,
+				xlsxText(createdAt),
+				xlsxText(strings.Join(tagPairs, ",")),
+			})
+		}
+
+		sheets = append(sheets, sheet)
+	}
+
+	return sheets
+}
+
+// buildCostsSheet lists every resource with a non-zero cost estimate, highest cost first
+func buildCostsSheet(resources []models.Resource, costEstimates map[string]*models.CostEstimate, costPeriod CostPeriod) xlsxSheet {
+	type costRow struct {
+		resource models.Resource
+		estimate *models.CostEstimate
+	}
+
+	var rows []costRow
+	for _, resource := range resources {
+		if estimate, exists := costEstimates[resource.Key()]; exists && estimate != nil && estimate.Amount > 0 {
+			rows = append(rows, costRow{resource, estimate})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].estimate.Amount > rows[j].estimate.Amount
+	})
+
+	sheet := xlsxSheet{name: "Costs"}
+	sheet.rows = append(sheet.rows, []xlsxCell{
+		xlsxHeader("Service"), xlsxHeader("Region"), xlsxHeader("ID"), xlsxHeader("Name"),
+		xlsxHeader(costPeriod.Title() + "Cost"), xlsxHeader("Accuracy"), xlsxHeader("Explanation"), xlsxHeader("Formula"),
+	})
+	for _, row := range rows {
+		sheet.rows = append(sheet.rows, []xlsxCell{
+			xlsxText(row.resource.Service),
+			xlsxText(row.resource.Region),
+			xlsxText(row.resource.ID),
+			xlsxText(row.resource.Name),
+			xlsxMoney(row.estimate.Amount),
+			xlsxText(row.estimate.Accuracy),
+			xlsxText(row.estimate.Explanation),
+			xlsxText(row.estimate.Formula),
+		})
+	}
+
+	return sheet
+}
+
+// buildFindingsSheet lists the security findings, most serious first
+func buildFindingsSheet(found []findings.Finding) xlsxSheet {
+	sheet := xlsxSheet{name: "Findings"}
+	sheet.rows = append(sheet.rows, []xlsxCell{
+		xlsxHeader("Severity"), xlsxHeader("Rule"), xlsxHeader("Service"), xlsxHeader("Region"),
+		xlsxHeader("ID"), xlsxHeader("Name"), xlsxHeader("Finding"),
+	})
+	for _, finding := range found {
+		sheet.rows = append(sheet.rows, []xlsxCell{
+			xlsxText(string(finding.Severity)),
+			xlsxText(finding.RuleID),
+			xlsxText(finding.Service),
+			xlsxText(finding.Region),
+			xlsxText(finding.ResourceID),
+			xlsxText(finding.ResourceName),
+			xlsxText(finding.Message),
+		})
+	}
+
+	return sheet
+}
+
+// buildTagComplianceSheet lists the tag coverage per service, region and account,
+// then the resources missing required tags
+func buildTagComplianceSheet(compliance *models.TagCompliance) xlsxSheet {
+	sheet := xlsxSheet{name: "Tag Compliance"}
+	sheet.rows = append(sheet.rows, []xlsxCell{
+		xlsxHeader("Group"), xlsxHeader("Name"), xlsxHeader("Resources"), xlsxHeader("Compliant"), xlsxHeader("Coverage %"),
+	})
+	addCoverage := func(group string, coverage models.TagCoverage) {
+		sheet.rows = append(sheet.rows, []xlsxCell{
+			xlsxText(group),
+			xlsxText(coverage.Name),
+			xlsxNumber(float64(coverage.Resources)),
+			xlsxNumber(float64(coverage.Compliant)),
+			xlsxNumber(coverage.Coverage),
+		})
+	}
+	addCoverage("Total", compliance.Total)
+	for _, coverage := range compliance.ByService {
+		addCoverage("Service", coverage)
+	}
+	for _, coverage := range compliance.ByRegion {
+		addCoverage("Region", coverage)
+	}
+	for _, coverage := range compliance.ByAccount {
+		addCoverage("Account", coverage)
+	}
+
+	if len(compliance.NonCompliant) > 0 {
+		sheet.rows = append(sheet.rows, nil, []xlsxCell{
+			xlsxHeader("Service"), xlsxHeader("Region"), xlsxHeader("ID"), xlsxHeader("Name"), xlsxHeader("Missing Tags"),
+		})
+		for _, resource := range compliance.NonCompliant {
+			sheet.rows = append(sheet.rows, []xlsxCell{
+				xlsxText(resource.Service),
+				xlsxText(resource.Region),
+				xlsxText(resource.ID),
+				xlsxText(resource.Name),
+				xlsxText(strings.Join(resource.Missing, ", ")),
+			})
+		}
+	}
+
+	return sheet
+}
+
+// writeWorkbook writes the sheets as an Office Open XML workbook
+func writeWorkbook(w io.Writer, sheets []xlsxSheet) error {
+	archive := zip.NewWriter(w)
+
+	var contentTypes, workbook, workbookRels strings.Builder
+	contentTypes.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	workbookRels.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	usedNames := make(map[string]bool)
+	for i, sheet := range sheets {
+		id := i + 1
+		name := xlsxSheetName(sheet.name, usedNames)
+
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, id)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xlsxEscape(name), id, id)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, id, id)
+
+		if err := writeZipEntry(archive, fmt.Sprintf("xl/worksheets/sheet%d.xml", id), sheetXML(sheet)); err != nil {
+			return err
+		}
+	}
+
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/></Relationships>`, len(sheets)+1)
+
+	entries := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, entry := range entries {
+		if err := writeZipEntry(archive, entry.name, entry.content); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// writeZipEntry adds a file to the workbook archive
+func writeZipEntry(archive *zip.Writer, name, content string) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(entry, content)
+	return err
+}
+
+// sheetXML renders a worksheet with a frozen first row and columns sized to their content
+func sheetXML(sheet xlsxSheet) string {
+	var widths []int
+	for _, row := range sheet.rows {
+		for col, cell := range row {
+			if col >= len(widths) {
+				widths = append(widths, 0)
+			}
+			width := len([]rune(cell.text)) + 2
+			if cell.isNumber {
+				width = len(fmt.Sprintf("%.2f", cell.number)) + 4
+			}
+			if width > widths[col] {
+				widths[col] = width
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+
+	if len(widths) > 0 {
+		b.WriteString(`<cols>`)
+		for col, width := range widths {
+			if width > xlsxMaxColumnWidth {
+				width = xlsxMaxColumnWidth
+			}
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, col+1, col+1, width)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	for r, row := range sheet.rows {
+		if len(row) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, `<row r="%d">`, r+1)
+		for col, cell := range row {
+			ref := fmt.Sprintf("%s%d", xlsxColumnName(col), r+1)
+			if cell.isNumber {
+				fmt.Fprintf(&b, `<c r="%s" s="%d"><v>%s</v></c>`, ref, cell.style, strconv.FormatFloat(cell.number, 'f', -1, 64))
+				continue
+			}
+			if cell.text == "" {
+				continue
+			}
+			fmt.Fprintf(&b, `<c r="%s" s="%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, cell.style, xlsxEscape(cell.text))
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+
+	return b.String()
+}
+
+// xlsxStyles defines the default, bold header and currency cell styles
+const xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+	`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="&quot;$&quot;#,##0.00"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
+
+// xlsxColumnName converts a zero-based column index to its letter name (A, B, ..., AA)
+func xlsxColumnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
+
+// xlsxSheetName makes a sheet name valid and unique: at most 31 characters and
+// none of the characters Excel reserves
+func xlsxSheetName(name string, used map[string]bool) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "Sheet"
+	}
+
+	candidate := truncateRunes(name, 31)
+	for i := 2; used[strings.ToLower(candidate)]; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		candidate = truncateRunes(name, 31-len(suffix)) + suffix
+	}
+	used[strings.ToLower(candidate)] = true
+
+	return candidate
+}
+
+// truncateRunes shortens a string to at most n runes
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// xlsxEscape escapes text for use in XML content and attributes
+func xlsxEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+// xlsxText creates a text cell
+func xlsxText(text string) xlsxCell {
+	return xlsxCell{text: text, style: xlsxStyleDefault}
+}
+
+// xlsxHeader creates a bold text cell
+func xlsxHeader(text string) xlsxCell {
+	return xlsxCell{text: text, style: xlsxStyleHeader}
+}
+
+// xlsxNumber creates a numeric cell
+func xlsxNumber(number float64) xlsxCell {
+	return xlsxCell{number: number, isNumber: true, style: xlsxStyleDefault}
+}
+
+// xlsxMoney creates a numeric cell formatted as dollars
+func xlsxMoney(amount float64) xlsxCell {
+	return xlsxCell{number: amount, isNumber: true, style: xlsxStyleMoney}
+}
+
+// sortedKeys returns the keys of a string-keyed map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
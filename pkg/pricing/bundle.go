@@ -0,0 +1,188 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+)
+
+// BundleServices are the services whose prices a bundle holds: those priced by
+// instance type
+var BundleServices = []string{"ec2", "rds", "redis"}
+
+// Bundle is a snapshot of on-demand prices, so costs can be estimated without access
+// to the Pricing API
+type Bundle struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Currency  string    `json:"currency"`
+
+	// Prices are hourly prices by service, region and instance type. Services with
+	// variant attributes also have the price of each variant, keyed by priceKey.
+	Prices map[string]map[string]map[string]float64 `json:"prices"`
+}
+
+// Regions returns the regions whose prices can be looked up, in order
+func Regions() []string {
+	regions := make([]string, 0, len(regionLocations))
+	for region := range regionLocations {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// LoadBundle reads a bundle written by Bundle.Save
+func LoadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pricing bundle: %w", err)
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse pricing bundle %s: %w", path, err)
+	}
+	return &bundle, nil
+}
+
+// Save writes the bundle to path
+func (b *Bundle) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// lookup returns the price of an instance type variant as a pricing result. Bundles
+// without the variant, such as those saved before variants were kept, give the lowest
+// price of the instance type, graded less accurate.
+func (b *Bundle) lookup(service, region, instanceType string, attributes map[string]string) *PricingResult {
+	accuracy := "High"
+	price, ok := b.Prices[service][region][priceKey(instanceType, serviceConfigs[service].VariantAttributes, attributes)]
+	if !ok && len(attributes) > 0 {
+		price, ok = b.Prices[service][region][instanceType]
+		accuracy = "Medium"
+	}
+	if !ok {
+		return nil
+	}
+	return &PricingResult{
+		HourlyPrice:  price,
+		MonthlyPrice: price * 730,
+		Currency:     b.Currency,
+		Region:       region,
+		Accuracy:     accuracy,
+		Source:       "bundle",
+	}
+}
+
+// DownloadBundle fetches the on-demand prices of every instance type of the services
+// in the regions from the Pricing API. Where a type has several prices, such as RDS
+// instances of different engines, the lowest is kept for the type along with the price
+// of each variant. progress, if set, is called after each service and region.
+func (ps *PricingService) DownloadBundle(ctx context.Context, services, regions []string, progress func(service, region string, prices int)) (*Bundle, error) {
+	bundle := &Bundle{
+		CreatedAt: time.Now().UTC(),
+		Currency:  "USD",
+		Prices:    make(map[string]map[string]map[string]float64),
+	}
+
+	for _, service := range services {
+		serviceConfig := ps.GetServiceConfig(service)
+		if serviceConfig.ServiceCode == "Unknown" {
+			return nil, fmt.Errorf("no prices for service %s (available: %v)", service, BundleServices)
+		}
+		bundle.Prices[service] = make(map[string]map[string]float64)
+
+		for _, region := range regions {
+			location, ok := ps.getLocationFromRegion(region)
+			if !ok {
+				return nil, fmt.Errorf("no pricing location for region %s", region)
+			}
+
+			prices, err := ps.fetchRegionPrices(ctx, serviceConfig, location)
+			if err != nil {
+				return nil, fmt.Errorf("failed to download %s prices for %s: %w", service, region, err)
+			}
+			bundle.Prices[service][region] = prices
+			if progress != nil {
+				progress(service, region, len(prices))
+			}
+		}
+	}
+
+	return bundle, nil
+}
+
+// fetchRegionPrices lists the hourly price of each instance type of a service in a
+// location
+func (ps *PricingService) fetchRegionPrices(ctx context.Context, serviceConfig ServiceConfig, location string) (map[string]float64, error) {
+	prices := make(map[string]float64)
+
+	var nextToken *string
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		resp, err := ps.pricingClient.GetProducts(ctx, &pricing.GetProductsInput{
+			ServiceCode: aws.String(serviceConfig.ServiceCode),
+			Filters:     productFilters(serviceConfig, location, "", nil),
+			MaxResults:  aws.Int32(100),
+			NextToken:   nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.PriceList {
+			attributes := productAttributes(item)
+			instanceType := attributes["instanceType"]
+			if instanceType == "" {
+				continue
+			}
+			price, err := ps.parsePricingData(item)
+			if err != nil || price == 0 {
+				continue
+			}
+
+			keys := []string{instanceType}
+			if len(serviceConfig.VariantAttributes) > 0 {
+				keys = append(keys, priceKey(instanceType, serviceConfig.VariantAttributes, attributes))
+			}
+			for _, key := range keys {
+				if existing, ok := prices[key]; !ok || price < existing {
+					prices[key] = price
+				}
+			}
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return prices, nil
+}
+
+// productAttributes returns the attributes of a price list product, such as its
+// instance type
+func productAttributes(priceListItem string) map[string]string {
+	var data struct {
+		Product struct {
+			Attributes map[string]string `json:"attributes"`
+		} `json:"product"`
+	}
+	if err := json.Unmarshal([]byte(priceListItem), &data); err != nil {
+		return nil
+	}
+	return data.Product.Attributes
+}
@@ -0,0 +1,155 @@
+package pricing
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ec2LargeHourlyPrices are us-east-1 Linux on-demand hourly prices of the large size
+// of each EC2 instance family, or what it would cost for families whose smallest size
+// is bigger. Within a family, prices scale with the size's normalization factor.
+// GPU and accelerator families are left out, as their prices do not scale with size.
+var ec2LargeHourlyPrices = map[string]float64{
+	// Burstable
+	"t2":  0.0928,
+	"t3":  0.0832,
+	"t3a": 0.0752,
+	"t4g": 0.0672,
+
+	// General purpose
+	"m4":       0.10,
+	"m5":       0.096,
+	"m5a":      0.086,
+	"m5ad":     0.103,
+	"m5d":      0.113,
+	"m5n":      0.119,
+	"m5zn":     0.1652,
+	"m6a":      0.0864,
+	"m6g":      0.077,
+	"m6gd":     0.0904,
+	"m6i":      0.096,
+	"m6id":     0.1187,
+	"m6in":     0.1393,
+	"m7a":      0.1159,
+	"m7g":      0.0816,
+	"m7gd":     0.1068,
+	"m7i":      0.1008,
+	"m7i-flex": 0.0958,
+	"m8g":      0.0898,
+
+	// Compute optimized
+	"c4":   0.10,
+	"c5":   0.085,
+	"c5a":  0.077,
+	"c5d":  0.096,
+	"c5n":  0.108,
+	"c6a":  0.0765,
+	"c6g":  0.068,
+	"c6gd": 0.0768,
+	"c6gn": 0.0864,
+	"c6i":  0.085,
+	"c6id": 0.1008,
+	"c6in": 0.1134,
+	"c7a":  0.1026,
+	"c7g":  0.0725,
+	"c7gd": 0.0907,
+	"c7gn": 0.0999,
+	"c7i":  0.0893,
+	"c8g":  0.0798,
+
+	// Memory optimized
+	"r4":   0.133,
+	"r5":   0.126,
+	"r5a":  0.113,
+	"r5b":  0.149,
+	"r5d":  0.144,
+	"r5n":  0.149,
+	"r6a":  0.1134,
+	"r6g":  0.1008,
+	"r6gd": 0.1152,
+	"r6i":  0.126,
+	"r6id": 0.1512,
+	"r7a":  0.1521,
+	"r7g":  0.1071,
+	"r7gd": 0.1361,
+	"r7i":  0.1323,
+	"r8g":  0.1178,
+	"x2gd": 0.167,
+	"z1d":  0.186,
+
+	// Storage optimized
+	"i3":    0.156,
+	"i3en":  0.226,
+	"i4g":   0.1544,
+	"i4i":   0.172,
+	"im4gn": 0.1817,
+}
+
+// ec2SizeFactors are the normalization factors of instance sizes, which on-demand
+// prices are proportional to within a family
+var ec2SizeFactors = map[string]float64{
+	"nano":     0.25,
+	"micro":    0.5,
+	"small":    1,
+	"medium":   2,
+	"large":    4,
+	"xlarge":   8,
+	"2xlarge":  16,
+	"3xlarge":  24,
+	"4xlarge":  32,
+	"6xlarge":  48,
+	"8xlarge":  64,
+	"9xlarge":  72,
+	"10xlarge": 80,
+	"12xlarge": 96,
+	"16xlarge": 128,
+	"18xlarge": 144,
+	"24xlarge": 192,
+	"32xlarge": 256,
+	"48xlarge": 384,
+}
+
+// ec2CatalogPrice returns the built-in us-east-1 hourly price of an instance type,
+// from its family's price and its size, and whether the type is in the catalog
+func ec2CatalogPrice(instanceType string) (float64, bool) {
+	family, size, ok := strings.Cut(instanceType, ".")
+	if !ok {
+		return 0, false
+	}
+	largePrice, ok := ec2LargeHourlyPrices[family]
+	if !ok {
+		return 0, false
+	}
+	factor, ok := ec2SizeFactors[size]
+	if !ok {
+		return 0, false
+	}
+	return largePrice * factor / ec2SizeFactors["large"], true
+}
+
+// gravitonFamily matches the instance families of AWS Graviton (arm64) processors,
+// whose generation is followed by a g, such as m6g, c7gn, t4g and im4gn
+var gravitonFamily = regexp.MustCompile(`^[a-z]+\d+g`)
+
+// isGraviton reports whether an instance type runs on AWS Graviton processors
+func isGraviton(instanceType string) bool {
+	family, _, _ := strings.Cut(instanceType, ".")
+	return gravitonFamily.MatchString(family)
+}
+
+// burstableCreditPrices are the prices per vCPU-hour of the CPU credits burstable
+// families charge for sustained use above their baseline in unlimited mode
+var burstableCreditPrices = map[string]float64{
+	"t2":  0.05,
+	"t3":  0.05,
+	"t3a": 0.05,
+	"t4g": 0.04,
+}
+
+// burstableCreditPrice returns the surplus CPU credit price of a burstable instance
+// type, and whether the type is burstable
+func burstableCreditPrice(instanceType string) (float64, bool) {
+	family, _, _ := strings.Cut(instanceType, ".")
+	price, ok := burstableCreditPrices[family]
+	return price, ok
+}
@@ -0,0 +1,292 @@
+package pricing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// storageBreakdownKeys are the parts of an instance estimate that reservations and
+// Savings Plans do not cover
+var storageBreakdownKeys = map[string]bool{"storage": true, "iops": true, "throughput": true}
+
+// reservationHourly returns the effective hourly rate of one reserved instance: the
+// upfront price spread over the term, plus the usage and recurring hourly charges
+func reservationHourly(resource models.Resource) float64 {
+	fixed, _ := models.ExtraNumber(resource.Extra, "fixedPrice")
+	usage, _ := models.ExtraNumber(resource.Extra, "usagePrice")
+	recurring, _ := models.ExtraNumber(resource.Extra, "recurringHourly")
+	duration, _ := models.ExtraNumber(resource.Extra, "durationSeconds")
+
+	hourly := usage + recurring
+	if duration > 0 {
+		hourly += fixed / (duration / 3600)
+	}
+	return hourly
+}
+
+// estimateReservationCost estimates the monthly cost of a reservation as if none of
+// it were used. Annotate attributes the used part to the instances it covers.
+func estimateReservationCost(resource models.Resource) *models.CostEstimate {
+	if resource.State != "active" {
+		return &models.CostEstimate{Amount: 0, Explanation: fmt.Sprintf("Reservation %s: $0.00/month (%s)", resource.Name, resource.State)}
+	}
+
+	count, _ := models.ExtraNumber(resource.Extra, "instanceCount")
+	hourly := reservationHourly(resource)
+	monthlyCost := hourly * 730 * count
+
+	return &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        fmt.Sprintf("Reservation %s: $%.2f/month for %.0f × %s", resource.Name, monthlyCost, count, resource.Class),
+		Formula:            "Monthly Cost = (Upfront / Term hours + Hourly charges) × 730 hours × Instances",
+		FormulaExplanation: "Reservations are paid whether or not matching instances run. The cost of each covered instance is attributed to that instance.",
+		Breakdown:          map[string]float64{"unused": monthlyCost},
+		Accuracy:           "High",
+		Source:             "commitment",
+		Assumptions: []string{
+			fmt.Sprintf("Effective rate $%.4f/hour per instance", hourly),
+		},
+	}
+}
+
+// estimateSavingsPlanCost estimates the monthly cost of a Savings Plan commitment
+func estimateSavingsPlanCost(resource models.Resource) *models.CostEstimate {
+	if resource.State != "active" {
+		return &models.CostEstimate{Amount: 0, Explanation: fmt.Sprintf("Savings Plan %s: $0.00/month (%s)", resource.Name, resource.State)}
+	}
+
+	commitment, _ := models.ExtraNumber(resource.Extra, "commitmentHourly")
+	monthlyCost := commitment * 730
+
+	return &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        fmt.Sprintf("%s Savings Plan %s: $%.2f/month ($%.2f/hour commitment)", resource.Type, resource.Name, monthlyCost, commitment),
+		Formula:            "Monthly Cost = Hourly commitment × 730 hours",
+		FormulaExplanation: "Savings Plans charge the hourly commitment whether or not it is used. Covered usage is billed against it instead of at on-demand rates.",
+		Breakdown:          map[string]float64{"commitment": monthlyCost},
+		Accuracy:           "High",
+		Source:             "commitment",
+	}
+}
+
+// applyCommitments attributes reservations and Savings Plans to the running
+// instances they cover, in the order AWS applies them: zonal reservations, regional
+// reservations, then Savings Plans. Covered instances are marked and repriced, and
+// each commitment's estimate records how much of it is used.
+func applyCommitments(resources []models.Resource, costs map[string]*models.CostEstimate) {
+	var reservations, plans []models.Resource
+	for _, resource := range resources {
+		if resource.State != "active" {
+			continue
+		}
+		switch resource.Service {
+		case "reservations":
+			reservations = append(reservations, resource)
+		case "savingsplans":
+			plans = append(plans, resource)
+		}
+	}
+
+	sort.SliceStable(reservations, func(i, j int) bool {
+		zonalI, _ := reservations[i].Extra["availabilityZone"].(string)
+		zonalJ, _ := reservations[j].Extra["availabilityZone"].(string)
+		if (zonalI != "") != (zonalJ != "") {
+			return zonalI != ""
+		}
+		return reservations[i].ID < reservations[j].ID
+	})
+	for _, reservation := range reservations {
+		applyReservation(reservation, resources, costs)
+	}
+
+	// EC2 Instance Savings Plans apply before Compute Savings Plans
+	sort.SliceStable(plans, func(i, j int) bool {
+		if plans[i].Type != plans[j].Type {
+			return plans[i].Type == "EC2Instance"
+		}
+		return plans[i].ID < plans[j].ID
+	})
+	for _, plan := range plans {
+		applySavingsPlan(plan, resources, costs)
+	}
+}
+
+// applyReservation covers up to the reservation's instance count of matching,
+// uncovered instances
+func applyReservation(reservation models.Resource, resources []models.Resource, costs map[string]*models.CostEstimate) {
+	estimate := costs[reservation.Key()]
+	if estimate == nil {
+		return
+	}
+
+	count, _ := models.ExtraNumber(reservation.Extra, "instanceCount")
+	hourly := reservationHourly(reservation)
+	used := 0
+	for _, resource := range resources {
+		if float64(used) >= count {
+			break
+		}
+		covered := costs[resource.Key()]
+		if covered == nil || covered.Coverage != "" || !reservationMatches(reservation, resource) {
+			continue
+		}
+
+		coverInstance(covered, "reserved", hourly*730)
+		covered.Explanation = fmt.Sprintf("%s (reserved by %s)", covered.Explanation, reservation.ID)
+		covered.Assumptions = append(covered.Assumptions,
+			fmt.Sprintf("Covered by reservation %s at $%.4f/hour instead of $%.2f/month on demand", reservation.ID, hourly, covered.OnDemandAmount))
+		used++
+	}
+
+	unused := count - float64(used)
+	estimate.Amount = unused * hourly * 730
+	estimate.Breakdown = map[string]float64{"unused": estimate.Amount}
+	estimate.Explanation = fmt.Sprintf("Reservation %s: %d of %.0f × %s in use, $%.2f/month unused", reservation.Name, used, count, reservation.Class, estimate.Amount)
+	estimate.Assumptions = append(estimate.Assumptions,
+		"The cost of used capacity is attributed to the covered instances",
+		"Matched on exact instance type; size flexibility across an instance family is not applied")
+}
+
+// reservationMatches reports whether a reservation applies to a resource: a running
+// instance of the same type in the same region and, for zonal reservations, zone
+func reservationMatches(reservation, resource models.Resource) bool {
+	if resource.Region != reservation.Region || resource.Class == "" {
+		return false
+	}
+
+	switch reservation.Type {
+	case "ec2-reserved-instance":
+		if resource.Service != "ec2" || resource.State != "running" || resource.Type != reservation.Class {
+			return false
+		}
+		// Spot instances are not covered by commitments
+		if resource.Extra["lifecycle"] != nil {
+			return false
+		}
+		if zone, _ := reservation.Extra["availabilityZone"].(string); zone != "" && zone != resource.Extra["availabilityZone"] {
+			return false
+		}
+		// Windows reservations only cover Windows instances, and the other way round
+		description, _ := reservation.Extra["productDescription"].(string)
+		platform, _ := resource.Extra["platform"].(string)
+		return strings.Contains(description, "Windows") == (platform == "windows")
+	case "rds-reserved-instance":
+		if resource.Service != "rds" || resource.State != "available" || resource.Class != reservation.Class {
+			return false
+		}
+		multiAZ, _ := reservation.Extra["multiAZ"].(bool)
+		instanceMultiAZ, _ := resource.Extra["multiAZ"].(bool)
+		return multiAZ == instanceMultiAZ
+	default:
+		return false
+	}
+}
+
+// applySavingsPlan covers uncovered running EC2 instances, most expensive first,
+// while the plan's monthly commitment lasts
+func applySavingsPlan(plan models.Resource, resources []models.Resource, costs map[string]*models.CostEstimate) {
+	estimate := costs[plan.Key()]
+	if estimate == nil {
+		return
+	}
+	commitment := estimate.Amount
+
+	type candidate struct {
+		resource models.Resource
+		compute  float64
+	}
+	var candidates []candidate
+	for _, resource := range resources {
+		covered := costs[resource.Key()]
+		if covered == nil || covered.Coverage != "" || !savingsPlanMatches(plan, resource) {
+			continue
+		}
+		if compute := computeCost(covered); compute > 0 {
+			candidates = append(candidates, candidate{resource, compute})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].compute != candidates[j].compute {
+			return candidates[i].compute > candidates[j].compute
+		}
+		return candidates[i].resource.ID < candidates[j].resource.ID
+	})
+
+	remaining := commitment
+	covered := 0
+	for _, candidate := range candidates {
+		if candidate.compute > remaining {
+			continue
+		}
+		remaining -= candidate.compute
+
+		instance := costs[candidate.resource.Key()]
+		coverInstance(instance, "savings-plan", 0)
+		instance.Explanation = fmt.Sprintf("%s (compute covered by Savings Plan %s)", instance.Explanation, plan.ID)
+		instance.Assumptions = append(instance.Assumptions,
+			fmt.Sprintf("Compute covered by Savings Plan %s; its cost is counted on the plan", plan.ID))
+		covered++
+	}
+
+	estimate.Breakdown = map[string]float64{"used": commitment - remaining, "unused": remaining}
+	estimate.Explanation = fmt.Sprintf("%s (%d instances covered, $%.2f/month unused)", estimate.Explanation, covered, remaining)
+	estimate.Assumptions = append(estimate.Assumptions,
+		"Covered instances are counted at on-demand rates against the commitment, so fewer instances are covered than with the plan's discounted rates",
+		"Only EC2 instances are matched; Fargate and Lambda usage is not attributed")
+}
+
+// savingsPlanMatches reports whether a Savings Plan applies to a resource: any running
+// EC2 instance for Compute plans, and instances of the plan's family in its region
+// for EC2 Instance plans
+func savingsPlanMatches(plan, resource models.Resource) bool {
+	if resource.Service != "ec2" || resource.State != "running" || resource.Extra["lifecycle"] != nil {
+		return false
+	}
+
+	switch plan.Type {
+	case "Compute":
+		return true
+	case "EC2Instance":
+		region, _ := plan.Extra["region"].(string)
+		family, _, _ := strings.Cut(resource.Type, ".")
+		return resource.Region == region && family == plan.Class
+	default:
+		return false
+	}
+}
+
+// computeCost returns the part of an instance estimate that commitments can cover,
+// everything but its storage
+func computeCost(estimate *models.CostEstimate) float64 {
+	storage := 0.0
+	for key, amount := range estimate.Breakdown {
+		if storageBreakdownKeys[key] {
+			storage += amount
+		}
+	}
+	return estimate.Amount - storage
+}
+
+// coverInstance marks an instance estimate as covered by a commitment, replacing its
+// compute cost with the committed monthly cost
+func coverInstance(estimate *models.CostEstimate, coverage string, committed float64) {
+	compute := computeCost(estimate)
+	estimate.OnDemandAmount = estimate.Amount
+	estimate.Amount = estimate.Amount - compute + committed
+	estimate.Coverage = coverage
+
+	for key := range estimate.Breakdown {
+		if !storageBreakdownKeys[key] {
+			delete(estimate.Breakdown, key)
+		}
+	}
+	if committed > 0 {
+		if estimate.Breakdown == nil {
+			estimate.Breakdown = make(map[string]float64)
+		}
+		estimate.Breakdown[coverage] = committed
+	}
+}
@@ -0,0 +1,213 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// Engine estimates the monthly cost of resources. It prefers live prices from the
+// Pricing API, cached by the PricingService, and falls back to built-in prices when
+// the API is unavailable or has no price for a resource. It is safe for concurrent use.
+type Engine struct {
+	service *PricingService
+	bundle  *Bundle
+
+	mu      sync.Mutex
+	liveErr error // set once the Pricing API fails, after which only built-in prices are used
+}
+
+// NewEngine creates an engine that looks up live prices with service, or that only
+// uses built-in prices when service is nil
+func NewEngine(service *PricingService) *Engine {
+	return &Engine{service: service}
+}
+
+// builtInPriceRegion is the region the built-in prices are taken from
+const builtInPriceRegion = "us-east-1"
+
+// NewOfflineEngine creates an engine that uses the prices of a bundle, falling back to
+// built-in prices, without calling the Pricing API
+func NewOfflineEngine(bundle *Bundle) *Engine {
+	return &Engine{bundle: bundle}
+}
+
+// Estimate returns the monthly cost estimate of a resource. Estimates of resources
+// outside us-east-1 that use built-in prices are graded less accurate, since those
+// prices are for us-east-1.
+func (e *Engine) Estimate(ctx context.Context, resource models.Resource) *models.CostEstimate {
+	estimate := e.estimate(ctx, resource)
+	if estimate != nil && !regionallyPriced(estimate, resource.Region) {
+		estimate.Accuracy = lowerAccuracy(estimate.Accuracy)
+		estimate.Assumptions = append(estimate.Assumptions,
+			fmt.Sprintf("No %s prices available; %s prices used", resource.Region, builtInPriceRegion))
+	}
+	return estimate
+}
+
+// estimate returns the estimate of the estimator registered for the resource's
+// service, or else the built-in estimate of the service
+func (e *Engine) estimate(ctx context.Context, resource models.Resource) *models.CostEstimate {
+	if estimate := customEstimate(resource); estimate != nil {
+		return estimate
+	}
+
+	switch resource.Service {
+	case "ec2":
+		return e.estimateEC2Cost(ctx, resource)
+	case "rds":
+		return e.estimateRDSCost(ctx, resource)
+	case "lambda":
+		return estimateLambdaCost(resource)
+	case "s3":
+		return estimateS3Cost(resource)
+	case "dynamodb":
+		return estimateDynamoDBCost(resource)
+	case "sfn":
+		return estimateSFNCost(resource)
+	case "cloudwatch":
+		return estimateCloudWatchCost(resource)
+	case "ecs":
+		return estimateECSCost(resource)
+	case "redis":
+		return e.estimateRedisCost(ctx, resource)
+	case "efs":
+		return estimateEFSCost(resource)
+	case "waf":
+		return estimateWAFCost(resource)
+	case "shield":
+		return estimateShieldCost(resource)
+	case "eip":
+		return estimateEIPCost(resource)
+	case "ami":
+		return estimateAMICost(resource)
+	case "ebs":
+		return estimateEBSCost(resource)
+	case "elb":
+		return estimateELBCost(resource)
+	case "network":
+		return estimateNetworkCost(resource)
+	case "directconnect":
+		return estimateDirectConnectCost(resource)
+	case "apprunner":
+		return estimateAppRunnerCost(resource)
+	case "amplify":
+		return estimateAmplifyCost(resource)
+	case "backup":
+		return estimateBackupCost(resource)
+	case "fsx":
+		return estimateFSxCost(resource)
+	case "eventbridge":
+		return estimateEventBridgeCost(resource)
+	case "batch":
+		return estimateBatchCost(resource)
+	case "codebuild":
+		return estimateCodeBuildCost(resource)
+	case "codepipeline":
+		return estimateCodePipelineCost(resource)
+	case "reservations":
+		return estimateReservationCost(resource)
+	case "savingsplans":
+		return estimateSavingsPlanCost(resource)
+	default:
+		return &models.CostEstimate{Amount: 0}
+	}
+}
+
+// regionallyPriced reports whether an estimate is priced for the region: it comes
+// from the Pricing API, a bundle, a spot price, the prices of a commitment or a
+// registered estimator, the region is that of the built-in prices, or nothing is
+// charged
+func regionallyPriced(estimate *models.CostEstimate, region string) bool {
+	switch {
+	case estimate.Source == "api" || estimate.Source == "cache" || estimate.Source == "bundle" ||
+		estimate.Source == "spot-price" || estimate.Source == "commitment" || estimate.Source == customSource:
+		return true
+	case region == builtInPriceRegion || region == models.GlobalRegion || region == "":
+		return true
+	default:
+		return estimate.Amount == 0
+	}
+}
+
+// lowerAccuracy returns the accuracy grade below the given one
+func lowerAccuracy(accuracy string) string {
+	switch accuracy {
+	case "High":
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+// EstimateAll returns the monthly cost estimates of resources, keyed by Resource.Key
+func (e *Engine) EstimateAll(ctx context.Context, resources []models.Resource) map[string]*models.CostEstimate {
+	costs := make(map[string]*models.CostEstimate, len(resources))
+	for _, resource := range resources {
+		if estimate := e.Estimate(ctx, resource); estimate != nil {
+			costs[resource.Key()] = estimate
+		}
+	}
+	return costs
+}
+
+// Annotate adds the cost estimate of each resource to the collection, unless it was
+// annotated already, so every output of a run shows the same figures. Instances
+// covered by the collected reservations and Savings Plans are priced at the committed
+// rates.
+func (e *Engine) Annotate(ctx context.Context, collection *models.ResourceCollection) {
+	if collection.Costs != nil {
+		return
+	}
+	collection.Costs = e.EstimateAll(ctx, collection.Resources)
+	applyCommitments(collection.Resources, collection.Costs)
+}
+
+// FreeTier returns the free tier usage by service and whether the account is
+// eligible, or nil without live pricing
+func (e *Engine) FreeTier() (map[string]FreeTierUsage, bool) {
+	if e.service == nil {
+		return nil, false
+	}
+	return e.service.GetFreeTierInfo(), e.service.IsFreeTierEligible()
+}
+
+// livePrice looks up a price in the bundle or the Pricing API, or returns nil to fall
+// back to built-in prices. attributes select a variant of the instance type, see
+// ServiceConfig.VariantAttributes. After the API fails once, it is not called again.
+func (e *Engine) livePrice(ctx context.Context, service, region, instanceType string, attributes map[string]string) *PricingResult {
+	if instanceType == "" {
+		return nil
+	}
+	if e.bundle != nil {
+		return e.bundle.lookup(service, region, instanceType, attributes)
+	}
+	if e.service == nil {
+		return nil
+	}
+
+	e.mu.Lock()
+	failed := e.liveErr != nil
+	e.mu.Unlock()
+	if failed {
+		return nil
+	}
+
+	result, err := e.service.GetPricing(ctx, service, region, instanceType, attributes)
+	if err != nil {
+		if !errors.Is(err, errNoPrice) {
+			e.mu.Lock()
+			if e.liveErr == nil {
+				e.liveErr = err
+				log.Printf("Warning: Pricing API unavailable, using built-in prices: %v", err)
+			}
+			e.mu.Unlock()
+		}
+		return nil
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package pricing
+
+import (
+	"sync"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// CostEstimator estimates the monthly cost of a resource. Programs embedding the
+// engine register estimators to replace the built-in estimates of a service, for
+// example to apply negotiated private pricing.
+type CostEstimator interface {
+	// Estimate returns the monthly cost estimate of a resource, or nil to use the
+	// built-in estimate
+	Estimate(resource models.Resource) *models.CostEstimate
+}
+
+// CostEstimatorFunc adapts a function to the CostEstimator interface
+type CostEstimatorFunc func(resource models.Resource) *models.CostEstimate
+
+// Estimate calls f
+func (f CostEstimatorFunc) Estimate(resource models.Resource) *models.CostEstimate {
+	return f(resource)
+}
+
+// customSource is the Source of estimates made by registered estimators
+const customSource = "custom"
+
+var (
+	estimatorsMu sync.RWMutex
+	estimators   = make(map[string]CostEstimator)
+)
+
+// RegisterEstimator makes every Engine estimate the resources of a service with
+// estimator before the built-in estimate, which is only used when estimator returns
+// nil. Registering nil removes the service's estimator.
+func RegisterEstimator(service string, estimator CostEstimator) {
+	estimatorsMu.Lock()
+	defer estimatorsMu.Unlock()
+
+	if estimator == nil {
+		delete(estimators, service)
+		return
+	}
+	estimators[service] = estimator
+}
+
+// customEstimate returns the estimate of the estimator registered for the resource's
+// service, or nil without one. Estimates without a source are marked custom, so
+// they are not graded down as built-in prices are outside us-east-1.
+func customEstimate(resource models.Resource) *models.CostEstimate {
+	estimatorsMu.RLock()
+	estimator := estimators[resource.Service]
+	estimatorsMu.RUnlock()
+
+	if estimator == nil {
+		return nil
+	}
+	estimate := estimator.Estimate(resource)
+	if estimate != nil && estimate.Source == "" {
+		estimate.Source = customSource
+	}
+	return estimate
+}
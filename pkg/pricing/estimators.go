@@ -0,0 +1,1716 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// estimateEC2Cost estimates EC2 instance cost including attached EBS volumes
+func (e *Engine) estimateEC2Cost(ctx context.Context, resource models.Resource) *models.CostEstimate {
+	estimate := e.estimateEC2InstanceCost(ctx, resource)
+	switch resource.State {
+	case "running":
+		if resource.Extra["lifecycle"] == "spot" {
+			priceSpot(estimate, resource)
+		}
+	case "stopping", "stopped":
+		// Stopped instances are not charged for compute, but their volumes keep billing
+		estimate.Assumptions = append(estimate.Assumptions, "Stopped instances are not charged for compute, but their EBS volumes are billed until deleted")
+	default:
+		return estimate
+	}
+
+	if storage, ok := ebsStorageCost(resource); ok {
+		addStorageCost(estimate, storage)
+	} else {
+		estimate.Assumptions = append(estimate.Assumptions, "Excludes EBS storage (volumes not collected)")
+	}
+	return estimate
+}
+
+// spotDiscount is the discount from on-demand rates assumed for spot instances
+// without a current spot price. Spot prices are usually 60-90% lower, so half off
+// errs on the side of cost.
+const spotDiscount = 0.5
+
+// priceSpot reprices the on-demand estimate of a spot instance, at the current spot
+// price the collector recorded or else at a conservative discount
+func priceSpot(estimate *models.CostEstimate, resource models.Resource) {
+	// The breakdown holds the on-demand price, before any free tier
+	onDemand := 0.0
+	for _, amount := range estimate.Breakdown {
+		onDemand += amount
+	}
+	if onDemand == 0 {
+		onDemand = estimate.Amount
+	}
+
+	estimate.FreeTierCovered = false
+	estimate.FreeTierSavings = 0
+	estimate.Examples = nil
+
+	if price, ok := models.ExtraNumber(resource.Extra, "spotPrice"); ok {
+		zone, _ := resource.Extra["availabilityZone"].(string)
+		estimate.Amount = price * 730
+		estimate.Explanation = fmt.Sprintf("EC2 %s spot instance: $%.2f/month at the current spot price", resource.Type, estimate.Amount)
+		estimate.Formula = "Monthly Cost = Spot price × 730 hours"
+		estimate.FormulaExplanation = "Spot instances are charged the spot price of their type and zone, which changes with supply and demand."
+		estimate.Accuracy = "Medium"
+		estimate.Source = "spot-price"
+		estimate.Assumptions = []string{
+			fmt.Sprintf("Current spot price $%.4f/hour in %s", price, zone),
+			fmt.Sprintf("On-demand: $%.2f/month", onDemand),
+			"Spot prices change over time and the instance may be interrupted",
+			"Excludes data transfer and other costs",
+		}
+	} else {
+		estimate.Amount = onDemand * (1 - spotDiscount)
+		estimate.Explanation = fmt.Sprintf("EC2 %s spot instance: $%.2f/month (estimated at %.0f%% off on-demand)", resource.Type, estimate.Amount, spotDiscount*100)
+		estimate.Formula = fmt.Sprintf("Monthly Cost = On-demand rate × 730 hours × %.0f%%", (1-spotDiscount)*100)
+		estimate.FormulaExplanation = "No current spot price was collected, so the on-demand rate is discounted conservatively."
+		estimate.Accuracy = "Low"
+		estimate.Assumptions = []string{
+			fmt.Sprintf("On-demand: $%.2f/month; spot prices are usually 60-90%% lower", onDemand),
+			"Excludes data transfer and other costs",
+		}
+	}
+	estimate.Breakdown = map[string]float64{"spot": estimate.Amount}
+}
+
+// estimateEC2InstanceCost estimates EC2 instance cost, from live prices when available
+func (e *Engine) estimateEC2InstanceCost(ctx context.Context, resource models.Resource) *models.CostEstimate {
+	// Only charge for running instances
+	if resource.State != "running" {
+		return &models.CostEstimate{
+			Amount:             0,
+			Explanation:        fmt.Sprintf("EC2 %s instance: $0.00/month (not running)", resource.Type),
+			Formula:            "Monthly Cost = $0 (stopped instances)",
+			FormulaExplanation: "Stopped EC2 instances are not charged for compute time.",
+			Breakdown:          make(map[string]float64),
+			Accuracy:           "High",
+			Source:             "state-check",
+		}
+	}
+
+	// Prefer live prices, falling back to built-in ones
+	if result := e.livePrice(ctx, "ec2", resource.Region, resource.Type, nil); result != nil {
+		estimate := &models.CostEstimate{
+			Amount:             result.MonthlyPrice,
+			Explanation:        fmt.Sprintf("EC2 %s instance: $%.2f/month", resource.Type, result.MonthlyPrice),
+			Formula:            "Monthly Cost = Hourly Rate × 730 hours",
+			FormulaExplanation: "AWS charges per hour for running instances. We multiply by 730 hours for monthly cost.",
+			Breakdown:          map[string]float64{resource.Type: result.MonthlyPrice},
+			Accuracy:           result.Accuracy,
+			Source:             result.Source,
+			FreeTierCovered:    result.FreeTierCovered,
+			FreeTierSavings:    result.FreeTierSavings,
+			Assumptions: []string{
+				fmt.Sprintf("Pricing from %s", result.Source),
+				"Only running instances are charged",
+				"Excludes data transfer and other costs",
+				"Assumes 24/7 usage (730 hours/month)",
+			},
+			Examples: []string{
+				"t3.micro: $0.0116/hour × 730 hours = $8.47/month",
+				"t3.small: $0.0232/hour × 730 hours = $16.94/month",
+				"m5.large: $0.096/hour × 730 hours = $70.08/month",
+			},
+		}
+
+		addEC2InstanceNotes(estimate, resource.Type)
+
+		// Update explanation for free tier
+		if result.FreeTierCovered {
+			estimate.Explanation = fmt.Sprintf("EC2 %s instance: $0.00/month (FREE TIER)", resource.Type)
+			estimate.Amount = 0
+			estimate.Assumptions = append(estimate.Assumptions, "FREE TIER: t2.micro instances are free for 750 hours/month during first 12 months")
+		} else if result.FreeTierSavings > 0 {
+			estimate.Explanation = fmt.Sprintf("EC2 %s instance: $%.2f/month (FREE TIER saves $%.2f)", resource.Type, result.MonthlyPrice-result.FreeTierSavings, result.FreeTierSavings)
+			estimate.Amount = result.MonthlyPrice - result.FreeTierSavings
+			estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("FREE TIER: Partial coverage saves $%.2f/month", result.FreeTierSavings))
+		}
+
+		return estimate
+	}
+
+	// Fallback to hardcoded estimates
+	return e.fallbackEC2Cost(resource)
+}
+
+// fallbackEC2Cost estimates from built-in prices when live prices are unavailable
+func (e *Engine) fallbackEC2Cost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             0,
+		Explanation:        "EC2 costs are based on instance type and running state",
+		Formula:            "Monthly Cost = Hourly Rate × 730 hours",
+		FormulaExplanation: "AWS charges per hour, so we multiply the hourly rate by 730 hours (average hours per month) to get monthly cost.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Medium",
+		Source:             "fallback",
+		Assumptions: []string{
+			"Based on us-east-1 on-demand pricing (built-in catalog, scaled by instance size within each family)",
+			"Only running instances are charged",
+			"Excludes data transfer and other costs",
+			"Assumes 24/7 usage (730 hours/month)",
+		},
+		Examples: []string{
+			"t3.micro: $0.0104/hour × 730 hours = $7.59/month",
+			"m6g.large: $0.077/hour × 730 hours = $56.21/month",
+			"m5.large: $0.096/hour × 730 hours = $70.08/month",
+		},
+	}
+
+	if hourly, ok := ec2CatalogPrice(resource.Type); ok {
+		cost := hourly * 730
+		estimate.Amount = cost
+		estimate.Breakdown[resource.Type] = cost
+		estimate.Explanation = fmt.Sprintf("EC2 %s instance: $%.2f/month", resource.Type, cost)
+		estimate.Formula = fmt.Sprintf("Monthly Cost = $%.4f/hour × 730 hours", hourly)
+	} else {
+		estimate.Amount = 50.0
+		estimate.Breakdown["unknown"] = 50.0
+		estimate.Explanation = fmt.Sprintf("EC2 %s instance: $50.00/month (estimated for unknown instance type)", resource.Type)
+		estimate.Assumptions = append(estimate.Assumptions, "Unknown instance type - using conservative estimate")
+		estimate.Accuracy = "Low"
+	}
+	addEC2InstanceNotes(estimate, resource.Type)
+
+	// Check free tier for fallback
+	if e.service != nil && resource.Type == "t2.micro" && e.service.IsFreeTierEligible() {
+		estimate.FreeTierCovered = true
+		estimate.Amount = 0
+		estimate.Explanation = fmt.Sprintf("EC2 %s instance: $0.00/month (FREE TIER)", resource.Type)
+		estimate.Assumptions = append(estimate.Assumptions, "FREE TIER: t2.micro instances are free for 750 hours/month during first 12 months")
+	}
+
+	return estimate
+}
+
+// addEC2InstanceNotes notes the pricing traits of an instance type's family in an
+// estimate's assumptions: Graviton processors and burstable CPU credits
+func addEC2InstanceNotes(estimate *models.CostEstimate, instanceType string) {
+	if isGraviton(instanceType) {
+		estimate.Assumptions = append(estimate.Assumptions, "AWS Graviton (arm64) instance, usually about 20% cheaper than the x86 family of the same generation")
+	}
+	if creditPrice, ok := burstableCreditPrice(instanceType); ok {
+		estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("Burstable instance: the price covers baseline CPU; sustained use above it in unlimited mode adds $%.2f per vCPU-hour of surplus credits", creditPrice))
+	}
+}
+
+// estimateRDSCost estimates RDS instance cost including allocated storage
+func (e *Engine) estimateRDSCost(ctx context.Context, resource models.Resource) *models.CostEstimate {
+	estimate := e.estimateRDSInstanceCost(ctx, resource)
+	if resource.State != "available" {
+		return estimate
+	}
+
+	if storage, ok := rdsStorageCost(resource); ok {
+		addStorageCost(estimate, storage)
+	}
+	return estimate
+}
+
+// rdsEngine is how the Pricing API names an RDS engine
+type rdsEngine struct {
+	name    string // databaseEngine attribute
+	edition string // databaseEdition attribute, for commercial engines
+
+	// licenseFactor approximates the license-included price relative to MySQL, for
+	// built-in prices
+	licenseFactor float64
+}
+
+// rdsEngines are the RDS engines priced by engine, by engine identifier. The license
+// factors are the us-east-1 ratios to MySQL of m5 class prices.
+var rdsEngines = map[string]rdsEngine{
+	"mysql":             {name: "MySQL", licenseFactor: 1.0},
+	"mariadb":           {name: "MariaDB", licenseFactor: 1.0},
+	"postgres":          {name: "PostgreSQL", licenseFactor: 1.04},
+	"aurora":            {name: "Aurora MySQL", licenseFactor: 1.16},
+	"aurora-mysql":      {name: "Aurora MySQL", licenseFactor: 1.16},
+	"aurora-postgresql": {name: "Aurora PostgreSQL", licenseFactor: 1.16},
+	"oracle-se2":        {name: "Oracle", edition: "Standard Two", licenseFactor: 2.7},
+	"oracle-se2-cdb":    {name: "Oracle", edition: "Standard Two", licenseFactor: 2.7},
+	"oracle-ee":         {name: "Oracle", edition: "Enterprise", licenseFactor: 1.0},
+	"oracle-ee-cdb":     {name: "Oracle", edition: "Enterprise", licenseFactor: 1.0},
+	"sqlserver-ex":      {name: "SQL Server", edition: "Express", licenseFactor: 1.2},
+	"sqlserver-web":     {name: "SQL Server", edition: "Web", licenseFactor: 2.3},
+	"sqlserver-se":      {name: "SQL Server", edition: "Standard", licenseFactor: 5.7},
+	"sqlserver-ee":      {name: "SQL Server", edition: "Enterprise", licenseFactor: 8.0},
+}
+
+// rdsLicenseModels are the Pricing API names of RDS license models
+var rdsLicenseModels = map[string]string{
+	"license-included":       "License included",
+	"bring-your-own-license": "Bring your own license",
+}
+
+// rdsPriceAttributes returns the Pricing API attributes of an RDS instance's engine,
+// edition and license model, or nil for engines that are not priced by engine
+func rdsPriceAttributes(resource models.Resource) map[string]string {
+	engine, ok := rdsEngines[resource.Type]
+	if !ok {
+		return nil
+	}
+
+	license, _ := resource.Extra["licenseModel"].(string)
+	licenseModel, ok := rdsLicenseModels[license]
+	if !ok {
+		licenseModel = "No license required"
+	}
+	return map[string]string{
+		"databaseEngine":  engine.name,
+		"databaseEdition": engine.edition,
+		"licenseModel":    licenseModel,
+	}
+}
+
+// rdsMultiAZ reports whether an instance runs with a standby in a second zone, which
+// doubles its instance and storage prices. Aurora instances are priced one by one,
+// whatever the cluster's zones.
+func rdsMultiAZ(resource models.Resource) bool {
+	multiAZ, _ := resource.Extra["multiAZ"].(bool)
+	return multiAZ && !strings.HasPrefix(resource.Type, "aurora")
+}
+
+// rdsEngineLabel describes the engine and license of an instance for explanations
+func rdsEngineLabel(resource models.Resource) string {
+	attributes := rdsPriceAttributes(resource)
+	if attributes == nil {
+		return resource.Type
+	}
+	label := strings.TrimSpace(attributes["databaseEngine"] + " " + attributes["databaseEdition"])
+	if attributes["licenseModel"] != "No license required" {
+		label += ", " + strings.ToLower(attributes["licenseModel"])
+	}
+	return label
+}
+
+// estimateRDSInstanceCost estimates RDS instance cost, from live prices of the
+// instance's engine when available
+func (e *Engine) estimateRDSInstanceCost(ctx context.Context, resource models.Resource) *models.CostEstimate {
+	// Only charge for available instances
+	if resource.State != "available" {
+		return &models.CostEstimate{
+			Amount:             0,
+			Explanation:        fmt.Sprintf("RDS %s instance: $0.00/month (not available)", resource.Class),
+			Formula:            "Monthly Cost = $0 (stopped instances)",
+			FormulaExplanation: "Stopped RDS instances are not charged for compute time.",
+			Breakdown:          make(map[string]float64),
+			Accuracy:           "High",
+			Source:             "state-check",
+		}
+	}
+
+	// Prefer live prices, falling back to built-in ones
+	attributes := rdsPriceAttributes(resource)
+	if result := e.livePrice(ctx, "rds", resource.Region, resource.Class, attributes); result != nil {
+		estimate := &models.CostEstimate{
+			Amount:             result.MonthlyPrice,
+			Explanation:        fmt.Sprintf("RDS %s instance (%s): $%.2f/month", resource.Class, rdsEngineLabel(resource), result.MonthlyPrice),
+			Formula:            "Monthly Cost = Hourly Rate × 730 hours",
+			FormulaExplanation: "RDS instances are charged per hour, similar to EC2, at a rate that depends on the engine and its license. We multiply by 730 hours for monthly cost.",
+			Breakdown:          map[string]float64{resource.Class: result.MonthlyPrice},
+			Accuracy:           result.Accuracy,
+			Source:             result.Source,
+			Assumptions: []string{
+				fmt.Sprintf("Pricing from %s", result.Source),
+				"Only available instances are charged",
+				"Excludes backup and data transfer costs",
+				"Assumes 24/7 usage (730 hours/month)",
+			},
+			Examples: []string{
+				"db.t3.micro: $0.0205/hour × 730 hours = $15.00/month",
+				"db.m5.large: $0.234/hour × 730 hours = $171.00/month",
+				"db.r5.large: $0.312/hour × 730 hours = $228.00/month",
+			},
+		}
+		if attributes == nil {
+			estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("No prices for engine %s; price of the instance class for another engine", resource.Type))
+			estimate.Accuracy = lowerAccuracy(estimate.Accuracy)
+		} else if result.Accuracy != "High" {
+			estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("No %s price in the bundle; lowest price of the instance class", rdsEngineLabel(resource)))
+		}
+
+		if rdsMultiAZ(resource) {
+			applyRDSMultiAZ(estimate, resource)
+			return estimate
+		}
+		estimate.Assumptions = append(estimate.Assumptions, "Single-AZ deployment pricing")
+
+		// The free tier only covers Single-AZ instances
+		estimate.FreeTierCovered = result.FreeTierCovered
+		estimate.FreeTierSavings = result.FreeTierSavings
+		if result.FreeTierCovered {
+			estimate.Explanation = fmt.Sprintf("RDS %s instance: $0.00/month (FREE TIER)", resource.Class)
+			estimate.Amount = 0
+			estimate.Assumptions = append(estimate.Assumptions, "FREE TIER: db.t2.micro instances are free for 750 hours/month during first 12 months")
+		} else if result.FreeTierSavings > 0 {
+			estimate.Explanation = fmt.Sprintf("RDS %s instance: $%.2f/month (FREE TIER saves $%.2f)", resource.Class, result.MonthlyPrice-result.FreeTierSavings, result.FreeTierSavings)
+			estimate.Amount = result.MonthlyPrice - result.FreeTierSavings
+			estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("FREE TIER: Partial coverage saves $%.2f/month", result.FreeTierSavings))
+		}
+
+		return estimate
+	}
+
+	// Fallback to hardcoded estimates
+	return fallbackRDSCost(resource)
+}
+
+// applyRDSMultiAZ doubles a Single-AZ instance estimate for the standby of a Multi-AZ
+// deployment
+func applyRDSMultiAZ(estimate *models.CostEstimate, resource models.Resource) {
+	singleAZ := estimate.Amount
+	estimate.Amount = singleAZ * 2
+	estimate.Breakdown = map[string]float64{resource.Class: estimate.Amount}
+	estimate.Explanation = fmt.Sprintf("RDS %s instance (%s, Multi-AZ): $%.2f/month", resource.Class, rdsEngineLabel(resource), estimate.Amount)
+	estimate.Formula = "Monthly Cost = Hourly Rate × 730 hours × 2 (Multi-AZ)"
+	estimate.Assumptions = append(estimate.Assumptions,
+		fmt.Sprintf("Multi-AZ deployment: primary and standby at twice the Single-AZ $%.2f/month", singleAZ))
+}
+
+// fallbackRDSCost estimates from built-in prices when live prices are unavailable
+func fallbackRDSCost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             0,
+		Explanation:        "RDS costs are based on instance class and availability",
+		Formula:            "Monthly Cost = Hourly Rate × 730 hours",
+		FormulaExplanation: "RDS instances are charged per hour, similar to EC2. We multiply the hourly rate by 730 hours for monthly cost.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Medium",
+		Source:             "fallback",
+		Assumptions: []string{
+			"Based on us-east-1 on-demand pricing",
+			"Only available instances are charged",
+			"Excludes backup and data transfer costs",
+			"Assumes 24/7 usage (730 hours/month)",
+		},
+		Examples: []string{
+			"db.t3.micro: $0.0205/hour × 730 hours = $15.00/month",
+			"db.m5.large: $0.234/hour × 730 hours = $171.00/month",
+			"db.r5.large: $0.312/hour × 730 hours = $228.00/month",
+		},
+	}
+
+	if resource.State != "available" {
+		return estimate
+	}
+
+	// Rough cost estimates per month (us-east-1 pricing)
+	costMap := map[string]float64{
+		"db.t3.micro":  15.00,
+		"db.t3.small":  30.00,
+		"db.t3.medium": 60.00,
+		"db.t3.large":  120.00,
+		"db.m5.large":  171.00,
+		"db.m5.xlarge": 342.00,
+		"db.r5.large":  228.00,
+		"db.r5.xlarge": 456.00,
+	}
+
+	if cost, exists := costMap[resource.Class]; exists {
+		estimate.Amount = cost
+		estimate.Breakdown[resource.Class] = cost
+		estimate.Explanation = fmt.Sprintf("RDS %s instance: $%.2f/month", resource.Class, cost)
+	} else {
+		estimate.Amount = 100.0
+		estimate.Breakdown["unknown"] = 100.0
+		estimate.Explanation = fmt.Sprintf("RDS %s instance: $100.00/month (estimated for unknown instance class)", resource.Class)
+		estimate.Assumptions = append(estimate.Assumptions, "Unknown instance class - using conservative estimate")
+	}
+
+	// The built-in prices are for MySQL; other engines are scaled by their usual
+	// difference in price
+	license, _ := resource.Extra["licenseModel"].(string)
+	if engine, ok := rdsEngines[resource.Type]; ok && engine.licenseFactor != 1 && license != "bring-your-own-license" {
+		for key := range estimate.Breakdown {
+			estimate.Breakdown[key] *= engine.licenseFactor
+		}
+		estimate.Amount *= engine.licenseFactor
+		estimate.Explanation = fmt.Sprintf("RDS %s instance (%s): $%.2f/month (MySQL price × %.2f)", resource.Class, rdsEngineLabel(resource), estimate.Amount, engine.licenseFactor)
+		estimate.Assumptions = append(estimate.Assumptions,
+			fmt.Sprintf("%s priced at about %.2f × the MySQL price of the instance class", rdsEngineLabel(resource), engine.licenseFactor))
+		estimate.Accuracy = lowerAccuracy(estimate.Accuracy)
+	} else if !ok {
+		estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("No prices for engine %s; MySQL price of the instance class", resource.Type))
+		estimate.Accuracy = lowerAccuracy(estimate.Accuracy)
+	}
+
+	if rdsMultiAZ(resource) {
+		applyRDSMultiAZ(estimate, resource)
+	} else {
+		estimate.Assumptions = append(estimate.Assumptions, "Single-AZ deployment pricing")
+	}
+
+	return estimate
+}
+
+// estimateLambdaCost estimates Lambda function cost (rough monthly estimate)
+func estimateLambdaCost(resource models.Resource) *models.CostEstimate {
+	// Event source mappings and layers are not billed themselves
+	switch resource.Type {
+	case "event-source-mapping":
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("Lambda event source mapping for %s: $0.00/month (billed through function invocations)", resource.Name),
+			Accuracy:    "High",
+		}
+	case "layer":
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("Lambda layer %s: $0.00/month (storage counts towards the code storage quota)", resource.Name),
+			Accuracy:    "High",
+		}
+	}
+
+	if estimate, ok := estimateLambdaUsageCost(resource); ok {
+		return estimate
+	}
+
+	estimate := &models.CostEstimate{
+		Amount:             5.0, // Conservative estimate
+		Explanation:        "Lambda costs are based on function execution and memory usage",
+		Formula:            "Monthly Cost = $5.00 (estimated moderate usage)",
+		FormulaExplanation: "Lambda pricing is complex (requests + duration + memory). Using conservative estimate for moderate usage.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Medium",
+		Assumptions: []string{
+			"Estimated moderate usage (1000 requests/month)",
+			"128MB memory allocation",
+			"100ms average execution time",
+			"Conservative estimate for unknown usage patterns",
+		},
+		Examples: []string{
+			"Low usage: $1-3/month",
+			"Moderate usage: $5-10/month",
+			"High usage: $20-50/month",
+		},
+	}
+
+	estimate.Breakdown["estimated"] = estimate.Amount
+	addLambdaProvisionedCost(resource, estimate)
+	estimate.Explanation = fmt.Sprintf("Lambda function %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
+
+	return estimate
+}
+
+// estimateS3Cost estimates S3 bucket cost (rough monthly estimate)
+func estimateS3Cost(resource models.Resource) *models.CostEstimate {
+	if estimate, ok := estimateS3StorageCost(resource); ok {
+		return estimate
+	}
+
+	estimate := &models.CostEstimate{
+		Amount:             1.0, // Minimal usage estimate
+		Explanation:        "S3 costs are based on storage, requests, and data transfer",
+		Formula:            "Monthly Cost = $1.00 (estimated minimal usage)",
+		FormulaExplanation: "S3 pricing includes storage, requests, and data transfer. Using conservative estimate for minimal usage.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Estimated minimal usage (1GB storage)",
+			"Standard storage class",
+			"Low request volume",
+			"Conservative estimate for unknown usage patterns",
+		},
+		Examples: []string{
+			"Minimal usage: $1-3/month",
+			"Moderate usage: $5-15/month",
+			"High usage: $20-100/month",
+		},
+	}
+
+	estimate.Breakdown["estimated"] = estimate.Amount
+	estimate.Explanation = fmt.Sprintf("S3 bucket %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
+
+	return estimate
+}
+
+// estimateDynamoDBCost estimates a DynamoDB table's monthly cost from its billing
+// mode: provisioned tables are billed for their capacity whatever they consume, while
+// on-demand tables are billed per request, which is only known with --usage-metrics
+func estimateDynamoDBCost(resource models.Resource) *models.CostEstimate {
+	if resource.Type == "dax-cluster" {
+		return estimateDAXCost(resource)
+	}
+	if estimate, ok := estimateDynamoDBUsageCost(resource); ok {
+		return estimate
+	}
+
+	prices := dynamoDBPricesOf(resource)
+	sizeBytes, _ := models.ExtraNumber(resource.Extra, "tableSizeBytes")
+	storageGB := sizeBytes / bytesPerGB
+	storageCost := storageGB * prices.storageGB
+
+	estimate := &models.CostEstimate{
+		Breakdown: map[string]float64{"storage": storageCost},
+		Assumptions: []string{
+			"Based on us-east-1 pricing",
+			fmt.Sprintf("%.2f GB of table storage at $%.2f per GB, as last reported by DynamoDB (updated about every 6 hours)", storageGB, prices.storageGB),
+			"Excludes backups, streams, global table replication and data transfer",
+		},
+	}
+	if class, _ := resource.Extra["tableClass"].(string); class == "STANDARD_INFREQUENT_ACCESS" {
+		estimate.Assumptions = append(estimate.Assumptions, "Standard-Infrequent Access table class")
+	}
+
+	var mode string
+	if dynamoDBProvisioned(resource) {
+		mode = "provisioned"
+		rcu, wcu := dynamoDBCapacity(resource)
+		readCost := rcu * prices.rcuHour * 730
+		writeCost := wcu * prices.wcuHour * 730
+		estimate.Breakdown["reads"] = readCost
+		estimate.Breakdown["writes"] = writeCost
+		estimate.Amount = readCost + writeCost + storageCost
+		estimate.Accuracy = "Medium"
+		estimate.Formula = fmt.Sprintf("Monthly Cost = (RCU × $%g + WCU × $%g) × 730 hours + Storage GB × $%.2f", prices.rcuHour, prices.wcuHour, prices.storageGB)
+		estimate.FormulaExplanation = "Provisioned tables are billed per capacity unit-hour, whatever they consume, for the table and its global secondary indexes."
+		estimate.Assumptions = append(estimate.Assumptions,
+			fmt.Sprintf("Provisioned capacity, including global secondary indexes: %.0f RCU, %.0f WCU", rcu, wcu),
+			"Capacity as currently provisioned; auto scaling may change it during the month",
+		)
+		estimate.Examples = []string{
+			"5 RCU and 5 WCU, 1 GB: (5 × $0.00013 + 5 × $0.00065) × 730 + $0.25 = $3.10/month",
+			"100 RCU and 50 WCU, 10 GB: (100 × $0.00013 + 50 × $0.00065) × 730 + $2.50 = $35.72/month",
+		}
+	} else {
+		mode = "on-demand, storage only"
+		estimate.Amount = storageCost
+		estimate.Accuracy = "Low"
+		estimate.Formula = fmt.Sprintf("Monthly Cost = Reads / 1M × $%g + Writes / 1M × $%g + Storage GB × $%.2f", prices.read, prices.write, prices.storageGB)
+		estimate.FormulaExplanation = "On-demand tables are billed per request unit. The requests are not known without --usage-metrics, so only storage is counted."
+		estimate.Assumptions = append(estimate.Assumptions,
+			"On-demand billing mode",
+			"Request volume unknown; run with --usage-metrics to measure it",
+		)
+		estimate.Examples = []string{
+			"10M reads and 1M writes, 1 GB: $2.50 + $1.25 + $0.25 = $4.00/month",
+		}
+	}
+
+	estimate.Explanation = fmt.Sprintf("DynamoDB table %s: $%.2f/month (%s)", resource.Name, estimate.Amount, mode)
+
+	// Each replica of a global table is listed in its own region, so the estimate is per replica
+	if replicas, ok := models.ExtraNumber(resource.Extra, "replicaCount"); ok && replicas > 0 {
+		estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("Global table replica in %s (%.0f other replicas); replicated writes are billed in each replica region", resource.Region, replicas))
+	}
+
+	return estimate
+}
+
+// estimateSFNCost estimates Step Functions cost (rough monthly estimate)
+func estimateSFNCost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             5.0, // Conservative estimate
+		Explanation:        "Step Functions costs are based on state transitions and execution time",
+		Formula:            "Monthly Cost = $5.00 (estimated moderate usage)",
+		FormulaExplanation: "Step Functions pricing is based on state transitions and execution time. Using conservative estimate for moderate usage.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Estimated moderate workflow complexity",
+			"Standard workflow execution",
+			"Conservative estimate for unknown usage patterns",
+		},
+		Examples: []string{
+			"Low usage: $2-5/month",
+			"Moderate usage: $5-15/month",
+			"High usage: $20-100/month",
+		},
+	}
+
+	estimate.Breakdown["estimated"] = estimate.Amount
+	estimate.Explanation = fmt.Sprintf("Step Function %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
+
+	return estimate
+}
+
+// estimateCloudWatchCost estimates CloudWatch cost (rough monthly estimate)
+func estimateCloudWatchCost(resource models.Resource) *models.CostEstimate {
+	switch resource.Type {
+	case "dashboard":
+		return estimateCloudWatchDashboardCost(resource)
+	case "metric-stream":
+		return estimateCloudWatchMetricStreamCost(resource)
+	case "custom-metrics":
+		return estimateCloudWatchCustomMetricsCost(resource)
+	}
+
+	estimate := &models.CostEstimate{
+		Amount:             2.0, // Conservative estimate
+		Explanation:        "CloudWatch costs are based on metrics, logs, and alarms",
+		Formula:            "Monthly Cost = $2.00 (estimated moderate usage)",
+		FormulaExplanation: "CloudWatch pricing includes metrics, logs, and alarms. Using conservative estimate for moderate usage.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Estimated moderate metric resolution",
+			"Standard resolution metrics",
+			"Conservative estimate for unknown usage patterns",
+		},
+		Examples: []string{
+			"Low usage: $1-3/month",
+			"Moderate usage: $2-8/month",
+			"High usage: $10-50/month",
+		},
+	}
+
+	estimate.Breakdown["estimated"] = estimate.Amount
+	estimate.Explanation = fmt.Sprintf("CloudWatch %s: $%.2f/month (estimated)", resource.Name, estimate.Amount)
+
+	return estimate
+}
+
+// us-east-1 Linux Fargate prices
+const (
+	fargateVcpuHourPrice    = 0.04048  // x86_64, per vCPU-hour
+	fargateGBHourPrice      = 0.004445 // x86_64, per GB-hour
+	fargateARMVcpuHourPrice = 0.03238  // arm64, per vCPU-hour
+	fargateARMGBHourPrice   = 0.00356  // arm64, per GB-hour
+)
+
+// estimateECSCost estimates ECS cost. ECS itself is free: Fargate services and tasks
+// are priced by their task size, and EC2 launch type tasks run on instances that are
+// priced in the EC2 inventory.
+func estimateECSCost(resource models.Resource) *models.CostEstimate {
+	switch resource.Type {
+	case "service", "task":
+		if fargate, _ := resource.Extra["fargate"].(bool); fargate {
+			return estimateFargateCost(resource)
+		}
+		return &models.CostEstimate{
+			Amount:             0,
+			Explanation:        fmt.Sprintf("ECS %s %s: $0.00/month (runs on the cluster's EC2 instances, priced there)", resource.Type, resource.Name),
+			Formula:            "Monthly Cost = $0 (charged through the container instances)",
+			FormulaExplanation: "Tasks of the EC2 launch type run on container instances, which appear in the EC2 inventory and are priced there. Counting them here too would count them twice.",
+			Accuracy:           "High",
+		}
+	case "cluster":
+		return &models.CostEstimate{
+			Amount:             0,
+			Explanation:        fmt.Sprintf("ECS cluster %s: $0.00/month (clusters are free)", resource.Name),
+			Formula:            "Monthly Cost = $0 (no charge for ECS)",
+			FormulaExplanation: "ECS clusters are free. Their services and tasks are priced on Fargate, or through their EC2 container instances.",
+			Accuracy:           "High",
+		}
+	default:
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("ECS %s %s: $0.00/month (definitions are free)", resource.Type, resource.ID),
+			Accuracy:    "High",
+		}
+	}
+}
+
+// estimateFargateCost estimates the cost of a Fargate service's desired tasks, or of a
+// standalone Fargate task, running 24/7
+func estimateFargateCost(resource models.Resource) *models.CostEstimate {
+	vcpuPrice, gbPrice, architecture := fargateVcpuHourPrice, fargateGBHourPrice, "x86_64"
+	if resource.Extra["cpuArchitecture"] == "ARM64" {
+		vcpuPrice, gbPrice, architecture = fargateARMVcpuHourPrice, fargateARMGBHourPrice, "arm64"
+	}
+
+	estimate := &models.CostEstimate{
+		Formula:            "Monthly Cost = (vCPU × vCPU-hour rate + memory GB × GB-hour rate) × tasks × 730 hours",
+		FormulaExplanation: "Fargate charges per second for the vCPU and memory of each running task. The task size comes from the task definition.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "High",
+		Assumptions: []string{
+			fmt.Sprintf("Linux/%s pricing: $%.5f per vCPU-hour, $%.6f per GB-hour", architecture, vcpuPrice, gbPrice),
+			"Based on us-east-1 on-demand pricing",
+			"Assumes 24/7 usage (730 hours/month)",
+			"Excludes ephemeral storage above 20 GB and data transfer",
+		},
+	}
+
+	vcpu, hasVcpu := models.ExtraNumber(resource.Extra, "fargateVcpu")
+	memoryGB, hasMemory := models.ExtraNumber(resource.Extra, "fargateMemoryGB")
+	if !hasVcpu && !hasMemory {
+		estimate.Accuracy = "Low"
+		estimate.Explanation = fmt.Sprintf("Fargate %s %s: $0.00/month (task size unknown)", resource.Type, resource.Name)
+		return estimate
+	}
+
+	// Stopped tasks and services scaled to zero record no running capacity
+	estimate.Breakdown["vcpu"] = vcpu * vcpuPrice * 730
+	estimate.Breakdown["memory"] = memoryGB * gbPrice * 730
+	estimate.Amount = estimate.Breakdown["vcpu"] + estimate.Breakdown["memory"]
+
+	if resource.Type == "service" {
+		desired, _ := models.ExtraNumber(resource.Extra, "desiredCount")
+		estimate.Explanation = fmt.Sprintf("Fargate service %s: $%.2f/month for %.0f tasks (%.2f vCPU, %.2f GB in total)", resource.Name, estimate.Amount, desired, vcpu, memoryGB)
+	} else {
+		estimate.Explanation = fmt.Sprintf("Fargate task %s: $%.2f/month (%.2f vCPU, %.2f GB)", resource.Name, estimate.Amount, vcpu, memoryGB)
+	}
+	if provider, _ := resource.Extra["capacityProvider"].(string); provider == "FARGATE_SPOT" {
+		estimate.Assumptions = append(estimate.Assumptions, "Priced at on-demand rates; Fargate Spot is up to 70% cheaper")
+		estimate.Accuracy = "Medium"
+	}
+
+	return estimate
+}
+
+// estimateRedisCost estimates Redis (ElastiCache) cost, from live prices when available
+func (e *Engine) estimateRedisCost(ctx context.Context, resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             0,
+		Explanation:        "Redis costs are based on node type and node count",
+		Formula:            "Monthly Cost = Hourly Rate × Nodes × 730 hours",
+		FormulaExplanation: "ElastiCache Redis nodes are charged per hour, similar to EC2. We multiply the hourly rate by the cluster's nodes and 730 hours for monthly cost.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "High",
+		Assumptions: []string{
+			"Based on us-east-1 on-demand pricing",
+			"Only available instances are charged",
+			"Excludes data transfer and backup costs",
+			"Assumes 24/7 usage (730 hours/month)",
+		},
+		Examples: []string{
+			"cache.t3.micro × 1 node: $0.017/hour × 730 hours = $12.41/month",
+			"cache.t3.small × 1 node: $0.034/hour × 730 hours = $24.82/month",
+			"cache.m5.large × 2 nodes: $0.136/hour × 2 × 730 hours = $198.56/month",
+		},
+	}
+
+	if resource.State != "available" {
+		return estimate
+	}
+
+	nodes := 1.0
+	if count, ok := models.ExtraNumber(resource.Extra, "numCacheNodes"); ok && count > 1 {
+		nodes = count
+	}
+	estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("%.0f node(s) of %s", nodes, resource.Class))
+	// The primary and each replica of a replication group are listed as clusters of
+	// their own, so every node of the group is priced once, on its own cluster
+	if group, _ := resource.Extra["replicationGroupId"].(string); group != "" {
+		estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("Member of replication group %s; its other members are priced on their own clusters", group))
+	}
+
+	// Prefer live prices, falling back to built-in ones
+	if result := e.livePrice(ctx, "redis", resource.Region, resource.Class, nil); result != nil {
+		estimate.Amount = result.MonthlyPrice * nodes
+		estimate.Breakdown[resource.Class] = estimate.Amount
+		estimate.Explanation = fmt.Sprintf("Redis %s × %.0f node(s): $%.2f/month", resource.Class, nodes, estimate.Amount)
+		estimate.Source = result.Source
+		estimate.Assumptions[0] = fmt.Sprintf("Pricing from %s", result.Source)
+		return estimate
+	}
+	estimate.Source = "fallback"
+
+	// Rough cost estimates per node per month (us-east-1 pricing)
+	costMap := map[string]float64{
+		"cache.t3.micro":  12.41,
+		"cache.t3.small":  24.82,
+		"cache.t3.medium": 49.64,
+		"cache.t3.large":  99.28,
+		"cache.m5.large":  99.28,
+		"cache.m5.xlarge": 198.56,
+		"cache.r5.large":  145.60,
+		"cache.r5.xlarge": 291.20,
+		"cache.c5.large":  81.60,
+		"cache.c5.xlarge": 163.20,
+	}
+
+	if cost, exists := costMap[resource.Class]; exists {
+		estimate.Amount = cost * nodes
+		estimate.Breakdown[resource.Class] = estimate.Amount
+		estimate.Explanation = fmt.Sprintf("Redis %s × %.0f node(s): $%.2f/month", resource.Class, nodes, estimate.Amount)
+	} else {
+		estimate.Amount = 50.0 * nodes
+		estimate.Breakdown["unknown"] = estimate.Amount
+		estimate.Explanation = fmt.Sprintf("Redis %s × %.0f node(s): $%.2f/month (estimated for unknown node type)", resource.Class, nodes, estimate.Amount)
+		estimate.Assumptions = append(estimate.Assumptions, "Unknown node type - using conservative estimate")
+	}
+
+	return estimate
+}
+
+// estimateEFSCost estimates EFS file system cost (rough monthly estimate)
+func estimateEFSCost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             0,
+		Explanation:        "EFS costs are based on storage usage and throughput mode",
+		Formula:            "Monthly Cost = Storage × $0.30/GB + Throughput costs",
+		FormulaExplanation: "EFS pricing includes storage costs ($0.30/GB/month) plus throughput costs based on mode (Provisioned or Bursting).",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Medium",
+		Source:             "fallback",
+		Assumptions: []string{
+			"Based on us-east-1 pricing",
+			"Standard storage class",
+			"Estimated storage usage",
+			"Conservative throughput estimate",
+		},
+		Examples: []string{
+			"Small file system (10GB): $3.00/month",
+			"Medium file system (100GB): $30.00/month",
+			"Large file system (1TB): $300.00/month",
+		},
+	}
+
+	// Only charge for available file systems
+	if resource.State != "available" {
+		estimate.Explanation = fmt.Sprintf("EFS %s: $0.00/month (not available)", resource.Name)
+		estimate.Source = "state-check"
+		return estimate
+	}
+
+	// Estimate storage size based on extra data or use default
+	var storageGB float64 = 50.0 // Default estimate
+
+	if sizeBytes, exists := resource.Extra["sizeBytes"]; exists {
+		if size, ok := sizeBytes.(map[string]interface{}); ok {
+			if value, ok := size["Value"]; ok {
+				if val, ok := value.(float64); ok {
+					storageGB = val / (1024 * 1024 * 1024) // Convert bytes to GB
+				}
+			}
+		}
+	}
+
+	// Calculate storage cost ($0.30/GB/month)
+	storageCost := storageGB * 0.30
+
+	// Add throughput cost based on mode
+	var throughputCost float64
+	switch resource.Class {
+	case "provisioned":
+		throughputCost = 5.0 // Provisioned throughput has additional cost
+	case "bursting":
+		throughputCost = 0.0 // Bursting is included in storage cost
+	default:
+		throughputCost = 2.0 // Conservative estimate for unknown mode
+	}
+
+	totalCost := storageCost + throughputCost
+
+	estimate.Amount = totalCost
+	estimate.Breakdown["storage"] = storageCost
+	estimate.Breakdown["throughput"] = throughputCost
+	estimate.Explanation = fmt.Sprintf("EFS %s: $%.2f/month (%.0fGB storage)", resource.Name, totalCost, storageGB)
+
+	// Add performance mode info
+	if resource.Type == "maxIO" {
+		estimate.Assumptions = append(estimate.Assumptions, "Max I/O performance mode")
+	} else if resource.Type == "generalPurpose" {
+		estimate.Assumptions = append(estimate.Assumptions, "General Purpose performance mode")
+	}
+
+	return estimate
+}
+
+// estimateWAFCost estimates WAF web ACL cost from its rule count
+func estimateWAFCost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             0,
+		Explanation:        "WAF costs are based on web ACLs, rules and requests",
+		Formula:            "Monthly Cost = $5.00 per web ACL + $1.00 × Rules",
+		FormulaExplanation: "AWS WAF charges a fixed monthly fee per web ACL and per rule (managed rule groups count as rules), plus $0.60 per million requests inspected.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Medium",
+		Assumptions: []string{
+			"Based on us-east-1 pricing",
+			"Excludes request charges ($0.60 per million requests)",
+			"Excludes marketplace managed rule group subscriptions",
+		},
+		Examples: []string{
+			"Web ACL with 5 rules: $5.00 + 5 × $1.00 = $10.00/month",
+			"Web ACL with 10 rules: $5.00 + 10 × $1.00 = $15.00/month",
+		},
+	}
+
+	ruleCount := 0
+	if count, ok := models.ExtraNumber(resource.Extra, "ruleCount"); ok {
+		ruleCount = int(count)
+	}
+
+	aclCost := 5.0
+	rulesCost := float64(ruleCount) * 1.0
+
+	estimate.Amount = aclCost + rulesCost
+	estimate.Breakdown["webAcl"] = aclCost
+	estimate.Breakdown["rules"] = rulesCost
+	estimate.Explanation = fmt.Sprintf("WAF web ACL %s: $%.2f/month (%d rules)", resource.Name, estimate.Amount, ruleCount)
+
+	return estimate
+}
+
+// estimateShieldCost estimates Shield Advanced cost
+func estimateShieldCost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             0,
+		Explanation:        "Shield Advanced is billed as a flat monthly subscription",
+		Formula:            "Monthly Cost = $3,000 subscription (protections included)",
+		FormulaExplanation: "Shield Advanced charges a $3,000 monthly fee per organization with a 1-year commitment. Individual protections carry no extra fixed charge.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "High",
+		Assumptions: []string{
+			"One subscription per organization",
+			"Excludes data transfer out usage fees for protected resources",
+		},
+		Examples: []string{
+			"Subscription: $3,000.00/month",
+			"Protection: $0.00/month (covered by subscription)",
+		},
+	}
+
+	if resource.Type == "subscription" {
+		estimate.Amount = 3000.0
+		estimate.Breakdown["subscription"] = estimate.Amount
+		estimate.Explanation = fmt.Sprintf("Shield Advanced subscription: $%.2f/month", estimate.Amount)
+	} else {
+		estimate.Explanation = fmt.Sprintf("Shield protection %s: $0.00/month (covered by subscription)", resource.Name)
+	}
+
+	return estimate
+}
+
+// estimateEIPCost estimates Elastic IP cost from the public IPv4 hourly charge
+func estimateEIPCost(resource models.Resource) *models.CostEstimate {
+	hourlyRate := 0.005
+	monthlyCost := hourlyRate * 730
+
+	estimate := &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        "Elastic IPs are billed per hour as public IPv4 addresses",
+		Formula:            "Monthly Cost = $0.005/hour × 730 hours",
+		FormulaExplanation: "AWS charges $0.005 per hour for every public IPv4 address, whether or not it is attached to a running resource.",
+		Breakdown:          map[string]float64{"publicIpv4": monthlyCost},
+		Accuracy:           "High",
+		Assumptions: []string{
+			"Based on us-east-1 public IPv4 pricing",
+			"Address is held for the full month (730 hours)",
+		},
+		Examples: []string{
+			"1 Elastic IP: $0.005/hour × 730 hours = $3.65/month",
+			"10 unassociated Elastic IPs: $36.50/month wasted",
+		},
+	}
+
+	if resource.State == "unassociated" {
+		estimate.Explanation = fmt.Sprintf("Elastic IP %s: $%.2f/month (UNASSOCIATED - release to save)", resource.Name, monthlyCost)
+		estimate.Assumptions = append(estimate.Assumptions, "Unassociated addresses provide no value and can usually be released")
+	} else {
+		estimate.Explanation = fmt.Sprintf("Elastic IP %s: $%.2f/month", resource.Name, monthlyCost)
+	}
+
+	return estimate
+}
+
+// estimateAMICost estimates the EBS snapshot storage behind an AMI
+func estimateAMICost(resource models.Resource) *models.CostEstimate {
+	pricePerGB := 0.05
+
+	sizeGB, _ := models.ExtraNumber(resource.Extra, "snapshotSizeGB")
+
+	monthlyCost := sizeGB * pricePerGB
+
+	estimate := &models.CostEstimate{
+		Amount:             monthlyCost,
+		Formula:            fmt.Sprintf("Monthly Cost = %.0f GB × $%.2f/GB", sizeGB, pricePerGB),
+		FormulaExplanation: "AMIs are billed for the EBS snapshots that back them. Snapshots are incremental, so the source volume size is an upper bound on stored data.",
+		Breakdown:          map[string]float64{"snapshotStorage": monthlyCost},
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Based on us-east-1 standard snapshot pricing",
+			"Snapshot size approximated by the source volume size",
+			"Snapshots shared with other AMIs or backups may be counted more than once",
+		},
+		Examples: []string{
+			"8 GB root volume: 8 GB × $0.05 = $0.40/month",
+			"100 GB image: 100 GB × $0.05 = $5.00/month",
+		},
+	}
+
+	if resource.Class == "unused" {
+		estimate.Explanation = fmt.Sprintf("AMI %s: $%.2f/month (UNUSED - no running instances, consider deregistering)", resource.Name, monthlyCost)
+	} else {
+		estimate.Explanation = fmt.Sprintf("AMI %s: $%.2f/month for %.0f GB of snapshots", resource.Name, monthlyCost, sizeGB)
+	}
+
+	return estimate
+}
+
+// estimateEBSCost estimates the storage of EBS volumes not attached to an instance,
+// whose cost the instance estimate does not include, and of EBS snapshots
+func estimateEBSCost(resource models.Resource) *models.CostEstimate {
+	if resource.Type == "snapshot" {
+		return estimateSnapshotCost(resource)
+	}
+
+	if resource.State == "in-use" {
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("EBS volume %s: $0.00/month (billed with the instance it is attached to)", resource.Name),
+			Accuracy:    "High",
+		}
+	}
+	if resource.State == "deleting" || resource.State == "deleted" || resource.State == "error" {
+		return &models.CostEstimate{Amount: 0, Explanation: fmt.Sprintf("EBS volume %s: $0.00/month (%s)", resource.Name, resource.State)}
+	}
+
+	var cost storageCost
+	size := cost.addEBSVolume(resource.Extra)
+	estimate := &models.CostEstimate{
+		Amount:             cost.total(),
+		Explanation:        fmt.Sprintf("EBS volume %s: $%.2f/month (NOT ATTACHED - snapshot and delete to save)", resource.Name, cost.total()),
+		Formula:            fmt.Sprintf("Monthly Cost = %.0f GB × %s price + provisioned IOPS and throughput", size, resource.Class),
+		FormulaExplanation: "EBS volumes are billed for their provisioned size and performance whether or not an instance uses them.",
+		Breakdown:          map[string]float64{"storage": cost.storage},
+		Accuracy:           "High",
+		Assumptions: []string{
+			"Based on us-east-1 EBS pricing",
+			"Volume is kept for the full month",
+		},
+		Examples: []string{
+			"100 GB gp3 volume: 100 GB × $0.08 = $8.00/month",
+			"500 GB gp2 volume: 500 GB × $0.10 = $50.00/month",
+		},
+	}
+	if cost.iops > 0 {
+		estimate.Breakdown["iops"] = cost.iops
+	}
+	if cost.throughput > 0 {
+		estimate.Breakdown["throughput"] = cost.throughput
+	}
+	return estimate
+}
+
+// estimateSnapshotCost estimates the storage of an EBS snapshot from its source volume
+// size. Snapshots of AMIs are included in the AMI estimate.
+func estimateSnapshotCost(resource models.Resource) *models.CostEstimate {
+	if imageSnapshot, _ := resource.Extra["imageSnapshot"].(bool); imageSnapshot {
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("EBS snapshot %s: $0.00/month (billed with the AMI it backs)", resource.Name),
+			Accuracy:    "Medium",
+		}
+	}
+
+	pricePerGB := 0.05
+	if resource.Class == "archive" {
+		pricePerGB = 0.0125
+	}
+	sizeGB, _ := models.ExtraNumber(resource.Extra, "volumeSizeGB")
+	monthlyCost := sizeGB * pricePerGB
+
+	return &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        fmt.Sprintf("EBS snapshot %s: $%.2f/month for up to %.0f GB", resource.Name, monthlyCost, sizeGB),
+		Formula:            fmt.Sprintf("Monthly Cost = %.0f GB × $%.4f/GB", sizeGB, pricePerGB),
+		FormulaExplanation: "Snapshots are billed for the data they store. They are incremental, so the source volume size is an upper bound.",
+		Breakdown:          map[string]float64{"snapshotStorage": monthlyCost},
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Based on us-east-1 snapshot pricing ($0.05/GB standard, $0.0125/GB archive)",
+			"Snapshot size approximated by the source volume size",
+		},
+		Examples: []string{
+			"100 GB volume snapshot: up to 100 GB × $0.05 = $5.00/month",
+		},
+	}
+}
+
+// elbHourlyRates are the us-east-1 hourly charges of each load balancer type, before
+// capacity units
+var elbHourlyRates = map[string]float64{
+	"application": 0.0225,
+	"network":     0.0225,
+	"gateway":     0.0125,
+}
+
+// estimateELBCost estimates the hourly charge of a load balancer. Target groups are free.
+func estimateELBCost(resource models.Resource) *models.CostEstimate {
+	hourlyRate, ok := elbHourlyRates[resource.Type]
+	if !ok {
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("Target group %s: $0.00/month (no charge)", resource.Name),
+			Accuracy:    "High",
+		}
+	}
+	if resource.State == "failed" {
+		return &models.CostEstimate{Amount: 0, Explanation: fmt.Sprintf("Load balancer %s: $0.00/month (%s)", resource.Name, resource.State)}
+	}
+
+	monthlyCost := hourlyRate * 730
+	return &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        fmt.Sprintf("Load balancer %s (%s): $%.2f/month plus capacity units", resource.Name, resource.Type, monthlyCost),
+		Formula:            fmt.Sprintf("Monthly Cost = $%.4f/hour × 730 hours", hourlyRate),
+		FormulaExplanation: "Load balancers are billed per hour while provisioned, plus capacity units for the traffic they handle.",
+		Breakdown:          map[string]float64{"loadBalancerHours": monthlyCost},
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Based on us-east-1 load balancer pricing",
+			"Excludes capacity unit (LCU) charges, which depend on traffic",
+		},
+		Examples: []string{
+			"1 Application Load Balancer: $0.0225/hour × 730 hours = $16.43/month",
+		},
+	}
+}
+
+// estimateNetworkCost estimates transit gateway attachment and VPN connection hourly charges
+func estimateNetworkCost(resource models.Resource) *models.CostEstimate {
+	hourlyRate := 0.05
+	monthlyCost := hourlyRate * 730
+
+	switch resource.Type {
+	case "nat-gateway":
+		return estimateNATGatewayCost(resource)
+	case "security-group":
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("Security group %s: $0.00/month (no charge)", resource.Name),
+			Accuracy:    "High",
+		}
+	case "network-interface":
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("Network interface %s: $0.00/month (no charge)", resource.Name),
+			Accuracy:    "High",
+		}
+	case "tgw-attachment":
+		if resource.State == "deleted" || resource.State == "deleting" || resource.State == "rejected" || resource.State == "failed" {
+			return &models.CostEstimate{Amount: 0, Explanation: fmt.Sprintf("Transit gateway attachment %s: $0.00/month (%s)", resource.Name, resource.State)}
+		}
+		return &models.CostEstimate{
+			Amount:             monthlyCost,
+			Explanation:        fmt.Sprintf("Transit gateway attachment %s: $%.2f/month", resource.Name, monthlyCost),
+			Formula:            "Monthly Cost = $0.05/hour × 730 hours",
+			FormulaExplanation: "Transit gateways are billed per attachment-hour. Data processing is charged separately at $0.02/GB.",
+			Breakdown:          map[string]float64{"attachmentHours": monthlyCost},
+			Accuracy:           "Medium",
+			Assumptions: []string{
+				"Based on us-east-1 attachment pricing",
+				"Excludes data processing charges",
+			},
+			Examples: []string{
+				"1 VPC attachment: $0.05/hour × 730 hours = $36.50/month",
+				"10 VPC attachments: $365.00/month",
+			},
+		}
+	case "vpn-connection":
+		if resource.State == "deleted" || resource.State == "deleting" {
+			return &models.CostEstimate{Amount: 0, Explanation: fmt.Sprintf("VPN connection %s: $0.00/month (%s)", resource.Name, resource.State)}
+		}
+		return &models.CostEstimate{
+			Amount:             monthlyCost,
+			Explanation:        fmt.Sprintf("VPN connection %s: $%.2f/month", resource.Name, monthlyCost),
+			Formula:            "Monthly Cost = $0.05/hour × 730 hours",
+			FormulaExplanation: "Site-to-site VPN connections are billed per connection-hour while provisioned, regardless of traffic.",
+			Breakdown:          map[string]float64{"connectionHours": monthlyCost},
+			Accuracy:           "High",
+			Assumptions: []string{
+				"Based on us-east-1 VPN pricing",
+				"Excludes data transfer out",
+			},
+			Examples: []string{
+				"1 VPN connection: $0.05/hour × 730 hours = $36.50/month",
+			},
+		}
+	default:
+		// Transit gateways themselves are free, charges accrue on attachments
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("Transit gateway %s: $0.00/month (billed per attachment)", resource.Name),
+			Accuracy:    "High",
+		}
+	}
+}
+
+// estimateDirectConnectCost estimates Direct Connect port-hour charges from connection bandwidth
+func estimateDirectConnectCost(resource models.Resource) *models.CostEstimate {
+	if resource.Type != "connection" {
+		// Virtual interfaces have no hourly charge
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("Virtual interface %s: $0.00/month (billed on the connection)", resource.Name),
+			Accuracy:    "High",
+		}
+	}
+
+	if resource.State == "deleted" || resource.State == "rejected" {
+		return &models.CostEstimate{Amount: 0, Explanation: fmt.Sprintf("Direct Connect connection %s: $0.00/month (%s)", resource.Name, resource.State)}
+	}
+
+	// Port-hour rates for US locations
+	dedicatedRates := map[string]float64{
+		"1Gbps":   0.30,
+		"10Gbps":  2.25,
+		"100Gbps": 22.50,
+		"400Gbps": 85.00,
+	}
+	hostedRates := map[string]float64{
+		"50Mbps":  0.03,
+		"100Mbps": 0.06,
+		"200Mbps": 0.08,
+		"300Mbps": 0.12,
+		"400Mbps": 0.16,
+		"500Mbps": 0.20,
+		"1Gbps":   0.33,
+		"2Gbps":   0.66,
+		"5Gbps":   1.65,
+		"10Gbps":  2.48,
+		"25Gbps":  7.43,
+	}
+
+	connectionType, _ := resource.Extra["connectionType"].(string)
+	bandwidth := resource.Class
+
+	rates := dedicatedRates
+	if connectionType == "hosted" {
+		rates = hostedRates
+	}
+
+	hourlyRate, ok := rates[bandwidth]
+	accuracy := "Medium"
+	if !ok {
+		// Fall back to the 1Gbps dedicated port rate
+		hourlyRate = 0.30
+		accuracy = "Low"
+	}
+
+	monthlyCost := hourlyRate * 730
+
+	return &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        fmt.Sprintf("Direct Connect %s %s connection %s: $%.2f/month", connectionType, bandwidth, resource.Name, monthlyCost),
+		Formula:            fmt.Sprintf("Monthly Cost = $%.2f/hour × 730 hours", hourlyRate),
+		FormulaExplanation: "Direct Connect charges a port-hour rate based on connection bandwidth. Data transfer out is charged separately.",
+		Breakdown:          map[string]float64{"portHours": monthlyCost},
+		Accuracy:           accuracy,
+		Assumptions: []string{
+			"Based on US Direct Connect location pricing",
+			"Excludes data transfer out and partner fees",
+		},
+		Examples: []string{
+			"1Gbps dedicated: $0.30/hour × 730 hours = $219.00/month",
+			"10Gbps dedicated: $2.25/hour × 730 hours = $1,642.50/month",
+		},
+	}
+}
+
+// estimateAppRunnerCost estimates the provisioned-instance floor of an App Runner service
+func estimateAppRunnerCost(resource models.Resource) *models.CostEstimate {
+	if resource.State == "paused" {
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("App Runner service %s: $0.00/month (paused)", resource.Name),
+			Accuracy:    "High",
+		}
+	}
+
+	memoryRate := 0.007 // per GB-hour, charged for provisioned instances
+	vcpuRate := 0.064   // per vCPU-hour, charged only while processing requests
+
+	memoryGB, _ := models.ExtraNumber(resource.Extra, "memoryGB")
+	vcpu, _ := models.ExtraNumber(resource.Extra, "vcpu")
+	minSize, ok := models.ExtraNumber(resource.Extra, "minSize")
+	if !ok {
+		minSize = 1
+	}
+
+	// Provisioned instances are always billed for memory, even when idle
+	monthlyCost := minSize * memoryGB * memoryRate * 730
+
+	return &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        fmt.Sprintf("App Runner service %s: $%.2f/month (provisioned minimum, excludes active compute)", resource.Name, monthlyCost),
+		Formula:            fmt.Sprintf("Monthly Cost = %.0f instances × %gGB × $%.3f/GB-hour × 730 hours", minSize, memoryGB, memoryRate),
+		FormulaExplanation: fmt.Sprintf("App Runner keeps the minimum number of instances provisioned and bills their memory around the clock. Active instances additionally pay $%.3f per vCPU-hour (%g vCPU each) while handling requests.", vcpuRate, vcpu),
+		Breakdown:          map[string]float64{"provisionedMemory": monthlyCost},
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Based on us-east-1 App Runner pricing",
+			"Only the provisioned instance floor is estimated",
+			"Active vCPU time and build minutes depend on traffic and are excluded",
+		},
+		Examples: []string{
+			"1 vCPU/2GB, min 1 instance idle: 2GB × $0.007 × 730 = $10.22/month",
+			"Same service active 24/7: + 1 vCPU × $0.064 × 730 = $46.72/month",
+		},
+	}
+}
+
+// estimateAmplifyCost describes Amplify Hosting charges, which are usage-based
+func estimateAmplifyCost(resource models.Resource) *models.CostEstimate {
+	return &models.CostEstimate{
+		Amount:             0,
+		Explanation:        fmt.Sprintf("Amplify %s %s: $0.00/month (usage-based, not estimated)", resource.Type, resource.Name),
+		Formula:            "Monthly Cost = Build minutes × $0.01 + GB stored × $0.023 + GB served × $0.15",
+		FormulaExplanation: "Amplify Hosting has no fixed charge per app or branch. Costs come from build minutes, stored artifacts and data served.",
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Based on us-east-1 Amplify Hosting pricing",
+			"Usage data is not collected, so no amount is estimated",
+		},
+		Examples: []string{
+			"100 build minutes + 5GB stored + 20GB served: $1.00 + $0.12 + $3.00 = $4.12/month",
+		},
+	}
+}
+
+// estimateBackupCost estimates backup vault storage from recovery point sizes
+func estimateBackupCost(resource models.Resource) *models.CostEstimate {
+	if resource.Type != "vault" {
+		// Plans and protected resources are billed through vault storage
+		return &models.CostEstimate{
+			Amount:      0,
+			Explanation: fmt.Sprintf("Backup %s %s: $0.00/month (billed as vault storage)", resource.Type, resource.Name),
+			Accuracy:    "High",
+		}
+	}
+
+	pricePerGB := 0.05
+	sizeGB, _ := models.ExtraNumber(resource.Extra, "storageSizeGB")
+	monthlyCost := sizeGB * pricePerGB
+
+	return &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        fmt.Sprintf("Backup vault %s: $%.2f/month for %.1f GB of recovery points", resource.Name, monthlyCost, sizeGB),
+		Formula:            fmt.Sprintf("Monthly Cost = %.1f GB × $%.2f/GB", sizeGB, pricePerGB),
+		FormulaExplanation: "AWS Backup charges per GB-month of warm storage. Rates vary by resource type ($0.05 for EBS/EFS, up to $0.10 for DynamoDB) and cold storage is cheaper.",
+		Breakdown:          map[string]float64{"warmStorage": monthlyCost},
+		Accuracy:           "Medium",
+		Assumptions: []string{
+			"Based on us-east-1 warm storage pricing for EBS/EFS backups",
+			"Recovery point sizes as reported by AWS Backup",
+			"Excludes restore and cross-region copy charges",
+		},
+		Examples: []string{
+			"100 GB of recovery points: 100 GB × $0.05 = $5.00/month",
+			"1 TB of recovery points: 1024 GB × $0.05 = $51.20/month",
+		},
+	}
+}
+
+// estimateFSxCost estimates FSx cost from storage and throughput capacity
+func estimateFSxCost(resource models.Resource) *models.CostEstimate {
+	storageGB, _ := models.ExtraNumber(resource.Extra, "storageCapacityGB")
+	throughput, _ := models.ExtraNumber(resource.Extra, "throughputCapacity")
+	storageType, _ := resource.Extra["storageType"].(string)
+	multiAZ := strings.HasPrefix(resource.Class, "MULTI_AZ")
+
+	// us-east-1 monthly rates per GB of storage and per MBps of throughput capacity
+	var storageRate, throughputRate float64
+	switch resource.Type {
+	case "windows":
+		storageRate, throughputRate = 0.13, 2.30
+		if storageType == "HDD" {
+			storageRate = 0.013
+		}
+		if multiAZ {
+			storageRate, throughputRate = storageRate*1.8, 4.50
+		}
+	case "ontap":
+		storageRate, throughputRate = 0.125, 0.72
+		if multiAZ {
+			storageRate, throughputRate = 0.25, 1.44
+		}
+	case "openzfs":
+		storageRate, throughputRate = 0.09, 0.26
+		if multiAZ {
+			storageRate, throughputRate = 0.18, 0.52
+		}
+	case "lustre":
+		// Lustre throughput is bundled into the per-GB storage rate
+		storageRate = 0.14
+		perUnit, _ := models.ExtraNumber(resource.Extra, "perUnitStorageThroughput")
+		switch {
+		case perUnit >= 1000:
+			storageRate = 0.60
+		case perUnit >= 500:
+			storageRate = 0.34
+		case perUnit >= 200:
+			storageRate = 0.29
+		case perUnit >= 100:
+			storageRate = 0.21
+		case perUnit > 0:
+			storageRate = 0.145
+		}
+		throughput = 0
+	default:
+		storageRate = 0.13
+	}
+
+	storageCost := storageGB * storageRate
+	throughputCost := throughput * throughputRate
+	monthlyCost := storageCost + throughputCost
+
+	return &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        fmt.Sprintf("FSx for %s %s: $%.2f/month (%.0f GB, %s)", resource.Type, resource.Name, monthlyCost, storageGB, resource.Class),
+		Formula:            fmt.Sprintf("Monthly Cost = %.0f GB × $%.3f/GB + %.0f MBps × $%.2f/MBps", storageGB, storageRate, throughput, throughputRate),
+		FormulaExplanation: "FSx bills provisioned storage capacity per GB-month and, except for Lustre, provisioned throughput capacity per MBps-month. Multi-AZ deployments cost roughly twice as much.",
+		Breakdown: map[string]float64{
+			"storage":    storageCost,
+			"throughput": throughputCost,
+		},
+		Accuracy: "Medium",
+		Assumptions: []string{
+			"Based on us-east-1 pricing",
+			"Provisioned capacity is billed whether or not it is used",
+			"Excludes backups, SSD IOPS above baseline and data transfer",
+		},
+		Examples: []string{
+			"Windows Single-AZ 1024 GB SSD, 32 MBps: $133.12 + $73.60 = $206.72/month",
+			"Lustre SCRATCH_2 1200 GB: 1200 GB × $0.14 = $168.00/month",
+		},
+	}
+}
+
+// estimateEventBridgeCost describes EventBridge charges, which are per event rather than per resource
+func estimateEventBridgeCost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:   0,
+		Accuracy: "Low",
+		Assumptions: []string{
+			"Based on us-east-1 pricing",
+			"Event volume is not collected, so no amount is estimated",
+		},
+	}
+
+	switch resource.Type {
+	case "schedule":
+		estimate.Explanation = fmt.Sprintf("Schedule %s: $0.00/month (first 14M invocations free, then $1.00/million)", resource.Name)
+		estimate.Formula = "Monthly Cost = max(0, invocations - 14M) × $1.00/million"
+	case "event-bus":
+		estimate.Explanation = fmt.Sprintf("Event bus %s: $0.00/month (custom events $1.00/million)", resource.Name)
+		estimate.Formula = "Monthly Cost = custom events × $1.00/million"
+	default:
+		estimate.Explanation = fmt.Sprintf("EventBridge %s %s: $0.00/month (rules are free)", resource.Type, resource.Name)
+		estimate.Formula = "Monthly Cost = $0 (charges accrue on published events)"
+	}
+
+	return estimate
+}
+
+// estimateBatchCost describes AWS Batch charges, which accrue on the underlying compute
+func estimateBatchCost(resource models.Resource) *models.CostEstimate {
+	return &models.CostEstimate{
+		Amount:             0,
+		Explanation:        fmt.Sprintf("Batch %s %s: $0.00/month (billed through EC2/Fargate capacity)", resource.Type, resource.Name),
+		Formula:            "Monthly Cost = $0 (no additional charge for Batch)",
+		FormulaExplanation: "AWS Batch itself is free. Instances launched by managed compute environments appear in the EC2 inventory and are priced there.",
+		Accuracy:           "High",
+	}
+}
+
+// estimateCodeBuildCost describes CodeBuild charges, which are per build minute
+func estimateCodeBuildCost(resource models.Resource) *models.CostEstimate {
+	return &models.CostEstimate{
+		Amount:             0,
+		Explanation:        fmt.Sprintf("CodeBuild project %s: $0.00/month (billed per build minute, not estimated)", resource.Name),
+		Formula:            "Monthly Cost = build minutes × compute type rate",
+		FormulaExplanation: "CodeBuild charges per build minute based on the compute type, e.g. $0.005/minute for BUILD_GENERAL1_SMALL. Idle projects cost nothing.",
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Based on us-east-1 on-demand build pricing",
+			"Build minutes are not collected, so no amount is estimated",
+		},
+		Examples: []string{
+			"BUILD_GENERAL1_SMALL, 500 minutes: 500 × $0.005 = $2.50/month",
+			"BUILD_GENERAL1_MEDIUM, 500 minutes: 500 × $0.01 = $5.00/month",
+		},
+	}
+}
+
+// estimateCodePipelineCost estimates CodePipeline cost by pipeline type
+func estimateCodePipelineCost(resource models.Resource) *models.CostEstimate {
+	if resource.Class == "V2" {
+		return &models.CostEstimate{
+			Amount:             0,
+			Explanation:        fmt.Sprintf("Pipeline %s: $0.00/month (V2 billed per action execution minute, not estimated)", resource.Name),
+			Formula:            "Monthly Cost = action execution minutes × $0.002",
+			FormulaExplanation: "V2 pipelines have no monthly fee and are charged $0.002 per action execution minute after 100 free minutes.",
+			Accuracy:           "Low",
+		}
+	}
+
+	monthlyCost := 1.0
+	return &models.CostEstimate{
+		Amount:             monthlyCost,
+		Explanation:        fmt.Sprintf("Pipeline %s: $%.2f/month (V1 active pipeline)", resource.Name, monthlyCost),
+		Formula:            "Monthly Cost = $1.00 per active pipeline",
+		FormulaExplanation: "V1 pipelines cost $1.00 per month once they are older than 30 days and had at least one code change during the month.",
+		Breakdown:          map[string]float64{"activePipeline": monthlyCost},
+		Accuracy:           "Medium",
+		Assumptions: []string{
+			"Pipeline is active (had a code change this month)",
+			"Excludes the first 30 free days of new pipelines",
+		},
+	}
+}
+
+// estimateCloudWatchDashboardCost estimates CloudWatch dashboard cost
+func estimateCloudWatchDashboardCost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             3.0,
+		Explanation:        "CloudWatch dashboards are billed per dashboard beyond the free tier",
+		Formula:            "Monthly Cost = $3.00 per dashboard (first 3 free)",
+		FormulaExplanation: "Each account gets 3 dashboards with up to 50 metrics each for free. Every additional dashboard costs $3.00 per month.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "High",
+		Assumptions: []string{
+			"The first 3 dashboards by name are counted against the free tier",
+		},
+		Examples: []string{
+			"5 dashboards: 2 × $3.00 = $6.00/month",
+		},
+	}
+
+	if freeTier, ok := resource.Extra["freeTier"].(bool); ok && freeTier {
+		estimate.Amount = 0
+		estimate.Explanation = fmt.Sprintf("CloudWatch dashboard %s: covered by the free tier", resource.Name)
+		return estimate
+	}
+
+	estimate.Breakdown["dashboard"] = estimate.Amount
+	estimate.Explanation = fmt.Sprintf("CloudWatch dashboard %s: $%.2f/month", resource.Name, estimate.Amount)
+
+	return estimate
+}
+
+// estimateCloudWatchMetricStreamCost estimates CloudWatch metric stream cost
+func estimateCloudWatchMetricStreamCost(resource models.Resource) *models.CostEstimate {
+	return &models.CostEstimate{
+		Amount:             0,
+		Explanation:        fmt.Sprintf("CloudWatch metric stream %s: billed per metric update ($0.003 per 1,000)", resource.Name),
+		Formula:            "Monthly Cost = Metric Updates / 1,000 × $0.003",
+		FormulaExplanation: "Metric streams are charged per metric update delivered, plus the Kinesis Data Firehose delivery costs. Update volume is not collected.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Low",
+		Assumptions: []string{
+			"Metric update volume is not collected",
+			"Excludes Kinesis Data Firehose costs",
+		},
+		Examples: []string{
+			"100 metrics streamed every minute: ~4.4M updates = $13.14/month",
+		},
+	}
+}
+
+// estimateCloudWatchCustomMetricsCost estimates the cost of the custom metrics in a namespace
+func estimateCloudWatchCustomMetricsCost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             0,
+		Explanation:        "CloudWatch custom metrics are billed per metric per month",
+		Formula:            "Monthly Cost = Metrics × $0.30 (first 10,000), $0.10 (next 240,000), $0.05 (next 750,000), $0.02 (over 1,000,000)",
+		FormulaExplanation: "Each unique combination of metric name and dimensions is a billable metric, prorated by the hour. Volume tiers apply per account.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Medium",
+		Assumptions: []string{
+			"Based on us-east-1 pricing",
+			"Counts metrics with data points in the last two weeks",
+			"Volume tiers are applied per namespace rather than per account",
+			"Excludes the 10 free-tier metrics and API request charges",
+		},
+		Examples: []string{
+			"50 custom metrics: 50 × $0.30 = $15.00/month",
+		},
+	}
+
+	metrics, _ := models.ExtraNumber(resource.Extra, "metricCount")
+
+	tiers := []struct {
+		size float64
+		rate float64
+	}{
+		{10000, 0.30},
+		{240000, 0.10},
+		{750000, 0.05},
+		{-1, 0.02},
+	}
+
+	remaining := metrics
+	for _, tier := range tiers {
+		if remaining <= 0 {
+			break
+		}
+		count := remaining
+		if tier.size > 0 && count > tier.size {
+			count = tier.size
+		}
+		estimate.Amount += count * tier.rate
+		remaining -= count
+	}
+
+	estimate.Breakdown["metrics"] = estimate.Amount
+	estimate.Explanation = fmt.Sprintf("CloudWatch namespace %s: %.0f custom metrics = $%.2f/month", resource.Name, metrics, estimate.Amount)
+
+	return estimate
+}
+
+// estimateDAXCost estimates DynamoDB Accelerator (DAX) cluster cost
+func estimateDAXCost(resource models.Resource) *models.CostEstimate {
+	estimate := &models.CostEstimate{
+		Amount:             0,
+		Explanation:        "DAX costs are based on node type and node count",
+		Formula:            "Monthly Cost = Hourly Node Rate × Nodes × 730 hours",
+		FormulaExplanation: "DAX clusters are charged per node-hour for every node in the cluster, including read replicas.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "High",
+		Assumptions: []string{
+			"Based on us-east-1 on-demand pricing",
+			"Only available clusters are charged",
+			"Assumes 24/7 usage (730 hours/month)",
+		},
+		Examples: []string{
+			"dax.t3.small × 3 nodes: $0.04/hour × 3 × 730 = $87.60/month",
+			"dax.r5.large × 3 nodes: $0.269/hour × 3 × 730 = $589.11/month",
+		},
+	}
+
+	if resource.State != "available" {
+		estimate.Explanation = fmt.Sprintf("DAX cluster %s is %s - no charge", resource.Name, resource.State)
+		return estimate
+	}
+
+	hourlyRates := map[string]float64{
+		"dax.t2.small":    0.045,
+		"dax.t2.medium":   0.09,
+		"dax.t3.small":    0.04,
+		"dax.t3.medium":   0.08,
+		"dax.r4.large":    0.322,
+		"dax.r4.xlarge":   0.643,
+		"dax.r4.2xlarge":  1.286,
+		"dax.r4.4xlarge":  2.572,
+		"dax.r4.8xlarge":  5.144,
+		"dax.r4.16xlarge": 10.288,
+		"dax.r5.large":    0.269,
+		"dax.r5.xlarge":   0.537,
+		"dax.r5.2xlarge":  1.074,
+		"dax.r5.4xlarge":  2.148,
+		"dax.r5.8xlarge":  4.296,
+		"dax.r5.12xlarge": 6.444,
+		"dax.r5.16xlarge": 8.592,
+		"dax.r5.24xlarge": 12.888,
+	}
+
+	nodes, ok := models.ExtraNumber(resource.Extra, "totalNodes")
+	if !ok || nodes <= 0 {
+		nodes = 1
+		estimate.Assumptions = append(estimate.Assumptions, "Node count unknown - assuming a single node")
+	}
+
+	hourly, exists := hourlyRates[resource.Class]
+	if !exists {
+		hourly = 0.269
+		estimate.Accuracy = "Medium"
+		estimate.Assumptions = append(estimate.Assumptions, "Unknown node type - using dax.r5.large pricing")
+	}
+
+	estimate.Amount = hourly * nodes * 730
+	estimate.Breakdown[resource.Class] = estimate.Amount
+	estimate.Explanation = fmt.Sprintf("DAX cluster %s: %.0f × %s at $%.3f/hour = $%.2f/month", resource.Name, nodes, resource.Class, hourly, estimate.Amount)
+
+	return estimate
+}
@@ -0,0 +1,283 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// testBundle holds the fixed prices the tests look up offline. Instance types that
+// are not in it are priced from the built-in prices.
+var testBundle = &Bundle{
+	Currency: "USD",
+	Prices: map[string]map[string]map[string]float64{
+		"ec2": {"us-east-1": {"c5.large": 0.085}},
+		"rds": {"us-east-1": {
+			"db.r5.large": 0.25,
+			"db.r5.large|PostgreSQL||No license required": 0.3,
+		}},
+		"redis": {"us-east-1": {"cache.r6g.large": 0.206}},
+	},
+}
+
+// estimateTest is a resource and the estimate expected for it
+type estimateTest struct {
+	name         string
+	resource     models.Resource
+	wantAmount   float64
+	wantSource   string // not checked when empty
+	wantAccuracy string // not checked when empty
+}
+
+// runEstimateTests estimates the resources of a service, in us-east-1 unless they
+// set a region, with the prices of testBundle
+func runEstimateTests(t *testing.T, service string, tests []estimateTest) {
+	t.Helper()
+	engine := NewOfflineEngine(testBundle)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := tt.resource
+			resource.Service = service
+			if resource.Region == "" {
+				resource.Region = "us-east-1"
+			}
+
+			got := engine.Estimate(context.Background(), resource)
+			if got == nil {
+				t.Fatalf("Estimate() = nil, want $%.2f", tt.wantAmount)
+			}
+			if math.Abs(got.Amount-tt.wantAmount) > 0.005 {
+				t.Errorf("Estimate().Amount = %.4f, want %.4f (%s)", got.Amount, tt.wantAmount, got.Explanation)
+			}
+			if tt.wantSource != "" && got.Source != tt.wantSource {
+				t.Errorf("Estimate().Source = %q, want %q", got.Source, tt.wantSource)
+			}
+			if tt.wantAccuracy != "" && got.Accuracy != tt.wantAccuracy {
+				t.Errorf("Estimate().Accuracy = %q, want %q", got.Accuracy, tt.wantAccuracy)
+			}
+		})
+	}
+}
+
+func TestEstimateEC2Cost(t *testing.T) {
+	gp3 := func(sizeGB int) map[string]interface{} {
+		return map[string]interface{}{"volumeType": "gp3", "sizeGB": sizeGB}
+	}
+
+	runEstimateTests(t, "ec2", []estimateTest{
+		// Built-in prices scale with the size within a family
+		{name: "catalog large", resource: models.Resource{Type: "m5.large", State: "running"}, wantAmount: 0.096 * 730, wantSource: "fallback", wantAccuracy: "Medium"},
+		{name: "catalog xlarge", resource: models.Resource{Type: "m5.xlarge", State: "running"}, wantAmount: 0.192 * 730},
+		{name: "catalog micro", resource: models.Resource{Type: "t3.micro", State: "running"}, wantAmount: 0.0104 * 730},
+		{name: "catalog graviton", resource: models.Resource{Type: "m6g.2xlarge", State: "running"}, wantAmount: 0.308 * 730},
+		{name: "unknown type", resource: models.Resource{Type: "x9.large", State: "running"}, wantAmount: 50, wantAccuracy: "Low"},
+		{name: "bundle price", resource: models.Resource{Type: "c5.large", State: "running"}, wantAmount: 0.085 * 730, wantSource: "bundle", wantAccuracy: "High"},
+		{name: "built-in price in another region", resource: models.Resource{Type: "m5.large", State: "running", Region: "eu-west-1"}, wantAmount: 0.096 * 730, wantSource: "fallback", wantAccuracy: "Low"},
+
+		// Spot instances
+		{name: "spot at the spot price", resource: models.Resource{Type: "m5.large", State: "running", Extra: map[string]interface{}{
+			"lifecycle": "spot", "spotPrice": 0.04, "availabilityZone": "us-east-1a",
+		}}, wantAmount: 0.04 * 730, wantSource: "spot-price", wantAccuracy: "Medium"},
+		{name: "spot without a spot price", resource: models.Resource{Type: "m5.large", State: "running", Extra: map[string]interface{}{
+			"lifecycle": "spot",
+		}}, wantAmount: 0.096 * 730 * spotDiscount, wantAccuracy: "Low"},
+		{name: "spot with a volume", resource: models.Resource{Type: "m5.large", State: "running", Extra: map[string]interface{}{
+			"lifecycle": "spot", "volumes": []map[string]interface{}{gp3(100)},
+		}}, wantAmount: 0.096*730*spotDiscount + 100*0.08},
+
+		// Attached EBS volumes
+		{name: "running with volumes", resource: models.Resource{Type: "m5.large", State: "running", Extra: map[string]interface{}{
+			"volumes": []map[string]interface{}{
+				{"volumeType": "gp3", "sizeGB": 200, "iops": 4000, "throughput": 250},
+				{"volumeType": "gp2", "sizeGB": 50},
+			},
+		}}, wantAmount: 0.096*730 + 200*0.08 + 1000*0.005 + 125*0.04 + 50*0.10},
+
+		// Stopped instances are only charged for their volumes
+		{name: "stopped with volumes", resource: models.Resource{Type: "m5.large", State: "stopped", Extra: map[string]interface{}{
+			"volumes": []interface{}{
+				gp3(100),
+				map[string]interface{}{"volumeType": "io1", "sizeGB": 50, "iops": 1000},
+			},
+		}}, wantAmount: 100*0.08 + 50*0.125 + 1000*0.065, wantSource: "state-check"},
+		{name: "stopping with a volume", resource: models.Resource{Type: "m5.large", State: "stopping", Extra: map[string]interface{}{
+			"volumes": []map[string]interface{}{gp3(30)},
+		}}, wantAmount: 30 * 0.08},
+		{name: "stopped without volumes", resource: models.Resource{Type: "m5.large", State: "stopped"}, wantAmount: 0},
+		{name: "terminated", resource: models.Resource{Type: "m5.large", State: "terminated", Extra: map[string]interface{}{
+			"volumes": []map[string]interface{}{gp3(100)},
+		}}, wantAmount: 0},
+	})
+}
+
+func TestEstimateRDSCost(t *testing.T) {
+	db := func(engine, class string, extra map[string]interface{}) models.Resource {
+		return models.Resource{Type: engine, Class: class, State: "available", Extra: extra}
+	}
+	const mysql = 171.00 // built-in db.m5.large price
+
+	runEstimateTests(t, "rds", []estimateTest{
+		// Built-in prices are scaled by the engine's license factor
+		{name: "mysql", resource: db("mysql", "db.m5.large", nil), wantAmount: mysql, wantSource: "fallback", wantAccuracy: "Medium"},
+		{name: "postgres", resource: db("postgres", "db.m5.large", nil), wantAmount: mysql * 1.04, wantAccuracy: "Low"},
+		{name: "aurora postgresql", resource: db("aurora-postgresql", "db.m5.large", nil), wantAmount: mysql * 1.16},
+		{name: "oracle license included", resource: db("oracle-se2", "db.m5.large", map[string]interface{}{
+			"licenseModel": "license-included",
+		}), wantAmount: mysql * 2.7},
+		{name: "oracle bring your own license", resource: db("oracle-se2", "db.m5.large", map[string]interface{}{
+			"licenseModel": "bring-your-own-license",
+		}), wantAmount: mysql},
+		{name: "oracle enterprise", resource: db("oracle-ee", "db.m5.large", nil), wantAmount: mysql},
+		{name: "sql server standard", resource: db("sqlserver-se", "db.m5.large", map[string]interface{}{
+			"licenseModel": "license-included",
+		}), wantAmount: mysql * 5.7},
+		{name: "sql server enterprise", resource: db("sqlserver-ee", "db.m5.large", nil), wantAmount: mysql * 8.0},
+		{name: "unknown engine", resource: db("db2-se", "db.m5.large", nil), wantAmount: mysql, wantAccuracy: "Low"},
+		{name: "unknown class", resource: db("mysql", "db.x9.large", nil), wantAmount: 100},
+		{name: "built-in price in another region", resource: models.Resource{Type: "mysql", Class: "db.m5.large", State: "available", Region: "eu-west-1"},
+			wantAmount: mysql, wantSource: "fallback", wantAccuracy: "Low"},
+
+		// Multi-AZ deployments double the instance and its storage, but not Aurora
+		{name: "mysql multi-az", resource: db("mysql", "db.m5.large", map[string]interface{}{"multiAZ": true}), wantAmount: mysql * 2},
+		{name: "sql server web multi-az", resource: db("sqlserver-web", "db.m5.large", map[string]interface{}{"multiAZ": true}), wantAmount: mysql * 2.3 * 2},
+		{name: "aurora multi-az", resource: db("aurora-mysql", "db.m5.large", map[string]interface{}{"multiAZ": true}), wantAmount: mysql * 1.16},
+		{name: "postgres multi-az with storage", resource: db("postgres", "db.m5.large", map[string]interface{}{
+			"multiAZ": true, "allocatedStorage": 100, "storageType": "gp2",
+		}), wantAmount: mysql*1.04*2 + 100*0.115*2},
+
+		// Allocated storage
+		{name: "gp3 storage above the baseline", resource: db("mysql", "db.m5.large", map[string]interface{}{
+			"allocatedStorage": 500, "storageType": "gp3", "iops": 15000, "storageThroughput": 600,
+		}), wantAmount: mysql + 500*0.115 + 3000*0.02 + 100*0.08},
+		{name: "io1 storage", resource: db("mysql", "db.m5.large", map[string]interface{}{
+			"allocatedStorage": 100, "storageType": "io1", "iops": 1000,
+		}), wantAmount: mysql + 100*0.125 + 1000*0.10},
+		{name: "aurora storage is not allocated", resource: db("aurora-mysql", "db.m5.large", map[string]interface{}{
+			"allocatedStorage": 1, "storageType": "aurora",
+		}), wantAmount: mysql * 1.16},
+
+		// Bundle prices of the engine, or of the class when the engine has none
+		{name: "bundle engine price", resource: db("postgres", "db.r5.large", nil), wantAmount: 0.3 * 730, wantSource: "bundle", wantAccuracy: "High"},
+		{name: "bundle engine price multi-az", resource: db("postgres", "db.r5.large", map[string]interface{}{"multiAZ": true}), wantAmount: 0.3 * 730 * 2, wantSource: "bundle"},
+		{name: "bundle class price", resource: db("mysql", "db.r5.large", nil), wantAmount: 0.25 * 730, wantSource: "bundle", wantAccuracy: "Medium"},
+
+		{name: "stopped", resource: models.Resource{Type: "mysql", Class: "db.m5.large", State: "stopped", Extra: map[string]interface{}{
+			"allocatedStorage": 100, "storageType": "gp2",
+		}}, wantAmount: 0, wantSource: "state-check"},
+	})
+}
+
+func TestEstimateRedisCost(t *testing.T) {
+	cluster := func(class string, extra map[string]interface{}) models.Resource {
+		return models.Resource{Class: class, State: "available", Extra: extra}
+	}
+
+	runEstimateTests(t, "redis", []estimateTest{
+		{name: "single node", resource: cluster("cache.m5.large", nil), wantAmount: 99.28, wantSource: "fallback"},
+		{name: "one node", resource: cluster("cache.m5.large", map[string]interface{}{"numCacheNodes": 1}), wantAmount: 99.28},
+		{name: "nodes multiply", resource: cluster("cache.m5.large", map[string]interface{}{"numCacheNodes": int32(3)}), wantAmount: 99.28 * 3},
+		{name: "unknown node type", resource: cluster("cache.x9.large", map[string]interface{}{"numCacheNodes": 2}), wantAmount: 50 * 2},
+		{name: "bundle price", resource: cluster("cache.r6g.large", map[string]interface{}{"numCacheNodes": 2}), wantAmount: 0.206 * 730 * 2, wantSource: "bundle"},
+		{name: "replication group member", resource: cluster("cache.t3.small", map[string]interface{}{"replicationGroupId": "sessions"}), wantAmount: 24.82},
+		{name: "not available", resource: models.Resource{Class: "cache.m5.large", State: "creating", Extra: map[string]interface{}{"numCacheNodes": 3}}, wantAmount: 0},
+	})
+}
+
+func TestEstimateDynamoDBCost(t *testing.T) {
+	table := func(extra map[string]interface{}) models.Resource {
+		return models.Resource{Type: "table", State: "ACTIVE", Extra: extra}
+	}
+
+	runEstimateTests(t, "dynamodb", []estimateTest{
+		{name: "provisioned", resource: table(map[string]interface{}{
+			"billingMode": "PROVISIONED", "readCapacityUnits": 5, "writeCapacityUnits": 5, "tableSizeBytes": int64(bytesPerGB),
+		}), wantAmount: (5*0.00013+5*0.00065)*730 + 0.25, wantAccuracy: "Medium"},
+		{name: "provisioned with indexes", resource: table(map[string]interface{}{
+			"readCapacityUnits": 10, "writeCapacityUnits": 4, "indexReadCapacityUnits": 5, "indexWriteCapacityUnits": 1,
+		}), wantAmount: (15*0.00013 + 5*0.00065) * 730},
+		{name: "infrequent access", resource: table(map[string]interface{}{
+			"billingMode": "PROVISIONED", "tableClass": "STANDARD_INFREQUENT_ACCESS",
+			"readCapacityUnits": 100, "writeCapacityUnits": 50, "tableSizeBytes": int64(10 * bytesPerGB),
+		}), wantAmount: (100*0.00016+50*0.00081)*730 + 10*0.10},
+		{name: "on-demand storage only", resource: table(map[string]interface{}{
+			"billingMode": "PAY_PER_REQUEST", "tableSizeBytes": int64(10 * bytesPerGB),
+		}), wantAmount: 10 * 0.25, wantAccuracy: "Low"},
+		{name: "without capacity or billing mode", resource: table(nil), wantAmount: 0},
+
+		// Usage measured with --usage-metrics over 30 days, scaled to 730 hours
+		{name: "measured on-demand", resource: table(map[string]interface{}{
+			"billingMode": "PAY_PER_REQUEST", "readUnits30d": 720e6, "writeUnits30d": 72e6,
+		}), wantAmount: 730*0.25 + 73*1.25, wantAccuracy: "High"},
+		{name: "measured provisioned", resource: table(map[string]interface{}{
+			"billingMode": "PROVISIONED", "readCapacityUnits": 5, "writeCapacityUnits": 5, "readUnits30d": 720e6, "tableSizeBytes": int64(bytesPerGB),
+		}), wantAmount: (5*0.00013+5*0.00065)*730 + 0.25},
+
+		{name: "dax nodes multiply", resource: models.Resource{Type: "dax-cluster", Class: "dax.r5.large", State: "available", Extra: map[string]interface{}{
+			"totalNodes": 3,
+		}}, wantAmount: 0.269 * 3 * 730},
+	})
+}
+
+func TestEstimateLambdaCost(t *testing.T) {
+	function := func(extra map[string]interface{}) models.Resource {
+		return models.Resource{Type: "function", Name: "handler", Extra: extra}
+	}
+	const provisionedSeconds = 730 * 3600
+
+	runEstimateTests(t, "lambda", []estimateTest{
+		{name: "unknown usage", resource: function(nil), wantAmount: 5},
+		{name: "provisioned concurrency", resource: function(map[string]interface{}{
+			"memorySize": int32(1024), "provisionedConcurrency": 10,
+		}), wantAmount: 5 + 10*1*provisionedSeconds*0.0000041667},
+		{name: "arm64 provisioned concurrency", resource: function(map[string]interface{}{
+			"memorySize": 512, "provisionedConcurrency": 4, "architectures": []string{"arm64"},
+		}), wantAmount: 5 + 4*0.5*provisionedSeconds*0.0000033334},
+		{name: "measured usage", resource: function(map[string]interface{}{
+			"invocations30d": 720000, "durationMs30d": 72e6,
+		}), wantAmount: (0.72*0.20 + 72000*0.125*0.0000166667) * usageMonthScale},
+		{name: "measured arm64 usage", resource: function(map[string]interface{}{
+			"invocations30d": 720000, "durationMs30d": 72e6, "memorySize": 256, "architectures": []interface{}{"arm64"},
+		}), wantAmount: (0.72*0.20 + 72000*0.25*0.0000133334) * usageMonthScale},
+		{name: "event source mapping", resource: models.Resource{Type: "event-source-mapping"}, wantAmount: 0},
+	})
+}
+
+func TestEstimateEBSCost(t *testing.T) {
+	volume := func(state string, extra map[string]interface{}) models.Resource {
+		return models.Resource{Type: "volume", Name: "data", State: state, Extra: extra}
+	}
+
+	runEstimateTests(t, "ebs", []estimateTest{
+		{name: "unattached gp3", resource: volume("available", map[string]interface{}{"volumeType": "gp3", "sizeGB": 500}), wantAmount: 500 * 0.08},
+		{name: "gp3 above the baseline", resource: volume("available", map[string]interface{}{
+			"volumeType": "gp3", "sizeGB": 500, "iops": 6000, "throughput": 250,
+		}), wantAmount: 500*0.08 + 3000*0.005 + 125*0.04},
+		{name: "io2", resource: volume("available", map[string]interface{}{"volumeType": "io2", "sizeGB": 100, "iops": 2000}), wantAmount: 100*0.125 + 2000*0.065},
+		{name: "unknown type priced as gp2", resource: volume("available", map[string]interface{}{"volumeType": "gp9", "sizeGB": 10}), wantAmount: 10 * 0.10},
+		{name: "attached", resource: volume("in-use", map[string]interface{}{"volumeType": "gp3", "sizeGB": 500}), wantAmount: 0},
+		{name: "deleting", resource: volume("deleting", map[string]interface{}{"volumeType": "gp3", "sizeGB": 500}), wantAmount: 0},
+	})
+}
+
+func TestEstimateFargateCost(t *testing.T) {
+	service := func(extra map[string]interface{}) models.Resource {
+		return models.Resource{Type: "service", Name: "web", Extra: extra}
+	}
+
+	runEstimateTests(t, "ecs", []estimateTest{
+		{name: "x86_64", resource: service(map[string]interface{}{
+			"fargate": true, "fargateVcpu": 2.0, "fargateMemoryGB": 4.0, "desiredCount": 2,
+		}), wantAmount: (2*0.04048 + 4*0.004445) * 730, wantAccuracy: "High"},
+		{name: "arm64", resource: service(map[string]interface{}{
+			"fargate": true, "fargateVcpu": 2.0, "fargateMemoryGB": 4.0, "cpuArchitecture": "ARM64",
+		}), wantAmount: (2*0.03238 + 4*0.00356) * 730},
+		{name: "spot at on-demand rates", resource: service(map[string]interface{}{
+			"fargate": true, "fargateVcpu": 0.25, "fargateMemoryGB": 0.5, "capacityProvider": "FARGATE_SPOT",
+		}), wantAmount: (0.25*0.04048 + 0.5*0.004445) * 730, wantAccuracy: "Medium"},
+		{name: "unknown task size", resource: service(map[string]interface{}{"fargate": true}), wantAmount: 0, wantAccuracy: "Low"},
+		{name: "ec2 launch type", resource: service(map[string]interface{}{"fargateVcpu": 2.0}), wantAmount: 0},
+	})
+}
@@ -0,0 +1,138 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// rightsizeHeadroom is the utilization an instance may peak at after downsizing. Each
+// size down halves capacity, so one size down needs a peak under half of it.
+const rightsizeHeadroom = 80.0
+
+// rightsizeMaxSteps is the most sizes an instance is taken down at once
+const rightsizeMaxSteps = 2
+
+// Rightsize recommends smaller sizes for running EC2 and RDS instances whose CPU, and
+// where measured memory, peaked low enough over the last 30 days that a smaller size
+// would still peak under 80%. Savings assume prices scale with size within an
+// instance family, as they do on demand. Instances covered by reservations or
+// Savings Plans are left out, since their cost is committed. The recommendations are
+// sorted by savings, highest first.
+func Rightsize(resources []models.Resource, costs map[string]*models.CostEstimate) []models.Recommendation {
+	var recommendations []models.Recommendation
+	for _, resource := range resources {
+		cpuPeak, ok := models.ExtraNumber(resource.Extra, "cpuPeak30d")
+		if !ok {
+			continue
+		}
+		estimate := costs[resource.Key()]
+		if estimate == nil || estimate.Coverage != "" {
+			continue
+		}
+
+		current := resource.Type
+		if resource.Service == "rds" {
+			current = resource.Class
+		}
+
+		peak := cpuPeak
+		memoryPeak, hasMemory := models.ExtraNumber(resource.Extra, "memoryPeak30d")
+		if hasMemory {
+			peak = math.Max(peak, memoryPeak)
+		}
+
+		// Take the instance down while the peak, doubled each size, stays under the headroom
+		recommended, steps := current, 0
+		for steps < rightsizeMaxSteps && peak*math.Pow(2, float64(steps+1)) < rightsizeHeadroom {
+			smaller, ok := smallerSize(recommended)
+			if !ok {
+				break
+			}
+			recommended = smaller
+			steps++
+		}
+		if steps == 0 {
+			continue
+		}
+
+		compute := computeCost(estimate)
+		if compute <= 0 {
+			continue
+		}
+		savings := compute * (1 - 1/math.Pow(2, float64(steps)))
+
+		cpuAverage, _ := models.ExtraNumber(resource.Extra, "cpuAverage30d")
+		reason := fmt.Sprintf("CPU peaked at %.0f%% (average %.0f%%) over 30 days", cpuPeak, cpuAverage)
+		if hasMemory {
+			reason += fmt.Sprintf(", memory at %.0f%%", memoryPeak)
+		} else if resource.Service == "ec2" {
+			reason += "; memory not measured"
+		}
+
+		recommendations = append(recommendations, models.Recommendation{
+			Service:       resource.Service,
+			Region:        resource.Region,
+			ID:            resource.ID,
+			Name:          resource.Name,
+			Current:       current,
+			Recommended:   recommended,
+			Reason:        reason,
+			CurrentCost:   estimate.Amount,
+			ProjectedCost: estimate.Amount - savings,
+			Savings:       savings,
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].Savings > recommendations[j].Savings
+	})
+	return recommendations
+}
+
+// smallerSizes maps instance sizes to the size of half the capacity and price in the
+// same family. Sizes whose half is not commonly offered, such as 12xlarge, have none.
+var smallerSizes = map[string]string{
+	"xlarge":   "large",
+	"2xlarge":  "xlarge",
+	"4xlarge":  "2xlarge",
+	"8xlarge":  "4xlarge",
+	"16xlarge": "8xlarge",
+	"24xlarge": "12xlarge",
+	"32xlarge": "16xlarge",
+	"48xlarge": "24xlarge",
+}
+
+// burstableSmallerSizes are the smaller sizes of burstable (T family) instances, which
+// also come in sizes below large
+var burstableSmallerSizes = map[string]string{
+	"large":  "medium",
+	"medium": "small",
+	"small":  "micro",
+}
+
+// smallerSize returns the instance type of half the size in the same family, such as
+// m5.xlarge for m5.2xlarge or db.r6g.large for db.r6g.xlarge, and whether there is one
+func smallerSize(instanceType string) (string, bool) {
+	prefix := ""
+	if rest, ok := strings.CutPrefix(instanceType, "db."); ok {
+		prefix, instanceType = "db.", rest
+	}
+
+	family, size, ok := strings.Cut(instanceType, ".")
+	if !ok {
+		return "", false
+	}
+
+	smaller, ok := smallerSizes[size]
+	if !ok && strings.HasPrefix(family, "t") {
+		smaller, ok = burstableSmallerSizes[size]
+	}
+	if !ok {
+		return "", false
+	}
+	return prefix + family + "." + smaller, true
+}
@@ -3,34 +3,46 @@ package pricing
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/freetier"
 	"github.com/aws/aws-sdk-go-v2/service/pricing"
 	"github.com/aws/aws-sdk-go-v2/service/pricing/types"
-	"github.com/aws/aws-sdk-go-v2/service/support"
 )
 
+// errNoPrice is returned when the Pricing API has no usable price for a product
+var errNoPrice = errors.New("no price found")
+
 // PricingService handles AWS pricing API integration and caching
 type PricingService struct {
-	pricingClient *pricing.Client
-	supportClient *support.Client
+	pricingClient  *pricing.Client
+	freeTierClient *freetier.Client
 	cache         *PricingCache
 	freeTier      *FreeTierService
 	mu            sync.RWMutex
 }
 
-// PricingCache stores pricing data with TTL
+// PricingCache stores pricing data with TTL, optionally in a file so prices are kept
+// between runs
 type PricingCache struct {
 	data map[string]CachedPrice
+	path string // file the cache is kept in, or empty to keep it in memory only
 	mu   sync.RWMutex
 }
 
+// priceTTL is how long a price from the Pricing API is reused
+const priceTTL = 24 * time.Hour
+
 // CachedPrice represents a cached pricing entry
 type CachedPrice struct {
 	Price     float64   `json:"price"`
@@ -40,7 +52,6 @@ type CachedPrice struct {
 
 // FreeTierService handles free tier detection and calculations
 type FreeTierService struct {
-	accountAge   time.Duration
 	isEligible   bool
 	usage        map[string]FreeTierUsage
 	mu           sync.RWMutex
@@ -53,6 +64,39 @@ type FreeTierUsage struct {
 	RemainingGB       float64   `json:"remaining_gb"`
 	RemainingRequests int64     `json:"remaining_requests"`
 	LastUpdated       time.Time `json:"last_updated"`
+
+	// Allowances are the service's free tier offers as reported by the Free Tier API
+	Allowances []FreeTierAllowance `json:"allowances,omitempty"`
+}
+
+// FreeTierAllowance is the month-to-date usage of one free tier offer
+type FreeTierAllowance struct {
+	Description string  `json:"description"`
+	UsageType   string  `json:"usage_type"`
+	Type        string  `json:"type"` // "Always Free", "12 Months Free" or "Trial"
+	Unit        string  `json:"unit"`
+	Used        float64 `json:"used"`
+	Forecast    float64 `json:"forecast"`
+	Limit       float64 `json:"limit"`
+}
+
+// Remaining returns how much of the allowance is left this month
+func (a FreeTierAllowance) Remaining() float64 {
+	if a.Used >= a.Limit {
+		return 0
+	}
+	return a.Limit - a.Used
+}
+
+// freeTierServices maps the service names of the Free Tier API to the service names
+// of cost estimates
+var freeTierServices = map[string]string{
+	"Amazon Elastic Compute Cloud":       "ec2",
+	"Amazon Relational Database Service": "rds",
+	"AWS Lambda":                         "lambda",
+	"Amazon Simple Storage Service":      "s3",
+	"Amazon DynamoDB":                    "dynamodb",
+	"Amazon ElastiCache":                 "redis",
 }
 
 // PricingResult contains pricing information with free tier considerations
@@ -64,7 +108,7 @@ type PricingResult struct {
 	FreeTierSavings float64
 	Region          string
 	Accuracy        string
-	Source          string // "api", "cache", "fallback"
+	Source          string // "api" or "cache"
 }
 
 // ServiceConfig contains service-specific pricing configuration
@@ -72,23 +116,53 @@ type ServiceConfig struct {
 	ServiceCode  string
 	ProductFamily string
 	AttributeFilters map[string]string
+
+	// VariantAttributes are the product attributes that set apart the prices of one
+	// instance type, such as the database engine of RDS instances
+	VariantAttributes []string
+}
+
+// ServiceOptions configures a PricingService
+type ServiceOptions struct {
+	// CachePath is the file prices are cached in between runs; empty keeps them in memory
+	CachePath string
+
+	// Refresh ignores the prices cached in CachePath, fetching them again
+	Refresh bool
+
+	// Config is the AWS configuration to call the Pricing API with; by default it is
+	// loaded from the environment
+	Config *aws.Config
 }
 
 // NewPricingService creates a new pricing service instance
-func NewPricingService(ctx context.Context) (*PricingService, error) {
-	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+func NewPricingService(ctx context.Context, opts ServiceOptions) (*PricingService, error) {
+	var cfg aws.Config
+	if opts.Config != nil {
+		cfg = opts.Config.Copy()
+		cfg.Region = "us-east-1"
+	} else {
+		var err error
+		cfg, err = config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
 	}
 
 	// Pricing API is only available in us-east-1
 	pricingClient := pricing.NewFromConfig(cfg)
 	
-	// Support API for account information
-	supportClient := support.NewFromConfig(cfg)
+	// Free Tier API for the account's remaining free tier usage, also in us-east-1
+	freeTierClient := freetier.NewFromConfig(cfg)
 
 	cache := &PricingCache{
 		data: make(map[string]CachedPrice),
+		path: opts.CachePath,
+	}
+	if !opts.Refresh {
+		if err := cache.load(); err != nil {
+			log.Printf("Warning: Could not read the pricing cache: %v", err)
+		}
 	}
 
 	freeTier := &FreeTierService{
@@ -96,106 +170,148 @@ func NewPricingService(ctx context.Context) (*PricingService, error) {
 	}
 
 	service := &PricingService{
-		pricingClient: pricingClient,
-		supportClient: supportClient,
-		cache:         cache,
-		freeTier:      freeTier,
+		pricingClient:  pricingClient,
+		freeTierClient: freeTierClient,
+		cache:          cache,
+		freeTier:       freeTier,
 	}
 
-	// Initialize free tier information
+	// Without free tier information, no free tier is applied to estimates
 	if err := service.initializeFreeTier(ctx); err != nil {
-		log.Printf("Warning: Could not initialize free tier information: %v", err)
+		log.Printf("Warning: Could not get free tier usage, estimates exclude the free tier: %v", err)
 	}
 
 	return service, nil
 }
 
-// initializeFreeTier initializes free tier eligibility and usage
+// initializeFreeTier reads the account's month-to-date free tier usage from the Free
+// Tier API. The account is eligible while it has 12-month free tier offers; the
+// remaining hours, storage and requests of each service are those of the offer with
+// the least left in each unit.
 func (ps *PricingService) initializeFreeTier(ctx context.Context) error {
-	ps.freeTier.mu.Lock()
-	defer ps.freeTier.mu.Unlock()
+	var usages []FreeTierAllowance
+	var services []string
+	var nextToken *string
 
-	// For now, assume account is eligible for free tier
-	// In production, you would call Support API to get account creation date
-	ps.freeTier.isEligible = true
-	ps.freeTier.accountAge = time.Hour * 24 * 30 // Assume 30 days old
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-	// Initialize free tier usage for supported services
-	ps.freeTier.usage["ec2"] = FreeTierUsage{
-		Service:           "ec2",
-		RemainingHours:    750.0, // 750 hours/month for t2.micro
-		LastUpdated:       time.Now(),
-	}
+		result, err := ps.freeTierClient.GetFreeTierUsage(ctx, &freetier.GetFreeTierUsageInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get free tier usage: %w", err)
+		}
 
-	ps.freeTier.usage["rds"] = FreeTierUsage{
-		Service:           "rds",
-		RemainingHours:    750.0, // 750 hours/month for db.t2.micro
-		LastUpdated:       time.Now(),
-	}
+		for _, usage := range result.FreeTierUsages {
+			service, ok := freeTierServices[aws.ToString(usage.Service)]
+			if !ok {
+				continue
+			}
+			services = append(services, service)
+			usages = append(usages, FreeTierAllowance{
+				Description: aws.ToString(usage.Description),
+				UsageType:   aws.ToString(usage.UsageType),
+				Type:        aws.ToString(usage.FreeTierType),
+				Unit:        aws.ToString(usage.Unit),
+				Used:        usage.ActualUsageAmount,
+				Forecast:    usage.ForecastedUsageAmount,
+				Limit:       usage.Limit,
+			})
+		}
 
-	ps.freeTier.usage["lambda"] = FreeTierUsage{
-		Service:           "lambda",
-		RemainingRequests: 1000000, // 1M requests/month
-		LastUpdated:       time.Now(),
+		nextToken = result.NextToken
+		if nextToken == nil {
+			break
+		}
 	}
 
-	ps.freeTier.usage["s3"] = FreeTierUsage{
-		Service:        "s3",
-		RemainingGB:    5.0, // 5GB storage
-		LastUpdated:    time.Now(),
-	}
+	ps.freeTier.mu.Lock()
+	defer ps.freeTier.mu.Unlock()
+
+	now := time.Now()
+	remaining := make(map[string]map[string]float64)
+	for i, allowance := range usages {
+		service := services[i]
+		if strings.Contains(allowance.Type, "12 Months") {
+			ps.freeTier.isEligible = true
+		}
 
-	ps.freeTier.usage["dynamodb"] = FreeTierUsage{
-		Service:        "dynamodb",
-		RemainingGB:    25.0, // 25GB storage
-		LastUpdated:    time.Now(),
+		usage, exists := ps.freeTier.usage[service]
+		if !exists {
+			usage = FreeTierUsage{Service: service, LastUpdated: now}
+			remaining[service] = make(map[string]float64)
+		}
+		usage.Allowances = append(usage.Allowances, allowance)
+
+		// Offers in the same unit, such as Linux and Windows instance hours, are
+		// counted by the one with the least left
+		left, seen := remaining[service][allowance.Unit]
+		if !seen || allowance.Remaining() < left {
+			left = allowance.Remaining()
+			remaining[service][allowance.Unit] = left
+			switch allowance.Unit {
+			case "Hrs":
+				usage.RemainingHours = left
+			case "GB-Mo":
+				usage.RemainingGB = left
+			case "Requests", "Request":
+				usage.RemainingRequests = int64(left)
+			}
+		}
+		ps.freeTier.usage[service] = usage
 	}
 
 	return nil
 }
 
-// GetServiceConfig returns pricing configuration for a service
-func (ps *PricingService) GetServiceConfig(service string) ServiceConfig {
-	configs := map[string]ServiceConfig{
-		"ec2": {
-			ServiceCode:   "AmazonEC2",
-			ProductFamily: "Compute Instance",
-			AttributeFilters: map[string]string{
-				"tenancy":     "Shared",
-				"capacitystatus": "Used",
-				"preInstalledSw": "NA",
-			},
-		},
-		"rds": {
-			ServiceCode:   "AmazonRDS",
-			ProductFamily: "Database Instance",
-			AttributeFilters: map[string]string{
-				"deploymentOption": "Single-AZ",
-			},
+// serviceConfigs are the pricing configurations of the services
+var serviceConfigs = map[string]ServiceConfig{
+	"ec2": {
+		ServiceCode:   "AmazonEC2",
+		ProductFamily: "Compute Instance",
+		AttributeFilters: map[string]string{
+			"tenancy":     "Shared",
+			"capacitystatus": "Used",
+			"preInstalledSw": "NA",
+			"operatingSystem": "Linux",
 		},
-		"lambda": {
-			ServiceCode:   "AWSLambda",
-			ProductFamily: "Serverless",
+	},
+	"rds": {
+		ServiceCode:   "AmazonRDS",
+		ProductFamily: "Database Instance",
+		AttributeFilters: map[string]string{
+			"deploymentOption": "Single-AZ",
 		},
-		"s3": {
-			ServiceCode:   "AmazonS3",
-			ProductFamily: "Storage",
-		},
-		"dynamodb": {
-			ServiceCode:   "AmazonDynamoDB",
-			ProductFamily: "Database Storage and IO",
-		},
-		"redis": {
-			ServiceCode:   "AmazonElastiCache",
-			ProductFamily: "Cache Instance",
-		},
-		"efs": {
-			ServiceCode:   "AmazonEFS",
-			ProductFamily: "Storage",
-		},
-	}
+		VariantAttributes: []string{"databaseEngine", "databaseEdition", "licenseModel"},
+	},
+	"lambda": {
+		ServiceCode:   "AWSLambda",
+		ProductFamily: "Serverless",
+	},
+	"s3": {
+		ServiceCode:   "AmazonS3",
+		ProductFamily: "Storage",
+	},
+	"dynamodb": {
+		ServiceCode:   "AmazonDynamoDB",
+		ProductFamily: "Database Storage and IO",
+	},
+	"redis": {
+		ServiceCode:   "AmazonElastiCache",
+		ProductFamily: "Cache Instance",
+	},
+	"efs": {
+		ServiceCode:   "AmazonEFS",
+		ProductFamily: "Storage",
+	},
+}
 
-	if config, exists := configs[service]; exists {
+// GetServiceConfig returns pricing configuration for a service
+func (ps *PricingService) GetServiceConfig(service string) ServiceConfig {
+	if config, exists := serviceConfigs[service]; exists {
 		return config
 	}
 
@@ -206,9 +322,11 @@ func (ps *PricingService) GetServiceConfig(service string) ServiceConfig {
 	}
 }
 
-// GetPricing retrieves pricing for a specific resource
-func (ps *PricingService) GetPricing(ctx context.Context, service, region, instanceType string) (*PricingResult, error) {
-	cacheKey := fmt.Sprintf("%s-%s-%s", service, region, instanceType)
+// GetPricing retrieves pricing for a specific resource. attributes narrow the price
+// down to a variant of the instance type, see ServiceConfig.VariantAttributes.
+func (ps *PricingService) GetPricing(ctx context.Context, service, region, instanceType string, attributes map[string]string) (*PricingResult, error) {
+	serviceConfig := ps.GetServiceConfig(service)
+	cacheKey := fmt.Sprintf("%s-%s-%s", service, region, priceKey(instanceType, serviceConfig.VariantAttributes, attributes))
 
 	// Check cache first
 	if cachedPrice, found := ps.cache.get(cacheKey); found {
@@ -226,18 +344,16 @@ func (ps *PricingService) GetPricing(ctx context.Context, service, region, insta
 	}
 
 	// Get from AWS Pricing API
-	serviceConfig := ps.GetServiceConfig(service)
-	price, err := ps.fetchPricingFromAPI(ctx, serviceConfig, region, instanceType)
+	price, err := ps.fetchPricingFromAPI(ctx, serviceConfig, region, instanceType, attributes)
 	if err != nil {
-		log.Printf("Failed to fetch pricing from API for %s: %v", cacheKey, err)
-		// Fall back to hardcoded estimates
-		return ps.getFallbackPricing(service, region, instanceType), nil
+		// The Engine falls back to its built-in prices
+		return nil, err
 	}
 
 	// Cache the result
 	ps.cache.set(cacheKey, CachedPrice{
 		Price:     price,
-		ExpiresAt: time.Now().Add(24 * time.Hour), // Cache for 24 hours
+		ExpiresAt: time.Now().Add(priceTTL),
 		Currency:  "USD",
 	})
 
@@ -255,7 +371,38 @@ func (ps *PricingService) GetPricing(ctx context.Context, service, region, insta
 }
 
 // fetchPricingFromAPI retrieves pricing from AWS Pricing API
-func (ps *PricingService) fetchPricingFromAPI(ctx context.Context, serviceConfig ServiceConfig, region, instanceType string) (float64, error) {
+func (ps *PricingService) fetchPricingFromAPI(ctx context.Context, serviceConfig ServiceConfig, region, instanceType string, attributes map[string]string) (float64, error) {
+	location, ok := ps.getLocationFromRegion(region)
+	if !ok {
+		return 0, fmt.Errorf("%w: no pricing location for region %s", errNoPrice, region)
+	}
+
+	input := &pricing.GetProductsInput{
+		ServiceCode:   aws.String(serviceConfig.ServiceCode),
+		Filters:       productFilters(serviceConfig, location, instanceType, attributes),
+		MaxResults:    aws.Int32(10),
+	}
+
+	resp, err := ps.pricingClient.GetProducts(ctx, input)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	if len(resp.PriceList) == 0 {
+		return 0, fmt.Errorf("%w for %s %s in %s", errNoPrice, serviceConfig.ServiceCode, instanceType, region)
+	}
+
+	// Parse the first result (pricing data is in JSON format)
+	price, err := ps.parsePricingData(resp.PriceList[0])
+	if err != nil {
+		return 0, fmt.Errorf("%w for %s %s in %s: %v", errNoPrice, serviceConfig.ServiceCode, instanceType, region, err)
+	}
+	return price, nil
+}
+
+// productFilters returns the Pricing API filters for the products of a service in a
+// location, optionally of one instance type and variant
+func productFilters(serviceConfig ServiceConfig, location, instanceType string, attributes map[string]string) []types.Filter {
 	filters := []types.Filter{
 		{
 			Type:  types.FilterTypeTermMatch,
@@ -265,7 +412,7 @@ func (ps *PricingService) fetchPricingFromAPI(ctx context.Context, serviceConfig
 		{
 			Type:  types.FilterTypeTermMatch,
 			Field: aws.String("location"),
-			Value: aws.String(ps.getLocationFromRegion(region)),
+			Value: aws.String(location),
 		},
 	}
 
@@ -287,23 +434,33 @@ func (ps *PricingService) fetchPricingFromAPI(ctx context.Context, serviceConfig
 		})
 	}
 
-	input := &pricing.GetProductsInput{
-		ServiceCode:   aws.String(serviceConfig.ServiceCode),
-		Filters:       filters,
-		MaxResults:    aws.Int32(10),
+	// Products without an attribute, such as the edition of open source engines, do not
+	// match a filter on it
+	for _, field := range serviceConfig.VariantAttributes {
+		if value := attributes[field]; value != "" {
+			filters = append(filters, types.Filter{
+				Type:  types.FilterTypeTermMatch,
+				Field: aws.String(field),
+				Value: aws.String(value),
+			})
+		}
 	}
 
-	resp, err := ps.pricingClient.GetProducts(ctx, input)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get products: %w", err)
-	}
+	return filters
+}
 
-	if len(resp.PriceList) == 0 {
-		return 0, fmt.Errorf("no pricing data found for %s %s in %s", serviceConfig.ServiceCode, instanceType, region)
+// priceKey returns the key of the price of an instance type variant: the instance type,
+// followed by the values of the variant attributes when there are any
+func priceKey(instanceType string, variantAttributes []string, attributes map[string]string) string {
+	if len(attributes) == 0 {
+		return instanceType
 	}
 
-	// Parse the first result (pricing data is in JSON format)
-	return ps.parsePricingData(resp.PriceList[0])
+	key := instanceType
+	for _, field := range variantAttributes {
+		key += "|" + attributes[field]
+	}
+	return key
 }
 
 // parsePricingData extracts hourly price from AWS pricing JSON
@@ -360,32 +517,47 @@ func (ps *PricingService) parsePricingData(priceListItem string) (float64, error
 	return 0, fmt.Errorf("no valid price found in pricing data")
 }
 
-// getLocationFromRegion converts AWS region to location name used in pricing API
-func (ps *PricingService) getLocationFromRegion(region string) string {
-	locationMap := map[string]string{
-		"us-east-1":      "US East (N. Virginia)",
-		"us-east-2":      "US East (Ohio)",
-		"us-west-1":      "US West (N. California)",
-		"us-west-2":      "US West (Oregon)",
-		"eu-west-1":      "Europe (Ireland)",
-		"eu-west-2":      "Europe (London)",
-		"eu-west-3":      "Europe (Paris)",
-		"eu-central-1":   "Europe (Frankfurt)",
-		"eu-north-1":     "Europe (Stockholm)",
-		"ap-southeast-1": "Asia Pacific (Singapore)",
-		"ap-southeast-2": "Asia Pacific (Sydney)",
-		"ap-northeast-1": "Asia Pacific (Tokyo)",
-		"ap-northeast-2": "Asia Pacific (Seoul)",
-		"ap-northeast-3": "Asia Pacific (Osaka)",
-		"ap-south-1":     "Asia Pacific (Mumbai)",
-		"ca-central-1":   "Canada (Central)",
-		"sa-east-1":      "South America (São Paulo)",
-	}
-
-	if location, exists := locationMap[region]; exists {
-		return location
-	}
-	return "US East (N. Virginia)" // Default fallback
+// regionLocations maps regions to the location names the Pricing API uses
+var regionLocations = map[string]string{
+	"us-east-1":      "US East (N. Virginia)",
+	"us-east-2":      "US East (Ohio)",
+	"us-west-1":      "US West (N. California)",
+	"us-west-2":      "US West (Oregon)",
+	"us-gov-east-1":  "AWS GovCloud (US-East)",
+	"us-gov-west-1":  "AWS GovCloud (US-West)",
+	"af-south-1":     "Africa (Cape Town)",
+	"eu-west-1":      "Europe (Ireland)",
+	"eu-west-2":      "Europe (London)",
+	"eu-west-3":      "Europe (Paris)",
+	"eu-central-1":   "Europe (Frankfurt)",
+	"eu-central-2":   "Europe (Zurich)",
+	"eu-north-1":     "Europe (Stockholm)",
+	"eu-south-1":     "Europe (Milan)",
+	"eu-south-2":     "Europe (Spain)",
+	"il-central-1":   "Israel (Tel Aviv)",
+	"me-central-1":   "Middle East (UAE)",
+	"me-south-1":     "Middle East (Bahrain)",
+	"ap-east-1":      "Asia Pacific (Hong Kong)",
+	"ap-southeast-1": "Asia Pacific (Singapore)",
+	"ap-southeast-2": "Asia Pacific (Sydney)",
+	"ap-southeast-3": "Asia Pacific (Jakarta)",
+	"ap-southeast-4": "Asia Pacific (Melbourne)",
+	"ap-southeast-5": "Asia Pacific (Malaysia)",
+	"ap-northeast-1": "Asia Pacific (Tokyo)",
+	"ap-northeast-2": "Asia Pacific (Seoul)",
+	"ap-northeast-3": "Asia Pacific (Osaka)",
+	"ap-south-1":     "Asia Pacific (Mumbai)",
+	"ap-south-2":     "Asia Pacific (Hyderabad)",
+	"ca-central-1":   "Canada (Central)",
+	"ca-west-1":      "Canada West (Calgary)",
+	"sa-east-1":      "South America (São Paulo)",
+}
+
+// getLocationFromRegion converts AWS region to location name used in pricing API, and
+// reports whether the region is known
+func (ps *PricingService) getLocationFromRegion(region string) (string, bool) {
+	location, exists := regionLocations[region]
+	return location, exists
 }
 
 // freeTierCheck represents free tier check result
@@ -453,61 +625,10 @@ func (ps *PricingService) checkFreeTier(service, instanceType string, hourlyPric
 	return freeTierCheck{covered: false, savings: 0}
 }
 
-// getFallbackPricing returns hardcoded estimates when API fails
-func (ps *PricingService) getFallbackPricing(service, region, instanceType string) *PricingResult {
-	// Fallback to our existing hardcoded estimates
-	fallbackPrices := map[string]map[string]float64{
-		"ec2": {
-			"t3.micro":  0.0116,
-			"t3.small":  0.0232,
-			"t3.medium": 0.0464,
-			"t3.large":  0.0928,
-			"m5.large":  0.1184,
-			"default":   0.05,
-		},
-		"rds": {
-			"db.t3.micro":  0.0205,
-			"db.t3.small":  0.041,
-			"db.t3.medium": 0.082,
-			"db.m5.large":  0.234,
-			"default":      0.1,
-		},
-		"redis": {
-			"cache.t3.micro":  0.017,
-			"cache.t3.small":  0.034,
-			"cache.m5.large":  0.136,
-			"default":         0.05,
-		},
-	}
-
-	var hourlyPrice float64
-	if servicePrices, exists := fallbackPrices[service]; exists {
-		if price, exists := servicePrices[instanceType]; exists {
-			hourlyPrice = price
-		} else {
-			hourlyPrice = servicePrices["default"]
-		}
-	} else {
-		hourlyPrice = 0.05 // Default fallback
-	}
-
-	freeTierResult := ps.checkFreeTier(service, instanceType, hourlyPrice)
-	return &PricingResult{
-		HourlyPrice:     hourlyPrice,
-		MonthlyPrice:    hourlyPrice * 730,
-		Currency:        "USD",
-		FreeTierCovered: freeTierResult.covered,
-		FreeTierSavings: freeTierResult.savings,
-		Region:          region,
-		Accuracy:        "Medium",
-		Source:          "fallback",
-	}
-}
-
 // Cache methods
 func (cache *PricingCache) get(key string) (CachedPrice, bool) {
-	cache.mu.RLock()
-	defer cache.mu.RUnlock()
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
 
 	if price, exists := cache.data[key]; exists {
 		if time.Now().Before(price.ExpiresAt) {
@@ -523,6 +644,71 @@ func (cache *PricingCache) set(key string, price CachedPrice) {
 	cache.mu.Lock()
 	defer cache.mu.Unlock()
 	cache.data[key] = price
+
+	if err := cache.save(); err != nil {
+		log.Printf("Warning: Could not write the pricing cache: %v", err)
+	}
+}
+
+// load reads the unexpired prices of the cache file, if there is one
+func (cache *PricingCache) load() error {
+	if cache.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(cache.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var stored map[string]CachedPrice
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("%s: %w", cache.path, err)
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	now := time.Now()
+	for key, price := range stored {
+		if now.Before(price.ExpiresAt) {
+			cache.data[key] = price
+		}
+	}
+	return nil
+}
+
+// save writes the prices to the cache file, renaming it into place once complete.
+// The caller holds the lock.
+func (cache *PricingCache) save() error {
+	if cache.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(cache.data)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(cache.path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(cache.path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), cache.path)
 }
 
 // GetFreeTierInfo returns current free tier information
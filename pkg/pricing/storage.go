@@ -0,0 +1,238 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// ebsGBPrices are us-east-1 monthly EBS prices per GB, by volume type
+var ebsGBPrices = map[string]float64{
+	"gp2":      0.10,
+	"gp3":      0.08,
+	"io1":      0.125,
+	"io2":      0.125,
+	"st1":      0.045,
+	"sc1":      0.015,
+	"standard": 0.05,
+}
+
+// rdsGBPrices are us-east-1 monthly Single-AZ RDS storage prices per GB, by storage type
+var rdsGBPrices = map[string]float64{
+	"gp2":      0.115,
+	"gp3":      0.115,
+	"io1":      0.125,
+	"io2":      0.125,
+	"standard": 0.10,
+}
+
+// s3GBPrices are us-east-1 monthly S3 prices per GB for the first 50 TB, by the
+// storage type of the BucketSizeBytes metric. Overheads are billed at the price of
+// the class they are stored in.
+var s3GBPrices = map[string]float64{
+	"StandardStorage":                0.023,
+	"IntelligentTieringFAStorage":    0.023,
+	"IntelligentTieringIAStorage":    0.0125,
+	"IntelligentTieringAIAStorage":   0.004,
+	"IntelligentTieringAAStorage":    0.0036,
+	"IntelligentTieringDAAStorage":   0.00099,
+	"StandardIAStorage":              0.0125,
+	"StandardIASizeOverhead":         0.0125,
+	"OneZoneIAStorage":               0.01,
+	"OneZoneIASizeOverhead":          0.01,
+	"ReducedRedundancyStorage":       0.024,
+	"GlacierInstantRetrievalStorage": 0.004,
+	"GlacierIRSizeOverhead":          0.004,
+	"GlacierStorage":                 0.0036,
+	"GlacierStagingStorage":          0.0036,
+	"GlacierObjectOverhead":          0.0036,
+	"GlacierS3ObjectOverhead":        0.023,
+	"DeepArchiveStorage":             0.00099,
+	"DeepArchiveStagingStorage":      0.00099,
+	"DeepArchiveObjectOverhead":      0.00099,
+	"DeepArchiveS3ObjectOverhead":    0.023,
+}
+
+// us-east-1 monthly prices of provisioned performance
+const (
+	ebsIOPSPrice          = 0.065 // io1 and io2, per provisioned IOPS
+	ebsGP3IOPSPrice       = 0.005 // gp3, per IOPS above the baseline
+	ebsGP3ThroughputPrice = 0.04  // gp3, per MB/s above the baseline
+	rdsIOPSPrice          = 0.10  // io1 and io2, per provisioned IOPS
+	rdsGP3IOPSPrice       = 0.02  // gp3, per IOPS above the baseline
+	rdsGP3ThroughputPrice = 0.08  // gp3, per MB/s above the baseline
+)
+
+// storageCost is the monthly cost of provisioned storage, split into what is charged
+type storageCost struct {
+	storage     float64
+	iops        float64
+	throughput  float64
+	description string // what was priced, e.g. "2 EBS volumes, 150GB"
+}
+
+// total returns the monthly cost of the storage and its provisioned performance
+func (c storageCost) total() float64 {
+	return c.storage + c.iops + c.throughput
+}
+
+// ebsStorageCost returns the monthly cost of the EBS volumes recorded on an instance
+func ebsStorageCost(resource models.Resource) (storageCost, bool) {
+	volumes := models.ExtraList(resource.Extra, "volumes")
+	if len(volumes) == 0 {
+		return storageCost{}, false
+	}
+
+	var cost storageCost
+	var totalGB float64
+	for _, volume := range volumes {
+		totalGB += cost.addEBSVolume(volume)
+	}
+
+	noun := "EBS volumes"
+	if len(volumes) == 1 {
+		noun = "EBS volume"
+	}
+	cost.description = fmt.Sprintf("%d %s, %.0fGB", len(volumes), noun, totalGB)
+	return cost, true
+}
+
+// addEBSVolume adds the monthly cost of an EBS volume, from its volumeType, sizeGB,
+// iops and throughput fields, and returns its size in GB
+func (c *storageCost) addEBSVolume(volume map[string]interface{}) float64 {
+	volumeType, _ := volume["volumeType"].(string)
+	size, _ := models.ExtraNumber(volume, "sizeGB")
+	iops, _ := models.ExtraNumber(volume, "iops")
+	throughput, _ := models.ExtraNumber(volume, "throughput")
+
+	price, ok := ebsGBPrices[volumeType]
+	if !ok {
+		price = ebsGBPrices["gp2"]
+	}
+	c.storage += size * price
+
+	switch volumeType {
+	case "io1", "io2":
+		c.iops += iops * ebsIOPSPrice
+	case "gp3":
+		// gp3 includes 3,000 IOPS and 125 MB/s
+		c.iops += math.Max(iops-3000, 0) * ebsGP3IOPSPrice
+		c.throughput += math.Max(throughput-125, 0) * ebsGP3ThroughputPrice
+	}
+	return size
+}
+
+// rdsStorageCost returns the monthly cost of an RDS instance's allocated storage.
+// Aurora storage is billed by use for the whole cluster, so it is not included.
+func rdsStorageCost(resource models.Resource) (storageCost, bool) {
+	size, ok := models.ExtraNumber(resource.Extra, "allocatedStorage")
+	storageType, _ := resource.Extra["storageType"].(string)
+	if !ok || size == 0 || strings.HasPrefix(storageType, "aurora") {
+		return storageCost{}, false
+	}
+	iops, _ := models.ExtraNumber(resource.Extra, "iops")
+	throughput, _ := models.ExtraNumber(resource.Extra, "storageThroughput")
+
+	price, ok := rdsGBPrices[storageType]
+	if !ok {
+		price = rdsGBPrices["gp2"]
+	}
+	cost := storageCost{
+		storage:     size * price,
+		description: fmt.Sprintf("%.0fGB %s", size, storageType),
+	}
+
+	switch storageType {
+	case "io1", "io2":
+		cost.iops = iops * rdsIOPSPrice
+	case "gp3":
+		// gp3 includes 3,000 IOPS and 125 MB/s, or 12,000 IOPS and 500 MB/s from 400GB
+		baselineIOPS, baselineThroughput := 3000.0, 125.0
+		if size >= 400 {
+			baselineIOPS, baselineThroughput = 12000, 500
+		}
+		cost.iops = math.Max(iops-baselineIOPS, 0) * rdsGP3IOPSPrice
+		cost.throughput = math.Max(throughput-baselineThroughput, 0) * rdsGP3ThroughputPrice
+	}
+
+	// Multi-AZ instances keep a second copy of their storage on the standby
+	if rdsMultiAZ(resource) {
+		cost.storage, cost.iops, cost.throughput = cost.storage*2, cost.iops*2, cost.throughput*2
+		cost.description += " Multi-AZ"
+	}
+
+	return cost, true
+}
+
+// estimateS3StorageCost estimates a bucket's storage cost from its size by storage
+// class, as recorded from the S3 storage metrics
+func estimateS3StorageCost(resource models.Resource) (*models.CostEstimate, bool) {
+	sizeBytes, ok := models.ExtraNumber(resource.Extra, "sizeBytes")
+	if !ok {
+		return nil, false
+	}
+	objects, _ := models.ExtraNumber(resource.Extra, "objectCount")
+	storageClasses := models.ExtraNumbers(resource.Extra, "storageClasses")
+
+	estimate := &models.CostEstimate{
+		Formula:            "Monthly Cost = Σ Size by storage class (GB) × price per GB",
+		FormulaExplanation: "S3 storage is billed per GB-month at the price of each storage class. Requests and data transfer are charged separately.",
+		Breakdown:          make(map[string]float64),
+		Accuracy:           "Medium",
+		Assumptions: []string{
+			fmt.Sprintf("%.2f GB in %.0f objects, from the S3 storage metrics", sizeBytes/bytesPerGB, objects),
+			"First 50 TB price tier",
+			"Excludes requests, retrievals and data transfer",
+		},
+		Examples: []string{
+			"100 GB Standard: 100 × $0.023 = $2.30/month",
+			"1 TB Standard-IA: 1024 × $0.0125 = $12.80/month",
+			"1 TB Glacier Deep Archive: 1024 × $0.00099 = $1.01/month",
+		},
+	}
+
+	classes := make([]string, 0, len(storageClasses))
+	for class := range storageClasses {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	for _, class := range classes {
+		price, ok := s3GBPrices[class]
+		if !ok {
+			price = s3GBPrices["StandardStorage"]
+			estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("%s priced as Standard storage", class))
+		}
+		cost := storageClasses[class] / bytesPerGB * price
+		estimate.Breakdown[class] = cost
+		estimate.Amount += cost
+	}
+
+	estimate.Explanation = fmt.Sprintf("S3 bucket %s: $%.2f/month for %.2f GB", resource.Name, estimate.Amount, sizeBytes/bytesPerGB)
+
+	return estimate, true
+}
+
+// addStorageCost adds the cost of storage to an estimate, in its breakdown as storage,
+// iops and throughput
+func addStorageCost(estimate *models.CostEstimate, cost storageCost) {
+	if estimate.Breakdown == nil {
+		estimate.Breakdown = make(map[string]float64)
+	}
+	estimate.Breakdown["storage"] = cost.storage
+	if cost.iops > 0 {
+		estimate.Breakdown["iops"] = cost.iops
+	}
+	if cost.throughput > 0 {
+		estimate.Breakdown["throughput"] = cost.throughput
+	}
+
+	estimate.Amount += cost.total()
+	estimate.Formula += " + Storage"
+	estimate.Explanation += fmt.Sprintf(" + $%.2f storage (%s)", cost.total(), cost.description)
+	estimate.Assumptions = append(estimate.Assumptions,
+		fmt.Sprintf("Storage: %s at us-east-1 per-GB and provisioned performance prices", cost.description))
+}
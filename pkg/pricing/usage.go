@@ -0,0 +1,288 @@
+package pricing
+
+import (
+	"fmt"
+
+	"github.com/xiaochen/awsinv/pkg/models"
+)
+
+// us-east-1 prices of the usage measured with --usage-metrics
+const (
+	lambdaRequestPrice        = 0.20         // per million requests
+	lambdaGBSecondPrice       = 0.0000166667 // x86_64, per GB-second
+	lambdaARMGBSecondPrice    = 0.0000133334 // arm64, per GB-second
+	lambdaProvisionedPrice    = 0.0000041667 // x86_64 provisioned concurrency, per GB-second
+	lambdaARMProvisionedPrice = 0.0000033334 // arm64 provisioned concurrency, per GB-second
+	dynamoDBReadPrice         = 0.25         // on-demand, per million read request units
+	dynamoDBWritePrice        = 1.25         // on-demand, per million write request units
+	dynamoDBRCUHourPrice      = 0.00013      // provisioned, per read capacity unit-hour
+	dynamoDBWCUHourPrice      = 0.00065      // provisioned, per write capacity unit-hour
+	dynamoDBStorageGBPrice    = 0.25         // per GB-month
+	natGatewayHourPrice       = 0.045        // per NAT gateway-hour
+	natGatewayDataGBPrice     = 0.045        // per GB processed
+)
+
+// usageMonthScale converts usage measured over the 30 days of the metrics window to
+// the 730 hours of a billing month
+const usageMonthScale = 730.0 / 720.0
+
+// bytesPerGB converts the byte counts of CloudWatch metrics to the GB of prices
+const bytesPerGB = 1024 * 1024 * 1024
+
+// estimateLambdaUsageCost estimates a function's cost from its measured invocations
+// and duration, when --usage-metrics recorded them
+func estimateLambdaUsageCost(resource models.Resource) (*models.CostEstimate, bool) {
+	invocations, ok := models.ExtraNumber(resource.Extra, "invocations30d")
+	if !ok {
+		return nil, false
+	}
+	durationMs, _ := models.ExtraNumber(resource.Extra, "durationMs30d")
+	memoryGB := lambdaMemoryGB(resource)
+
+	gbSecondPrice, architecture := lambdaGBSecondPrice, "x86_64"
+	if hasExtraString(resource.Extra, "architectures", "arm64") {
+		gbSecondPrice, architecture = lambdaARMGBSecondPrice, "arm64"
+	}
+
+	monthlyRequests := invocations * usageMonthScale
+	gbSeconds := durationMs / 1000 * memoryGB * usageMonthScale
+	requestCost := monthlyRequests / 1e6 * lambdaRequestPrice
+	computeCost := gbSeconds * gbSecondPrice
+
+	estimate := &models.CostEstimate{
+		Amount:             requestCost + computeCost,
+		Formula:            "Monthly Cost = Requests / 1M × $0.20 + GB-seconds × price per GB-second",
+		FormulaExplanation: "Lambda charges per request and per GB-second of execution, the duration multiplied by the configured memory.",
+		Breakdown: map[string]float64{
+			"requests": requestCost,
+			"compute":  computeCost,
+		},
+		Accuracy: "High",
+		Assumptions: []string{
+			fmt.Sprintf("Measured usage over the last 30 days: %.0f invocations, %.0f GB-seconds per month", monthlyRequests, gbSeconds),
+			fmt.Sprintf("%s pricing at $%.10g per GB-second", architecture, gbSecondPrice),
+			"Excludes the free tier and data transfer",
+		},
+		Examples: []string{
+			"1M requests of 100ms at 128MB: $0.20 + 12,500 GB-s × $0.0000166667 = $0.41/month",
+			"10M requests of 200ms at 512MB: $2.00 + 1M GB-s × $0.0000166667 = $18.67/month",
+		},
+	}
+	addLambdaProvisionedCost(resource, estimate)
+	estimate.Explanation = fmt.Sprintf("Lambda function %s: $%.2f/month (%.0f requests, measured)", resource.Name, estimate.Amount, monthlyRequests)
+
+	return estimate, true
+}
+
+// lambdaMemoryGB returns the memory configured for a function, 128 MB when unknown
+func lambdaMemoryGB(resource models.Resource) float64 {
+	memoryMB, ok := models.ExtraNumber(resource.Extra, "memorySize")
+	if !ok || memoryMB <= 0 {
+		memoryMB = 128
+	}
+	return memoryMB / 1024
+}
+
+// addLambdaProvisionedCost adds the charge for a function's provisioned concurrency,
+// which is billed for every hour it is configured whether or not it serves requests
+func addLambdaProvisionedCost(resource models.Resource, estimate *models.CostEstimate) {
+	concurrency, ok := models.ExtraNumber(resource.Extra, "provisionedConcurrency")
+	if !ok || concurrency <= 0 {
+		return
+	}
+
+	price := lambdaProvisionedPrice
+	if hasExtraString(resource.Extra, "architectures", "arm64") {
+		price = lambdaARMProvisionedPrice
+	}
+	gbSeconds := concurrency * lambdaMemoryGB(resource) * 730 * 3600
+	cost := gbSeconds * price
+
+	estimate.Amount += cost
+	estimate.Breakdown["provisionedConcurrency"] = cost
+	estimate.Formula += " + Provisioned Concurrency × Memory GB × 730 hours × 3600 × price per GB-second"
+	estimate.Assumptions = append(estimate.Assumptions,
+		fmt.Sprintf("%.0f provisioned concurrent executions at $%.10g per GB-second: %.0f GB-seconds per month", concurrency, price, gbSeconds),
+		"Invocations served by provisioned concurrency are priced at the on-demand duration rate, which overestimates them",
+	)
+}
+
+// estimateDynamoDBUsageCost estimates a table's cost from its measured consumed
+// capacity and its size, when --usage-metrics recorded them. Provisioned tables are
+// billed for their capacity whatever they consume.
+func estimateDynamoDBUsageCost(resource models.Resource) (*models.CostEstimate, bool) {
+	readUnits, ok := models.ExtraNumber(resource.Extra, "readUnits30d")
+	if !ok {
+		return nil, false
+	}
+	writeUnits, _ := models.ExtraNumber(resource.Extra, "writeUnits30d")
+	sizeBytes, _ := models.ExtraNumber(resource.Extra, "tableSizeBytes")
+	rcu, wcu := dynamoDBCapacity(resource)
+	prices := dynamoDBPricesOf(resource)
+
+	storageGB := sizeBytes / bytesPerGB
+	storageCost := storageGB * prices.storageGB
+	monthlyReads := readUnits * usageMonthScale
+	monthlyWrites := writeUnits * usageMonthScale
+
+	estimate := &models.CostEstimate{
+		Breakdown: map[string]float64{"storage": storageCost},
+		Accuracy:  "High",
+		Assumptions: []string{
+			fmt.Sprintf("Measured usage over the last 30 days: %.0f read and %.0f write units per month", monthlyReads, monthlyWrites),
+			fmt.Sprintf("%.2f GB of table storage at $%.2f per GB", storageGB, prices.storageGB),
+			"Excludes backups, streams, global table replication and data transfer",
+		},
+	}
+
+	if dynamoDBProvisioned(resource) {
+		readCost := rcu * prices.rcuHour * 730
+		writeCost := wcu * prices.wcuHour * 730
+		estimate.Breakdown["reads"] = readCost
+		estimate.Breakdown["writes"] = writeCost
+		estimate.Amount = readCost + writeCost + storageCost
+		estimate.Formula = fmt.Sprintf("Monthly Cost = (RCU × $%g + WCU × $%g) × 730 hours + Storage", prices.rcuHour, prices.wcuHour)
+		estimate.FormulaExplanation = "Provisioned tables are billed for their read and write capacity per hour, whatever they consume."
+		estimate.Assumptions = append(estimate.Assumptions, fmt.Sprintf("Provisioned capacity: %.0f RCU, %.0f WCU", rcu, wcu))
+		estimate.Examples = []string{
+			"5 RCU and 5 WCU: (5 × $0.00013 + 5 × $0.00065) × 730 = $2.85/month",
+		}
+	} else {
+		readCost := monthlyReads / 1e6 * prices.read
+		writeCost := monthlyWrites / 1e6 * prices.write
+		estimate.Breakdown["reads"] = readCost
+		estimate.Breakdown["writes"] = writeCost
+		estimate.Amount = readCost + writeCost + storageCost
+		estimate.Formula = fmt.Sprintf("Monthly Cost = Reads / 1M × $%g + Writes / 1M × $%g + Storage", prices.read, prices.write)
+		estimate.FormulaExplanation = "On-demand tables are billed per request unit consumed, plus storage."
+		estimate.Assumptions = append(estimate.Assumptions, "On-demand billing mode")
+		estimate.Examples = []string{
+			"10M reads and 1M writes: $2.50 + $1.25 = $3.75/month plus storage",
+		}
+	}
+
+	estimate.Explanation = fmt.Sprintf("DynamoDB table %s: $%.2f/month (measured)", resource.Name, estimate.Amount)
+
+	return estimate, true
+}
+
+// dynamoDBPrices are the us-east-1 prices of a DynamoDB table class
+type dynamoDBPrices struct {
+	read      float64 // on-demand, per million read request units
+	write     float64 // on-demand, per million write request units
+	rcuHour   float64 // provisioned, per read capacity unit-hour
+	wcuHour   float64 // provisioned, per write capacity unit-hour
+	storageGB float64 // per GB-month
+}
+
+// dynamoDBTableClassPrices are the prices of each table class. Standard-Infrequent
+// Access trades cheaper storage for dearer reads and writes.
+var dynamoDBTableClassPrices = map[string]dynamoDBPrices{
+	"STANDARD": {
+		read:      dynamoDBReadPrice,
+		write:     dynamoDBWritePrice,
+		rcuHour:   dynamoDBRCUHourPrice,
+		wcuHour:   dynamoDBWCUHourPrice,
+		storageGB: dynamoDBStorageGBPrice,
+	},
+	"STANDARD_INFREQUENT_ACCESS": {
+		read:      0.3125,
+		write:     1.5625,
+		rcuHour:   0.00016,
+		wcuHour:   0.00081,
+		storageGB: 0.10,
+	},
+}
+
+// dynamoDBPricesOf returns the prices of a table's class, Standard when unknown
+func dynamoDBPricesOf(resource models.Resource) dynamoDBPrices {
+	class, _ := resource.Extra["tableClass"].(string)
+	if prices, ok := dynamoDBTableClassPrices[class]; ok {
+		return prices
+	}
+	return dynamoDBTableClassPrices["STANDARD"]
+}
+
+// dynamoDBCapacity returns the provisioned read and write capacity units of a table,
+// including those of its global secondary indexes
+func dynamoDBCapacity(resource models.Resource) (float64, float64) {
+	rcu, _ := models.ExtraNumber(resource.Extra, "readCapacityUnits")
+	wcu, _ := models.ExtraNumber(resource.Extra, "writeCapacityUnits")
+	indexRCU, _ := models.ExtraNumber(resource.Extra, "indexReadCapacityUnits")
+	indexWCU, _ := models.ExtraNumber(resource.Extra, "indexWriteCapacityUnits")
+	return rcu + indexRCU, wcu + indexWCU
+}
+
+// dynamoDBProvisioned reports whether a table is billed for provisioned capacity
+// rather than per request. Tables collected without their billing mode are taken to be
+// provisioned when they report capacity, as on-demand tables report none.
+func dynamoDBProvisioned(resource models.Resource) bool {
+	if mode, ok := resource.Extra["billingMode"].(string); ok {
+		return mode != "PAY_PER_REQUEST"
+	}
+	rcu, wcu := dynamoDBCapacity(resource)
+	return rcu > 0 || wcu > 0
+}
+
+// estimateNATGatewayCost estimates a NAT gateway's hourly charge, plus the data it
+// processed when --usage-metrics recorded it
+func estimateNATGatewayCost(resource models.Resource) *models.CostEstimate {
+	if resource.State == "deleted" || resource.State == "deleting" || resource.State == "failed" {
+		return &models.CostEstimate{Amount: 0, Explanation: fmt.Sprintf("NAT gateway %s: $0.00/month (%s)", resource.Name, resource.State)}
+	}
+
+	hourlyCost := natGatewayHourPrice * 730
+	estimate := &models.CostEstimate{
+		Amount:             hourlyCost,
+		Formula:            "Monthly Cost = $0.045/hour × 730 hours + Data processed (GB) × $0.045",
+		FormulaExplanation: "NAT gateways are billed per hour while provisioned and per GB of data they process.",
+		Breakdown:          map[string]float64{"gatewayHours": hourlyCost},
+		Accuracy:           "Medium",
+		Assumptions: []string{
+			"Based on us-east-1 NAT gateway pricing",
+		},
+		Examples: []string{
+			"Idle NAT gateway: $0.045/hour × 730 hours = $32.85/month",
+			"100 GB processed: $32.85 + 100 × $0.045 = $37.35/month",
+		},
+	}
+
+	processedBytes, ok := models.ExtraNumber(resource.Extra, "natBytes30d")
+	if !ok {
+		estimate.Assumptions = append(estimate.Assumptions, "Excludes data processing charges (use --usage-metrics to measure them)")
+		estimate.Explanation = fmt.Sprintf("NAT gateway %s: $%.2f/month", resource.Name, estimate.Amount)
+		return estimate
+	}
+
+	processedGB := processedBytes / bytesPerGB * usageMonthScale
+	processingCost := processedGB * natGatewayDataGBPrice
+	estimate.Amount += processingCost
+	estimate.Breakdown["dataProcessed"] = processingCost
+	estimate.Accuracy = "High"
+	estimate.Assumptions = append(estimate.Assumptions,
+		fmt.Sprintf("Measured usage over the last 30 days: %.2f GB processed per month", processedGB),
+		"Excludes data transfer out to the internet")
+	estimate.Explanation = fmt.Sprintf("NAT gateway %s: $%.2f/month (%.2f GB processed, measured)", resource.Name, estimate.Amount, processedGB)
+
+	return estimate
+}
+
+// hasExtraString reports whether a list of strings in a resource's extra fields, as
+// stored by a collector or loaded from JSON, contains value
+func hasExtraString(extra map[string]interface{}, key, value string) bool {
+	switch v := extra[key].(type) {
+	case []string:
+		for _, item := range v {
+			if item == value {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if item == value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,367 @@
+// Package server serves the inventory over HTTP: the HTML report, a JSON API and
+// Prometheus metrics, refreshed on demand or on a schedule
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/diff"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/output"
+)
+
+// ScanFunc collects a fresh inventory
+type ScanFunc func(ctx context.Context) (*models.ResourceCollection, error)
+
+// Options configures a Server
+type Options struct {
+	// Interval between scheduled scans; zero only scans on startup and on demand
+	Interval time.Duration
+
+	// Filters apply to everything served, before any request filters
+	Filters []output.Filter
+
+	// HTML configures the report served at /
+	HTML output.HTMLOptions
+}
+
+// Server holds the latest inventory and serves it over HTTP
+type Server struct {
+	scan    ScanFunc
+	options Options
+	trigger chan struct{}
+
+	mu           sync.RWMutex
+	collection   *models.ResourceCollection
+	snapshot     *diff.Snapshot
+	lastScan     time.Time
+	lastDuration time.Duration
+	lastError    error
+	scanning     bool
+	scans        int
+	failures     int
+}
+
+// New creates a server that collects inventories with scan
+func New(scan ScanFunc, options Options) *Server {
+	return &Server{
+		scan:    scan,
+		options: options,
+		trigger: make(chan struct{}, 1),
+	}
+}
+
+// Run scans on startup, on schedule and on demand until ctx is cancelled
+func (s *Server) Run(ctx context.Context) {
+	var tick <-chan time.Time
+	if s.options.Interval > 0 {
+		ticker := time.NewTicker(s.options.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		s.runScan(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case <-s.trigger:
+		}
+	}
+}
+
+// Trigger requests a scan; requests made while a scan is pending are merged
+func (s *Server) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// runScan collects a new inventory and replaces the served one if it succeeds
+func (s *Server) runScan(ctx context.Context) {
+	s.mu.Lock()
+	s.scanning = true
+	s.mu.Unlock()
+
+	start := time.Now()
+	collection, err := s.scan(ctx)
+
+	var snapshot *diff.Snapshot
+	if err == nil {
+		snapshot, err = summarize(collection, s.options.Filters)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scanning = false
+	s.scans++
+	s.lastDuration = time.Since(start)
+	s.lastError = err
+	if err != nil {
+		s.failures++
+		return
+	}
+	s.collection = collection
+	s.snapshot = snapshot
+	s.lastScan = time.Now()
+}
+
+// summarize turns a collection into a snapshot, which carries the cost estimates
+// used for metrics
+func summarize(collection *models.ResourceCollection, filters []output.Filter) (*diff.Snapshot, error) {
+	var buf bytes.Buffer
+	if err := output.NewJSONFormatterWithWriter(&buf).Format(copyCollection(collection), filters, "service", true); err != nil {
+		return nil, err
+	}
+	return diff.ReadSnapshot(&buf)
+}
+
+// copyCollection copies the resource slice, since formatters sort it in place and
+// requests are served concurrently
+func copyCollection(collection *models.ResourceCollection) *models.ResourceCollection {
+	copied := *collection
+	copied.Resources = append([]models.Resource(nil), collection.Resources...)
+	return &copied
+}
+
+// current returns a private copy of the served collection, or nil before the first scan
+func (s *Server) current() *models.ResourceCollection {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.collection == nil {
+		return nil
+	}
+	return copyCollection(s.collection)
+}
+
+// Handler returns the HTTP handler:
+//
+//	GET  /               HTML report
+//	GET  /api/resources  JSON output; filter, exclude, sort and query parameters
+//	POST /api/scan       start a scan
+//	GET  /metrics        Prometheus metrics
+//	GET  /healthz        liveness
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleReport)
+	mux.HandleFunc("/api/resources", s.handleResources)
+	mux.HandleFunc("/api/scan", s.handleScan)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	return mux
+}
+
+// handleReport serves the HTML report, filtered by the same parameters as the API
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	collection := s.current()
+	if collection == nil {
+		http.Error(w, "the first scan is still running", http.StatusServiceUnavailable)
+		return
+	}
+
+	filters, sortField, err := s.requestFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	formatter, err := output.NewHTMLFormatterWithOptions(&buf, s.options.HTML)
+	if err == nil {
+		err = formatter.Format(collection, filters, sortField, true)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// handleResources serves the JSON output
+func (s *Server) handleResources(w http.ResponseWriter, r *http.Request) {
+	collection := s.current()
+	if collection == nil {
+		http.Error(w, "the first scan is still running", http.StatusServiceUnavailable)
+		return
+	}
+
+	filters, sortField, err := s.requestFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	formatter := output.NewJSONFormatterWithWriter(&buf)
+	if query := r.URL.Query().Get("query"); query != "" {
+		formatter, err = output.NewJSONFormatterWithQuery(&buf, query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := formatter.Format(collection, filters, sortField, true); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(buf.Bytes())
+}
+
+// handleScan starts a scan
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "use POST to start a scan", http.StatusMethodNotAllowed)
+		return
+	}
+
+	s.Trigger()
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintln(w, "scan requested")
+}
+
+// requestFilters reads the filter, exclude and sort query parameters, adding them to
+// the server's filters
+func (s *Server) requestFilters(r *http.Request) ([]output.Filter, string, error) {
+	query := r.URL.Query()
+
+	filters, err := output.ParseFilters(query["filter"])
+	if err != nil {
+		return nil, "", err
+	}
+	excludes, err := output.ParseExcludes(query["exclude"])
+	if err != nil {
+		return nil, "", err
+	}
+
+	sortField := query.Get("sort")
+	if sortField == "" {
+		sortField = "service"
+	}
+
+	combined := append([]output.Filter(nil), s.options.Filters...)
+	combined = append(combined, filters...)
+	return append(combined, excludes...), sortField, nil
+}
+
+// handleMetrics serves metrics in the Prometheus text exposition format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var b strings.Builder
+
+	writeMetric(&b, "awsinv_scans_total", "counter", "Scans run since the server started.", nil, float64(s.scans))
+	writeMetric(&b, "awsinv_scan_failures_total", "counter", "Scans that failed since the server started.", nil, float64(s.failures))
+	writeMetric(&b, "awsinv_scan_in_progress", "gauge", "Whether a scan is running.", nil, boolValue(s.scanning))
+	writeMetric(&b, "awsinv_scan_duration_seconds", "gauge", "Duration of the last scan.", nil, s.lastDuration.Seconds())
+	if !s.lastScan.IsZero() {
+		writeMetric(&b, "awsinv_last_scan_timestamp_seconds", "gauge", "Time of the last successful scan.", nil, float64(s.lastScan.Unix()))
+	}
+
+	if s.snapshot != nil {
+		type key struct{ service, region string }
+		counts := make(map[key]float64)
+		costs := make(map[key]float64)
+		for _, resource := range s.snapshot.Resources {
+			k := key{resource.Service, resource.Region}
+			counts[k]++
+			if resource.CostEstimate != nil {
+				costs[k] += resource.CostEstimate.Amount
+			}
+		}
+
+		keys := make([]key, 0, len(counts))
+		for k := range counts {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if keys[i].service != keys[j].service {
+				return keys[i].service < keys[j].service
+			}
+			return keys[i].region < keys[j].region
+		})
+
+		writeHeader(&b, "awsinv_resources", "gauge", "Resources found by the last scan.")
+		for _, k := range keys {
+			writeSample(&b, "awsinv_resources", []string{"service", k.service, "region", k.region}, counts[k])
+		}
+		writeHeader(&b, "awsinv_estimated_monthly_cost_dollars", "gauge", "Estimated monthly cost of the resources found by the last scan.")
+		for _, k := range keys {
+			writeSample(&b, "awsinv_estimated_monthly_cost_dollars", []string{"service", k.service, "region", k.region}, costs[k])
+		}
+	}
+
+	if s.collection != nil {
+		writeMetric(&b, "awsinv_collection_errors", "gauge", "Collector errors in the last scan.", nil, float64(len(s.collection.Errors)))
+		writeMetric(&b, "awsinv_collection_warnings", "gauge", "Collector warnings in the last scan.", nil, float64(len(s.collection.Warnings)))
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprint(w, b.String())
+}
+
+// writeMetric writes a metric with a single sample
+func writeMetric(b *strings.Builder, name, metricType, help string, labels []string, value float64) {
+	writeHeader(b, name, metricType, help)
+	writeSample(b, name, labels, value)
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric
+func writeHeader(b *strings.Builder, name, metricType, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, metricType)
+}
+
+// writeSample writes one sample; labels alternate names and values
+func writeSample(b *strings.Builder, name string, labels []string, value float64) {
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteString("{")
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(b, "%s=\"%s\"", labels[i], escapeLabel(labels[i+1]))
+		}
+		b.WriteString("}")
+	}
+	fmt.Fprintf(b, " %g\n", value)
+}
+
+// escapeLabel escapes a label value for the text exposition format
+func escapeLabel(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
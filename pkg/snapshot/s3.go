@@ -0,0 +1,117 @@
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awspkg "github.com/xiaochen/awsinv/pkg/aws"
+)
+
+// S3Store keeps snapshots under a prefix of an S3 bucket
+type S3Store struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store creates a store for s3://bucket/prefix, using a client in the bucket's region
+func NewS3Store(ctx context.Context, clientManager *awspkg.ClientManager, bucket, prefix string) (*S3Store, error) {
+	// GetBucketLocation can be called from any region
+	client := s3.NewFromConfig(clientManager.GetConfig("us-east-1"))
+	location, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find region of bucket %s: %w", bucket, err)
+	}
+
+	// An empty location constraint means us-east-1, and EU is the legacy name of eu-west-1
+	region := string(location.LocationConstraint)
+	switch region {
+	case "":
+		region = "us-east-1"
+	case "EU":
+		region = "eu-west-1"
+	}
+
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	return &S3Store{
+		client: s3.NewFromConfig(clientManager.GetConfig(region)),
+		bucket: bucket,
+		prefix: prefix,
+	}, nil
+}
+
+// Put uploads a snapshot object
+func (s *S3Store) Put(ctx context.Context, name string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.prefix + name),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/gzip"),
+	})
+	return err
+}
+
+// List returns the names of the objects directly under the prefix
+func (s *S3Store) List(ctx context.Context) ([]string, error) {
+	var names []string
+	var continuationToken *string
+
+	for {
+		result, err := s.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(s.bucket),
+			Prefix:            aws.String(s.prefix),
+			Delimiter:         aws.String("/"),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range result.Contents {
+			names = append(names, path.Base(aws.ToString(object.Key)))
+		}
+
+		continuationToken = result.NextContinuationToken
+		if continuationToken == nil {
+			break
+		}
+	}
+
+	return names, nil
+}
+
+// Open downloads a snapshot object
+func (s *S3Store) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + name),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Body, nil
+}
+
+// NewStore creates the store for a location: s3://bucket/prefix or a local directory
+func NewStore(ctx context.Context, clientManager *awspkg.ClientManager, location string) (Store, error) {
+	if rest, ok := strings.CutPrefix(location, "s3://"); ok {
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("invalid snapshot store %q (expected s3://bucket/prefix)", location)
+		}
+		return NewS3Store(ctx, clientManager, bucket, prefix)
+	}
+	return NewDirStore(location)
+}
@@ -0,0 +1,245 @@
+// Package snapshot stores the inventory of each run so later runs can be compared
+// with it
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xiaochen/awsinv/pkg/diff"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/output"
+)
+
+// Snapshot names embed the time of the run, e.g. awsinv-20240115T103000Z.json.gz
+const (
+	namePrefix = "awsinv-"
+	nameSuffix = ".json.gz"
+	timeLayout = "20060102T150405Z"
+)
+
+// ErrNotFound is returned by Find when no stored snapshot matches
+var ErrNotFound = errors.New("no matching snapshot")
+
+// Entry is a stored snapshot
+type Entry struct {
+	Name    string
+	TakenAt time.Time
+}
+
+// Store keeps snapshots in a local directory or under an S3 prefix
+type Store interface {
+	// Put stores a snapshot under name
+	Put(ctx context.Context, name string, data []byte) error
+
+	// List returns the names of all stored objects
+	List(ctx context.Context) ([]string, error)
+
+	// Open reads the snapshot stored under name
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+// entryName returns the name of the snapshot taken at takenAt
+func entryName(takenAt time.Time) string {
+	return namePrefix + takenAt.UTC().Format(timeLayout) + nameSuffix
+}
+
+// parseEntry returns the entry for a stored name, or false for other objects
+func parseEntry(name string) (Entry, bool) {
+	stamp, ok := strings.CutPrefix(name, namePrefix)
+	if !ok {
+		return Entry{}, false
+	}
+	stamp, ok = strings.CutSuffix(stamp, nameSuffix)
+	if !ok {
+		return Entry{}, false
+	}
+	takenAt, err := time.Parse(timeLayout, stamp)
+	if err != nil {
+		return Entry{}, false
+	}
+	return Entry{Name: name, TakenAt: takenAt}, true
+}
+
+// Save stores the full, unfiltered collection as a compressed JSON snapshot that the
+// diff command can also read
+func Save(ctx context.Context, store Store, collection *models.ResourceCollection, takenAt time.Time) (Entry, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+
+	// The comparison belongs to this run's report, not to the stored inventory
+	stored := *collection
+	stored.Comparison = nil
+	if err := output.NewJSONFormatterWithWriter(gz).Format(&stored, nil, "service", true); err != nil {
+		return Entry{}, err
+	}
+	if err := gz.Close(); err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{Name: entryName(takenAt), TakenAt: takenAt.UTC().Truncate(time.Second)}
+	if err := store.Put(ctx, entry.Name, buf.Bytes()); err != nil {
+		return Entry{}, fmt.Errorf("failed to store snapshot %s: %w", entry.Name, err)
+	}
+	return entry, nil
+}
+
+// Entries returns the stored snapshots, oldest first
+func Entries(ctx context.Context, store Store) ([]Entry, error) {
+	names, err := store.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+
+	var entries []Entry
+	for _, name := range names {
+		if entry, ok := parseEntry(name); ok {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].TakenAt.Before(entries[j].TakenAt)
+	})
+	return entries, nil
+}
+
+// Find returns the snapshot selected by spec: "latest", or the last snapshot taken on
+// or before a date (2024-01-15, meaning the end of that UTC day) or RFC 3339 time
+func Find(ctx context.Context, store Store, spec string) (Entry, error) {
+	entries, err := Entries(ctx, store)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	if spec == "latest" {
+		if len(entries) == 0 {
+			return Entry{}, fmt.Errorf("%w: no snapshots stored yet", ErrNotFound)
+		}
+		return entries[len(entries)-1], nil
+	}
+
+	var until time.Time
+	if day, err := time.Parse(time.DateOnly, spec); err == nil {
+		until = day.Add(24*time.Hour - time.Second)
+	} else if at, err := time.Parse(time.RFC3339, spec); err == nil {
+		until = at
+	} else {
+		return Entry{}, fmt.Errorf("invalid snapshot %q (expected latest, a date such as 2024-01-15 or an RFC 3339 time)", spec)
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].TakenAt.After(until) {
+			return entries[i], nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: none taken on or before %s", ErrNotFound, spec)
+}
+
+// Load reads a stored snapshot
+func Load(ctx context.Context, store Store, entry Entry) (*diff.Snapshot, error) {
+	reader, err := store.Open(ctx, entry.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open snapshot %s: %w", entry.Name, err)
+	}
+	defer reader.Close()
+
+	snapshot, err := diff.ReadSnapshot(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot %s: %w", entry.Name, err)
+	}
+	return snapshot, nil
+}
+
+// Compare lists the resources of the collection that are new since a snapshot, and
+// the resources of the snapshot that have disappeared
+func Compare(collection *models.ResourceCollection, previous *diff.Snapshot, entry Entry) *models.Comparison {
+	comparison := &models.Comparison{
+		Snapshot:    entry.Name,
+		ComparedTo:  entry.TakenAt,
+		New:         []models.Resource{},
+		Disappeared: []models.Resource{},
+	}
+
+	before := make(map[string]bool, len(previous.Resources))
+	for _, resource := range previous.Resources {
+		before[diff.ResourceKey(resource.Resource)] = true
+	}
+
+	current := make(map[string]bool, len(collection.Resources))
+	for _, resource := range collection.Resources {
+		key := diff.ResourceKey(resource)
+		current[key] = true
+		if !before[key] {
+			comparison.New = append(comparison.New, resource)
+		}
+	}
+
+	for _, resource := range previous.Resources {
+		if !current[diff.ResourceKey(resource.Resource)] {
+			comparison.Disappeared = append(comparison.Disappeared, resource.Resource)
+		}
+	}
+
+	return comparison
+}
+
+// DirStore keeps snapshots in a local directory
+type DirStore struct {
+	dir string
+}
+
+// NewDirStore creates a store in dir, creating the directory if needed
+func NewDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+// Put writes a snapshot file, renaming it into place once complete
+func (s *DirStore) Put(ctx context.Context, name string, data []byte) error {
+	tmp, err := os.CreateTemp(s.dir, "."+name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+// List returns the names of the files in the directory
+func (s *DirStore) List(ctx context.Context) ([]string, error) {
+	dirEntries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, dirEntry := range dirEntries {
+		if !dirEntry.IsDir() {
+			names = append(names, dirEntry.Name())
+		}
+	}
+	return names, nil
+}
+
+// Open opens a snapshot file
+func (s *DirStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.dir, name))
+}
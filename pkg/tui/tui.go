@@ -0,0 +1,347 @@
+// Package tui is an interactive terminal browser for an inventory: a navigable
+// resource table with live filtering, sort hotkeys and a detail pane
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/xiaochen/awsinv/pkg/models"
+	"github.com/xiaochen/awsinv/pkg/output"
+	"github.com/xiaochen/awsinv/pkg/pricing"
+)
+
+// Options configures the browser
+type Options struct {
+	// Filters always apply, in addition to the filter typed in the browser
+	Filters []output.Filter
+
+	// Sort is the initial sort specification, e.g. service,-cost
+	Sort string
+
+	// CostEngine estimates the costs of inventories loaded without them, with the
+	// built-in prices when nil
+	CostEngine *pricing.Engine
+}
+
+// sortHotkeys maps keys in the table to the field they sort by; pressing the key
+// of the current sort field reverses the order
+var sortHotkeys = map[rune]string{
+	's': "service",
+	'r': "region",
+	'n': "name",
+	't': "type",
+	'x': "state",
+	'c': "cost",
+	'a': "created",
+}
+
+// columns are the table columns, with the field they show
+var columns = []struct {
+	title string
+	field string
+}{
+	{"SERVICE", "service"},
+	{"REGION", "region"},
+	{"ID", "id"},
+	{"NAME", "name"},
+	{"TYPE", "type"},
+	{"STATE", "state"},
+	{"CREATED", "created"},
+	{"COST/MO", "cost"},
+}
+
+const helpText = "[yellow]/[-] filter  [yellow]enter[-] details  [yellow]s r n t x c a[-] sort (again to reverse)  [yellow]q[-] quit"
+
+// browser holds the widgets and the state of the view
+type browser struct {
+	collection *models.ResourceCollection
+	options    Options
+
+	app    *tview.Application
+	filter *tview.InputField
+	table  *tview.Table
+	detail *tview.TextView
+	status *tview.TextView
+
+	expression    string
+	sortField     string
+	descending    bool
+	resources     []models.Resource
+	costEstimates map[string]*models.CostEstimate
+}
+
+// Run shows the browser until the user quits
+func Run(collection *models.ResourceCollection, options Options) error {
+	// The browser sorts and filters the resources, so work on a copy
+	copied := *collection
+	copied.Resources = append([]models.Resource(nil), collection.Resources...)
+
+	b := &browser{
+		collection: &copied,
+		options:    options,
+		sortField:  "service",
+	}
+	if options.Sort != "" {
+		b.sortField = options.Sort
+		if field, ok := strings.CutPrefix(options.Sort, "-"); ok && !strings.Contains(field, ",") {
+			b.sortField = field
+			b.descending = true
+		}
+	}
+
+	b.build()
+	b.refresh()
+	return b.app.Run()
+}
+
+// build creates the widgets and wires up the keys
+func (b *browser) build() {
+	b.app = tview.NewApplication()
+
+	b.filter = tview.NewInputField().
+		SetLabel("Filter: ").
+		SetPlaceholder("e.g. service=ec2 AND cost>100")
+	b.filter.SetChangedFunc(func(text string) {
+		b.expression = text
+		b.refresh()
+	})
+	b.filter.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			b.filter.SetText("")
+		}
+		b.app.SetFocus(b.table)
+	})
+
+	b.table = tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+	b.table.SetBorder(true)
+	b.table.SetSelectionChangedFunc(func(row, column int) {
+		b.showDetail(row)
+	})
+	b.table.SetSelectedFunc(func(row, column int) {
+		b.app.SetFocus(b.detail)
+	})
+	b.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch event.Rune() {
+		case '/':
+			b.app.SetFocus(b.filter)
+			return nil
+		case 'q':
+			b.app.Stop()
+			return nil
+		}
+		if field, ok := sortHotkeys[event.Rune()]; ok {
+			if field == b.sortField {
+				b.descending = !b.descending
+			} else {
+				b.sortField = field
+				b.descending = field == "cost"
+			}
+			b.refresh()
+			return nil
+		}
+		return event
+	})
+
+	b.detail = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true)
+	b.detail.SetBorder(true)
+	b.detail.SetTitle(" Details ")
+	b.detail.SetDoneFunc(func(key tcell.Key) {
+		b.app.SetFocus(b.table)
+	})
+
+	b.status = tview.NewTextView().SetDynamicColors(true)
+
+	body := tview.NewFlex().
+		AddItem(b.table, 0, 3, true).
+		AddItem(b.detail, 0, 2, false)
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(b.filter, 1, 0, false).
+		AddItem(body, 0, 1, true).
+		AddItem(b.status, 1, 0, false)
+
+	b.app.SetRoot(layout, true).SetFocus(b.table)
+}
+
+// refresh re-applies the filter and sort and redraws the table, keeping the
+// selected resource when it is still shown
+func (b *browser) refresh() {
+	var selectedKey string
+	if row, _ := b.table.GetSelection(); row > 0 && row <= len(b.resources) {
+		selectedKey = resourceKey(b.resources[row-1])
+	}
+
+	filters := b.options.Filters
+	var filterErr error
+	if strings.TrimSpace(b.expression) != "" {
+		filter, err := output.ParseFilter(b.expression)
+		if err != nil {
+			// Keep showing the last valid result while the expression is being typed
+			filterErr = err
+		} else {
+			filters = append(append([]output.Filter(nil), b.options.Filters...), filter)
+		}
+	}
+
+	if filterErr == nil || b.resources == nil {
+		sortField := b.sortField
+		if b.descending {
+			sortField = "-" + sortField
+		}
+		b.resources, b.costEstimates = output.PrepareResources(b.collection, filters, sortField, output.Options{CostEngine: b.options.CostEngine})
+	}
+
+	b.table.Clear()
+	for column, c := range columns {
+		title := c.title
+		if c.field == b.sortField {
+			if b.descending {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		b.table.SetCell(0, column, tview.NewTableCell(title).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	selectedRow := 1
+	var total float64
+	for i, resource := range b.resources {
+		row := i + 1
+		cost := b.cost(resource)
+		total += cost
+		if resourceKey(resource) == selectedKey {
+			selectedRow = row
+		}
+
+		created := "-"
+		if resource.CreatedAt != nil {
+			created = resource.CreatedAt.Format("2006-01-02")
+		}
+		values := []string{
+			resource.Service,
+			resource.Region,
+			resource.ID,
+			resource.Name,
+			resource.Type,
+			resource.State,
+			created,
+			fmt.Sprintf("$%.2f", cost),
+		}
+		for column, value := range values {
+			cell := tview.NewTableCell(tview.Escape(value)).SetMaxWidth(40)
+			if columns[column].field == "cost" {
+				cell.SetAlign(tview.AlignRight)
+			}
+			b.table.SetCell(row, column, cell)
+		}
+	}
+
+	b.table.SetTitle(fmt.Sprintf(" %d of %d resources, $%.2f/month ", len(b.resources), len(b.collection.Resources), total))
+	if len(b.resources) > 0 {
+		b.table.Select(selectedRow, 0)
+	}
+	b.showDetail(selectedRow)
+
+	if filterErr != nil {
+		b.status.SetText("[red]" + tview.Escape(filterErr.Error()))
+	} else {
+		b.status.SetText(helpText)
+	}
+}
+
+// cost returns the monthly cost estimate of a resource, or 0 without one
+func (b *browser) cost(resource models.Resource) float64 {
+	if estimate, exists := b.costEstimates[resource.Key()]; exists && estimate != nil {
+		return estimate.Amount
+	}
+	return 0
+}
+
+// showDetail shows every field, tag and extra value of the resource on a table row
+func (b *browser) showDetail(row int) {
+	if row < 1 || row > len(b.resources) {
+		b.detail.SetText("")
+		return
+	}
+	resource := b.resources[row-1]
+
+	var text strings.Builder
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&text, "[yellow]%s:[-] %s\n", name, tview.Escape(value))
+		}
+	}
+
+	field("Service", resource.Service)
+	field("Region", resource.Region)
+	field("ID", resource.ID)
+	field("Name", resource.Name)
+	field("Type", resource.Type)
+	field("State", resource.State)
+	field("Class", resource.Class)
+	if resource.CreatedAt != nil {
+		field("Created", fmt.Sprintf("%s (%s ago)", resource.CreatedAt.Format(time.RFC3339), formatAge(time.Since(*resource.CreatedAt))))
+	}
+
+	if estimate, exists := b.costEstimates[resource.Key()]; exists && estimate != nil {
+		fmt.Fprintf(&text, "\n[yellow::b]Cost estimate[-::-]\n")
+		field("Monthly", fmt.Sprintf("$%.2f", estimate.Amount))
+		field("Explanation", estimate.Explanation)
+		field("Formula", estimate.Formula)
+		field("Accuracy", estimate.Accuracy)
+	}
+
+	if len(resource.Tags) > 0 {
+		fmt.Fprintf(&text, "\n[yellow::b]Tags[-::-]\n")
+		for _, key := range sortedKeys(resource.Tags) {
+			fmt.Fprintf(&text, "  %s = %s\n", tview.Escape(key), tview.Escape(resource.Tags[key]))
+		}
+	}
+
+	if len(resource.Extra) > 0 {
+		fmt.Fprintf(&text, "\n[yellow::b]Extra[-::-]\n")
+		for _, key := range sortedKeys(resource.Extra) {
+			fmt.Fprintf(&text, "  %s: %s\n", tview.Escape(key), tview.Escape(fmt.Sprint(resource.Extra[key])))
+		}
+	}
+
+	b.detail.SetText(text.String())
+	b.detail.ScrollToBeginning()
+}
+
+// resourceKey identifies a resource across refreshes
+func resourceKey(resource models.Resource) string {
+	return resource.Service + "|" + resource.Region + "|" + resource.ID
+}
+
+// formatAge formats a duration in days, or hours for recent resources
+func formatAge(age time.Duration) string {
+	if age < 48*time.Hour {
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(age.Hours()/24))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}